@@ -35,8 +35,10 @@ const ModalForm = ({ isOpen, onClose, onSubmit, initialData, entity, genres, act
     const [resetAvatar, setResetAvatar] = useState(false);
     const [genreSearchQuery, setGenreSearchQuery] = useState("");
     const [actorSearchQuery, setActorSearchQuery] = useState("");
+    const [producerSearchQuery, setProducerSearchQuery] = useState("");
     const [isGenresOpen, setIsGenresOpen] = useState(false);
     const [isActorsOpen, setIsActorsOpen] = useState(false);
+    const [isProducersOpen, setIsProducersOpen] = useState(false);
     const [runtimeError, setRuntimeError] = useState("");
 
     const bgColor = useColorModeValue("white", "brand.900");
@@ -45,12 +47,22 @@ const ModalForm = ({ isOpen, onClose, onSubmit, initialData, entity, genres, act
 
     // Инициализация formData при изменении initialData
     useEffect(() => {
-        if (initialData) {
+        if (initialData && entity === "film") {
+            // Фильм приходит с участниками в cast и crew, а сохраняется с одним списком credits
+            const { cast, crew, ...film } = initialData;
+            const credits = [...(cast || []), ...(crew || [])].map(({ person_id, role, character, billing_order }) => ({
+                person_id,
+                role,
+                character,
+                billing_order,
+            }));
+            setFormData({ ...film, credits });
+        } else if (initialData) {
             setFormData(initialData);
         } else {
             setFormData({});
         }
-    }, [initialData]);
+    }, [initialData, entity]);
 
     const validateRuntime = (value) => {
         const regex = /^\d+h\s*\d*m$|^\d+h$|^\d+m$/;
@@ -82,11 +94,15 @@ const ModalForm = ({ isOpen, onClose, onSubmit, initialData, entity, genres, act
         setFormData({ ...formData, genre_ids: updatedGenres });
     };
 
-    const handleActorSelect = (actorId) => {
-        const updatedActors = formData.actor_ids?.includes(actorId)
-            ? formData.actor_ids.filter((id) => id !== actorId)
-            : [...(formData.actor_ids || []), actorId];
-        setFormData({ ...formData, actor_ids: updatedActors });
+    const hasCredit = (personId, role) =>
+        !!formData.credits?.some((credit) => credit.person_id === personId && credit.role === role);
+
+    const handleCreditSelect = (personId, role) => {
+        const credits = formData.credits || [];
+        const updatedCredits = hasCredit(personId, role)
+            ? credits.filter((credit) => !(credit.person_id === personId && credit.role === role))
+            : [...credits, { person_id: personId, role, billing_order: credits.filter((credit) => credit.role === role).length }];
+        setFormData({ ...formData, credits: updatedCredits });
     };
 
     const handleSubmit = () => {
@@ -134,6 +150,59 @@ const ModalForm = ({ isOpen, onClose, onSubmit, initialData, entity, genres, act
     const { getRootProps: getPosterRootProps, getInputProps: getPosterInputProps, isDragActive: isPosterDragActive } = useDropzone({ onDrop: onDropPoster });
     const { getRootProps: getAvatarRootProps, getInputProps: getAvatarInputProps, isDragActive: isAvatarDragActive } = useDropzone({ onDrop: onDropAvatar });
 
+    // Выбор персон на роль в фильме, отмеченные персоны попадают в credits
+    const renderCreditSelect = ({ role, label, isOpen, setIsOpen, searchQuery, setSearchQuery, placeholder, emptyText }) => (
+        <FormControl mt={4}>
+            <Flex justify="space-between" align="center">
+                <FormLabel>{label}</FormLabel>
+                <IconButton
+                    aria-label={isOpen ? "Скрыть" : "Показать"}
+                    icon={isOpen ? <ChevronUpIcon /> : <ChevronDownIcon />}
+                    size="sm"
+                    onClick={() => setIsOpen(!isOpen)}
+                />
+            </Flex>
+            <Collapse in={isOpen}>
+                <Box p={4} bg={bgColor} borderRadius="md" border="1px solid" borderColor={borderColor}>
+                    <Input
+                        placeholder={placeholder}
+                        value={searchQuery}
+                        onChange={(e) => setSearchQuery(e.target.value)}
+                        mb={2}
+                        borderColor={borderColor}
+                        focusBorderColor="accent.400"
+                    />
+                    <VStack align="start" spacing={2}>
+                        {actors?.length > 0 ? (
+                            actors
+                                .filter((person) =>
+                                    person.name.toLowerCase().includes(searchQuery.toLowerCase())
+                                )
+                                .map((person) => (
+                                    <Checkbox
+                                        key={person.person_id}
+                                        isChecked={hasCredit(person.person_id, role)}
+                                        onChange={() => handleCreditSelect(person.person_id, role)}
+                                        colorScheme="accent"
+                                        sx={{
+                                            "span[data-checked]": {
+                                                bg: "accent.400",
+                                                borderColor: "accent.400",
+                                            },
+                                        }}
+                                    >
+                                        {person.name}
+                                    </Checkbox>
+                                ))
+                        ) : (
+                            <Text color="gray.500">{emptyText}</Text>
+                        )}
+                    </VStack>
+                </Box>
+            </Collapse>
+        </FormControl>
+    );
+
     const renderFields = () => {
         switch (entity) {
             case "film":
@@ -178,15 +247,6 @@ const ModalForm = ({ isOpen, onClose, onSubmit, initialData, entity, genres, act
                             />
                             <FormErrorMessage>{runtimeError}</FormErrorMessage>
                         </FormControl>
-                        <FormControl mt={4}>
-                            <FormLabel>Producer</FormLabel>
-                            <Input
-                                name="producer"
-                                value={formData.producer || ""}
-                                onChange={handleChange}
-                                isRequired
-                            />
-                        </FormControl>
 
                         {/* Жанры */}
                         <FormControl mt={4}>
@@ -239,56 +299,27 @@ const ModalForm = ({ isOpen, onClose, onSubmit, initialData, entity, genres, act
                             </Collapse>
                         </FormControl>
 
-                        {/* Актеры */}
-                        <FormControl mt={4}>
-                            <Flex justify="space-between" align="center">
-                                <FormLabel>Actors</FormLabel>
-                                <IconButton
-                                    aria-label={isActorsOpen ? "Скрыть" : "Показать"}
-                                    icon={isActorsOpen ? <ChevronUpIcon /> : <ChevronDownIcon />}
-                                    size="sm"
-                                    onClick={() => setIsActorsOpen(!isActorsOpen)}
-                                />
-                            </Flex>
-                            <Collapse in={isActorsOpen}>
-                                <Box p={4} bg={bgColor} borderRadius="md" border="1px solid" borderColor={borderColor}>
-                                    <Input
-                                        placeholder="Поиск актеров..."
-                                        value={actorSearchQuery}
-                                        onChange={(e) => setActorSearchQuery(e.target.value)}
-                                        mb={2}
-                                        borderColor={borderColor}
-                                        focusBorderColor="accent.400"
-                                    />
-                                    <VStack align="start" spacing={2}>
-                                        {actors?.length > 0 ? (
-                                            actors
-                                                .filter((actor) =>
-                                                    actor.name.toLowerCase().includes(actorSearchQuery.toLowerCase())
-                                                )
-                                                .map((actor) => (
-                                                    <Checkbox
-                                                        key={actor.actor_id}
-                                                        isChecked={formData.actor_ids?.includes(actor.actor_id)}
-                                                        onChange={() => handleActorSelect(actor.actor_id)}
-                                                        colorScheme="accent"
-                                                        sx={{
-                                                            "span[data-checked]": {
-                                                                bg: "accent.400",
-                                                                borderColor: "accent.400",
-                                                            },
-                                                        }}
-                                                    >
-                                                        {actor.name}
-                                                    </Checkbox>
-                                                ))
-                                        ) : (
-                                            <Text color="gray.500">Нет доступных актеров.</Text>
-                                        )}
-                                    </VStack>
-                                </Box>
-                            </Collapse>
-                        </FormControl>
+                        {/* Актеры и продюсеры */}
+                        {renderCreditSelect({
+                            role: "cast",
+                            label: "Actors",
+                            isOpen: isActorsOpen,
+                            setIsOpen: setIsActorsOpen,
+                            searchQuery: actorSearchQuery,
+                            setSearchQuery: setActorSearchQuery,
+                            placeholder: "Поиск актеров...",
+                            emptyText: "Нет доступных актеров.",
+                        })}
+                        {renderCreditSelect({
+                            role: "producer",
+                            label: "Producers",
+                            isOpen: isProducersOpen,
+                            setIsOpen: setIsProducersOpen,
+                            searchQuery: producerSearchQuery,
+                            setSearchQuery: setProducerSearchQuery,
+                            placeholder: "Поиск продюсеров...",
+                            emptyText: "Нет доступных персон.",
+                        })}
 
                         {/* Постер */}
                         <FormControl mt={4}>
@@ -47,7 +47,7 @@ const AdminActors = () => {
 
     const handleSubmit = async (data) => {
         if (selectedActor) {
-            await handleUpdateActor(selectedActor.actor_id, data);
+            await handleUpdateActor(selectedActor.person_id, data);
         } else {
             await handleCreateActor(data);
         }
@@ -67,7 +67,7 @@ const AdminActors = () => {
         if (selectedActors.length === actors.length) {
             setSelectedActors([]);
         } else {
-            setSelectedActors(actors.map(actor => actor.actor_id));
+            setSelectedActors(actors.map(actor => actor.person_id));
         }
     };
 
@@ -127,7 +127,7 @@ const AdminActors = () => {
                 </Thead>
                 <Tbody>
                     {actors.map((actor) => (
-                        <Tr key={actor.actor_id}>
+                        <Tr key={actor.person_id}>
                             <Td>
                                 <Checkbox
                                     sx={{
@@ -137,11 +137,11 @@ const AdminActors = () => {
                                         },
                                     }}
                                     colorScheme="accent"
-                                    isChecked={selectedActors.includes(actor.actor_id)}
-                                    onChange={() => handleSelectActor(actor.actor_id)}
+                                    isChecked={selectedActors.includes(actor.person_id)}
+                                    onChange={() => handleSelectActor(actor.person_id)}
                                 />
                             </Td>
-                            <Td>{actor.actor_id}</Td>
+                            <Td>{actor.person_id}</Td>
                             <Td>{actor.name}</Td>
                             <Td> <Link href={actor.wiki_url} isExternal><Text maxW="200px" isTruncated>{actor.wiki_url}</Text></Link></Td>
                             <Td width="120px" textAlign="right">
@@ -156,7 +156,7 @@ const AdminActors = () => {
                                 <IconButton
                                     aria-label="Delete"
                                     icon={<DeleteIcon />}
-                                    onClick={() => handleDelete(actor.actor_id)}
+                                    onClick={() => handleDelete(actor.person_id)}
                                     colorScheme="red"
                                     size="sm"
                                 />
@@ -52,9 +52,9 @@ const ActorFilter = ({
                         <HStack wrap="wrap">
                             {actors.slice(0, 4).map((actor) => (
                                 <Checkbox
-                                    key={actor.actor_id}
-                                    isChecked={selectedActors.includes(actor.actor_id)}
-                                    onChange={() => onActorSelect(actor.actor_id)}
+                                    key={actor.person_id}
+                                    isChecked={selectedActors.includes(actor.person_id)}
+                                    onChange={() => onActorSelect(actor.person_id)}
                                     colorScheme="accent"
                                     sx={{
                                         "span[data-checked]": {
@@ -96,9 +96,9 @@ const ActorFilter = ({
                                         )
                                         .map((actor) => (
                                             <Checkbox
-                                                key={actor.actor_id}
-                                                isChecked={selectedActors.includes(actor.actor_id)}
-                                                onChange={() => onActorSelect(actor.actor_id)}
+                                                key={actor.person_id}
+                                                isChecked={selectedActors.includes(actor.person_id)}
+                                                onChange={() => onActorSelect(actor.person_id)}
                                                 colorScheme="accent"
                                                 sx={{
                                                     "span[data-checked]": {
@@ -120,7 +120,7 @@ const ActorFilter = ({
                         <Text fontWeight="bold" color={textColor}>Выбранные актеры:</Text>
                         <HStack wrap="wrap">
                             {selectedActors.map((actorId) => {
-                                const actor = actors.find((a) => a.actor_id === actorId);
+                                const actor = actors.find((a) => a.person_id === actorId);
                                 return (
                                     <Box
                                         key={actorId}
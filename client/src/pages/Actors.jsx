@@ -240,7 +240,7 @@ const Actors = () => {
                     ) : (
                         <Flex wrap="wrap" gap={4}>
                             {actors.map((actor) => (
-                                <ActorCard key={actor.actor_id} actor={actor} />
+                                <ActorCard key={actor.person_id} actor={actor} />
                             ))}
                         </Flex>
                     )}
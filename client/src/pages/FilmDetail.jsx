@@ -49,7 +49,7 @@ const FilmDetail = () => {
                 const response = await getFilmById(id);
                 if (response.status === "success") {
                     setFilm(response.data);
-                    setActors(response.data.actors);
+                    setActors(response.data.cast || []);
                     const reviews = await fetchReviewsByFilmId(id);
                     setReviews(reviews);
                 } else {
@@ -180,7 +180,8 @@ const FilmDetail = () => {
                     </HStack>
 
                     <Text fontSize="md">
-                        <strong>Продюсер:</strong> {film.producer}
+                        <strong>Продюсер:</strong>{" "}
+                        {film.crew?.filter((credit) => credit.role === "producer").map((credit) => credit.name).join(", ")}
                     </Text>
 
                     {/* Вывод жанров */}
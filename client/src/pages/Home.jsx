@@ -109,7 +109,7 @@ const Home = () => {
                         </Center>
                     ) : (
                         popularActors.map((actor) => (
-                            <ActorCard key={actor.actor_id} actor={actor} />
+                            <ActorCard key={actor.person_id} actor={actor} />
                         ))
                     )}
                 </Flex>
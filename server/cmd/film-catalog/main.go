@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -17,16 +18,20 @@ import (
 	"server/config"
 	"server/docs"
 	_ "server/docs"
+	"server/internal/init/blob"
 	"server/internal/init/cache"
 	"server/internal/init/database"
 	"server/internal/init/elasticsearch"
 	"server/internal/init/s3"
-	actorC "server/internal/modules/actor/controller"
-	actorRp "server/internal/modules/actor/repo"
-	actorCh "server/internal/modules/actor/repo/cache"
-	actorDb "server/internal/modules/actor/repo/database"
-	actorS3 "server/internal/modules/actor/repo/s3"
-	actorUC "server/internal/modules/actor/usecase"
+	auditC "server/internal/modules/audit/controller"
+	auditRp "server/internal/modules/audit/repo"
+	auditDb "server/internal/modules/audit/repo/database"
+	auditUC "server/internal/modules/audit/usecase"
+	collectionC "server/internal/modules/collection/controller"
+	collectionRp "server/internal/modules/collection/repo"
+	collectionCh "server/internal/modules/collection/repo/cache"
+	collectionDb "server/internal/modules/collection/repo/database"
+	collectionUC "server/internal/modules/collection/usecase"
 	filmC "server/internal/modules/film/controller"
 	filmRp "server/internal/modules/film/repo"
 	filmCh "server/internal/modules/film/repo/cache"
@@ -38,12 +43,61 @@ import (
 	genreRp "server/internal/modules/genre/repo"
 	genreCh "server/internal/modules/genre/repo/cache"
 	genreDb "server/internal/modules/genre/repo/database"
+	genreS3 "server/internal/modules/genre/repo/s3"
 	genreUC "server/internal/modules/genre/usecase"
+	mediaC "server/internal/modules/media/controller"
+	mediaRp "server/internal/modules/media/repo"
+	mediaCh "server/internal/modules/media/repo/cache"
+	mediaDb "server/internal/modules/media/repo/database"
+	mediaS3 "server/internal/modules/media/repo/s3"
+	mediaUC "server/internal/modules/media/usecase"
+	personC "server/internal/modules/person/controller"
+	personRp "server/internal/modules/person/repo"
+	personCh "server/internal/modules/person/repo/cache"
+	personDb "server/internal/modules/person/repo/database"
+	personS3 "server/internal/modules/person/repo/s3"
+	personUC "server/internal/modules/person/usecase"
+	recC "server/internal/modules/recommendation/controller"
+	recRp "server/internal/modules/recommendation/repo"
+	recCh "server/internal/modules/recommendation/repo/cache"
+	recDb "server/internal/modules/recommendation/repo/database"
+	recUC "server/internal/modules/recommendation/usecase"
 	reviewC "server/internal/modules/review/controller"
 	reviewRp "server/internal/modules/review/repo"
 	reviewCh "server/internal/modules/review/repo/cache"
 	reviewDb "server/internal/modules/review/repo/database"
 	reviewUC "server/internal/modules/review/usecase"
+	revisionC "server/internal/modules/revision/controller"
+	revisionRp "server/internal/modules/revision/repo"
+	revisionDb "server/internal/modules/revision/repo/database"
+	revisionUC "server/internal/modules/revision/usecase"
+	seriesC "server/internal/modules/series/controller"
+	seriesRp "server/internal/modules/series/repo"
+	seriesCh "server/internal/modules/series/repo/cache"
+	seriesDb "server/internal/modules/series/repo/database"
+	seriesUC "server/internal/modules/series/usecase"
+	suggestionC "server/internal/modules/suggestion/controller"
+	suggestionRp "server/internal/modules/suggestion/repo"
+	suggestionDb "server/internal/modules/suggestion/repo/database"
+	suggestionUC "server/internal/modules/suggestion/usecase"
+	tagC "server/internal/modules/tag/controller"
+	tagRp "server/internal/modules/tag/repo"
+	tagCh "server/internal/modules/tag/repo/cache"
+	tagDb "server/internal/modules/tag/repo/database"
+	tagUC "server/internal/modules/tag/usecase"
+	translationC "server/internal/modules/translation/controller"
+	translationRp "server/internal/modules/translation/repo"
+	translationDb "server/internal/modules/translation/repo/database"
+	translationUC "server/internal/modules/translation/usecase"
+	trashC "server/internal/modules/trash/controller"
+	trashRp "server/internal/modules/trash/repo"
+	trashDb "server/internal/modules/trash/repo/database"
+	trashUC "server/internal/modules/trash/usecase"
+	uploadC "server/internal/modules/upload/controller"
+	uploadRp "server/internal/modules/upload/repo"
+	uploadDb "server/internal/modules/upload/repo/database"
+	uploadS3 "server/internal/modules/upload/repo/s3"
+	uploadUC "server/internal/modules/upload/usecase"
 	authC "server/internal/modules/user/auth/controller"
 	authRp "server/internal/modules/user/auth/repo"
 	authCh "server/internal/modules/user/auth/repo/cache"
@@ -60,8 +114,11 @@ import (
 	profileS3 "server/internal/modules/user/profile/repo/s3"
 	profileUC "server/internal/modules/user/profile/usecase"
 	"server/pkg/lib/TaskService"
+	"server/pkg/lib/audit"
 	"server/pkg/lib/emailsender"
+	"server/pkg/middleware/httpcache"
 	middleAuth "server/pkg/middleware/jwt"
+	middlelocale "server/pkg/middleware/locale"
 	middlelog "server/pkg/middleware/logger"
 	"syscall"
 	"time"
@@ -70,7 +127,7 @@ import (
 type App struct {
 	Storage     *database.Storage
 	Cache       *cache.Cache
-	S3          *s3.S3Storage
+	Blob        blob.Store
 	ES          *elasticsearch.Search
 	EmailSender *emailsender.EmailSender
 	Router      chi.Router
@@ -80,6 +137,18 @@ type App struct {
 	TS          *TaskService.TaskService
 }
 
+// newBlobStore выбирает хранилище файлов: S3 или папку на диске для разработки без объектного хранилища
+func newBlobStore(cfg *config.Config) (blob.Store, error) {
+	switch cfg.StorageConfig.Driver {
+	case "local":
+		return blob.NewLocalStore(cfg.StorageConfig, cfg.S3Config.Buckets)
+	case "s3", "":
+		return s3.NewS3Storage(cfg.S3Config, cfg.StorageConfig.CDNPrefix)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageConfig.Driver)
+	}
+}
+
 func NewApp(cfg *config.Config, log *slog.Logger) (*App, error) {
 
 	Storage, err := database.NewStorage(cfg.DbConfig)
@@ -87,14 +156,14 @@ func NewApp(cfg *config.Config, log *slog.Logger) (*App, error) {
 		return nil, fmt.Errorf("db init failed: %w", err)
 	}
 
-	Cache, err := cache.NewCache(cfg.CacheConfig)
+	Cache, err := cache.NewCache(cfg.CacheConfig, log)
 	if err != nil {
 		return nil, fmt.Errorf("cache init failed: %w", err)
 	}
 
-	s3s, err := s3.NewS3Storage(cfg.S3Config)
+	blobStore, err := newBlobStore(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("s3 init failed: %w", err)
+		return nil, fmt.Errorf("storage init failed: %w", err)
 	}
 
 	es, err := elasticsearch.NewSearch(cfg.ElasticsearchConfig)
@@ -109,7 +178,9 @@ func NewApp(cfg *config.Config, log *slog.Logger) (*App, error) {
 
 	router := chi.NewRouter()
 
-	taskService := TaskService.NewTaskService(Storage.Db, log)
+	expvar.Publish("cache", expvar.Func(func() any { return Cache.Stats() }))
+
+	taskService := TaskService.NewTaskService(Storage.Db, log, blobStore)
 
 	c := cron.New()
 	_, err = c.AddFunc("0 0 * * *", func() {
@@ -118,9 +189,16 @@ func NewApp(cfg *config.Config, log *slog.Logger) (*App, error) {
 	if err != nil {
 		return nil, fmt.Errorf("cron init failed: %w", err)
 	}
+	_, err = c.AddFunc(cfg.MediaGCConfig.Schedule, func() {
+		taskService.PurgeRetiredMedia(cfg.MediaGCConfig.GracePeriod, cfg.MediaGCConfig.DryRun)
+		taskService.ReconcileMedia(cfg.MediaGCConfig.GracePeriod, cfg.MediaGCConfig.DryRun)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cron init failed: %w", err)
+	}
 	c.Start()
 
-	return &App{Storage: Storage, Cache: Cache, S3: s3s, ES: es, EmailSender: eSender, Router: router, Log: log, Cfg: cfg, Cron: c, TS: taskService}, nil
+	return &App{Storage: Storage, Cache: Cache, Blob: blobStore, ES: es, EmailSender: eSender, Router: router, Log: log, Cfg: cfg, Cron: c, TS: taskService}, nil
 }
 
 func (app *App) Start() error {
@@ -164,13 +242,15 @@ func (app *App) SetupRoutes() {
 	app.Router.Use(
 		middleware.Recoverer,
 		middleware.RequestID,
+		audit.Middleware(),
 		middlelog.New(app.Log),
+		middlelocale.New(),
 		middleware.URLFormat,
 		cors.Handler(cors.Options{
 			AllowedOrigins:   []string{"http://192.168.0.107:5174/"}, // Укажите домен вашего фронтенда
-			AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-			ExposedHeaders:   []string{"Link"},
+			AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders:   []string{"Accept", "Accept-Language", "Authorization", "Content-Type", "X-CSRF-Token", "If-None-Match", "If-Match"},
+			ExposedHeaders:   []string{"Link", "Content-Language", "ETag"},
 			AllowCredentials: true,
 			MaxAge:           300, // Максимальное время кэширования preflight запросов
 		}),
@@ -180,6 +260,11 @@ func (app *App) SetupRoutes() {
 	app.Router.Get("/swagger/*", swag.Handler(
 		swag.URL("https://film-catalog-8re5.onrender.com/swagger/doc.json"),
 	))
+	// локальное хранилище файлов отдает их через сервер, S3 - напрямую из бакетов
+	if local, ok := app.Blob.(*blob.LocalStore); ok {
+		app.Router.Mount(local.MountPath(), local.Handler())
+	}
+
 	apiVersion := "/v1"
 
 	AuthDB := authDb.NewAuthDatabase(app.Storage.Db, app.Log)
@@ -212,55 +297,76 @@ func (app *App) SetupRoutes() {
 	})
 
 	ProfileDB := profileDb.NewProfileDatabase(app.Storage.Db, app.Log)
-	ProfileS3 := profileS3.NewProfileS3(app.Log, app.S3)
+	ProfileS3 := profileS3.NewProfileS3(app.Log, app.Blob)
 	ProfileRp := profileRp.NewProfileRepo(ProfileDB, ProfileS3)
 	ProfileUC := profileUC.NewProfileUseCase(app.Log, ProfileRp)
 	ProfileC := profileC.NewProfileController(app.Log, ProfileUC)
 
 	var AuthMiddleware = middleAuth.NewUserAuth(app.Log)
 	var AuthAdminMiddleware = middleAuth.NewAdminAuth(app.Log)
-	_ = AuthAdminMiddleware
+
+	// попадания и промахи кэша по уровням (переменная cache) и статистика рантайма
+	app.Router.With(AuthAdminMiddleware).Get("/debug/vars", expvar.Handler().ServeHTTP)
 
 	app.Router.Route(apiVersion+"/profile", func(r chi.Router) {
 		r.Use(AuthMiddleware)
 		r.Get("/", ProfileC.GetUser)
 		r.Put("/", ProfileC.UpdateUser)
+		r.Patch("/", ProfileC.PatchUser)
 		r.Delete("/", ProfileC.DeleteUser)
 	})
 
-	ActorDB := actorDb.NewActorDatabase(app.Storage.Db, app.Log)
-	ActorS3 := actorS3.NewActorS3(app.Log, app.S3)
-	ActorCh := actorCh.NewActorCahce(app.Cache)
-	ActorRp := actorRp.NewActorRepo(ActorDB, ActorS3, ActorCh)
-	ActorUC := actorUC.NewActorUseCase(app.Log, ActorRp)
-	ActorC := actorC.NewActorController(app.Log, ActorUC)
+	// переводы нужны персонам, жанрам и фильмам, поэтому репозиторий создается раньше модулей
+	TranslationDB := translationDb.NewTranslationDatabase(app.Storage.Db, app.Log)
+	TranslationRp := translationRp.NewTranslationRepo(TranslationDB)
 
-	app.Router.Route(apiVersion+"/actors", func(r chi.Router) {
-		r.Get("/", ActorC.GetActors)
-		r.Get("/{id}", ActorC.GetActor)
+	PersonDB := personDb.NewPersonDatabase(app.Storage.Db, app.Log)
+	PersonS3 := personS3.NewPersonS3(app.Log, app.Blob)
+	PersonCh := personCh.NewPersonCahce(app.Cache)
+	PersonRp := personRp.NewPersonRepo(PersonDB, PersonS3, PersonCh)
+	PersonUC := personUC.NewPersonUseCase(app.Log, PersonRp, TranslationRp)
+	PersonC := personC.NewPersonController(app.Log, PersonUC)
+
+	personRoutes := func(r chi.Router) {
 		r.Group(func(r chi.Router) {
-			//r.Use(AuthAdminMiddleware)
-			r.Post("/", ActorC.CreateActor)
-			r.Put("/{id}", ActorC.UpdateActor)
-			r.Delete("/{id}", ActorC.DeleteActor)
+			r.Use(httpcache.New(5*time.Minute, time.Hour))
+			r.Get("/", PersonC.GetPersons)
+			r.Get("/{id}", PersonC.GetPerson)
+			r.Get("/{id}/filmography", PersonC.GetFilmography)
 		})
-	})
+		r.Group(func(r chi.Router) {
+			r.Use(AuthAdminMiddleware)
+			r.Post("/", PersonC.CreatePerson)
+			r.Put("/{id}", PersonC.UpdatePerson)
+			r.Delete("/{id}", PersonC.DeletePerson)
+		})
+		r.With(AuthAdminMiddleware).Patch("/{id}", PersonC.PatchPerson)
+	}
+	app.Router.Route(apiVersion+"/persons", personRoutes)
+	// старый адрес, оставлен для совместимости с клиентами
+	app.Router.Route(apiVersion+"/actors", personRoutes)
 
 	GenreDB := genreDb.NewGenreDatabase(app.Storage.Db, app.Log)
 	GenreCH := genreCh.NewGenreCache(app.Log, app.Cache)
-	GenreRp := genreRp.NewGenreRepo(GenreDB, GenreCH)
-	GenreUC := genreUC.NewGenreUsecase(GenreRp, app.Log)
+	GenreS3 := genreS3.NewGenreS3(app.Log, app.Blob)
+	GenreRp := genreRp.NewGenreRepo(GenreDB, GenreCH, GenreS3)
+	GenreUC := genreUC.NewGenreUsecase(GenreRp, app.Log, TranslationRp)
 	GenreC := genreC.NewGenreController(app.Log, GenreUC)
 
 	app.Router.Route(apiVersion+"/genres", func(r chi.Router) {
-		r.Get("/", GenreC.GetGenres)
-		r.Get("/{id}", GenreC.GetGenre)
 		r.Group(func(r chi.Router) {
-			//r.Use(AuthAdminMiddleware)
+			r.Use(httpcache.New(time.Hour, 24*time.Hour))
+			r.Get("/", GenreC.GetGenres)
+			r.Get("/{id}", GenreC.GetGenre)
+		})
+		r.Get("/slug/{slug}", GenreC.GetGenrePage)
+		r.Group(func(r chi.Router) {
+			r.Use(AuthAdminMiddleware)
 			r.Post("/", GenreC.CreateGenre)
 			r.Put("/", GenreC.UpdateGenre)
 			r.Delete("/{id}", GenreC.DeleteGenre)
 		})
+		r.With(AuthAdminMiddleware).Patch("/{id}", GenreC.PatchGenre)
 	})
 
 	ReviewDB := reviewDb.NewReviewDatabase(app.Storage.Db, app.Log)
@@ -270,11 +376,14 @@ func (app *App) SetupRoutes() {
 	ReviewC := reviewC.NewReviewController(app.Log, ReviewUC)
 
 	app.Router.Route(apiVersion+"/reviews", func(r chi.Router) {
-		r.Get("/", ReviewC.GetReviewsByFilmID)
+		r.Group(func(r chi.Router) {
+			r.Use(httpcache.New(30*time.Second, 5*time.Minute))
+			r.Get("/", ReviewC.GetReviewsByFilmID)
+			r.Get("/film/{film_id}", ReviewC.GetReviewsByFilmID)
+		})
 		r.Get("/user/{user_id}", ReviewC.GetReviewsByReviewerID)
-		r.Get("/film/{film_id}", ReviewC.GetReviewsByFilmID)
 		r.Group(func(r chi.Router) {
-			//r.Use(AuthMiddleware)
+			r.Use(AuthMiddleware)
 			r.Post("/", ReviewC.CreateReview)
 			r.Put("/", ReviewC.UpdateReview)
 			r.Delete("/{id}", ReviewC.DeleteReview)
@@ -282,24 +391,231 @@ func (app *App) SetupRoutes() {
 	})
 	FilmDB := filmDb.NewFilmDatabase(app.Storage.Db, app.Log)
 	FilmCH := filmCh.NewFilmCache(app.Cache)
-	FilmS3 := filmS3.NewFilmS3(app.Log, app.S3)
+	FilmS3 := filmS3.NewFilmS3(app.Log, app.Blob)
 	FilmES := filmES.NewFilmEs(app.Log, app.ES)
 	FilmRp := filmRp.NewFilmRepo(FilmDB, FilmCH, FilmS3, FilmES)
-	FilmUC := filmUC.NewFilmUsecase(FilmRp, app.Log)
+	FilmUC := filmUC.NewFilmUsecase(FilmRp, app.Log, TranslationRp)
 	FilmC := filmC.NewFilmController(app.Log, FilmUC)
 
+	SeriesDB := seriesDb.NewSeriesDatabase(app.Storage.Db, app.Log)
+	SeriesCh := seriesCh.NewSeriesCache(app.Cache)
+	SeriesRp := seriesRp.NewSeriesRepo(SeriesDB, SeriesCh)
+	SeriesUC := seriesUC.NewSeriesUseCase(app.Log, SeriesRp, FilmUC)
+	SeriesC := seriesC.NewSeriesController(app.Log, SeriesUC)
+
+	CollectionDB := collectionDb.NewCollectionDatabase(app.Storage.Db, app.Log)
+	CollectionCh := collectionCh.NewCollectionCache(app.Cache)
+	CollectionRp := collectionRp.NewCollectionRepo(CollectionDB, CollectionCh)
+	CollectionUC := collectionUC.NewCollectionUseCase(app.Log, CollectionRp, FilmUC)
+	CollectionC := collectionC.NewCollectionController(app.Log, CollectionUC)
+
+	TagDB := tagDb.NewTagDatabase(app.Storage.Db, app.Log)
+	TagCh := tagCh.NewTagCache(app.Cache)
+	TagRp := tagRp.NewTagRepo(TagDB, TagCh)
+	TagUC := tagUC.NewTagUseCase(app.Log, TagRp, FilmUC)
+	TagC := tagC.NewTagController(app.Log, TagUC)
+
+	MediaDB := mediaDb.NewMediaDatabase(app.Storage.Db, app.Log)
+	MediaCh := mediaCh.NewMediaCache(app.Cache)
+	MediaS3 := mediaS3.NewMediaS3(app.Log, app.Blob)
+	MediaRp := mediaRp.NewMediaRepo(MediaDB, MediaCh, MediaS3)
+	MediaUC := mediaUC.NewMediaUseCase(app.Log, MediaRp, FilmUC)
+	MediaC := mediaC.NewMediaController(app.Log, MediaUC)
+
 	// Настройка маршрутов для Film
 	app.Router.Route(apiVersion+"/films", func(r chi.Router) {
 		r.Get("/", FilmC.GetFilms)
-		r.Get("/{id}", FilmC.GetFilmByID)
+		r.With(httpcache.New(time.Minute, 10*time.Minute)).Get("/{id}", FilmC.GetFilmByID)
 		r.Get("/search", FilmC.SearchFilms)
+		r.Get("/{id}/similar", FilmC.GetSimilarFilms)
+		r.Get("/{id}/related", CollectionC.GetRelatedTitles)
+		r.Get("/{id}/tags", TagC.GetFilmTagCloud)
+		r.Get("/{id}/images", MediaC.GetFilmImages)
+		r.Get("/{id}/videos", MediaC.GetFilmVideos)
 
 		r.Group(func(r chi.Router) {
-			//r.Use(AuthAdminMiddleware)
+			r.Use(AuthAdminMiddleware)
 			r.Post("/", FilmC.CreateFilm)
 			r.Put("/{id}", FilmC.UpdateFilm)
 			r.Delete("/{id}", FilmC.DeleteFilm)
 		})
+
+		// Частичное изменение, связи, теги, изображения и видео фильма
+		r.Group(func(r chi.Router) {
+			r.Use(AuthAdminMiddleware)
+			r.Patch("/{id}", FilmC.PatchFilm)
+			r.Post("/{id}/relations", CollectionC.AddFilmRelation)
+			r.Delete("/{id}/relations/{related_id}", CollectionC.DeleteFilmRelation)
+			r.Put("/{id}/tags/{tag_id}", TagC.ApproveFilmTag)
+			r.Delete("/{id}/tags/{tag_id}", TagC.DeleteFilmTag)
+			r.Post("/{id}/images", MediaC.UploadFilmImage)
+			r.Put("/{id}/images/order", MediaC.ReorderFilmImages)
+			r.Put("/{id}/images/{image_id}/primary", MediaC.SetPrimaryFilmImage)
+			r.Delete("/{id}/images/{image_id}", MediaC.DeleteFilmImage)
+			r.Post("/{id}/videos", MediaC.CreateFilmVideo)
+			r.Put("/{id}/videos/{video_id}", MediaC.UpdateFilmVideo)
+			r.Delete("/{id}/videos/{video_id}", MediaC.DeleteFilmVideo)
+		})
+
+		// Предложения тегов и голоса за них
+		r.Group(func(r chi.Router) {
+			r.Use(AuthMiddleware)
+			r.Post("/{id}/tags", TagC.SuggestTag)
+			r.Put("/{id}/tags/{tag_id}/vote", TagC.VoteFilmTag)
+			r.Delete("/{id}/tags/{tag_id}/vote", TagC.DeleteFilmTagVote)
+		})
+
+		// Сезоны и серии сериалов
+		r.Route("/{id}/seasons", func(r chi.Router) {
+			r.Get("/", SeriesC.GetSeasons)
+			r.Get("/{season}", SeriesC.GetSeason)
+			r.Get("/{season}/reviews", ReviewC.GetReviewsBySeason)
+			r.Get("/{season}/episodes", SeriesC.GetEpisodes)
+			r.Get("/{season}/episodes/{episode}", SeriesC.GetEpisode)
+			r.Get("/{season}/episodes/{episode}/reviews", ReviewC.GetReviewsByEpisode)
+
+			r.Group(func(r chi.Router) {
+				r.Use(AuthAdminMiddleware)
+				r.Post("/", SeriesC.CreateSeason)
+				r.Put("/{season}", SeriesC.UpdateSeason)
+				r.Delete("/{season}", SeriesC.DeleteSeason)
+				r.Post("/{season}/episodes", SeriesC.CreateEpisode)
+				r.Put("/{season}/episodes/{episode}", SeriesC.UpdateEpisode)
+				r.Delete("/{season}/episodes/{episode}", SeriesC.DeleteEpisode)
+			})
+		})
+	})
+
+	app.Router.Route(apiVersion+"/collections", func(r chi.Router) {
+		r.Get("/", CollectionC.GetCollections)
+		r.Get("/{id}", CollectionC.GetCollection)
+		r.Group(func(r chi.Router) {
+			r.Use(AuthAdminMiddleware)
+			r.Post("/", CollectionC.CreateCollection)
+			r.Put("/{id}", CollectionC.UpdateCollection)
+			r.Delete("/{id}", CollectionC.DeleteCollection)
+			r.Put("/{id}/films", CollectionC.SetCollectionFilms)
+		})
+	})
+
+	app.Router.Route(apiVersion+"/tags", func(r chi.Router) {
+		r.Get("/", TagC.GetTags)
+		r.Group(func(r chi.Router) {
+			r.Use(AuthAdminMiddleware)
+			r.Get("/suggestions", TagC.GetSuggestions)
+			r.Post("/", TagC.CreateTag)
+			r.Put("/{id}", TagC.UpdateTag)
+			r.Delete("/{id}", TagC.DeleteTag)
+		})
+	})
+
+	TranslationUC := translationUC.NewTranslationUseCase(app.Log, TranslationRp, FilmUC)
+	TranslationC := translationC.NewTranslationController(app.Log, TranslationUC)
+
+	app.Router.Route(apiVersion+"/translations", func(r chi.Router) {
+		r.Get("/{entity}/{id}", TranslationC.GetTranslations)
+		r.Group(func(r chi.Router) {
+			r.Use(AuthAdminMiddleware)
+			r.Put("/{entity}/{id}/{locale}", TranslationC.SetTranslation)
+			r.Delete("/{entity}/{id}/{locale}", TranslationC.DeleteTranslation)
+		})
+	})
+
+	RecDB := recDb.NewRecommendationDatabase(app.Storage.Db, app.Log)
+	RecCh := recCh.NewRecommendationCache(app.Cache)
+	RecRp := recRp.NewRecommendationRepo(RecDB, RecCh)
+	RecUC := recUC.NewRecommendationUseCase(app.Log, RecRp, FilmUC, app.Cfg.RecommendationConfig)
+	RecC := recC.NewRecommendationController(app.Log, RecUC)
+
+	if _, err := app.Cron.AddFunc(app.Cfg.RecommendationConfig.Schedule, RecUC.RecomputeSimilarities); err != nil {
+		app.Log.Error("failed to schedule similarities recompute", "error", err)
+	}
+
+	app.Router.Route(apiVersion+"/recommendations", func(r chi.Router) {
+		r.Use(AuthMiddleware)
+		r.Get("/", RecC.GetRecommendations)
+	})
+
+	UploadDB := uploadDb.NewUploadDatabase(app.Storage.Db, app.Log)
+	UploadS3 := uploadS3.NewUploadS3(app.Log, app.Blob)
+	UploadRp := uploadRp.NewUploadRepo(UploadDB, UploadS3)
+	UploadUC := uploadUC.NewUploadUseCase(app.Log, UploadRp, FilmUC, PersonUC, ProfileUC)
+	UploadC := uploadC.NewUploadController(app.Log, UploadUC)
+
+	if _, err := app.Cron.AddFunc("*/30 * * * *", UploadUC.CleanStaging); err != nil {
+		app.Log.Error("failed to schedule staging uploads cleanup", "error", err)
+	}
+
+	// Загрузка постеров и аватаров напрямую в S3
+	app.Router.Route(apiVersion+"/uploads", func(r chi.Router) {
+		r.Use(AuthMiddleware)
+		r.Post("/", UploadC.CreateUpload)
+		r.Post("/{id}/finalize", UploadC.FinalizeUpload)
+	})
+
+	AuditDB := auditDb.NewAuditDatabase(app.Storage.Db, app.Log)
+	AuditRp := auditRp.NewAuditRepo(AuditDB)
+	AuditUC := auditUC.NewAuditUseCase(app.Log, AuditRp)
+	AuditC := auditC.NewAuditController(app.Log, AuditUC)
+
+	// Журнал изменений сущностей
+	app.Router.Route(apiVersion+"/admin/audit", func(r chi.Router) {
+		r.Use(AuthAdminMiddleware)
+		r.Get("/", AuditC.GetLog)
+	})
+
+	TrashDB := trashDb.NewTrashDatabase(app.Storage.Db, app.Log)
+	TrashRp := trashRp.NewTrashRepo(TrashDB)
+	TrashUC := trashUC.NewTrashUseCase(app.Log, TrashRp, FilmUC, PersonUC, GenreUC, ProfileUC, app.Cfg.TrashConfig)
+	TrashC := trashC.NewTrashController(app.Log, TrashUC)
+
+	if _, err := app.Cron.AddFunc(app.Cfg.TrashConfig.Schedule, TrashUC.PurgeExpired); err != nil {
+		app.Log.Error("failed to schedule trash purge", "error", err)
+	}
+
+	// Корзина удаленных фильмов, персон, жанров и пользователей
+	app.Router.Route(apiVersion+"/admin/trash", func(r chi.Router) {
+		r.Use(AuthAdminMiddleware)
+		r.Get("/", TrashC.GetTrash)
+		r.Post("/{entity}/{id}/restore", TrashC.Restore)
+	})
+
+	RevisionDB := revisionDb.NewRevisionDatabase(app.Storage.Db, app.Log)
+	RevisionRp := revisionRp.NewRevisionRepo(RevisionDB)
+	RevisionUC := revisionUC.NewRevisionUseCase(app.Log, RevisionRp, FilmUC, PersonUC)
+	RevisionC := revisionC.NewRevisionController(app.Log, RevisionUC)
+
+	// История версий фильмов и персон
+	app.Router.Route(apiVersion+"/revisions/{entity}/{id}", func(r chi.Router) {
+		r.Get("/", RevisionC.GetRevisions)
+		r.Get("/diff", RevisionC.DiffRevisions)
+		r.Get("/{version}", RevisionC.GetRevision)
+		r.With(AuthAdminMiddleware).Post("/{version}/revert", RevisionC.RevertRevision)
+	})
+
+	SuggestionDB := suggestionDb.NewSuggestionDatabase(app.Storage.Db, app.Log)
+	SuggestionRp := suggestionRp.NewSuggestionRepo(SuggestionDB)
+	SuggestionUC := suggestionUC.NewSuggestionUseCase(app.Log, SuggestionRp, FilmUC, PersonUC, ProfileUC, app.EmailSender,
+		filmC.ParsePatch, personC.ParsePatch)
+	SuggestionC := suggestionC.NewSuggestionController(app.Log, SuggestionUC)
+
+	// Предложения правок от пользователей
+	app.Router.Route(apiVersion+"/suggestions", func(r chi.Router) {
+		r.Use(AuthMiddleware)
+		r.Get("/my", SuggestionC.GetMySuggestions)
+		r.Get("/my/{suggestionId}", SuggestionC.GetMySuggestion)
+		r.Post("/my/{suggestionId}/comments", SuggestionC.CommentMySuggestion)
+		r.Post("/{entity}/{id}", SuggestionC.SubmitSuggestion)
+	})
+
+	// Очередь предложений правок для редакторов
+	app.Router.Route(apiVersion+"/admin/suggestions", func(r chi.Router) {
+		r.Use(AuthAdminMiddleware)
+		r.Get("/", SuggestionC.GetQueue)
+		r.Get("/{suggestionId}", SuggestionC.GetSuggestion)
+		r.Post("/{suggestionId}/approve", SuggestionC.ApproveSuggestion)
+		r.Post("/{suggestionId}/reject", SuggestionC.RejectSuggestion)
+		r.Post("/{suggestionId}/comments", SuggestionC.CommentSuggestion)
 	})
 }
 
@@ -8,14 +8,24 @@ import (
 )
 
 type Config struct {
-	Env                 string              `yaml:"env" env-Default:"development"`
-	DbConfig            DbConfig            `yaml:"db" env-required:"true"`
-	HttpServerConfig    HttpServerConfig    `yaml:"http_server"  env-required:"true"`
-	CacheConfig         CacheConfig         `yaml:"cache" env-required:"true"`
-	SMTPConfig          SMTPConfig          `yaml:"smtp" env-required:"true"`
-	JWTConfig           JWTConfig           `yaml:"jwt" env-required:"true"`
-	S3Config            S3Config            `yaml:"s3" env-required:"true"`
-	ElasticsearchConfig ElasticsearchConfig `yaml:"elasticsearch" env-required:"true"`
+	Env                  string               `yaml:"env" env-Default:"development"`
+	DbConfig             DbConfig             `yaml:"db" env-required:"true"`
+	HttpServerConfig     HttpServerConfig     `yaml:"http_server"  env-required:"true"`
+	CacheConfig          CacheConfig          `yaml:"cache" env-required:"true"`
+	SMTPConfig           SMTPConfig           `yaml:"smtp" env-required:"true"`
+	JWTConfig            JWTConfig            `yaml:"jwt" env-required:"true"`
+	S3Config             S3Config             `yaml:"s3" env-required:"true"`
+	StorageConfig        StorageConfig        `yaml:"storage"`
+	MediaGCConfig        MediaGCConfig        `yaml:"media_gc"`
+	ElasticsearchConfig  ElasticsearchConfig  `yaml:"elasticsearch" env-required:"true"`
+	RecommendationConfig RecommendationConfig `yaml:"recommendation"`
+	TrashConfig          TrashConfig          `yaml:"trash"`
+}
+
+type RecommendationConfig struct {
+	Schedule           string `yaml:"schedule" env-default:"0 */6 * * *"`
+	MinCommonReviewers int    `yaml:"min_common_reviewers" env-default:"3"`
+	NeighboursPerFilm  int    `yaml:"neighbours_per_film" env-default:"50"`
 }
 
 type ElasticsearchConfig struct {
@@ -29,6 +39,15 @@ type CacheConfig struct {
 	Db                           int           `yaml:"db"`
 	StateExpiration              time.Duration `yaml:"state_expiration" env-required:"true"`
 	EmailConfirmedCodeExpiration time.Duration `yaml:"email_confirmed_code_expiration" env-required:"true"`
+	L1                           L1CacheConfig `yaml:"l1"`
+}
+
+// L1CacheConfig - кэш в памяти процесса перед Redis. Реплики сбрасывают записи друг у друга через pub/sub,
+// TTL ограничивает, сколько может прожить запись, если сообщение об изменении потерялось
+type L1CacheConfig struct {
+	Enabled   bool          `yaml:"enabled" env:"CACHE_L1_ENABLED" env-default:"false"`
+	MaxSizeMB int           `yaml:"max_size_mb" env-default:"64"`
+	TTL       time.Duration `yaml:"ttl" env-default:"30s"`
 }
 
 type HttpServerConfig struct {
@@ -74,6 +93,30 @@ type S3Config struct {
 	Buckets  []BucketConfig `yaml:"buckets"`
 }
 
+// StorageConfig - где хранятся файлы. Driver s3 - объектное хранилище из S3Config,
+// local - папка на диске, файлы отдает сам сервер (для разработки и интеграционных тестов)
+type StorageConfig struct {
+	Driver    string `yaml:"driver" env:"STORAGE_DRIVER" env-default:"s3"`
+	CDNPrefix string `yaml:"cdn_prefix" env:"STORAGE_CDN_PREFIX"`
+	LocalRoot string `yaml:"local_root" env-default:"./storage"`
+	PublicURL string `yaml:"public_url" env-default:"http://localhost:8080/storage"`
+}
+
+// MediaGCConfig - сверка файлов хранилища с БД. Файлы моложе GracePeriod не трогаются: их запись в БД
+// могла еще не завершиться. В режиме DryRun найденные файлы только выводятся в лог
+type MediaGCConfig struct {
+	Schedule    string        `yaml:"schedule" env-default:"0 4 * * *"`
+	GracePeriod time.Duration `yaml:"grace_period" env-default:"72h"`
+	DryRun      bool          `yaml:"dry_run" env:"MEDIA_GC_DRY_RUN" env-default:"true"`
+}
+
+// TrashConfig - корзина удаленных фильмов, персон, жанров и пользователей. Через Retention после удаления
+// строка удаляется окончательно вместе с файлами и документом поиска
+type TrashConfig struct {
+	Schedule  string        `yaml:"schedule" env-default:"0 3 * * *"`
+	Retention time.Duration `yaml:"retention" env-default:"720h"`
+}
+
 func MustLoad() *Config {
 	ConfigPath := os.Getenv("CONFIG_PATH")
 	if ConfigPath == "" {
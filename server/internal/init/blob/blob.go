@@ -0,0 +1,121 @@
+package blob
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ImmutableCacheControl - для объектов с хэшем содержимого в ключе: по такому ключу файл никогда не меняется
+const ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+var (
+	ErrNotFound = errors.New("object not found")
+	ErrTooLarge = errors.New("object too large")
+)
+
+// Store - хранилище файлов по бакетам и ключам. Реализации: S3 (init/s3) и локальный диск (LocalStore)
+type Store interface {
+	Put(bucket, key string, data []byte, opts PutOptions) error
+	// Get читает объект целиком, объект больше maxSize байт не читается (ErrTooLarge)
+	Get(bucket, key string, maxSize int64) ([]byte, error)
+	Delete(bucket, key string) error
+	// List возвращает все объекты бакета, ключи которых начинаются с prefix
+	List(bucket, prefix string) ([]Object, error)
+	// DeletePrefix удаляет все объекты бакета, ключи которых начинаются с prefix
+	DeletePrefix(bucket, prefix string) error
+	// DeleteOlderThan удаляет объекты с ключами на prefix, загруженные раньше before, и возвращает их количество
+	DeleteOlderThan(bucket, prefix string, before time.Time) (int, error)
+	Copy(bucket, srcKey, dstKey string) error
+	// URL - публичный адрес объекта или папки (ключ с "/" на конце)
+	URL(bucket, key string) string
+	// PresignPut подписывает загрузку объекта клиентом напрямую в хранилище. Тип и размер входят в подпись
+	PresignPut(bucket, key, contentType string, size int64, ttl time.Duration) (*PresignedRequest, error)
+}
+
+// PutOptions - заголовки, с которыми хранилище отдает объект
+type PutOptions struct {
+	ContentType  string
+	CacheControl string
+}
+
+// Object - объект хранилища в списке List
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// PresignedRequest - подписанный запрос, который клиент отправляет в хранилище напрямую
+type PresignedRequest struct {
+	URL     string
+	Method  string
+	Headers map[string]string // заголовки, которые клиент должен отправить вместе с запросом
+}
+
+// URLBuilder строит публичные адреса объектов. Если задан CDN, адрес строится как {cdn}/{bucket}/{key},
+// иначе адрес дает само хранилище
+type URLBuilder struct {
+	cdnPrefix string
+	origin    func(bucket, key string) string
+}
+
+func NewURLBuilder(cdnPrefix string, origin func(bucket, key string) string) URLBuilder {
+	return URLBuilder{
+		cdnPrefix: strings.TrimSuffix(cdnPrefix, "/"),
+		origin:    origin,
+	}
+}
+
+func (b URLBuilder) URL(bucket, key string) string {
+	key = strings.TrimPrefix(key, "/")
+	if b.cdnPrefix != "" {
+		return b.cdnPrefix + "/" + bucket + "/" + key
+	}
+	return b.origin(bucket, key)
+}
+
+// KeyFromURL находит ключ объекта бакета по сохраненному в БД адресу. Адрес мог быть построен до смены
+// CDN или хранилища, поэтому бакет ищется и в имени хоста (https://{bucket}.{endpoint}/{key}),
+// и в пути ({prefix}/{bucket}/{key})
+func KeyFromURL(rawURL, bucket string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+
+	if strings.HasPrefix(u.Host, bucket+".") {
+		return strings.TrimPrefix(u.Path, "/"), true
+	}
+	if i := strings.Index(u.Path, "/"+bucket+"/"); i >= 0 {
+		return u.Path[i+len(bucket)+2:], true
+	}
+
+	return "", false
+}
+
+// PutImages параллельно загружает WebP-изображения, objects - ключ объекта и его содержимое.
+// Ключи должны содержать хэш содержимого: изображения кэшируются навсегда
+func PutImages(store Store, bucket string, objects map[string][]byte) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var putErr error
+
+	for key, data := range objects {
+		wg.Add(1)
+		go func(key string, data []byte) {
+			defer wg.Done()
+			if err := store.Put(bucket, key, data, PutOptions{ContentType: "image/webp", CacheControl: ImmutableCacheControl}); err != nil {
+				mu.Lock()
+				putErr = fmt.Errorf("failed to put %s: %w", key, err)
+				mu.Unlock()
+			}
+		}(key, data)
+	}
+	wg.Wait()
+
+	return putErr
+}
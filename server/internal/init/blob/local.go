@@ -0,0 +1,450 @@
+package blob
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"server/config"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStore хранит файлы в папке на диске: {root}/{bucket}/{key}. Файлы и подписанные загрузки
+// обслуживает сам сервер через Handler, смонтированный по адресу publicURL
+type LocalStore struct {
+	root   string
+	public string
+	secret []byte
+	urls   URLBuilder
+}
+
+var _ Store = (*LocalStore)(nil)
+
+// NewLocalStore создает папки бакетов и кладет в них файлы по умолчанию, как это делает S3 при создании бакета
+func NewLocalStore(cfg config.StorageConfig, buckets []config.BucketConfig) (*LocalStore, error) {
+	root, err := filepath.Abs(cfg.LocalRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// ключ подписи живет до перезапуска, за это время загрузки успевают завершиться
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	s := &LocalStore{
+		root:   root,
+		public: strings.TrimSuffix(cfg.PublicURL, "/"),
+		secret: secret,
+	}
+	s.urls = NewURLBuilder(cfg.CDNPrefix, func(bucket, key string) string {
+		return s.public + "/" + bucket + "/" + key
+	})
+
+	for _, bucket := range buckets {
+		if err := s.seedBucket(bucket); err != nil {
+			return nil, fmt.Errorf("failed to initialize bucket %s: %v", bucket.Name, err)
+		}
+	}
+
+	return s, nil
+}
+
+func (s *LocalStore) seedBucket(bucket config.BucketConfig) error {
+	if err := os.MkdirAll(filepath.Join(s.root, bucket.Name), 0o755); err != nil {
+		return err
+	}
+
+	for i, src := range bucket.DefaultFile.Path {
+		if i >= len(bucket.DefaultFile.Keys) {
+			break
+		}
+		key := bucket.DefaultFile.Keys[i]
+
+		dst, err := s.path(bucket.Name, key)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return fmt.Errorf("failed to read default file %s: %v", src, err)
+		}
+		if err := s.Put(bucket.Name, key, data, PutOptions{ContentType: "image/webp"}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validBucket(bucket string) bool {
+	return bucket != "" && bucket != "." && bucket != ".." && !strings.ContainsAny(bucket, `/\`)
+}
+
+// path переводит бакет и ключ в путь на диске и не дает выйти за пределы бакета
+func (s *LocalStore) path(bucket, key string) (string, error) {
+	if !validBucket(bucket) {
+		return "", ErrNotFound
+	}
+
+	key = path.Clean("/" + key)
+	if key == "/" {
+		return "", ErrNotFound
+	}
+
+	return filepath.Join(s.root, bucket, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStore) URL(bucket, key string) string {
+	return s.urls.URL(bucket, key)
+}
+
+func (s *LocalStore) Put(bucket, key string, data []byte, opts PutOptions) error {
+	if err := s.write(bucket, key, bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	p, _ := s.path(bucket, key)
+	return writeMeta(p, opts)
+}
+
+// metaPath - скрытый файл рядом с объектом, в нем заголовки из PutOptions
+func metaPath(p string) string {
+	return filepath.Join(filepath.Dir(p), "."+filepath.Base(p)+".meta")
+}
+
+func writeMeta(p string, opts PutOptions) error {
+	if opts == (PutOptions{}) {
+		if err := os.Remove(metaPath(p)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(metaPath(p), data, 0o644)
+}
+
+func readMeta(p string) PutOptions {
+	var opts PutOptions
+	if data, err := os.ReadFile(metaPath(p)); err == nil {
+		_ = json.Unmarshal(data, &opts)
+	}
+	return opts
+}
+
+// remove удаляет объект вместе с его заголовками
+func remove(p string) error {
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.Remove(metaPath(p)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// write пишет файл во временный и переименовывает, чтобы читатели не видели недописанный файл
+func (s *LocalStore) write(bucket, key string, r io.Reader) error {
+	dst, err := s.path(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (s *LocalStore) Get(bucket, key string, maxSize int64) ([]byte, error) {
+	p, err := s.path(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, ErrNotFound
+	}
+	if info.Size() > maxSize {
+		return nil, ErrTooLarge
+	}
+
+	return io.ReadAll(file)
+}
+
+func (s *LocalStore) Delete(bucket, key string) error {
+	p, err := s.path(bucket, key)
+	if err != nil {
+		return nil
+	}
+
+	return remove(p)
+}
+
+func (s *LocalStore) List(bucket, prefix string) ([]Object, error) {
+	var objects []Object
+	err := s.walk(bucket, prefix, func(key string, _ string, info fs.FileInfo) error {
+		objects = append(objects, Object{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	return objects, err
+}
+
+func (s *LocalStore) DeletePrefix(bucket, prefix string) error {
+	_, err := s.deleteMatching(bucket, prefix, func(fs.FileInfo) bool { return true })
+	return err
+}
+
+func (s *LocalStore) DeleteOlderThan(bucket, prefix string, before time.Time) (int, error) {
+	return s.deleteMatching(bucket, prefix, func(info fs.FileInfo) bool {
+		return info.ModTime().Before(before)
+	})
+}
+
+// deleteMatching удаляет файлы бакета с ключами на prefix, подходящие под match
+func (s *LocalStore) deleteMatching(bucket, prefix string, match func(fs.FileInfo) bool) (int, error) {
+	deleted := 0
+	err := s.walk(bucket, prefix, func(_ string, p string, info fs.FileInfo) error {
+		if !match(info) {
+			return nil
+		}
+		if err := remove(p); err != nil {
+			return err
+		}
+		deleted++
+		return nil
+	})
+
+	return deleted, err
+}
+
+// walk обходит файлы бакета с ключами на prefix, fn получает ключ, путь на диске и сведения о файле
+func (s *LocalStore) walk(bucket, prefix string, fn func(key string, p string, info fs.FileInfo) error) error {
+	if !validBucket(bucket) {
+		return nil
+	}
+	bucketDir := filepath.Join(s.root, bucket)
+	prefix = strings.TrimPrefix(prefix, "/")
+
+	// обход начинается с папки префикса, а не со всего бакета
+	start := bucketDir
+	if dir := path.Dir(path.Clean("/" + prefix)); dir != "/" {
+		start = filepath.Join(bucketDir, filepath.FromSlash(dir))
+	}
+
+	return filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		// скрытые файлы - недописанные файлы write и заголовки объектов
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(bucketDir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(key, p, info)
+	})
+}
+
+func (s *LocalStore) Copy(bucket, srcKey, dstKey string) error {
+	src, err := s.path(bucket, srcKey)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(src)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrNotFound
+		}
+		return err
+	}
+	defer file.Close()
+
+	if err := s.write(bucket, dstKey, file); err != nil {
+		return err
+	}
+
+	dst, _ := s.path(bucket, dstKey)
+	return writeMeta(dst, readMeta(src))
+}
+
+// PresignPut подписывает загрузку на Handler. В подпись входят бакет, ключ, тип, размер и срок действия
+func (s *LocalStore) PresignPut(bucket, key, contentType string, size int64, ttl time.Duration) (*PresignedRequest, error) {
+	if _, err := s.path(bucket, key); err != nil {
+		return nil, err
+	}
+
+	expires := time.Now().Add(ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("size", strconv.FormatInt(size, 10))
+	query.Set("signature", s.sign(bucket, key, contentType, size, expires))
+
+	return &PresignedRequest{
+		URL:     s.public + "/" + bucket + "/" + strings.TrimPrefix(key, "/") + "?" + query.Encode(),
+		Method:  http.MethodPut,
+		Headers: map[string]string{"Content-Type": contentType},
+	}, nil
+}
+
+func (s *LocalStore) sign(bucket, key, contentType string, size int64, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d\n%d", bucket, strings.TrimPrefix(key, "/"), contentType, size, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// MountPath - путь из publicURL, по которому нужно смонтировать Handler
+func (s *LocalStore) MountPath() string {
+	u, err := url.Parse(s.public)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+// Handler отдает файлы по GET {bucket}/{key} и принимает подписанные загрузки по PUT
+func (s *LocalStore) Handler() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/{bucket}/*", s.serveFile)
+	r.Head("/{bucket}/*", s.serveFile)
+	r.Put("/{bucket}/*", s.receiveFile)
+	return r
+}
+
+func (s *LocalStore) serveFile(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "*")
+	p, err := s.path(chi.URLParam(r, "bucket"), key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	file, err := os.Open(p)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	meta := readMeta(p)
+	if meta.ContentType == "" {
+		meta.ContentType = mime.TypeByExtension(path.Ext(key))
+	}
+	if meta.ContentType != "" {
+		w.Header().Set("Content-Type", meta.ContentType)
+	}
+	if meta.CacheControl != "" {
+		w.Header().Set("Cache-Control", meta.CacheControl)
+	}
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
+
+func (s *LocalStore) receiveFile(w http.ResponseWriter, r *http.Request) {
+	bucket, key := chi.URLParam(r, "bucket"), chi.URLParam(r, "*")
+	contentType := r.Header.Get("Content-Type")
+
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	size, err := strconv.ParseInt(r.URL.Query().Get("size"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	signature := s.sign(bucket, key, contentType, size, expires)
+	if !hmac.Equal([]byte(signature), []byte(r.URL.Query().Get("signature"))) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	if time.Now().Unix() > expires {
+		http.Error(w, "request has expired", http.StatusForbidden)
+		return
+	}
+	if r.ContentLength != size {
+		http.Error(w, "content length does not match signature", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.write(bucket, key, io.LimitReader(r.Body, size)); err != nil {
+		http.Error(w, "failed to store file", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"log/slog"
 	"os"
 	"server/config"
 	"time"
@@ -13,9 +15,16 @@ type Cache struct {
 	Client                       *redis.Client
 	StateExpiration              time.Duration
 	EmailConfirmedCodeExpiration time.Duration
+
+	log *slog.Logger
+	// l1 - кэш в памяти процесса перед Redis, nil если выключен
+	l1 *lru
+	// instance отличает свои сообщения об инвалидации от сообщений других реплик
+	instance string
+	metrics  metrics
 }
 
-func NewCache(cfg config.CacheConfig) (*Cache, error) {
+func NewCache(cfg config.CacheConfig, log *slog.Logger) (*Cache, error) {
 
 	client := redis.NewClient(&redis.Options{
 		Addr:     cfg.Address,
@@ -27,5 +36,104 @@ func NewCache(cfg config.CacheConfig) (*Cache, error) {
 		return nil, errors.New(os.Getenv("REDIS_PASSWORD"))
 	}
 
-	return &Cache{client, cfg.StateExpiration, cfg.EmailConfirmedCodeExpiration}, nil
+	c := &Cache{
+		Client:                       client,
+		StateExpiration:              cfg.StateExpiration,
+		EmailConfirmedCodeExpiration: cfg.EmailConfirmedCodeExpiration,
+		log:                          log.With("op", "cache"),
+		instance:                     uuid.NewString(),
+	}
+
+	if cfg.L1.Enabled {
+		c.l1 = newLRU(int64(cfg.L1.MaxSizeMB)<<20, cfg.L1.TTL)
+		go c.listenInvalidations()
+	}
+
+	return c, nil
+}
+
+// get читает запись сначала из L1, затем из Redis. Прочитанное из Redis попадает в L1
+func (c *Cache) get(key string) ([]byte, error) {
+	if c.l1 != nil {
+		if data, ok := c.l1.get(key); ok {
+			c.metrics.l1.hit()
+			return data, nil
+		}
+		c.metrics.l1.miss()
+	}
+
+	data, err := c.Client.Get(context.Background(), key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		c.metrics.redis.miss()
+		return nil, ErrMiss
+	} else if err != nil {
+		return nil, err
+	}
+	c.metrics.redis.hit()
+
+	if c.l1 != nil {
+		c.l1.set(key, data, 0)
+	}
+	return data, nil
+}
+
+// set сохраняет запись в Redis и L1 и сбрасывает ее в L1 других реплик
+func (c *Cache) set(key string, data []byte, ttl time.Duration, tags []string) error {
+	if err := c.Client.Set(context.Background(), key, data, ttl).Err(); err != nil {
+		return err
+	}
+
+	if len(tags) > 0 {
+		err := tagScript.Run(context.Background(), c.Client, tags, key, int64(ttl/time.Second)).Err()
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.l1 != nil {
+		c.l1.set(key, data, ttl)
+	}
+	c.publishInvalidation(key)
+	return nil
+}
+
+// Delete удаляет записи по ключам
+func (c *Cache) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := c.Client.Del(context.Background(), keys...).Err(); err != nil {
+		return err
+	}
+
+	c.evict(keys...)
+	return nil
+}
+
+// InvalidateTags удаляет все записи, помеченные любым из тегов
+func (c *Cache) InvalidateTags(tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	keys, err := invalidateScript.Run(context.Background(), c.Client, tags).StringSlice()
+	if err != nil {
+		return err
+	}
+
+	c.evict(keys...)
+	return nil
+}
+
+// evict сбрасывает записи в L1 этой и остальных реплик
+func (c *Cache) evict(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	if c.l1 != nil {
+		c.l1.remove(keys...)
+	}
+	c.publishInvalidation(keys...)
 }
@@ -0,0 +1,326 @@
+package cache
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"server/config"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis - Redis в памяти для тестов кэша: строки, множества тегов, pub/sub и два скрипта кэша,
+// которые выполняются кодом на Go вместо Lua. Сроки жизни не учитываются
+type fakeRedis struct {
+	ln net.Listener
+
+	mu          sync.Mutex
+	strings     map[string]string
+	sets        map[string]map[string]bool
+	subscribers map[string][]*fakeConn
+	conns       []*fakeConn
+}
+
+type fakeConn struct {
+	net.Conn
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	s := &fakeRedis{
+		ln:          ln,
+		strings:     make(map[string]string),
+		sets:        make(map[string]map[string]bool),
+		subscribers: make(map[string][]*fakeConn),
+	}
+	go s.serve()
+
+	t.Cleanup(func() {
+		_ = ln.Close()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, c := range s.conns {
+			_ = c.Close()
+		}
+	})
+	return s
+}
+
+// newTestCache подключает к серверу кэш, как у одной реплики сервиса
+func newTestCache(t *testing.T, s *fakeRedis, l1 bool) *Cache {
+	t.Helper()
+	t.Setenv("REDIS_PASSWORD", "")
+
+	ch, err := NewCache(config.CacheConfig{
+		Address: s.ln.Addr().String(),
+		L1:      config.L1CacheConfig{Enabled: l1, MaxSizeMB: 1, TTL: time.Minute},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	t.Cleanup(func() { _ = ch.Client.Close() })
+	return ch
+}
+
+// waitListening ждет, пока реплики начнут получать сообщения об инвалидации. Подтверждение подписки
+// очищает L1, поэтому ждать нужно его обработки, а не только регистрации подписки на сервере
+func waitListening(t *testing.T, replicas ...*Cache) {
+	t.Helper()
+	for _, ch := range replicas {
+		ch.l1.set("probe", []byte("1"), 0)
+		eventually(t, func() bool {
+			// свои сообщения реплика пропускает, поэтому проба идет от имени другой
+			probe := &Cache{Client: ch.Client, instance: "probe", log: ch.log}
+			probe.publishInvalidation("probe")
+			_, ok := ch.l1.get("probe")
+			return !ok
+		})
+	}
+}
+
+// put меняет значение в обход кэша, как если бы его записал кто-то без публикации
+func (s *fakeRedis) put(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.strings[key] = value
+}
+
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		nc, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		c := &fakeConn{Conn: nc, w: bufio.NewWriter(nc)}
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.mu.Unlock()
+		go s.handle(c)
+	}
+}
+
+func (s *fakeRedis) handle(c *fakeConn) {
+	r := bufio.NewReader(c)
+	subscribed := false
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(args[0])
+		if cmd == "SUBSCRIBE" {
+			subscribed = true
+		}
+		if subscribed && cmd == "PING" {
+			c.reply([]string{"pong", ""})
+			continue
+		}
+		s.exec(c, cmd, args[1:])
+	}
+}
+
+func (s *fakeRedis) exec(c *fakeConn, cmd string, args []string) {
+	switch cmd {
+	case "PING":
+		c.reply(status("PONG"))
+	case "GET":
+		s.mu.Lock()
+		value, ok := s.strings[args[0]]
+		s.mu.Unlock()
+		if !ok {
+			c.reply(nil)
+			return
+		}
+		c.reply(value)
+	case "SET":
+		s.put(args[0], args[1])
+		c.reply(status("OK"))
+	case "DEL":
+		c.reply(int64(len(s.del(args...))))
+	case "PUBLISH":
+		c.reply(int64(s.publish(args[0], args[1])))
+	case "SUBSCRIBE":
+		for _, channel := range args {
+			s.mu.Lock()
+			s.subscribers[channel] = append(s.subscribers[channel], c)
+			s.mu.Unlock()
+			c.reply([]interface{}{"subscribe", channel, int64(1)})
+		}
+	case "EVAL":
+		sum := sha1.Sum([]byte(args[0]))
+		s.eval(c, hex.EncodeToString(sum[:]), args[1:])
+	case "EVALSHA":
+		s.eval(c, args[0], args[1:])
+	default:
+		c.reply(fmt.Errorf("ERR unknown command '%s'", cmd))
+	}
+}
+
+// eval выполняет tagScript и invalidateScript
+func (s *fakeRedis) eval(c *fakeConn, sha string, args []string) {
+	n, _ := strconv.Atoi(args[0])
+	keys, argv := args[1:1+n], args[1+n:]
+
+	switch sha {
+	case tagScript.Hash():
+		s.mu.Lock()
+		for _, tag := range keys {
+			if s.sets[tag] == nil {
+				s.sets[tag] = make(map[string]bool)
+			}
+			s.sets[tag][argv[0]] = true
+		}
+		s.mu.Unlock()
+		c.reply(int64(0))
+	case invalidateScript.Hash():
+		deleted := make([]interface{}, 0)
+		for _, tag := range keys {
+			s.mu.Lock()
+			members := make([]string, 0, len(s.sets[tag]))
+			for key := range s.sets[tag] {
+				members = append(members, key)
+			}
+			s.mu.Unlock()
+
+			s.del(members...)
+			s.del(tag)
+			for _, key := range members {
+				deleted = append(deleted, key)
+			}
+		}
+		c.reply(deleted)
+	default:
+		c.reply(fmt.Errorf("NOSCRIPT No matching script"))
+	}
+}
+
+func (s *fakeRedis) del(keys ...string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted []string
+	for _, key := range keys {
+		_, isString := s.strings[key]
+		_, isSet := s.sets[key]
+		if isString || isSet {
+			deleted = append(deleted, key)
+		}
+		delete(s.strings, key)
+		delete(s.sets, key)
+	}
+	return deleted
+}
+
+func (s *fakeRedis) publish(channel, payload string) int {
+	s.mu.Lock()
+	subscribers := append([]*fakeConn(nil), s.subscribers[channel]...)
+	s.mu.Unlock()
+
+	for _, c := range subscribers {
+		c.reply([]interface{}{"message", channel, payload})
+	}
+	return len(subscribers)
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimPrefix(header, "$"))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+type status string
+
+func (c *fakeConn) reply(v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeValue(c.w, v)
+	_ = c.w.Flush()
+}
+
+func writeValue(w *bufio.Writer, v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		_, _ = w.WriteString("$-1\r\n")
+	case status:
+		_, _ = fmt.Fprintf(w, "+%s\r\n", v)
+	case error:
+		_, _ = fmt.Fprintf(w, "-%s\r\n", v)
+	case int64:
+		_, _ = fmt.Fprintf(w, ":%d\r\n", v)
+	case string:
+		_, _ = fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []string:
+		_, _ = fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			writeValue(w, item)
+		}
+	case []interface{}:
+		_, _ = fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			writeValue(w, item)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-redis/redis/v8"
+)
+
+// invalidationChannel - канал Redis, через который реплики сбрасывают друг у друга записи L1
+const invalidationChannel = "cache:invalidate"
+
+type invalidation struct {
+	Instance string   `json:"instance"`
+	Keys     []string `json:"keys"`
+}
+
+// publishInvalidation сообщает остальным репликам, что записи изменились. Сообщение отправляется
+// и без своего L1: он может быть включен на других репликах. Ошибка только пишется в лог,
+// устаревшая запись L1 проживет не дольше его TTL
+func (c *Cache) publishInvalidation(keys ...string) {
+	data, err := json.Marshal(invalidation{Instance: c.instance, Keys: keys})
+	if err != nil {
+		c.log.Error("failed to encode cache invalidation", "error", err)
+		return
+	}
+
+	if err := c.Client.Publish(context.Background(), invalidationChannel, data).Err(); err != nil {
+		c.log.Error("failed to publish cache invalidation", "error", err)
+	}
+}
+
+// listenInvalidations сбрасывает записи L1 по сообщениям других реплик. Пока подписка была
+// разорвана, сообщения могли потеряться, поэтому после каждой (пере)подписки L1 очищается целиком
+func (c *Cache) listenInvalidations() {
+	sub := c.Client.Subscribe(context.Background(), invalidationChannel)
+
+	for msg := range sub.ChannelWithSubscriptions(context.Background(), 100) {
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind == "subscribe" {
+				c.l1.clear()
+			}
+		case *redis.Message:
+			var inv invalidation
+			if err := json.Unmarshal([]byte(m.Payload), &inv); err != nil {
+				c.log.Error("failed to decode cache invalidation", "error", err)
+				continue
+			}
+			if inv.Instance == c.instance {
+				continue
+			}
+			c.l1.remove(inv.Keys...)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lru - кэш в памяти процесса (L1) с ограничением размера в байтах и сроком жизни записей.
+// Хранит JSON, а не готовые значения: вызывающий код меняет полученные DTO (например, переводит их),
+// и общий экземпляр испортился бы для следующих запросов
+type lru struct {
+	mu        sync.Mutex
+	maxBytes  int64
+	ttl       time.Duration
+	size      int64
+	evictions int64
+	items     map[string]*list.Element
+	order     *list.List
+}
+
+type lruEntry struct {
+	key     string
+	data    []byte
+	expires time.Time
+}
+
+func newLRU(maxBytes int64, ttl time.Duration) *lru {
+	return &lru{
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (l *lru) get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		l.removeElement(el)
+		return nil, false
+	}
+
+	l.order.MoveToFront(el)
+	return entry.data, true
+}
+
+// set сохраняет запись на ttl, но не дольше срока жизни L1. Запись больше всего кэша не сохраняется
+func (l *lru) set(key string, data []byte, ttl time.Duration) {
+	if ttl <= 0 || ttl > l.ttl {
+		ttl = l.ttl
+	}
+	if int64(len(data)) > l.maxBytes {
+		l.remove(key)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if el, ok := l.items[key]; ok {
+		l.removeElement(el)
+	}
+
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, data: data, expires: time.Now().Add(ttl)})
+	l.size += int64(len(data))
+
+	for l.size > l.maxBytes {
+		l.removeElement(l.order.Back())
+		l.evictions++
+	}
+}
+
+func (l *lru) remove(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := l.items[key]; ok {
+			l.removeElement(el)
+		}
+	}
+}
+
+func (l *lru) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.items = make(map[string]*list.Element)
+	l.order.Init()
+	l.size = 0
+}
+
+func (l *lru) removeElement(el *list.Element) {
+	entry := l.order.Remove(el).(*lruEntry)
+	delete(l.items, entry.key)
+	l.size -= int64(len(entry.data))
+}
+
+func (l *lru) stats() (entries int, size int64, evictions int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len(), l.size, l.evictions
+}
@@ -0,0 +1,63 @@
+package cache
+
+import "sync/atomic"
+
+type metrics struct {
+	l1    tierMetrics
+	redis tierMetrics
+}
+
+type tierMetrics struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func (m *tierMetrics) hit() {
+	m.hits.Add(1)
+}
+
+func (m *tierMetrics) miss() {
+	m.misses.Add(1)
+}
+
+func (m *tierMetrics) snapshot() TierStats {
+	s := TierStats{Hits: m.hits.Load(), Misses: m.misses.Load()}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRatio = float64(s.Hits) / float64(total)
+	}
+	return s
+}
+
+// Stats - попадания и промахи по уровням кэша с запуска процесса. Промах L1 - это обращение к Redis
+type Stats struct {
+	L1    *L1Stats  `json:"l1,omitempty"`
+	Redis TierStats `json:"redis"`
+}
+
+type TierStats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+type L1Stats struct {
+	TierStats
+	Entries   int   `json:"entries"`
+	Bytes     int64 `json:"bytes"`
+	Evictions int64 `json:"evictions"`
+}
+
+// Stats учитывает только записи через Typed, служебные ключи модулей auth и email читаются из Redis напрямую
+func (c *Cache) Stats() Stats {
+	stats := Stats{Redis: c.metrics.redis.snapshot()}
+	if c.l1 != nil {
+		entries, size, evictions := c.l1.stats()
+		stats.L1 = &L1Stats{
+			TierStats: c.metrics.l1.snapshot(),
+			Entries:   entries,
+			Bytes:     size,
+			Evictions: evictions,
+		}
+	}
+	return stats
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/sync/singleflight"
+	"strings"
+	"time"
+)
+
+var ErrMiss = errors.New("cache miss")
+
+// Key собирает ключ кэша из частей через ":", например Key("film", 5, "similar") = "film:5:similar"
+func Key(parts ...any) string {
+	s := make([]string, len(parts))
+	for i, part := range parts {
+		s[i] = fmt.Sprint(part)
+	}
+	return strings.Join(s, ":")
+}
+
+// Tag - тег для инвалидации, например Tag("film", 5). Тегом помечаются все записи, в которые входит сущность,
+// InvalidateTags(Tag("film", 5)) удаляет их разом
+func Tag(parts ...any) string {
+	return "tag:" + Key(parts...)
+}
+
+// tagScript добавляет ключ в множества тегов. Множество живет не меньше самой долгой записи в нем,
+// для записи без срока жизни (ttl 0) срок множества не меняется
+var tagScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+for _, tag in ipairs(KEYS) do
+	redis.call('SADD', tag, ARGV[1])
+	if ttl > 0 and redis.call('TTL', tag) < ttl then
+		redis.call('EXPIRE', tag, ttl)
+	end
+end
+return 0`)
+
+// invalidateScript удаляет записи, помеченные тегами, и сами множества тегов. Возвращает удаленные ключи,
+// чтобы сбросить их и в L1
+var invalidateScript = redis.NewScript(`
+local deleted = {}
+for _, tag in ipairs(KEYS) do
+	local keys = redis.call('SMEMBERS', tag)
+	for i = 1, #keys, 500 do
+		redis.call('DEL', unpack(keys, i, math.min(i + 499, #keys)))
+	end
+	for _, key in ipairs(keys) do
+		table.insert(deleted, key)
+	end
+	redis.call('DEL', tag)
+end
+return deleted`)
+
+// Typed - кэш значений одного типа в JSON, с L1 в памяти процесса, если он включен. Одновременные промахи по одному ключу в GetOrLoad
+// загружают значение один раз (singleflight), остальные запросы ждут результат. Каждый вызывающий получает свою копию значения:
+// вызывающий код меняет полученные DTO (например, переводит их)
+type Typed[T any] struct {
+	ch    *Cache
+	group singleflight.Group
+}
+
+func NewTyped[T any](ch *Cache) *Typed[T] {
+	return &Typed[T]{ch: ch}
+}
+
+// Get возвращает значение по ключу или ErrMiss
+func (t *Typed[T]) Get(key string) (T, error) {
+	var value T
+
+	data, err := t.ch.get(key)
+	if err != nil {
+		return value, err
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, err
+	}
+	return value, nil
+}
+
+// Set сохраняет значение и помечает его тегами
+func (t *Typed[T]) Set(key string, value T, ttl time.Duration, tags ...string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return t.ch.set(key, data, ttl, tags)
+}
+
+func (t *Typed[T]) Delete(keys ...string) error {
+	return t.ch.Delete(keys...)
+}
+
+// GetOrLoad возвращает значение из кэша, при промахе загружает его через load и сохраняет с тегами,
+// которые вернул load. Ошибка кэша не мешает загрузке: значение просто не сохранится
+func (t *Typed[T]) GetOrLoad(key string, ttl time.Duration, load func() (T, []string, error)) (T, error) {
+	if value, err := t.Get(key); err == nil {
+		return value, nil
+	}
+
+	var value T
+
+	// загрузка отдает JSON, а не значение: ожидающие запросы не должны делить один экземпляр
+	v, err, _ := t.group.Do(key, func() (interface{}, error) {
+		// значение могла сохранить только что завершившаяся загрузка
+		if data, err := t.ch.get(key); err == nil {
+			return data, nil
+		}
+
+		loaded, tags, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(loaded)
+		if err != nil {
+			return nil, err
+		}
+
+		_ = t.ch.set(key, data, ttl, tags)
+		return data, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	if err := json.Unmarshal(v.([]byte), &value); err != nil {
+		return value, err
+	}
+	return value, nil
+}
@@ -0,0 +1,230 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tiers - варианты кэша: только Redis и Redis с L1
+var tiers = []struct {
+	name string
+	l1   bool
+}{
+	{name: "redis"},
+	{name: "l1", l1: true},
+}
+
+type testFilm struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+func TestKeyAndTag(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{got: Key("film", 5, "similar"), want: "film:5:similar"},
+		{got: Key("genres"), want: "genres"},
+		{got: Tag("film", 5), want: "tag:film:5"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+// Одновременные промахи загружают значение один раз, но каждый вызывающий получает свою копию
+// и может менять ее (запускать с -race)
+func TestGetOrLoadConcurrent(t *testing.T) {
+	for _, tier := range tiers {
+		t.Run(tier.name, func(t *testing.T) {
+			typed := NewTyped[*testFilm](newTestCache(t, newFakeRedis(t), tier.l1))
+
+			var loads atomic.Int32
+			load := func() (*testFilm, []string, error) {
+				loads.Add(1)
+				time.Sleep(50 * time.Millisecond)
+				return &testFilm{ID: 5, Title: "Alien"}, []string{Tag("film", 5)}, nil
+			}
+
+			const callers = 20
+			var (
+				wg    sync.WaitGroup
+				mu    sync.Mutex
+				seen  = make(map[*testFilm]bool)
+				start = make(chan struct{})
+			)
+			for i := 0; i < callers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+
+					film, err := typed.GetOrLoad(Key("film", 5), time.Minute, load)
+					if err != nil {
+						t.Errorf("GetOrLoad() error = %v", err)
+						return
+					}
+					if film.Title != "Alien" {
+						t.Errorf("GetOrLoad() title = %q, want Alien", film.Title)
+					}
+					// как LocalizeFilms в контроллере
+					film.Title = "Чужой"
+
+					mu.Lock()
+					defer mu.Unlock()
+					if seen[film] {
+						t.Error("GetOrLoad() returned the same value to two callers")
+					}
+					seen[film] = true
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			if n := loads.Load(); n != 1 {
+				t.Errorf("load called %d times, want 1", n)
+			}
+		})
+	}
+}
+
+func TestGetOrLoadError(t *testing.T) {
+	typed := NewTyped[*testFilm](newTestCache(t, newFakeRedis(t), false))
+	errLoad := errors.New("db is down")
+
+	calls := 0
+	load := func() (*testFilm, []string, error) {
+		calls++
+		if calls == 1 {
+			return nil, nil, errLoad
+		}
+		return &testFilm{ID: 5}, nil, nil
+	}
+
+	if _, err := typed.GetOrLoad("film:5", time.Minute, load); !errors.Is(err, errLoad) {
+		t.Fatalf("GetOrLoad() error = %v, want %v", err, errLoad)
+	}
+	// ошибка не кэшируется
+	if film, err := typed.GetOrLoad("film:5", time.Minute, load); err != nil || film.ID != 5 {
+		t.Fatalf("GetOrLoad() = %+v, %v, want film 5", film, err)
+	}
+	if calls != 2 {
+		t.Errorf("load called %d times, want 2", calls)
+	}
+}
+
+func TestInvalidateTags(t *testing.T) {
+	entries := map[string][]string{
+		"film:5":         {Tag("film", 5)},
+		"film:5:similar": {Tag("film", 5), Tag("film", 6)},
+		"film:6":         {Tag("film", 6)},
+		"genres":         {Tag("genre", 1)},
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		want []string // ключи, которые остались
+	}{
+		{name: "one tag", tags: []string{Tag("film", 5)}, want: []string{"film:6", "genres"}},
+		{name: "shared entry", tags: []string{Tag("film", 6)}, want: []string{"film:5", "genres"}},
+		{name: "several tags", tags: []string{Tag("film", 5), Tag("genre", 1)}, want: []string{"film:6"}},
+		{name: "unknown tag", tags: []string{Tag("person", 1)}, want: []string{"film:5", "film:5:similar", "film:6", "genres"}},
+	}
+
+	for _, tt := range tests {
+		for _, tier := range tiers {
+			t.Run(tt.name+"/"+tier.name, func(t *testing.T) {
+				ch := newTestCache(t, newFakeRedis(t), tier.l1)
+				typed := NewTyped[string](ch)
+				for key, tags := range entries {
+					if err := typed.Set(key, key, time.Minute, tags...); err != nil {
+						t.Fatalf("Set(%q) error = %v", key, err)
+					}
+				}
+
+				if err := ch.InvalidateTags(tt.tags...); err != nil {
+					t.Fatalf("InvalidateTags() error = %v", err)
+				}
+
+				want := make(map[string]bool)
+				for _, key := range tt.want {
+					want[key] = true
+				}
+				for key := range entries {
+					_, err := typed.Get(key)
+					if want[key] && err != nil {
+						t.Errorf("Get(%q) error = %v, want value", key, err)
+					}
+					if !want[key] && !errors.Is(err, ErrMiss) {
+						t.Errorf("Get(%q) error = %v, want %v", key, err, ErrMiss)
+					}
+				}
+			})
+		}
+	}
+}
+
+// Изменение на одной реплике сбрасывает запись в L1 другой через pub/sub
+func TestL1Invalidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(typed *Typed[string]) error
+		want   string // "" - промах
+	}{
+		{
+			name:   "set",
+			change: func(typed *Typed[string]) error { return typed.Set("film:5", "new", time.Minute) },
+			want:   "new",
+		},
+		{
+			name:   "delete",
+			change: func(typed *Typed[string]) error { return typed.Delete("film:5") },
+		},
+		{
+			name:   "invalidate tags",
+			change: func(typed *Typed[string]) error { return typed.ch.InvalidateTags(Tag("film", 5)) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeRedis(t)
+			a := NewTyped[string](newTestCache(t, srv, true))
+			b := NewTyped[string](newTestCache(t, srv, true))
+			waitListening(t, a.ch, b.ch)
+
+			if err := a.Set("film:5", "old", time.Minute, Tag("film", 5)); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			if got, err := b.Get("film:5"); err != nil || got != "old" {
+				t.Fatalf("Get() = %q, %v, want old", got, err)
+			}
+
+			// запись в обход кэша не видна: реплика b читает из своего L1
+			srv.put("film:5", `"bypass"`)
+			if got, _ := b.Get("film:5"); got != "old" {
+				t.Fatalf("Get() = %q, want old from L1", got)
+			}
+
+			if err := tt.change(a); err != nil {
+				t.Fatalf("change error = %v", err)
+			}
+
+			eventually(t, func() bool {
+				got, err := b.Get("film:5")
+				if tt.want == "" {
+					return errors.Is(err, ErrMiss)
+				}
+				return err == nil && got == tt.want
+			})
+		})
+	}
+}
@@ -0,0 +1,15 @@
+ALTER TABLE genres
+    DROP COLUMN IF EXISTS cover_color,
+    DROP COLUMN IF EXISTS cover_blurhash;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS avatar_color,
+    DROP COLUMN IF EXISTS avatar_blurhash;
+
+ALTER TABLE persons
+    DROP COLUMN IF EXISTS avatar_color,
+    DROP COLUMN IF EXISTS avatar_blurhash;
+
+ALTER TABLE films
+    DROP COLUMN IF EXISTS poster_color,
+    DROP COLUMN IF EXISTS poster_blurhash;
@@ -0,0 +1,17 @@
+-- Изображения хранятся папкой с несколькими размерами (см. avatarManager.Profile), рядом с адресом
+-- лежат blurhash-заглушка и средний цвет для отображения до загрузки картинки
+ALTER TABLE films
+    ADD COLUMN poster_blurhash VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN poster_color VARCHAR(7) NOT NULL DEFAULT '';
+
+ALTER TABLE persons
+    ADD COLUMN avatar_blurhash VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN avatar_color VARCHAR(7) NOT NULL DEFAULT '';
+
+ALTER TABLE users
+    ADD COLUMN avatar_blurhash VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN avatar_color VARCHAR(7) NOT NULL DEFAULT '';
+
+ALTER TABLE genres
+    ADD COLUMN cover_blurhash VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN cover_color VARCHAR(7) NOT NULL DEFAULT '';
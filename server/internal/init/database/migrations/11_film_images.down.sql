@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_film_images_primary;
+DROP INDEX IF EXISTS idx_film_images_film_id;
+
+DROP TABLE IF EXISTS film_images CASCADE;
@@ -0,0 +1,20 @@
+-- Галерея фильма: фоны, кадры, логотипы и альтернативные постеры. Изображения лежат в бакете filmposter
+-- в папке images/{film_id}/{image_id}/, по файлу на каждый размер (см. avatarManager.Profile)
+CREATE TABLE film_images (
+    image_id SERIAL PRIMARY KEY,
+    film_id INT NOT NULL,
+    image_type VARCHAR(16) NOT NULL CHECK (image_type IN ('backdrop', 'still', 'logo', 'poster')),
+    url TEXT NOT NULL DEFAULT '',
+    width INT NOT NULL DEFAULT 0,
+    height INT NOT NULL DEFAULT 0,
+    blurhash VARCHAR(64) NOT NULL DEFAULT '',
+    color VARCHAR(7) NOT NULL DEFAULT '',
+    position INT NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT false,
+    create_at DATE DEFAULT current_date,
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_film_images_film_id ON film_images (film_id, image_type, position);
+-- основным может быть только одно изображение каждого типа, основной фон попадает в карточку фильма
+CREATE UNIQUE INDEX idx_film_images_primary ON film_images (film_id, image_type) WHERE is_primary;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_film_videos_film_id;
+
+DROP TABLE IF EXISTS film_videos CASCADE;
@@ -0,0 +1,18 @@
+-- Трейлеры и другие видео фильма. Хранится только ссылка на видео у провайдера: провайдер и id видео,
+-- адреса страницы и плеера строятся из них (см. pkg/lib/videolink)
+CREATE TABLE film_videos (
+    video_id SERIAL PRIMARY KEY,
+    film_id INT NOT NULL,
+    video_type VARCHAR(16) NOT NULL CHECK (video_type IN ('trailer', 'teaser', 'clip')),
+    provider VARCHAR(16) NOT NULL CHECK (provider IN ('youtube', 'vk', 'rutube')),
+    video_key VARCHAR(64) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    language VARCHAR(2) NOT NULL DEFAULT '',
+    official BOOLEAN NOT NULL DEFAULT false,
+    published_at DATE,
+    create_at DATE DEFAULT current_date,
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE,
+    CONSTRAINT uq_film_video UNIQUE (film_id, provider, video_key)
+);
+
+CREATE INDEX idx_film_videos_film_id ON film_videos (film_id, video_type);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_uploads_expires_at;
+
+DROP TABLE IF EXISTS uploads CASCADE;
@@ -0,0 +1,17 @@
+-- Загрузки изображений напрямую в S3: клиент получает подписанную ссылку на временный ключ staging/{upload_id}
+-- в бакете назначения, загружает файл и вызывает finalize. Незавершенные загрузки удаляет cron
+CREATE TABLE uploads (
+    upload_id UUID PRIMARY KEY,
+    purpose VARCHAR(32) NOT NULL CHECK (purpose IN ('film_poster', 'person_avatar', 'user_avatar')),
+    target_id INT NOT NULL,
+    user_id INT NOT NULL,
+    bucket VARCHAR(64) NOT NULL,
+    staging_key TEXT NOT NULL,
+    content_type VARCHAR(32) NOT NULL,
+    size BIGINT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    create_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_uploads_expires_at ON uploads (expires_at);
@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS track_genre_cover ON genres;
+DROP TRIGGER IF EXISTS track_user_avatar ON users;
+DROP TRIGGER IF EXISTS track_person_avatar ON persons;
+DROP TRIGGER IF EXISTS track_film_image ON film_images;
+DROP TRIGGER IF EXISTS track_film_poster ON films;
+DROP FUNCTION IF EXISTS track_media_object();
+
+DROP INDEX IF EXISTS idx_media_objects_retired_at;
+DROP INDEX IF EXISTS idx_media_objects_url;
+DROP INDEX IF EXISTS idx_media_objects_active;
+
+DROP TABLE IF EXISTS media_objects CASCADE;
@@ -0,0 +1,96 @@
+-- Объекты хранилища, на которые ссылаются сущности. Изображения лежат в папках с хэшем содержимого в ключе
+-- и не перезаписываются: новая версия изображения - новая папка. Ссылку переключает триггер в той же транзакции,
+-- что меняет адрес у сущности, прежняя папка помечается выведенной (retired_at) и удаляется cron по истечении
+-- grace period, когда ее уже не отдают кэши CDN и браузеров
+CREATE TABLE media_objects (
+    object_id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(32) NOT NULL CHECK (entity_type IN ('film_poster', 'film_image', 'person_avatar', 'user_avatar', 'genre_cover')),
+    entity_id INT NOT NULL,
+    url TEXT NOT NULL,
+    create_at TIMESTAMP NOT NULL DEFAULT now(),
+    retired_at TIMESTAMP
+);
+
+-- у сущности одна активная ссылка
+CREATE UNIQUE INDEX idx_media_objects_active ON media_objects (entity_type, entity_id) WHERE retired_at IS NULL;
+CREATE INDEX idx_media_objects_url ON media_objects (url);
+CREATE INDEX idx_media_objects_retired_at ON media_objects (retired_at) WHERE retired_at IS NOT NULL;
+
+-- TG_ARGV[0] - тип сущности, TG_ARGV[1] - колонка с id, TG_ARGV[2] - колонка с адресом.
+-- Адреса по умолчанию (папка default) не отслеживаются, их нельзя удалять
+CREATE OR REPLACE FUNCTION track_media_object()
+    RETURNS TRIGGER AS $$
+DECLARE
+    target_id INT;
+    old_url TEXT;
+    new_url TEXT;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        target_id := (to_jsonb(OLD) ->> TG_ARGV[1])::INT;
+        old_url := to_jsonb(OLD) ->> TG_ARGV[2];
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        target_id := (to_jsonb(NEW) ->> TG_ARGV[1])::INT;
+        new_url := to_jsonb(NEW) ->> TG_ARGV[2];
+    END IF;
+
+    IF old_url IS NOT DISTINCT FROM new_url THEN
+        RETURN NULL;
+    END IF;
+
+    UPDATE media_objects
+    SET retired_at = now()
+    WHERE media_objects.entity_type = TG_ARGV[0]
+      AND media_objects.entity_id = target_id
+      AND retired_at IS NULL;
+
+    IF COALESCE(new_url, '') <> '' AND new_url !~ '/default(/|$)' THEN
+        INSERT INTO media_objects (entity_type, entity_id, url)
+        VALUES (TG_ARGV[0], target_id, new_url);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER track_film_poster
+    AFTER INSERT OR UPDATE OF poster_url OR DELETE ON films
+    FOR EACH ROW
+EXECUTE FUNCTION track_media_object('film_poster', 'film_id', 'poster_url');
+
+CREATE TRIGGER track_film_image
+    AFTER INSERT OR UPDATE OF url OR DELETE ON film_images
+    FOR EACH ROW
+EXECUTE FUNCTION track_media_object('film_image', 'image_id', 'url');
+
+CREATE TRIGGER track_person_avatar
+    AFTER INSERT OR UPDATE OF avatar_url OR DELETE ON persons
+    FOR EACH ROW
+EXECUTE FUNCTION track_media_object('person_avatar', 'person_id', 'avatar_url');
+
+CREATE TRIGGER track_user_avatar
+    AFTER INSERT OR UPDATE OF avatar_url OR DELETE ON users
+    FOR EACH ROW
+EXECUTE FUNCTION track_media_object('user_avatar', 'user_id', 'avatar_url');
+
+CREATE TRIGGER track_genre_cover
+    AFTER INSERT OR UPDATE OF cover_url OR DELETE ON genres
+    FOR EACH ROW
+EXECUTE FUNCTION track_media_object('genre_cover', 'genre_id', 'cover_url');
+
+-- текущие изображения становятся активными ссылками
+INSERT INTO media_objects (entity_type, entity_id, url)
+SELECT 'film_poster', film_id, poster_url FROM films
+WHERE COALESCE(poster_url, '') <> '' AND poster_url !~ '/default(/|$)'
+UNION ALL
+SELECT 'film_image', image_id, url FROM film_images
+WHERE url <> ''
+UNION ALL
+SELECT 'person_avatar', person_id, avatar_url FROM persons
+WHERE COALESCE(avatar_url, '') <> '' AND avatar_url !~ '/default(/|$)'
+UNION ALL
+SELECT 'user_avatar', user_id, avatar_url FROM users
+WHERE COALESCE(avatar_url, '') <> '' AND avatar_url !~ '/default(/|$)'
+UNION ALL
+SELECT 'genre_cover', genre_id, cover_url FROM genres
+WHERE cover_url <> '' AND cover_url !~ '/default(/|$)';
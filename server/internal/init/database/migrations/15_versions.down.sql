@@ -0,0 +1,4 @@
+ALTER TABLE reviews DROP COLUMN IF EXISTS version;
+ALTER TABLE genres DROP COLUMN IF EXISTS version;
+ALTER TABLE persons DROP COLUMN IF EXISTS version;
+ALTER TABLE films DROP COLUMN IF EXISTS version;
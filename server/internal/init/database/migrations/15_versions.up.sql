@@ -0,0 +1,5 @@
+-- счетчик изменений для оптимистической блокировки, увеличивается каждым обновлением строки
+ALTER TABLE films ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE persons ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE genres ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE reviews ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,18 @@
+-- Журнал изменений сущностей. Пишется в транзакции изменения, поэтому откаченное изменение в него не попадает.
+-- diff - изменившиеся колонки: {"before": {...}, "after": {...}}, у создания только after, у удаления только before.
+-- user_id без внешнего ключа: записи остаются после удаления пользователя
+CREATE TABLE audit_log (
+    audit_id BIGSERIAL PRIMARY KEY,
+    user_id INT,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id INT NOT NULL,
+    diff JSONB NOT NULL DEFAULT '{}',
+    request_id VARCHAR(128),
+    ip VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log (entity_type, entity_id, created_at);
+CREATE INDEX idx_audit_log_user ON audit_log (user_id, created_at);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
@@ -0,0 +1,15 @@
+DELETE FROM audit_log WHERE action IN ('restore', 'purge');
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check
+    CHECK (action IN ('create', 'update', 'delete'));
+
+-- строки из корзины удаляются окончательно, иначе после отката они снова станут видны
+DELETE FROM films WHERE deleted_at IS NOT NULL;
+DELETE FROM persons WHERE deleted_at IS NOT NULL;
+DELETE FROM genres WHERE deleted_at IS NOT NULL;
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE films DROP COLUMN deleted_at;
+ALTER TABLE persons DROP COLUMN deleted_at;
+ALTER TABLE genres DROP COLUMN deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
@@ -0,0 +1,15 @@
+-- Мягкое удаление: удаленные фильмы, персоны, жанры и пользователи остаются в корзине до очистки по сроку хранения.
+-- Связи и отзывы не трогаются, пока строка не удалена окончательно
+ALTER TABLE films ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE persons ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE genres ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_films_deleted_at ON films (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_persons_deleted_at ON persons (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_genres_deleted_at ON genres (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge'));
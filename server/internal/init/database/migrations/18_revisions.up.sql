@@ -0,0 +1,13 @@
+-- История версий фильмов и персон: полный снимок DTO со связями после каждого изменения.
+-- Одна строка на версию сущности (столбец version), по снимку запись можно вернуть к прежней версии.
+-- user_id без внешнего ключа, как в журнале изменений; снимки удаляются вместе с сущностью при очистке корзины
+CREATE TABLE revisions (
+    revision_id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id INT NOT NULL,
+    version INT NOT NULL,
+    snapshot JSONB NOT NULL,
+    user_id INT,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (entity_type, entity_id, version)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS edit_suggestion_comments;
+DROP TABLE IF EXISTS edit_suggestions;
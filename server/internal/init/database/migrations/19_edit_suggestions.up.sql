@@ -0,0 +1,35 @@
+-- Предложения правок: пользователь предлагает изменение фильма или персоны телом JSON Merge Patch,
+-- редактор принимает его (правка применяется обычным PATCH) или отклоняет. base_version - версия,
+-- которую видел автор, applied_version - версия сущности после применения
+CREATE TABLE edit_suggestions (
+    suggestion_id SERIAL PRIMARY KEY,
+    entity_type VARCHAR(32) NOT NULL CHECK (entity_type IN ('film', 'person')),
+    entity_id INT NOT NULL,
+    user_id INT NOT NULL,
+    patch JSONB NOT NULL,
+    base_version INT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    reviewer_id INT,
+    reviewed_at TIMESTAMP,
+    applied_version INT,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT fk_suggestion_user FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    CONSTRAINT fk_suggestion_reviewer FOREIGN KEY (reviewer_id) REFERENCES users (user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_edit_suggestions_status ON edit_suggestions (status, created_at);
+CREATE INDEX idx_edit_suggestions_entity ON edit_suggestions (entity_type, entity_id);
+CREATE INDEX idx_edit_suggestions_user ON edit_suggestions (user_id, created_at);
+
+-- Обсуждение предложения: комментарии автора и редакторов
+CREATE TABLE edit_suggestion_comments (
+    comment_id SERIAL PRIMARY KEY,
+    suggestion_id INT NOT NULL,
+    user_id INT,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT fk_comment_suggestion FOREIGN KEY (suggestion_id) REFERENCES edit_suggestions (suggestion_id) ON DELETE CASCADE,
+    CONSTRAINT fk_comment_user FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_edit_suggestion_comments_suggestion ON edit_suggestion_comments (suggestion_id, created_at);
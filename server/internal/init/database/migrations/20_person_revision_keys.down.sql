@@ -0,0 +1,14 @@
+UPDATE revisions
+SET snapshot = jsonb_build_object(
+    'PersonId', snapshot -> 'person_id',
+    'Name', snapshot -> 'name',
+    'Department', snapshot -> 'department',
+    'AvatarUrl', snapshot -> 'avatar_url',
+    'WikiUrl', snapshot -> 'wiki_url',
+    'CreatedAt', snapshot -> 'create_at',
+    'Version', snapshot -> 'version',
+    'ResetAvatar', snapshot -> 'reset_avatar',
+    'AvatarBlurhash', snapshot -> 'avatar_blurhash',
+    'AvatarColor', snapshot -> 'avatar_color'
+)
+WHERE entity_type = 'person' AND snapshot ? 'person_id';
@@ -0,0 +1,15 @@
+-- Снимки персон сохранялись с именами полей Go, у PersonDTO теперь json-теги как у FilmDTO
+UPDATE revisions
+SET snapshot = jsonb_build_object(
+    'person_id', snapshot -> 'PersonId',
+    'name', snapshot -> 'Name',
+    'department', snapshot -> 'Department',
+    'avatar_url', snapshot -> 'AvatarUrl',
+    'wiki_url', snapshot -> 'WikiUrl',
+    'create_at', snapshot -> 'CreatedAt',
+    'version', snapshot -> 'Version',
+    'reset_avatar', snapshot -> 'ResetAvatar',
+    'avatar_blurhash', snapshot -> 'AvatarBlurhash',
+    'avatar_color', snapshot -> 'AvatarColor'
+)
+WHERE entity_type = 'person' AND snapshot ? 'PersonId';
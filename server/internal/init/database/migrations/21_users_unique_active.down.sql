@@ -0,0 +1,13 @@
+-- из корзины удаляются пользователи, которые заняли бы email или логин повторно
+DELETE FROM users u
+WHERE u.deleted_at IS NOT NULL
+  AND EXISTS (
+    SELECT 1 FROM users o
+    WHERE o.user_id <> u.user_id AND (o.login = u.login OR o.email = u.email)
+  );
+
+DROP INDEX IF EXISTS idx_users_login_active;
+DROP INDEX IF EXISTS idx_users_email_active;
+
+ALTER TABLE users ADD CONSTRAINT users_login_key UNIQUE (login);
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
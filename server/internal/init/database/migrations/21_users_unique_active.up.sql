@@ -0,0 +1,7 @@
+-- Пользователь в корзине не занимает email и логин: уникальность проверяется только среди неудаленных.
+-- Вернуть из корзины пользователя, чьи email или логин уже заняты, нельзя
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_login_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+
+CREATE UNIQUE INDEX idx_users_login_active ON users (login) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_users_email_active ON users (email) WHERE deleted_at IS NULL;
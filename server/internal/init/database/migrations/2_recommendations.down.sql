@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_film_similarity_similar_film_id;
+DROP TABLE IF EXISTS film_similarity CASCADE;
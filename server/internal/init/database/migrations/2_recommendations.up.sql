@@ -0,0 +1,13 @@
+-- Item-item похожесть фильмов по оценкам пользователей, пересчитывается кроном
+CREATE TABLE film_similarity (
+    film_id INT NOT NULL,
+    similar_film_id INT NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    common_reviewers INT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (film_id, similar_film_id),
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE,
+    CONSTRAINT fk_similar_film FOREIGN KEY (similar_film_id) REFERENCES films (film_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_film_similarity_similar_film_id ON film_similarity (similar_film_id);
@@ -0,0 +1,23 @@
+DROP INDEX IF EXISTS idx_film_languages_language_code;
+DROP INDEX IF EXISTS idx_film_countries_country_code;
+DROP INDEX IF EXISTS idx_films_box_office;
+DROP INDEX IF EXISTS idx_films_budget;
+DROP INDEX IF EXISTS idx_films_age_rating;
+DROP INDEX IF EXISTS idx_films_tmdb_id;
+DROP INDEX IF EXISTS idx_films_kinopoisk_id;
+DROP INDEX IF EXISTS idx_films_imdb_id;
+
+DROP TABLE IF EXISTS film_languages CASCADE;
+DROP TABLE IF EXISTS film_countries CASCADE;
+DROP TABLE IF EXISTS film_alt_titles CASCADE;
+
+ALTER TABLE films
+    DROP COLUMN IF EXISTS tmdb_id,
+    DROP COLUMN IF EXISTS kinopoisk_id,
+    DROP COLUMN IF EXISTS imdb_id,
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS box_office,
+    DROP COLUMN IF EXISTS budget,
+    DROP COLUMN IF EXISTS age_rating,
+    DROP COLUMN IF EXISTS tagline,
+    DROP COLUMN IF EXISTS original_title;
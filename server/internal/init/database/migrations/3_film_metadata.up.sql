@@ -0,0 +1,43 @@
+ALTER TABLE films
+    ADD COLUMN original_title TEXT NOT NULL DEFAULT '',
+    ADD COLUMN tagline TEXT NOT NULL DEFAULT '',
+    ADD COLUMN age_rating VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN budget BIGINT NOT NULL DEFAULT 0 CHECK (budget >= 0),
+    ADD COLUMN box_office BIGINT NOT NULL DEFAULT 0 CHECK (box_office >= 0),
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT '',
+    ADD COLUMN imdb_id VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN kinopoisk_id VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN tmdb_id VARCHAR(16) NOT NULL DEFAULT '';
+
+-- Альтернативные названия (прокатные, рабочие, на других языках)
+CREATE TABLE film_alt_titles (
+    film_id INT NOT NULL,
+    title TEXT NOT NULL,
+    PRIMARY KEY (film_id, title),
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE
+);
+
+-- Страны производства, ISO 3166-1 alpha-2
+CREATE TABLE film_countries (
+    film_id INT NOT NULL,
+    country_code CHAR(2) NOT NULL,
+    PRIMARY KEY (film_id, country_code),
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE
+);
+
+-- Языки фильма, ISO 639-1
+CREATE TABLE film_languages (
+    film_id INT NOT NULL,
+    language_code CHAR(2) NOT NULL,
+    PRIMARY KEY (film_id, language_code),
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_films_imdb_id ON films (imdb_id) WHERE imdb_id <> '';
+CREATE UNIQUE INDEX idx_films_kinopoisk_id ON films (kinopoisk_id) WHERE kinopoisk_id <> '';
+CREATE UNIQUE INDEX idx_films_tmdb_id ON films (tmdb_id) WHERE tmdb_id <> '';
+CREATE INDEX idx_films_age_rating ON films (age_rating);
+CREATE INDEX idx_films_budget ON films (budget);
+CREATE INDEX idx_films_box_office ON films (box_office);
+CREATE INDEX idx_film_countries_country_code ON film_countries (country_code);
+CREATE INDEX idx_film_languages_language_code ON film_languages (language_code);
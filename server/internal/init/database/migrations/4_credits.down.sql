@@ -0,0 +1,40 @@
+ALTER TABLE films ADD COLUMN producer VARCHAR(255);
+
+UPDATE films f
+SET producer = c.names
+FROM (
+    SELECT fc.film_id, string_agg(p.name, ', ' ORDER BY fc.billing_order) AS names
+    FROM film_credits fc
+    JOIN persons p ON p.person_id = fc.person_id
+    WHERE fc.role = 'producer'
+    GROUP BY fc.film_id
+) c
+WHERE c.film_id = f.film_id;
+
+CREATE INDEX idx_films_producer ON films (producer);
+
+CREATE TABLE film_actor (
+    film_id INT NOT NULL,
+    actor_id INT NOT NULL,
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE,
+    CONSTRAINT fk_actor FOREIGN KEY (actor_id) REFERENCES persons (person_id) ON DELETE CASCADE
+);
+
+INSERT INTO film_actor (film_id, actor_id)
+SELECT DISTINCT film_id, person_id
+FROM film_credits
+WHERE role = 'cast';
+
+CREATE INDEX idx_film_actor_film_id_actor_id ON film_actor (film_id, actor_id);
+
+DROP INDEX IF EXISTS idx_film_credits_person_id_role;
+DROP INDEX IF EXISTS idx_film_credits_film_id_role;
+DROP TABLE IF EXISTS film_credits CASCADE;
+
+-- Персоны, созданные из строкового producer, остаются в таблице: их нельзя отличить от добавленных вручную
+ALTER TABLE persons DROP COLUMN IF EXISTS department;
+ALTER INDEX idx_persons_name RENAME TO idx_actors_name;
+ALTER INDEX persons_pkey RENAME TO actors_pkey;
+ALTER SEQUENCE persons_person_id_seq RENAME TO actors_actor_id_seq;
+ALTER TABLE persons RENAME COLUMN person_id TO actor_id;
+ALTER TABLE persons RENAME TO actors;
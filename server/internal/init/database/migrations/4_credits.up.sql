@@ -0,0 +1,53 @@
+-- Актеры обобщаются до персон: режиссеры, сценаристы, продюсеры и композиторы хранятся в той же таблице
+ALTER TABLE actors RENAME TO persons;
+ALTER TABLE persons RENAME COLUMN actor_id TO person_id;
+ALTER SEQUENCE actors_actor_id_seq RENAME TO persons_person_id_seq;
+ALTER INDEX actors_pkey RENAME TO persons_pkey;
+ALTER INDEX idx_actors_name RENAME TO idx_persons_name;
+
+ALTER TABLE persons
+    ADD COLUMN department VARCHAR(32) NOT NULL DEFAULT 'acting'
+        CHECK (department IN ('acting', 'directing', 'writing', 'production', 'sound'));
+
+CREATE TABLE film_credits (
+    credit_id SERIAL PRIMARY KEY,
+    film_id INT NOT NULL,
+    person_id INT NOT NULL,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('cast', 'director', 'writer', 'producer', 'composer')),
+    character_name TEXT NOT NULL DEFAULT '',
+    billing_order INT NOT NULL DEFAULT 0 CHECK (billing_order >= 0),
+    UNIQUE (film_id, person_id, role, character_name),
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE,
+    CONSTRAINT fk_person FOREIGN KEY (person_id) REFERENCES persons (person_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_film_credits_film_id_role ON film_credits (film_id, role, billing_order);
+CREATE INDEX idx_film_credits_person_id_role ON film_credits (person_id, role);
+
+-- Перенос актерского состава, порядок в титрах по порядку добавления актеров
+INSERT INTO film_credits (film_id, person_id, role, billing_order)
+SELECT film_id, actor_id, 'cast', ROW_NUMBER() OVER (PARTITION BY film_id ORDER BY actor_id) - 1
+FROM (SELECT DISTINCT film_id, actor_id FROM film_actor) fa;
+
+-- Перенос строкового поля producer: несколько продюсеров перечислены через запятую.
+-- Персона с таким же именем переиспользуется, остальные создаются с департаментом production.
+CREATE TEMPORARY TABLE film_producers AS
+SELECT f.film_id, btrim(p.name) AS name, MIN(p.ord) - 1 AS billing_order
+FROM films f, LATERAL regexp_split_to_table(f.producer, ',') WITH ORDINALITY AS p(name, ord)
+WHERE f.producer IS NOT NULL AND btrim(p.name) NOT IN ('', 'Неизвестен')
+GROUP BY f.film_id, btrim(p.name);
+
+INSERT INTO persons (name, department)
+SELECT DISTINCT fp.name, 'production'
+FROM film_producers fp
+WHERE NOT EXISTS (SELECT 1 FROM persons p WHERE p.name = fp.name);
+
+INSERT INTO film_credits (film_id, person_id, role, billing_order)
+SELECT fp.film_id, MIN(p.person_id), 'producer', fp.billing_order
+FROM film_producers fp
+JOIN persons p ON p.name = fp.name
+GROUP BY fp.film_id, fp.name, fp.billing_order;
+
+DROP TABLE film_producers;
+DROP TABLE film_actor;
+ALTER TABLE films DROP COLUMN producer;
@@ -0,0 +1,21 @@
+DROP INDEX IF EXISTS idx_reviews_episode_id;
+DROP INDEX IF EXISTS idx_reviews_season_id;
+DROP INDEX IF EXISTS idx_episodes_season_id;
+DROP INDEX IF EXISTS idx_films_content_type;
+DROP INDEX IF EXISTS idx_reviews_user_episode;
+DROP INDEX IF EXISTS idx_reviews_user_season;
+DROP INDEX IF EXISTS idx_reviews_user_film_title;
+
+-- Отзывы на сезоны и серии не укладываются в UNIQUE (user_id, film_id)
+DELETE FROM reviews WHERE season_id IS NOT NULL OR episode_id IS NOT NULL;
+
+ALTER TABLE reviews
+    DROP CONSTRAINT IF EXISTS chk_reviews_episode_season,
+    DROP COLUMN IF EXISTS episode_id,
+    DROP COLUMN IF EXISTS season_id,
+    ADD CONSTRAINT reviews_user_id_film_id_key UNIQUE (user_id, film_id);
+
+DROP TABLE IF EXISTS episodes CASCADE;
+DROP TABLE IF EXISTS seasons CASCADE;
+
+ALTER TABLE films DROP COLUMN IF EXISTS content_type;
@@ -0,0 +1,46 @@
+ALTER TABLE films
+    ADD COLUMN content_type VARCHAR(16) NOT NULL DEFAULT 'movie'
+        CHECK (content_type IN ('movie', 'series', 'mini_series'));
+
+CREATE TABLE seasons (
+    season_id SERIAL PRIMARY KEY,
+    film_id INT NOT NULL,
+    season_number INT NOT NULL CHECK (season_number >= 0),
+    title TEXT NOT NULL DEFAULT '',
+    synopsis TEXT NOT NULL DEFAULT '',
+    air_date DATE,
+    create_at DATE DEFAULT current_date,
+    UNIQUE (film_id, season_number),
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE
+);
+
+CREATE TABLE episodes (
+    episode_id SERIAL PRIMARY KEY,
+    season_id INT NOT NULL,
+    episode_number INT NOT NULL CHECK (episode_number >= 1),
+    title TEXT NOT NULL DEFAULT '',
+    synopsis TEXT NOT NULL DEFAULT '',
+    air_date DATE,
+    runtime INT NOT NULL DEFAULT 0 CHECK (runtime >= 0),
+    create_at DATE DEFAULT current_date,
+    UNIQUE (season_id, episode_number),
+    CONSTRAINT fk_season FOREIGN KEY (season_id) REFERENCES seasons (season_id) ON DELETE CASCADE
+);
+
+-- Отзывы на сезон и серию хранят film_id сериала, поэтому попадают в film_stats сериала.
+-- У отзыва на серию season_id тоже заполнен, чтобы статистика сезона включала его серии.
+ALTER TABLE reviews
+    ADD COLUMN season_id INT REFERENCES seasons (season_id) ON DELETE CASCADE,
+    ADD COLUMN episode_id INT REFERENCES episodes (episode_id) ON DELETE CASCADE,
+    ADD CONSTRAINT chk_reviews_episode_season CHECK (episode_id IS NULL OR season_id IS NOT NULL),
+    DROP CONSTRAINT IF EXISTS reviews_user_id_film_id_key;
+
+-- Один отзыв пользователя на сериал целиком, на каждый сезон и на каждую серию
+CREATE UNIQUE INDEX idx_reviews_user_film_title ON reviews (user_id, film_id) WHERE season_id IS NULL AND episode_id IS NULL;
+CREATE UNIQUE INDEX idx_reviews_user_season ON reviews (user_id, season_id) WHERE season_id IS NOT NULL AND episode_id IS NULL;
+CREATE UNIQUE INDEX idx_reviews_user_episode ON reviews (user_id, episode_id) WHERE episode_id IS NOT NULL;
+
+CREATE INDEX idx_films_content_type ON films (content_type);
+CREATE INDEX idx_episodes_season_id ON episodes (season_id);
+CREATE INDEX idx_reviews_season_id ON reviews (season_id);
+CREATE INDEX idx_reviews_episode_id ON reviews (episode_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_film_relations_related_film_id;
+DROP INDEX IF EXISTS idx_collection_films_collection_position;
+
+DROP TABLE IF EXISTS film_relations CASCADE;
+DROP TABLE IF EXISTS collection_films CASCADE;
+DROP TABLE IF EXISTS collections CASCADE;
@@ -0,0 +1,31 @@
+-- Франшизы и подборки: фильм входит не больше чем в одну коллекцию
+CREATE TABLE collections (
+    collection_id SERIAL PRIMARY KEY,
+    name VARCHAR(200) UNIQUE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    create_at DATE DEFAULT current_date
+);
+
+CREATE TABLE collection_films (
+    collection_id INT NOT NULL,
+    film_id INT NOT NULL UNIQUE,
+    position INT NOT NULL CHECK (position >= 1),
+    PRIMARY KEY (collection_id, film_id),
+    CONSTRAINT fk_collection FOREIGN KEY (collection_id) REFERENCES collections (collection_id) ON DELETE CASCADE,
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE
+);
+
+-- related_film_id является relation_type для film_id: (Матрица, Матрица: Перезагрузка, sequel).
+-- Обратная связь не хранится, а выводится при чтении (sequel <-> prequel, remake -> original, spin_off -> parent)
+CREATE TABLE film_relations (
+    film_id INT NOT NULL,
+    related_film_id INT NOT NULL,
+    relation_type VARCHAR(16) NOT NULL CHECK (relation_type IN ('sequel', 'prequel', 'remake', 'spin_off')),
+    PRIMARY KEY (film_id, related_film_id, relation_type),
+    CHECK (film_id <> related_film_id),
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE,
+    CONSTRAINT fk_related_film FOREIGN KEY (related_film_id) REFERENCES films (film_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_collection_films_collection_position ON collection_films (collection_id, position);
+CREATE INDEX idx_film_relations_related_film_id ON film_relations (related_film_id);
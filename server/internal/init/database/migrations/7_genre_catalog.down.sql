@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS genre_descriptions;
+
+DROP INDEX IF EXISTS idx_genres_parent_id;
+
+ALTER TABLE genres
+    DROP CONSTRAINT IF EXISTS genres_slug_key,
+    DROP CONSTRAINT IF EXISTS chk_genre_parent,
+    DROP CONSTRAINT IF EXISTS fk_genre_parent,
+    DROP COLUMN IF EXISTS parent_id,
+    DROP COLUMN IF EXISTS cover_url,
+    DROP COLUMN IF EXISTS slug;
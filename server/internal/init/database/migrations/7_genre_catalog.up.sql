@@ -0,0 +1,36 @@
+-- Жанры как каталог: slug для ЧПУ, обложка, описания на разных языках и вложенность (Фантастика > Киберпанк)
+ALTER TABLE genres
+    ADD COLUMN slug VARCHAR(200),
+    ADD COLUMN cover_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN parent_id INT,
+    ADD CONSTRAINT fk_genre_parent FOREIGN KEY (parent_id) REFERENCES genres (genre_id) ON DELETE SET NULL,
+    ADD CONSTRAINT chk_genre_parent CHECK (parent_id <> genre_id);
+
+-- Транслитерация существующих названий, пустой результат заменяется на genre-<id>
+UPDATE genres
+SET slug = trim(BOTH '-' FROM regexp_replace(
+        replace(replace(replace(replace(replace(replace(replace(replace(
+            translate(lower(name), 'абвгдеёзийклмнопрстуфыэъь', 'abvgdeezijklmnoprstufye'),
+            'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'), 'ч', 'ch'), 'ш', 'sh'), 'щ', 'shch'), 'ю', 'yu'), 'я', 'ya'),
+        '[^a-z0-9]+', '-', 'g'));
+
+UPDATE genres SET slug = 'genre-' || genre_id WHERE slug = '';
+
+-- при совпадении транслитераций к slug дописывается id
+UPDATE genres g
+SET slug = g.slug || '-' || g.genre_id
+WHERE EXISTS (SELECT 1 FROM genres o WHERE o.slug = g.slug AND o.genre_id < g.genre_id);
+
+ALTER TABLE genres
+    ALTER COLUMN slug SET NOT NULL,
+    ADD CONSTRAINT genres_slug_key UNIQUE (slug);
+
+CREATE INDEX idx_genres_parent_id ON genres (parent_id);
+
+CREATE TABLE genre_descriptions (
+    genre_id INT NOT NULL,
+    locale VARCHAR(8) NOT NULL,
+    description TEXT NOT NULL,
+    PRIMARY KEY (genre_id, locale),
+    CONSTRAINT fk_genre FOREIGN KEY (genre_id) REFERENCES genres (genre_id) ON DELETE CASCADE
+);
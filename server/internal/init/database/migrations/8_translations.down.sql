@@ -0,0 +1,30 @@
+ALTER TABLE films
+    ALTER COLUMN title SET DEFAULT 'фильмец под чипсики',
+    ALTER COLUMN synopsis SET DEFAULT '-';
+
+CREATE TABLE genre_descriptions (
+    genre_id INT NOT NULL,
+    locale VARCHAR(8) NOT NULL,
+    description TEXT NOT NULL,
+    PRIMARY KEY (genre_id, locale),
+    CONSTRAINT fk_genre FOREIGN KEY (genre_id) REFERENCES genres (genre_id) ON DELETE CASCADE
+);
+
+INSERT INTO genre_descriptions (genre_id, locale, description)
+SELECT genre_id, 'ru', description
+FROM genres
+WHERE description <> '';
+
+INSERT INTO genre_descriptions (genre_id, locale, description)
+SELECT entity_id, locale, value
+FROM translations
+WHERE entity_type = 'genre' AND field = 'description' AND locale <> 'ru';
+
+ALTER TABLE genres DROP COLUMN IF EXISTS description;
+
+DROP TRIGGER IF EXISTS delete_person_translations ON persons;
+DROP TRIGGER IF EXISTS delete_genre_translations ON genres;
+DROP TRIGGER IF EXISTS delete_film_translations ON films;
+DROP FUNCTION IF EXISTS delete_entity_translations();
+
+DROP TABLE IF EXISTS translations;
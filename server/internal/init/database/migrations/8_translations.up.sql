@@ -0,0 +1,58 @@
+-- Переводы полей фильмов, жанров и персон. Основные колонки сущностей хранят текст на языке по умолчанию (ru),
+-- здесь лежат переводы на остальные языки: (film, 1, en, title) -> "The Matrix"
+CREATE TABLE translations (
+    entity_type VARCHAR(16) NOT NULL CHECK (entity_type IN ('film', 'genre', 'person')),
+    entity_id INT NOT NULL,
+    locale VARCHAR(8) NOT NULL,
+    field VARCHAR(32) NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (entity_type, entity_id, locale, field)
+);
+
+-- Внешний ключ на разные таблицы невозможен, поэтому переводы удаляются триггером.
+-- TG_ARGV[0] - тип сущности, TG_ARGV[1] - колонка с id
+CREATE OR REPLACE FUNCTION delete_entity_translations()
+    RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM translations
+    WHERE entity_type = TG_ARGV[0] AND entity_id = (to_jsonb(OLD) ->> TG_ARGV[1])::INT;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER delete_film_translations
+    AFTER DELETE ON films
+    FOR EACH ROW
+EXECUTE FUNCTION delete_entity_translations('film', 'film_id');
+
+CREATE TRIGGER delete_genre_translations
+    AFTER DELETE ON genres
+    FOR EACH ROW
+EXECUTE FUNCTION delete_entity_translations('genre', 'genre_id');
+
+CREATE TRIGGER delete_person_translations
+    AFTER DELETE ON persons
+    FOR EACH ROW
+EXECUTE FUNCTION delete_entity_translations('person', 'person_id');
+
+-- Описания жанров переезжают в основную колонку (ru) и в translations (остальные языки)
+ALTER TABLE genres ADD COLUMN description TEXT NOT NULL DEFAULT '';
+
+UPDATE genres g
+SET description = d.description
+FROM genre_descriptions d
+WHERE d.genre_id = g.genre_id AND d.locale = 'ru';
+
+INSERT INTO translations (entity_type, entity_id, locale, field, value)
+SELECT 'genre', genre_id, locale, 'description', description
+FROM genre_descriptions
+WHERE locale <> 'ru';
+
+DROP TABLE genre_descriptions;
+
+-- Значения по умолчанию не должны подставлять русский текст в чужой язык
+ALTER TABLE films
+    ALTER COLUMN title DROP DEFAULT,
+    ALTER COLUMN synopsis SET DEFAULT '';
+
+UPDATE films SET synopsis = '' WHERE synopsis = '-';
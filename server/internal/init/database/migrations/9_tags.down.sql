@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_film_tags_status;
+DROP INDEX IF EXISTS idx_film_tags_tag_id;
+
+DROP TABLE IF EXISTS film_tag_votes CASCADE;
+DROP TABLE IF EXISTS film_tags CASCADE;
+DROP TABLE IF EXISTS tags CASCADE;
@@ -0,0 +1,38 @@
+-- Теги (ключевые слова): "путешествия во времени", "ограбление", "основано на реальных событиях".
+-- Теги, созданные администратором, сразу одобрены, предложенные пользователями ждут модерации
+CREATE TABLE tags (
+    tag_id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'approved' CHECK (status IN ('approved', 'pending')),
+    created_by INT,
+    create_at DATE DEFAULT current_date,
+    CONSTRAINT fk_tag_created_by FOREIGN KEY (created_by) REFERENCES users (user_id) ON DELETE SET NULL
+);
+
+-- Тег фильма. Предложение пользователя хранится со статусом pending до решения администратора
+CREATE TABLE film_tags (
+    film_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'approved' CHECK (status IN ('approved', 'pending')),
+    suggested_by INT,
+    create_at DATE DEFAULT current_date,
+    PRIMARY KEY (film_id, tag_id),
+    CONSTRAINT fk_film FOREIGN KEY (film_id) REFERENCES films (film_id) ON DELETE CASCADE,
+    CONSTRAINT fk_tag FOREIGN KEY (tag_id) REFERENCES tags (tag_id) ON DELETE CASCADE,
+    CONSTRAINT fk_suggested_by FOREIGN KEY (suggested_by) REFERENCES users (user_id) ON DELETE SET NULL
+);
+
+-- Голоса пользователей за то, насколько тег подходит фильму
+CREATE TABLE film_tag_votes (
+    film_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    user_id INT NOT NULL,
+    vote SMALLINT NOT NULL CHECK (vote IN (-1, 1)),
+    PRIMARY KEY (film_id, tag_id, user_id),
+    CONSTRAINT fk_film_tag FOREIGN KEY (film_id, tag_id) REFERENCES film_tags (film_id, tag_id) ON DELETE CASCADE,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_film_tags_tag_id ON film_tags (tag_id);
+CREATE INDEX idx_film_tags_status ON film_tags (status) WHERE status = 'pending';
@@ -55,6 +55,10 @@ func NewSearch(cfg config.ElasticsearchConfig) (*Search, error) {
 		}
 
 		log.Println("Successfully connected to Elasticsearch")
+
+		if err := ensureLocalizedMapping(client, cfg.Index); err != nil {
+			log.Printf("Failed to ensure localized mapping: %v", err)
+		}
 		return &Search{
 			Client: client,
 			Index:  cfg.Index,
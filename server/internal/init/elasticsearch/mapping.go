@@ -0,0 +1,66 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8"
+	"net/http"
+)
+
+// localizedAnalyzers - встроенный анализатор Elasticsearch для каждого поддерживаемого языка
+var localizedAnalyzers = map[string]string{
+	"ru": "russian",
+	"en": "english",
+}
+
+// localizedFields - поля фильма, которые индексируются отдельно на каждом языке (title_ru, title_en, ...)
+var localizedFields = []string{"title", "tagline", "synopsis"}
+
+// ensureLocalizedMapping создает индекс с маппингом языковых полей или добавляет их в существующий индекс.
+// Новые поля можно добавить в маппинг без переиндексации, остальные поля по-прежнему маппятся динамически
+func ensureLocalizedMapping(client *elasticsearch.Client, index string) error {
+	properties := make(map[string]interface{}, len(localizedFields)*len(localizedAnalyzers))
+	for _, field := range localizedFields {
+		for lang, analyzer := range localizedAnalyzers {
+			properties[field+"_"+lang] = map[string]interface{}{
+				"type":     "text",
+				"analyzer": analyzer,
+			}
+		}
+	}
+	mapping := map[string]interface{}{"properties": properties}
+
+	res, err := client.Indices.Exists([]string{index})
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		body, err := json.Marshal(map[string]interface{}{"mappings": mapping})
+		if err != nil {
+			return err
+		}
+		res, err = client.Indices.Create(index, client.Indices.Create.WithBody(bytes.NewReader(body)))
+		if err != nil {
+			return err
+		}
+	} else {
+		body, err := json.Marshal(mapping)
+		if err != nil {
+			return err
+		}
+		res, err = client.Indices.PutMapping([]string{index}, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("failed to update index mapping: %s", res.String())
+	}
+
+	return nil
+}
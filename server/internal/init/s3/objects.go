@@ -0,0 +1,216 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"io"
+	"net/http"
+	"net/url"
+	"server/internal/init/blob"
+	"strings"
+	"time"
+)
+
+// URL - публичный адрес объекта или папки (ключ с "/" на конце) в бакете, с CDN - адрес через CDN
+func (s *S3Storage) URL(bucket, key string) string {
+	return s.urls.URL(bucket, key)
+}
+
+func (s *S3Storage) Put(bucket, key string, data []byte, opts blob.PutOptions) error {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(opts.ContentType),
+	}
+	if opts.CacheControl != "" {
+		input.CacheControl = aws.String(opts.CacheControl)
+	}
+
+	_, err := s.Client.PutObject(context.TODO(), input)
+	return err
+}
+
+func (s *S3Storage) Copy(bucket, srcKey, dstKey string) error {
+	_, err := s.Client.CopyObject(context.TODO(), &s3.CopyObjectInput{
+		Bucket:     aws.String(bucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(bucket + "/" + url.PathEscape(srcKey)),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return blob.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *S3Storage) List(bucket, prefix string) ([]blob.Object, error) {
+	paginator := s3.NewListObjectsV2Paginator(s.Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	var objects []blob.Object
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range page.Contents {
+			obj := blob.Object{Key: aws.ToString(object.Key), Size: aws.ToInt64(object.Size)}
+			if object.LastModified != nil {
+				obj.LastModified = *object.LastModified
+			}
+			objects = append(objects, obj)
+		}
+	}
+
+	return objects, nil
+}
+
+// DeletePrefix удаляет все объекты бакета, ключи которых начинаются с prefix
+func (s *S3Storage) DeletePrefix(bucket, prefix string) error {
+	paginator := s3.NewListObjectsV2Paginator(s.Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return err
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+
+		objects := make([]types.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objects = append(objects, types.ObjectIdentifier{Key: object.Key})
+		}
+
+		_, err = s.Client.DeleteObjects(context.TODO(), &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PresignPut подписывает загрузку объекта методом PUT. Тип и размер входят в подпись,
+// поэтому S3 примет только файл с этим Content-Type и ровно size байт
+func (s *S3Storage) PresignPut(bucket, key, contentType string, size int64, ttl time.Duration) (*blob.PresignedRequest, error) {
+	presigner := s3.NewPresignClient(s.Client)
+	req, err := presigner.PresignPutObject(context.TODO(), &s3.PutObjectInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]string, len(req.SignedHeader))
+	for name := range req.SignedHeader {
+		if strings.EqualFold(name, "Host") {
+			continue
+		}
+		headers[http.CanonicalHeaderKey(name)] = req.SignedHeader.Get(name)
+	}
+
+	return &blob.PresignedRequest{URL: req.URL, Method: req.Method, Headers: headers}, nil
+}
+
+// Get читает объект целиком, объект больше maxSize байт не читается
+func (s *S3Storage) Get(bucket, key string, maxSize int64) ([]byte, error) {
+	out, err := s.Client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, blob.ErrNotFound
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	if out.ContentLength != nil && *out.ContentLength > maxSize {
+		return nil, blob.ErrTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(out.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, blob.ErrTooLarge
+	}
+
+	return data, nil
+}
+
+func (s *S3Storage) Delete(bucket, key string) error {
+	_, err := s.Client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// DeleteOlderThan удаляет объекты с ключами на prefix, загруженные раньше before, и возвращает их количество
+func (s *S3Storage) DeleteOlderThan(bucket, prefix string, before time.Time) (int, error) {
+	paginator := s3.NewListObjectsV2Paginator(s.Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	deleted := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return deleted, err
+		}
+
+		var objects []types.ObjectIdentifier
+		for _, object := range page.Contents {
+			if object.LastModified != nil && object.LastModified.Before(before) {
+				objects = append(objects, types.ObjectIdentifier{Key: object.Key})
+			}
+		}
+		if len(objects) == 0 {
+			continue
+		}
+
+		_, err = s.Client.DeleteObjects(context.TODO(), &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return deleted, err
+		}
+		deleted += len(objects)
+	}
+
+	return deleted, nil
+}
@@ -12,6 +12,7 @@ import (
 	"log"
 	"os"
 	"server/config"
+	"server/internal/init/blob"
 	"time"
 )
 
@@ -20,9 +21,14 @@ type S3Storage struct {
 	Endpoint string
 	Region   string
 	Buckets  []config.BucketConfig
+	urls     blob.URLBuilder
 }
 
-func NewS3Storage(config config.S3Config) (*S3Storage, error) {
+var _ blob.Store = (*S3Storage)(nil)
+
+// NewS3Storage подключается к S3 и создает бакеты из конфигурации. cdnPrefix - адрес CDN перед бакетами,
+// пустой - файлы отдаются напрямую из бакетов
+func NewS3Storage(config config.S3Config, cdnPrefix string) (*S3Storage, error) {
 	accessKey := os.Getenv("S3_ACCESS_KEY")
 	secretKey := os.Getenv("S3_SECRET_KEY")
 
@@ -55,6 +61,9 @@ func NewS3Storage(config config.S3Config) (*S3Storage, error) {
 		Region:   config.Region,
 		Buckets:  config.Buckets,
 	}
+	storage.urls = blob.NewURLBuilder(cdnPrefix, func(bucket, key string) string {
+		return fmt.Sprintf("https://%s.%s/%s", bucket, storage.Endpoint, key)
+	})
 
 	// Создаем бакеты из конфигурации
 	for _, bucket := range config.Buckets {
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"errors"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	au "server/internal/modules/audit"
+	resp "server/pkg/lib/response"
+	"strconv"
+	"time"
+)
+
+type AuditController struct {
+	log *slog.Logger
+	uc  au.UseCase
+}
+
+func NewAuditController(log *slog.Logger, uc au.UseCase) *AuditController {
+	return &AuditController{
+		log: log,
+		uc:  uc,
+	}
+}
+
+// GetLog - Журнал изменений
+// @Summary Получить журнал изменений
+// @Description Возвращает записи журнала изменений фильмов, персон, жанров, отзывов и пользователей от новых к старым: автор, действие, изменившиеся колонки до и после, id запроса и IP. Только для администраторов
+// @Tags audit
+// @Produce json
+// @Security ApiKeyAuth
+// @Param entity_type query string false "Тип сущности: film, person, genre, review, user"
+// @Param entity_id query int false "Id сущности, только вместе с entity_type"
+// @Param user_id query int false "Id пользователя, внесшего изменение"
+// @Param from query string false "Начало периода включительно (формат: RFC3339)"
+// @Param to query string false "Конец периода, не включая (формат: RFC3339)"
+// @Param page query int false "Номер страницы"
+// @Param page_size query int false "Размер страницы (1-100, по умолчанию 50)"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/audit [get]
+func (c *AuditController) GetLog(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetLog")
+
+	query := r.URL.Query()
+	filter := &au.LogFilter{EntityType: query.Get("entity_type")}
+
+	if entityID := query.Get("entity_id"); entityID != "" {
+		id, err := strconv.ParseUint(entityID, 10, 32)
+		if err != nil || id == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid entity_id format"))
+			return
+		}
+		filter.EntityID = uint(id)
+	}
+
+	if userID := query.Get("user_id"); userID != "" {
+		id, err := strconv.ParseUint(userID, 10, 32)
+		if err != nil || id == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid user_id format"))
+			return
+		}
+		filter.UserID = uint(id)
+	}
+
+	if from := query.Get("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid from format, expected RFC3339"))
+			return
+		}
+		filter.From = &t
+	}
+
+	if to := query.Get("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid to format, expected RFC3339"))
+			return
+		}
+		filter.To = &t
+	}
+
+	if page := query.Get("page"); page != "" {
+		pageNum, err := strconv.Atoi(page)
+		if err != nil || pageNum < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid page format, expected positive integer"))
+			return
+		}
+		filter.Page = pageNum
+	}
+
+	if pageSize := query.Get("page_size"); pageSize != "" {
+		size, err := strconv.Atoi(pageSize)
+		if err != nil || size < 1 || size > 100 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid page_size format, expected positive integer between 1 and 100"))
+			return
+		}
+		filter.PageSize = size
+	}
+
+	entries, err := c.uc.GetLog(filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, au.ErrUnknownEntity) || errors.Is(err, au.ErrEntityTypeRequired) || errors.Is(err, au.ErrInvalidTimeRange):
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		default:
+			log.Error("failed to get audit log", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, au.ErrInternal.Error()))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.AuditLog(entries))
+}
@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// LogDTO - запись журнала изменений. Diff - изменившиеся колонки: {"before": {...}, "after": {...}}
+type LogDTO struct {
+	ID         uint
+	UserID     *uint
+	Action     string
+	EntityType string
+	EntityID   uint
+	Diff       json.RawMessage
+	RequestID  string
+	IP         string
+	CreatedAt  time.Time
+}
+
+// LogFilter - поиск по журналу, пустые поля выборку не ограничивают. EntityID ищется только вместе с EntityType
+type LogFilter struct {
+	EntityType string
+	EntityID   uint
+	UserID     uint
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	PageSize   int
+}
+
+type Controller interface {
+	GetLog(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	GetLog(filter *LogFilter) ([]*LogDTO, error)
+}
+
+type Repo interface {
+	//DB
+	GetLog(filter *LogFilter) ([]*LogDTO, error)
+}
@@ -0,0 +1,10 @@
+package audit
+
+import "errors"
+
+var (
+	ErrInternal           = errors.New("internal server error")
+	ErrUnknownEntity      = errors.New("unknown entity type, expected one of: film, person, genre, review, user")
+	ErrEntityTypeRequired = errors.New("entity_id filter requires entity_type")
+	ErrInvalidTimeRange   = errors.New("invalid time range, from must be before to")
+)
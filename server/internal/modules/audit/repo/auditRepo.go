@@ -0,0 +1,21 @@
+package repo
+
+import (
+	au "server/internal/modules/audit"
+)
+
+type AuditDB interface {
+	GetLog(filter *au.LogFilter) ([]*au.LogDTO, error)
+}
+
+type Repo struct {
+	db AuditDB
+}
+
+func NewAuditRepo(db AuditDB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) GetLog(filter *au.LogFilter) ([]*au.LogDTO, error) {
+	return r.db.GetLog(filter)
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"log/slog"
+	au "server/internal/modules/audit"
+	"server/pkg/lib/audit"
+)
+
+type AuditDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewAuditDatabase(db *gorm.DB, log *slog.Logger) *AuditDatabase {
+	return &AuditDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetLog возвращает записи журнала по фильтру, от новых к старым
+func (db *AuditDatabase) GetLog(filter *au.LogFilter) ([]*au.LogDTO, error) {
+	query := db.db.Model(&audit.Log{})
+
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var rows []*audit.Log
+	err := query.Order("created_at DESC, audit_id DESC").
+		Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize).
+		Find(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get audit log", "error", err)
+		return nil, au.ErrInternal
+	}
+
+	entries := make([]*au.LogDTO, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, &au.LogDTO{
+			ID:         row.AuditID,
+			UserID:     row.UserID,
+			Action:     row.Action,
+			EntityType: row.EntityType,
+			EntityID:   row.EntityID,
+			Diff:       row.Diff,
+			RequestID:  row.RequestID,
+			IP:         row.IP,
+			CreatedAt:  row.CreatedAt,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"log/slog"
+	au "server/internal/modules/audit"
+	"server/pkg/lib/audit"
+	"slices"
+)
+
+const defaultPageSize = 50
+
+type AuditUseCase struct {
+	log *slog.Logger
+	rp  au.Repo
+}
+
+func NewAuditUseCase(log *slog.Logger, rp au.Repo) *AuditUseCase {
+	return &AuditUseCase{
+		log: log,
+		rp:  rp,
+	}
+}
+
+// GetLog ищет по журналу изменений. id сущностей разных типов пересекаются, поэтому entity_id без entity_type не ищется
+func (uc *AuditUseCase) GetLog(filter *au.LogFilter) ([]*au.LogDTO, error) {
+	if filter.EntityType != "" && !slices.Contains(audit.Entities, filter.EntityType) {
+		return nil, au.ErrUnknownEntity
+	}
+	if filter.EntityID != 0 && filter.EntityType == "" {
+		return nil, au.ErrEntityTypeRequired
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, au.ErrInvalidTimeRange
+	}
+
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize == 0 {
+		filter.PageSize = defaultPageSize
+	}
+
+	return uc.rp.GetLog(filter)
+}
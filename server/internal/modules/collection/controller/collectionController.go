@@ -0,0 +1,370 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	col "server/internal/modules/collection"
+	resp "server/pkg/lib/response"
+	"strconv"
+	"strings"
+)
+
+type Controller struct {
+	log      *slog.Logger
+	uc       col.UseCase
+	validate *validator.Validate
+}
+
+func NewCollectionController(log *slog.Logger, uc col.UseCase) *Controller {
+	return &Controller{
+		log:      log,
+		uc:       uc,
+		validate: validator.New(),
+	}
+}
+
+// GetCollections - Получение списка коллекций
+// @Summary Получить список коллекций
+// @Description Возвращает все коллекции и франшизы со сводной статистикой по их фильмам
+// @Tags collection
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /collections [get]
+func (c *Controller) GetCollections(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetCollections")
+
+	collections, err := c.uc.GetCollections()
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Collections(collections))
+}
+
+// GetCollection - Получение коллекции
+// @Summary Получить коллекцию по Id
+// @Description Возвращает коллекцию с фильмами в порядке просмотра и статистикой франшизы, посчитанной по film_stats
+// @Tags collection
+// @Produce json
+// @Param id path string true "Id коллекции"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /collections/{id} [get]
+func (c *Controller) GetCollection(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetCollection")
+
+	id, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	collection, err := c.uc.GetCollection(id)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Collections(collection))
+}
+
+// CreateCollection - Создание коллекции
+// @Summary Создать коллекцию
+// @Description Создает новую коллекцию или франшизу
+// @Tags collection
+// @Accept json
+// @Produce json
+// @Param json body CollectionRequest true "Данные коллекции"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /collections [post]
+func (c *Controller) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "CreateCollection")
+
+	var req CollectionRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	collection := &col.CollectionDTO{
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+	}
+
+	if err := c.uc.CreateCollection(collection); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.OK())
+}
+
+// UpdateCollection - Обновление коллекции
+// @Summary Обновить коллекцию
+// @Description Обновляет название и описание коллекции
+// @Tags collection
+// @Accept json
+// @Produce json
+// @Param id path string true "Id коллекции"
+// @Param json body CollectionRequest true "Данные коллекции"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /collections/{id} [put]
+func (c *Controller) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "UpdateCollection")
+
+	id, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req CollectionRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	collection := &col.CollectionDTO{
+		ID:          id,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+	}
+
+	if err := c.uc.UpdateCollection(collection); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// DeleteCollection - Удаление коллекции
+// @Summary Удалить коллекцию
+// @Description Удаляет коллекцию, фильмы при этом остаются в каталоге
+// @Tags collection
+// @Param id path string true "Id коллекции"
+// @Success 204 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /collections/{id} [delete]
+func (c *Controller) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DeleteCollection")
+
+	id, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteCollection(id); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	render.JSON(w, r, resp.OK())
+}
+
+// SetCollectionFilms - Состав коллекции
+// @Summary Задать фильмы коллекции
+// @Description Заменяет состав коллекции, порядок film_ids задает порядок просмотра. Фильм может входить только в одну коллекцию
+// @Tags collection
+// @Accept json
+// @Produce json
+// @Param id path string true "Id коллекции"
+// @Param json body CollectionFilmsRequest true "FilmId фильмов по порядку"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /collections/{id}/films [put]
+func (c *Controller) SetCollectionFilms(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "SetCollectionFilms")
+
+	id, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req CollectionFilmsRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	if err := c.uc.SetCollectionFilms(id, req.FilmIDs); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// GetRelatedTitles - Связанные фильмы
+// @Summary Получить связанные фильмы
+// @Description Возвращает коллекцию фильма и фильмы, связанные с ним как сиквел, приквел, ремейк или спин-офф. Для обратных связей тип указан со стороны связанного фильма (prequel, original, parent)
+// @Tags collection
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/related [get]
+func (c *Controller) GetRelatedTitles(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetRelatedTitles")
+
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	related, err := c.uc.GetRelatedTitles(filmID)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.RelatedTitles(related))
+}
+
+// AddFilmRelation - Добавление связи между фильмами
+// @Summary Связать фильмы
+// @Description Добавляет связь: related_film_id является relation для фильма (например, его сиквелом)
+// @Tags collection
+// @Accept json
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param json body FilmRelationRequest true "Связь"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/relations [post]
+func (c *Controller) AddFilmRelation(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "AddFilmRelation")
+
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req FilmRelationRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	if err := c.uc.AddFilmRelation(filmID, req.RelatedFilmID, req.Relation); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.OK())
+}
+
+// DeleteFilmRelation - Удаление связи между фильмами
+// @Summary Удалить связь между фильмами
+// @Description Удаляет прямую связь фильма с related_id указанного типа
+// @Tags collection
+// @Param id path string true "FilmId фильма"
+// @Param related_id path string true "FilmId связанного фильма"
+// @Param relation query string true "Тип связи (sequel, prequel, remake, spin_off)"
+// @Success 204 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/relations/{related_id} [delete]
+func (c *Controller) DeleteFilmRelation(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DeleteFilmRelation")
+
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+	relatedFilmID, ok := parseID(w, r, "related_id")
+	if !ok {
+		return
+	}
+
+	relation := r.URL.Query().Get("relation")
+	if err := c.validate.Var(relation, "required,oneof=sequel prequel remake spin_off"); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid relation, expected one of: sequel, prequel, remake, spin_off"))
+		return
+	}
+
+	if err := c.uc.DeleteFilmRelation(filmID, relatedFilmID, relation); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	render.JSON(w, r, resp.OK())
+}
+
+// decode читает и валидирует тело запроса, при ошибке сам отвечает клиенту
+func (c *Controller) decode(w http.ResponseWriter, r *http.Request, log *slog.Logger, req interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		log.Error("failed to decode request body", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
+		return false
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return false
+	}
+
+	return true
+}
+
+func (c *Controller) writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, col.ErrCollectionNotFound) || errors.Is(err, col.ErrFilmNotFound) || errors.Is(err, col.ErrRelationNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, col.ErrSelfRelation):
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, col.ErrCollectionExists) || errors.Is(err, col.ErrFilmInOtherCollection) || errors.Is(err, col.ErrRelationExists):
+		w.WriteHeader(http.StatusConflict)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	default:
+		log.Error("collection request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, col.ErrInternal.Error()))
+	}
+}
+
+func parseID(w http.ResponseWriter, r *http.Request, param string) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, param), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid "+param))
+		return 0, false
+	}
+
+	return uint(id), true
+}
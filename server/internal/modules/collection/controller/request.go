@@ -0,0 +1,16 @@
+package controller
+
+type CollectionRequest struct {
+	Name        string `json:"name" validate:"required,max=200"`
+	Description string `json:"description" validate:"omitempty"`
+}
+
+// CollectionFilmsRequest - фильмы коллекции в порядке просмотра
+type CollectionFilmsRequest struct {
+	FilmIDs []uint `json:"film_ids" validate:"unique,dive,min=1"`
+}
+
+type FilmRelationRequest struct {
+	RelatedFilmID uint   `json:"related_film_id" validate:"required,min=1"`
+	Relation      string `json:"relation" validate:"required,oneof=sequel prequel remake spin_off"`
+}
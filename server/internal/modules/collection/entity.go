@@ -0,0 +1,134 @@
+package collection
+
+import (
+	"net/http"
+	"time"
+)
+
+// Типы связей между фильмами. В film_relations хранятся только прямые связи,
+// обратные выводятся при чтении через InverseRelation.
+const (
+	RelationSequel  = "sequel"
+	RelationPrequel = "prequel"
+	RelationRemake  = "remake"
+	RelationSpinOff = "spin_off"
+
+	RelationOriginal = "original" // обратная к remake
+	RelationParent   = "parent"   // обратная к spin_off
+)
+
+// InverseRelation возвращает связь, видимую со стороны связанного фильма
+func InverseRelation(relation string) string {
+	switch relation {
+	case RelationSequel:
+		return RelationPrequel
+	case RelationPrequel:
+		return RelationSequel
+	case RelationRemake:
+		return RelationOriginal
+	case RelationSpinOff:
+		return RelationParent
+	default:
+		return relation
+	}
+}
+
+type CollectionDTO struct {
+	ID          uint                 `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	CreateAt    time.Time            `json:"create_at"`
+	Films       []*CollectionFilmDTO `json:"films"`
+	Stats       *CollectionStatsDTO  `json:"stats"`
+}
+
+// CollectionFilmDTO - фильм коллекции в порядке просмотра
+type CollectionFilmDTO struct {
+	FilmID       uint      `json:"film_id" gorm:"column:film_id"`
+	Position     int       `json:"position" gorm:"column:position"`
+	Title        string    `json:"title" gorm:"column:title"`
+	PosterURL    string    `json:"poster_url" gorm:"column:poster_url"`
+	ReleaseDate  time.Time `json:"release_date" gorm:"column:release_date"`
+	ContentType  string    `json:"content_type" gorm:"column:content_type"`
+	AvgRating    float64   `json:"avg_rating" gorm:"column:avg_rating"`
+	TotalReviews uint      `json:"total_reviews" gorm:"column:total_reviews"`
+
+	PosterBlurhash string `json:"poster_blurhash" gorm:"column:poster_blurhash"`
+	PosterColor    string `json:"poster_color" gorm:"column:poster_color"`
+}
+
+// CollectionStatsDTO - сводная статистика франшизы по film_stats ее фильмов
+type CollectionStatsDTO struct {
+	CollectionID       uint    `json:"-" gorm:"column:collection_id"`
+	FilmsCount         int     `json:"films_count" gorm:"column:films_count"`
+	AvgRating          float64 `json:"avg_rating" gorm:"column:avg_rating"`             // по всем отзывам франшизы
+	MeanFilmRating     float64 `json:"mean_film_rating" gorm:"column:mean_film_rating"` // среднее рейтингов фильмов с отзывами
+	TotalReviews       uint    `json:"total_reviews" gorm:"column:total_reviews"`
+	CountRatings0_20   uint    `json:"count_ratings_0_20" gorm:"column:count_ratings_0_20"`
+	CountRatings21_40  uint    `json:"count_ratings_21_40" gorm:"column:count_ratings_21_40"`
+	CountRatings41_60  uint    `json:"count_ratings_41_60" gorm:"column:count_ratings_41_60"`
+	CountRatings61_80  uint    `json:"count_ratings_61_80" gorm:"column:count_ratings_61_80"`
+	CountRatings81_100 uint    `json:"count_ratings_81_100" gorm:"column:count_ratings_81_100"`
+	BestFilmID         *uint   `json:"best_film_id" gorm:"column:best_film_id"`
+}
+
+// RelatedFilmDTO - фильм, связанный с данным, и тип связи с его стороны
+type RelatedFilmDTO struct {
+	FilmID      uint      `json:"film_id" gorm:"column:film_id"`
+	Title       string    `json:"title" gorm:"column:title"`
+	PosterURL   string    `json:"poster_url" gorm:"column:poster_url"`
+	ReleaseDate time.Time `json:"release_date" gorm:"column:release_date"`
+	Relation    string    `json:"relation" gorm:"column:relation"`
+
+	PosterBlurhash string `json:"poster_blurhash" gorm:"column:poster_blurhash"`
+	PosterColor    string `json:"poster_color" gorm:"column:poster_color"`
+}
+
+type RelatedTitlesDTO struct {
+	Collection *CollectionDTO    `json:"collection"`
+	Relations  []*RelatedFilmDTO `json:"relations"`
+}
+
+type Controller interface {
+	GetCollections(w http.ResponseWriter, r *http.Request)
+	GetCollection(w http.ResponseWriter, r *http.Request)
+	CreateCollection(w http.ResponseWriter, r *http.Request)
+	UpdateCollection(w http.ResponseWriter, r *http.Request)
+	DeleteCollection(w http.ResponseWriter, r *http.Request)
+	SetCollectionFilms(w http.ResponseWriter, r *http.Request)
+	GetRelatedTitles(w http.ResponseWriter, r *http.Request)
+	AddFilmRelation(w http.ResponseWriter, r *http.Request)
+	DeleteFilmRelation(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	GetCollections() ([]*CollectionDTO, error)
+	GetCollection(id uint) (*CollectionDTO, error)
+	CreateCollection(collection *CollectionDTO) error
+	UpdateCollection(collection *CollectionDTO) error
+	DeleteCollection(id uint) error
+	SetCollectionFilms(id uint, filmIDs []uint) error
+	GetRelatedTitles(filmID uint) (*RelatedTitlesDTO, error)
+	AddFilmRelation(filmID uint, relatedFilmID uint, relation string) error
+	DeleteFilmRelation(filmID uint, relatedFilmID uint, relation string) error
+}
+
+type Repo interface {
+	//DB
+	GetCollections() ([]*CollectionDTO, error)
+	GetCollection(id uint) (*CollectionDTO, error)
+	GetCollectionFilms(id uint) ([]*CollectionFilmDTO, error)
+	GetCollectionStats(ids []uint) (map[uint]*CollectionStatsDTO, error)
+	CreateCollection(collection *CollectionDTO) error
+	UpdateCollection(collection *CollectionDTO) error
+	DeleteCollection(id uint) error
+	SetCollectionFilms(id uint, filmIDs []uint) error
+	GetRelatedFilms(filmID uint) ([]*RelatedFilmDTO, error)
+	AddFilmRelation(filmID uint, relatedFilmID uint, relation string) error
+	DeleteFilmRelation(filmID uint, relatedFilmID uint, relation string) error
+
+	//Cache
+	GetCollectionsFromCache(key string) ([]*CollectionDTO, error)
+	SetCollectionsToCache(key string, collections []*CollectionDTO, ttl time.Duration) error
+	DeleteCollectionsFromCache(keys ...string) error
+}
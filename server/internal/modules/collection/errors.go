@@ -0,0 +1,15 @@
+package collection
+
+import "errors"
+
+var (
+	ErrInternal              = errors.New("internal server error")
+	ErrMissCache             = errors.New("miss cache error")
+	ErrCollectionNotFound    = errors.New("collection not found")
+	ErrCollectionExists      = errors.New("collection already exists")
+	ErrFilmNotFound          = errors.New("film not found")
+	ErrFilmInOtherCollection = errors.New("film already belongs to another collection")
+	ErrRelationExists        = errors.New("relation already exists")
+	ErrRelationNotFound      = errors.New("relation not found")
+	ErrSelfRelation          = errors.New("film can't be related to itself")
+)
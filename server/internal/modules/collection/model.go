@@ -0,0 +1,52 @@
+package collection
+
+import "time"
+
+type Collection struct {
+	CollectionID uint      `gorm:"primaryKey;column:collection_id;autoIncrement"`
+	Name         string    `gorm:"column:name;type:varchar(200);unique;not null"`
+	Description  string    `gorm:"column:description;type:text;not null;default:''"`
+	CreatedAt    time.Time `gorm:"column:create_at"`
+}
+
+func (Collection) TableName() string {
+	return "collections"
+}
+
+type CollectionFilm struct {
+	CollectionID uint `gorm:"primaryKey;column:collection_id"`
+	FilmID       uint `gorm:"primaryKey;column:film_id"`
+	Position     int  `gorm:"column:position;not null"`
+}
+
+func (CollectionFilm) TableName() string {
+	return "collection_films"
+}
+
+type FilmRelation struct {
+	FilmID        uint   `gorm:"primaryKey;column:film_id"`
+	RelatedFilmID uint   `gorm:"primaryKey;column:related_film_id"`
+	RelationType  string `gorm:"primaryKey;column:relation_type;type:varchar(16)"`
+}
+
+func (FilmRelation) TableName() string {
+	return "film_relations"
+}
+
+func (c *Collection) ToDTO() *CollectionDTO {
+	return &CollectionDTO{
+		ID:          c.CollectionID,
+		Name:        c.Name,
+		Description: c.Description,
+		CreateAt:    c.CreatedAt,
+	}
+}
+
+func (c *CollectionDTO) ToModel() *Collection {
+	return &Collection{
+		CollectionID: c.ID,
+		Name:         c.Name,
+		Description:  c.Description,
+		CreatedAt:    c.CreateAt,
+	}
+}
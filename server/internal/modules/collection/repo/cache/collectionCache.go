@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"errors"
+	"server/internal/init/cache"
+	col "server/internal/modules/collection"
+	"time"
+)
+
+type CollectionCache struct {
+	ch          *cache.Cache
+	collections *cache.Typed[[]*col.CollectionDTO]
+}
+
+func NewCollectionCache(ch *cache.Cache) *CollectionCache {
+	return &CollectionCache{
+		ch:          ch,
+		collections: cache.NewTyped[[]*col.CollectionDTO](ch),
+	}
+}
+
+func (c *CollectionCache) SetCollectionsToCache(key string, collections []*col.CollectionDTO, ttl time.Duration) error {
+	return c.collections.Set(key, collections, ttl)
+}
+
+func (c *CollectionCache) GetCollectionsFromCache(key string) ([]*col.CollectionDTO, error) {
+	result, err := c.collections.Get(key)
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, col.ErrMissCache
+	}
+	return result, err
+}
+
+func (c *CollectionCache) DeleteCollectionsFromCache(keys ...string) error {
+	return c.collections.Delete(keys...)
+}
@@ -0,0 +1,94 @@
+package repo
+
+import (
+	col "server/internal/modules/collection"
+	"time"
+)
+
+type CollectionDB interface {
+	GetCollections() ([]*col.CollectionDTO, error)
+	GetCollection(id uint) (*col.CollectionDTO, error)
+	GetCollectionFilms(id uint) ([]*col.CollectionFilmDTO, error)
+	GetCollectionStats(ids []uint) (map[uint]*col.CollectionStatsDTO, error)
+	CreateCollection(collection *col.CollectionDTO) error
+	UpdateCollection(collection *col.CollectionDTO) error
+	DeleteCollection(id uint) error
+	SetCollectionFilms(id uint, filmIDs []uint) error
+	GetRelatedFilms(filmID uint) ([]*col.RelatedFilmDTO, error)
+	AddFilmRelation(filmID uint, relatedFilmID uint, relation string) error
+	DeleteFilmRelation(filmID uint, relatedFilmID uint, relation string) error
+}
+
+type CollectionCache interface {
+	GetCollectionsFromCache(key string) ([]*col.CollectionDTO, error)
+	SetCollectionsToCache(key string, collections []*col.CollectionDTO, ttl time.Duration) error
+	DeleteCollectionsFromCache(keys ...string) error
+}
+
+type Repo struct {
+	db CollectionDB
+	ch CollectionCache
+}
+
+func NewCollectionRepo(db CollectionDB, ch CollectionCache) *Repo {
+	return &Repo{
+		db: db,
+		ch: ch,
+	}
+}
+
+func (r *Repo) GetCollections() ([]*col.CollectionDTO, error) {
+	return r.db.GetCollections()
+}
+
+func (r *Repo) GetCollection(id uint) (*col.CollectionDTO, error) {
+	return r.db.GetCollection(id)
+}
+
+func (r *Repo) GetCollectionFilms(id uint) ([]*col.CollectionFilmDTO, error) {
+	return r.db.GetCollectionFilms(id)
+}
+
+func (r *Repo) GetCollectionStats(ids []uint) (map[uint]*col.CollectionStatsDTO, error) {
+	return r.db.GetCollectionStats(ids)
+}
+
+func (r *Repo) CreateCollection(collection *col.CollectionDTO) error {
+	return r.db.CreateCollection(collection)
+}
+
+func (r *Repo) UpdateCollection(collection *col.CollectionDTO) error {
+	return r.db.UpdateCollection(collection)
+}
+
+func (r *Repo) DeleteCollection(id uint) error {
+	return r.db.DeleteCollection(id)
+}
+
+func (r *Repo) SetCollectionFilms(id uint, filmIDs []uint) error {
+	return r.db.SetCollectionFilms(id, filmIDs)
+}
+
+func (r *Repo) GetRelatedFilms(filmID uint) ([]*col.RelatedFilmDTO, error) {
+	return r.db.GetRelatedFilms(filmID)
+}
+
+func (r *Repo) AddFilmRelation(filmID uint, relatedFilmID uint, relation string) error {
+	return r.db.AddFilmRelation(filmID, relatedFilmID, relation)
+}
+
+func (r *Repo) DeleteFilmRelation(filmID uint, relatedFilmID uint, relation string) error {
+	return r.db.DeleteFilmRelation(filmID, relatedFilmID, relation)
+}
+
+func (r *Repo) GetCollectionsFromCache(key string) ([]*col.CollectionDTO, error) {
+	return r.ch.GetCollectionsFromCache(key)
+}
+
+func (r *Repo) SetCollectionsToCache(key string, collections []*col.CollectionDTO, ttl time.Duration) error {
+	return r.ch.SetCollectionsToCache(key, collections, ttl)
+}
+
+func (r *Repo) DeleteCollectionsFromCache(keys ...string) error {
+	return r.ch.DeleteCollectionsFromCache(keys...)
+}
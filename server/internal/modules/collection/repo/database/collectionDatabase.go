@@ -0,0 +1,276 @@
+package database
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"log/slog"
+	col "server/internal/modules/collection"
+)
+
+type CollectionDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewCollectionDatabase(db *gorm.DB, log *slog.Logger) *CollectionDatabase {
+	return &CollectionDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+func (db *CollectionDatabase) GetCollections() ([]*col.CollectionDTO, error) {
+	var collections []*col.Collection
+	if err := db.db.Order("name").Find(&collections).Error; err != nil {
+		db.log.Error("failed to get collections", "error", err)
+		return nil, col.ErrInternal
+	}
+
+	dtos := make([]*col.CollectionDTO, 0, len(collections))
+	for _, collection := range collections {
+		dtos = append(dtos, collection.ToDTO())
+	}
+
+	return dtos, nil
+}
+
+func (db *CollectionDatabase) GetCollection(id uint) (*col.CollectionDTO, error) {
+	var collection col.Collection
+	if err := db.db.First(&collection, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, col.ErrCollectionNotFound
+		}
+		db.log.Error("failed to get collection", "error", err, "id", id)
+		return nil, col.ErrInternal
+	}
+
+	return collection.ToDTO(), nil
+}
+
+// GetCollectionFilms возвращает фильмы коллекции по порядку, при равных позициях - по дате выхода
+func (db *CollectionDatabase) GetCollectionFilms(id uint) ([]*col.CollectionFilmDTO, error) {
+	var films []*col.CollectionFilmDTO
+
+	err := db.db.Raw(`
+		SELECT cf.film_id, cf.position, f.title, f.poster_url, f.poster_blurhash, f.poster_color, f.release_date, f.content_type,
+		       COALESCE(fs.avg_rating, 0) AS avg_rating,
+		       COALESCE(fs.total_count_reviews, 0) AS total_reviews
+		FROM collection_films cf
+		JOIN films f ON f.film_id = cf.film_id AND f.deleted_at IS NULL
+		LEFT JOIN film_stats fs ON fs.film_id = cf.film_id
+		WHERE cf.collection_id = ?
+		ORDER BY cf.position, f.release_date`, id).Scan(&films).Error
+	if err != nil {
+		db.log.Error("failed to get collection films", "error", err, "id", id)
+		return nil, col.ErrInternal
+	}
+
+	return films, nil
+}
+
+// GetCollectionStats сводит film_stats фильмов каждой коллекции. avg_rating взвешен
+// по числу отзывов, mean_film_rating - простое среднее рейтингов фильмов, у которых есть отзывы.
+func (db *CollectionDatabase) GetCollectionStats(ids []uint) (map[uint]*col.CollectionStatsDTO, error) {
+	stats := make(map[uint]*col.CollectionStatsDTO, len(ids))
+	if len(ids) == 0 {
+		return stats, nil
+	}
+
+	var rows []*col.CollectionStatsDTO
+	err := db.db.Raw(`
+		SELECT cf.collection_id,
+		       COUNT(*) AS films_count,
+		       COALESCE(ROUND(SUM(fs.avg_rating * fs.total_count_reviews) / NULLIF(SUM(fs.total_count_reviews), 0)), 0) AS avg_rating,
+		       COALESCE(ROUND(AVG(fs.avg_rating) FILTER (WHERE fs.total_count_reviews > 0)), 0) AS mean_film_rating,
+		       COALESCE(SUM(fs.total_count_reviews), 0) AS total_reviews,
+		       COALESCE(SUM(fs.count_0_20), 0) AS count_ratings_0_20,
+		       COALESCE(SUM(fs.count_21_40), 0) AS count_ratings_21_40,
+		       COALESCE(SUM(fs.count_41_60), 0) AS count_ratings_41_60,
+		       COALESCE(SUM(fs.count_61_80), 0) AS count_ratings_61_80,
+		       COALESCE(SUM(fs.count_81_100), 0) AS count_ratings_81_100,
+		       (ARRAY_AGG(cf.film_id ORDER BY fs.avg_rating DESC, fs.total_count_reviews DESC)
+		           FILTER (WHERE fs.total_count_reviews > 0))[1] AS best_film_id
+		FROM collection_films cf
+		LEFT JOIN film_stats fs ON fs.film_id = cf.film_id
+		WHERE cf.collection_id IN ?
+		GROUP BY cf.collection_id`, ids).Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get collection stats", "error", err)
+		return nil, col.ErrInternal
+	}
+
+	for _, row := range rows {
+		stats[row.CollectionID] = row
+	}
+
+	return stats, nil
+}
+
+func (db *CollectionDatabase) CreateCollection(collection *col.CollectionDTO) error {
+	collectionModel := collection.ToModel()
+	if err := db.db.Create(collectionModel).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return col.ErrCollectionExists
+		}
+		db.log.Error("failed to create collection", "error", err)
+		return col.ErrInternal
+	}
+	collection.ID = collectionModel.CollectionID
+
+	return nil
+}
+
+func (db *CollectionDatabase) UpdateCollection(collection *col.CollectionDTO) error {
+	result := db.db.Model(&col.Collection{}).
+		Where("collection_id = ?", collection.ID).
+		Updates(map[string]interface{}{
+			"name":        collection.Name,
+			"description": collection.Description,
+		})
+	if result.Error != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(result.Error, &pgErr) && pgErr.Code == "23505" {
+			return col.ErrCollectionExists
+		}
+		db.log.Error("failed to update collection", "error", result.Error)
+		return col.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return col.ErrCollectionNotFound
+	}
+
+	return nil
+}
+
+func (db *CollectionDatabase) DeleteCollection(id uint) error {
+	result := db.db.Delete(&col.Collection{}, id)
+	if result.Error != nil {
+		db.log.Error("failed to delete collection", "error", result.Error, "id", id)
+		return col.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return col.ErrCollectionNotFound
+	}
+
+	return nil
+}
+
+// SetCollectionFilms заменяет состав коллекции, позиция фильма - его индекс в filmIDs, начиная с 1
+func (db *CollectionDatabase) SetCollectionFilms(id uint, filmIDs []uint) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&col.CollectionFilm{}).Error; err != nil {
+			db.log.Error("failed to clear collection films", "error", err, "id", id)
+			return col.ErrInternal
+		}
+
+		if len(filmIDs) == 0 {
+			return nil
+		}
+
+		members := make([]col.CollectionFilm, 0, len(filmIDs))
+		for i, filmID := range filmIDs {
+			members = append(members, col.CollectionFilm{
+				CollectionID: id,
+				FilmID:       filmID,
+				Position:     i + 1,
+			})
+		}
+
+		if err := tx.Create(&members).Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.Code {
+				case "23505":
+					return col.ErrFilmInOtherCollection
+				case "23503":
+					if pgErr.ConstraintName == "fk_collection" {
+						return col.ErrCollectionNotFound
+					}
+					return col.ErrFilmNotFound
+				}
+			}
+			db.log.Error("failed to set collection films", "error", err, "id", id)
+			return col.ErrInternal
+		}
+
+		return nil
+	})
+}
+
+// GetRelatedFilms возвращает связанные фильмы в обе стороны: прямые связи как есть,
+// обратные - с типом связи со стороны связанного фильма
+func (db *CollectionDatabase) GetRelatedFilms(filmID uint) ([]*col.RelatedFilmDTO, error) {
+	var related []*col.RelatedFilmDTO
+
+	err := db.db.Raw(`
+		SELECT f.film_id, f.title, f.poster_url, f.poster_blurhash, f.poster_color, f.release_date, r.relation
+		FROM (
+			SELECT related_film_id AS film_id, relation_type AS relation
+			FROM film_relations
+			WHERE film_id = ?
+			UNION
+			SELECT film_id,
+			       CASE relation_type
+			           WHEN ? THEN ?
+			           WHEN ? THEN ?
+			           WHEN ? THEN ?
+			           WHEN ? THEN ?
+			       END AS relation
+			FROM film_relations
+			WHERE related_film_id = ?
+		) r
+		JOIN films f ON f.film_id = r.film_id AND f.deleted_at IS NULL
+		ORDER BY f.release_date`,
+		filmID,
+		col.RelationSequel, col.InverseRelation(col.RelationSequel),
+		col.RelationPrequel, col.InverseRelation(col.RelationPrequel),
+		col.RelationRemake, col.InverseRelation(col.RelationRemake),
+		col.RelationSpinOff, col.InverseRelation(col.RelationSpinOff),
+		filmID).Scan(&related).Error
+	if err != nil {
+		db.log.Error("failed to get related films", "error", err, "filmID", filmID)
+		return nil, col.ErrInternal
+	}
+
+	return related, nil
+}
+
+func (db *CollectionDatabase) AddFilmRelation(filmID uint, relatedFilmID uint, relation string) error {
+	err := db.db.Create(&col.FilmRelation{
+		FilmID:        filmID,
+		RelatedFilmID: relatedFilmID,
+		RelationType:  relation,
+	}).Error
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return col.ErrRelationExists
+			case "23503":
+				return col.ErrFilmNotFound
+			}
+		}
+		db.log.Error("failed to add film relation", "error", err, "filmID", filmID)
+		return col.ErrInternal
+	}
+
+	return nil
+}
+
+func (db *CollectionDatabase) DeleteFilmRelation(filmID uint, relatedFilmID uint, relation string) error {
+	result := db.db.
+		Where("film_id = ? AND related_film_id = ? AND relation_type = ?", filmID, relatedFilmID, relation).
+		Delete(&col.FilmRelation{})
+	if result.Error != nil {
+		db.log.Error("failed to delete film relation", "error", result.Error, "filmID", filmID)
+		return col.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return col.ErrRelationNotFound
+	}
+
+	return nil
+}
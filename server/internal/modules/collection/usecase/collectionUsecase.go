@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	col "server/internal/modules/collection"
+	f "server/internal/modules/film"
+	"time"
+)
+
+const collectionsCacheKey = "collections"
+
+// FilmService - то, что нужно модулю коллекций от фильмов
+type FilmService interface {
+	GetFilmByID(id uint) (*f.FilmDTO, error)
+	InvalidateFilmCache(id uint)
+}
+
+type CollectionUseCase struct {
+	log   *slog.Logger
+	rp    col.Repo
+	films FilmService
+}
+
+func NewCollectionUseCase(log *slog.Logger, rp col.Repo, films FilmService) *CollectionUseCase {
+	return &CollectionUseCase{
+		log:   log,
+		rp:    rp,
+		films: films,
+	}
+}
+
+// GetCollections возвращает все коллекции со сводной статистикой, без списка фильмов
+func (uc *CollectionUseCase) GetCollections() ([]*col.CollectionDTO, error) {
+	if collections, err := uc.rp.GetCollectionsFromCache(collectionsCacheKey); err == nil {
+		return collections, nil
+	}
+
+	collections, err := uc.rp.GetCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(collections))
+	for _, collection := range collections {
+		ids = append(ids, collection.ID)
+	}
+	stats, err := uc.rp.GetCollectionStats(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, collection := range collections {
+		collection.Stats = statsOrEmpty(stats[collection.ID])
+	}
+
+	if err := uc.rp.SetCollectionsToCache(collectionsCacheKey, collections, time.Minute*10); err != nil {
+		uc.log.Error("failed to cache collections", "error", err)
+	}
+
+	return collections, nil
+}
+
+// GetCollection возвращает коллекцию с фильмами по порядку и статистикой франшизы
+func (uc *CollectionUseCase) GetCollection(id uint) (*col.CollectionDTO, error) {
+	cacheKey := collectionCacheKey(id)
+	if collections, err := uc.rp.GetCollectionsFromCache(cacheKey); err == nil {
+		return collections[0], nil
+	}
+
+	collection, err := uc.rp.GetCollection(id)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.Films, err = uc.rp.GetCollectionFilms(id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.rp.GetCollectionStats([]uint{id})
+	if err != nil {
+		return nil, err
+	}
+	collection.Stats = statsOrEmpty(stats[id])
+
+	if err := uc.rp.SetCollectionsToCache(cacheKey, []*col.CollectionDTO{collection}, time.Minute*10); err != nil {
+		uc.log.Error("failed to cache collection", "error", err)
+	}
+
+	return collection, nil
+}
+
+func (uc *CollectionUseCase) CreateCollection(collection *col.CollectionDTO) error {
+	if err := uc.rp.CreateCollection(collection); err != nil {
+		return err
+	}
+
+	uc.invalidate()
+	return nil
+}
+
+func (uc *CollectionUseCase) UpdateCollection(collection *col.CollectionDTO) error {
+	films, err := uc.rp.GetCollectionFilms(collection.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.rp.UpdateCollection(collection); err != nil {
+		return err
+	}
+
+	// Название коллекции есть в FilmDTO ее фильмов
+	uc.invalidate(collection.ID)
+	uc.invalidateFilms(films)
+	return nil
+}
+
+func (uc *CollectionUseCase) DeleteCollection(id uint) error {
+	films, err := uc.rp.GetCollectionFilms(id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.rp.DeleteCollection(id); err != nil {
+		return err
+	}
+
+	uc.invalidate(id)
+	uc.invalidateFilms(films)
+	return nil
+}
+
+// SetCollectionFilms задает состав и порядок фильмов коллекции
+func (uc *CollectionUseCase) SetCollectionFilms(id uint, filmIDs []uint) error {
+	previous, err := uc.rp.GetCollectionFilms(id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.rp.SetCollectionFilms(id, filmIDs); err != nil {
+		return err
+	}
+
+	uc.invalidate(id)
+	uc.invalidateFilms(previous)
+	for _, filmID := range filmIDs {
+		uc.films.InvalidateFilmCache(filmID)
+	}
+	return nil
+}
+
+// GetRelatedTitles возвращает коллекцию фильма и фильмы, связанные с ним напрямую
+func (uc *CollectionUseCase) GetRelatedTitles(filmID uint) (*col.RelatedTitlesDTO, error) {
+	film, err := uc.films.GetFilmByID(filmID)
+	if err != nil {
+		if errors.Is(err, f.ErrFilmNotFound) {
+			return nil, col.ErrFilmNotFound
+		}
+		return nil, err
+	}
+
+	related := &col.RelatedTitlesDTO{}
+
+	if film.Collection != nil {
+		related.Collection, err = uc.GetCollection(film.Collection.ID)
+		if err != nil && !errors.Is(err, col.ErrCollectionNotFound) {
+			return nil, err
+		}
+	}
+
+	related.Relations, err = uc.rp.GetRelatedFilms(filmID)
+	if err != nil {
+		return nil, err
+	}
+
+	return related, nil
+}
+
+func (uc *CollectionUseCase) AddFilmRelation(filmID uint, relatedFilmID uint, relation string) error {
+	if filmID == relatedFilmID {
+		return col.ErrSelfRelation
+	}
+
+	return uc.rp.AddFilmRelation(filmID, relatedFilmID, relation)
+}
+
+func (uc *CollectionUseCase) DeleteFilmRelation(filmID uint, relatedFilmID uint, relation string) error {
+	return uc.rp.DeleteFilmRelation(filmID, relatedFilmID, relation)
+}
+
+// invalidate сбрасывает список коллекций и кэш указанных коллекций
+func (uc *CollectionUseCase) invalidate(ids ...uint) {
+	keys := []string{collectionsCacheKey}
+	for _, id := range ids {
+		keys = append(keys, collectionCacheKey(id))
+	}
+
+	if err := uc.rp.DeleteCollectionsFromCache(keys...); err != nil {
+		uc.log.Error("failed to delete collections from cache", "error", err)
+	}
+}
+
+func (uc *CollectionUseCase) invalidateFilms(films []*col.CollectionFilmDTO) {
+	for _, film := range films {
+		uc.films.InvalidateFilmCache(film.FilmID)
+	}
+}
+
+func statsOrEmpty(stats *col.CollectionStatsDTO) *col.CollectionStatsDTO {
+	if stats == nil {
+		return &col.CollectionStatsDTO{}
+	}
+	return stats
+}
+
+func collectionCacheKey(id uint) string {
+	return fmt.Sprintf("collection:%d", id)
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
@@ -10,7 +11,12 @@ import (
 	"log/slog"
 	"net/http"
 	f "server/internal/modules/film"
+	avatarManager "server/pkg/lib/avatarMenager"
+	"server/pkg/lib/optimistic"
+	"server/pkg/lib/patch"
 	resp "server/pkg/lib/response"
+	"server/pkg/lib/slug"
+	"server/pkg/middleware/locale"
 	"strconv"
 	"strings"
 	"time"
@@ -23,15 +29,21 @@ type Controller struct {
 }
 
 func NewFilmController(log *slog.Logger, filmUseCase f.UseCase) f.Controller {
-	validation := validator.New()
-	validation.RegisterValidation("runtime_format", validateRuntimeFormat)
 	return &Controller{
 		filmUseCase: filmUseCase,
 		log:         log,
-		validate:    validation,
+		validate:    newValidator(),
 	}
 }
 
+func newValidator() *validator.Validate {
+	validation := validator.New()
+	validation.RegisterValidation("runtime_format", validateRuntimeFormat)
+	validation.RegisterValidation("imdb_id", validateImdbID)
+	patch.RegisterValidation(validation, patch.Field[[]CreditRequest]{}, patch.Field[PatchExternalIDsRequest]{})
+	return validation
+}
+
 // GetFilmByID - Получение фильма по FilmId
 // @Summary Получить фильм по FilmId
 // @Description Возвращает информацию о фильме по его FilmId
@@ -48,7 +60,7 @@ func (c *Controller) GetFilmByID(w http.ResponseWriter, r *http.Request) {
 	id, err := strToUint(chi.URLParam(r, "id"))
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid id"))
+		render.JSON(w, r, resp.Error(r, "invalid id"))
 		return
 	}
 
@@ -57,15 +69,18 @@ func (c *Controller) GetFilmByID(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			w.WriteHeader(http.StatusNotFound)
-			render.JSON(w, r, resp.Error(f.ErrFilmNotFound.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrFilmNotFound.Error()))
 		default:
 			log.Error("failed get film", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(f.ErrInternal.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrInternal.Error()))
 		}
 		return
 	}
 
+	c.filmUseCase.LocalizeFilms([]*f.FilmDTO{film}, locale.FromRequest(r))
+
+	optimistic.SetETag(w, film.Version)
 	w.WriteHeader(http.StatusOK)
 	render.JSON(w, r, resp.Films(film))
 	return
@@ -73,12 +88,13 @@ func (c *Controller) GetFilmByID(w http.ResponseWriter, r *http.Request) {
 
 // CreateFilm - Создание нового фильма
 // @Summary Создать новый фильм
-// @Description Создает новый фильм с указанными параметрами
+// @Description Создает новый фильм с указанными параметрами. Большой постер лучше загрузить напрямую в S3
+// @Description через /uploads (purpose film_poster) после создания фильма
 // @Tags film
 // @Accept multipart/form-data
 // @Produce json
 // @Param data formData string true "Данные фильма в формате JSON"
-// @Param poster formData file false "Постер фильма"
+// @Param poster formData file false "Постер фильма, не меньше 400x600"
 // @Success 201 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 409 {object} response.Response
@@ -89,14 +105,14 @@ func (c *Controller) CreateFilm(w http.ResponseWriter, r *http.Request) {
 
 	if err := r.ParseMultipartForm(5 << 20); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("unable to parse form"))
+		render.JSON(w, r, resp.Error(r, "unable to parse form"))
 		return
 	}
 
 	jsonData := r.FormValue("data")
 	if jsonData == "" {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("missing JSON data"))
+		render.JSON(w, r, resp.Error(r, "missing JSON data"))
 		return
 	}
 
@@ -104,13 +120,13 @@ func (c *Controller) CreateFilm(w http.ResponseWriter, r *http.Request) {
 	if err := json.Unmarshal([]byte(jsonData), &req); err != nil {
 		log.Error("failed to unmarshal json", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid JSON data"))
+		render.JSON(w, r, resp.Error(r, "invalid JSON data"))
 		return
 	}
 
 	if err := c.validate.Struct(req); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+		render.JSON(w, r, resp.ValidationError(r, err))
 		return
 	}
 
@@ -120,14 +136,14 @@ func (c *Controller) CreateFilm(w http.ResponseWriter, r *http.Request) {
 		log.Info("file is missing")
 		file = nil
 	case err != nil:
-		log.Error("failed to get file from form", err)
+		log.Error("failed to get file from form", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("failed to get file from form"))
+		render.JSON(w, r, resp.Error(r, "failed to get file from form"))
 	default:
 		defer func() {
 			if file != nil {
 				if err := file.Close(); err != nil {
-					log.Error("failed to close avatar file", err)
+					log.Error("failed to close avatar file", "error", err)
 				}
 			}
 		}()
@@ -136,7 +152,7 @@ func (c *Controller) CreateFilm(w http.ResponseWriter, r *http.Request) {
 	releaseDate, err := time.Parse("2006-01-02", req.ReleaseDate)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid release_date"))
+		render.JSON(w, r, resp.Error(r, "invalid release_date"))
 		return
 	}
 
@@ -145,29 +161,32 @@ func (c *Controller) CreateFilm(w http.ResponseWriter, r *http.Request) {
 		Synopsis:    req.Synopsis,
 		ReleaseDate: releaseDate,
 		Runtime:     req.Runtime,
-		Producer:    req.Producer,
 		GenreIDs:    req.GenreIDs,
-		ActorIDs:    req.ActorIDs,
+		Credits:     toCredits(req.Credits),
 		CreateAt:    time.Now(),
 	}
+	applyMetadata(filmDTO, &req)
 
-	if err := c.filmUseCase.CreateFilm(filmDTO, &file); err != nil {
+	if err := c.filmUseCase.CreateFilm(r.Context(), filmDTO, &file); err != nil {
 		switch {
 		case errors.Is(err, f.ErrInvalidFilmData):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(f.ErrInvalidFilmData.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrInvalidFilmData.Error()))
 		case errors.Is(err, f.ErrFilmAlreadyExists):
 			w.WriteHeader(http.StatusConflict)
-			render.JSON(w, r, resp.Error(f.ErrFilmAlreadyExists.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrFilmAlreadyExists.Error()))
 		case errors.Is(err, f.ErrGenreNotFound):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(f.ErrGenreNotFound.Error()))
-		case errors.Is(err, f.ErrActorNotFound):
+			render.JSON(w, r, resp.Error(r, f.ErrGenreNotFound.Error()))
+		case errors.Is(err, f.ErrPersonNotFound):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(f.ErrActorNotFound.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrPersonNotFound.Error()))
+		case errors.Is(err, avatarManager.ErrInvalidTypePoster) || errors.Is(err, avatarManager.ErrInvalidResolutionPoster):
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, err.Error()))
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(f.ErrInternal.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrInternal.Error()))
 		}
 		return
 	}
@@ -185,10 +204,13 @@ func (c *Controller) CreateFilm(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "FilmId фильма"
 // @Param data formData string true "Данные фильма в формате JSON"
-// @Param poster formData file false "Постер фильма"
+// @Param poster formData file false "Постер фильма, не меньше 400x600"
+// @Param If-Match header string false "Версия фильма, на которой основано изменение, например \"3\". Без заголовка берется поле version"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /films/{id} [put]
 func (c *Controller) UpdateFilm(w http.ResponseWriter, r *http.Request) {
@@ -197,33 +219,45 @@ func (c *Controller) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 	id, err := strToUint(chi.URLParam(r, "id"))
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid id"))
+		render.JSON(w, r, resp.Error(r, "invalid id"))
 		return
 	}
 
 	if err := r.ParseMultipartForm(5 << 20); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("unable to parse form"))
+		render.JSON(w, r, resp.Error(r, "unable to parse form"))
 		return
 	}
 
 	jsonData := r.FormValue("data")
 	if jsonData == "" {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("missing JSON data"))
+		render.JSON(w, r, resp.Error(r, "missing JSON data"))
 		return
 	}
 
 	var req CreateFilmRequest
 	if err := json.Unmarshal([]byte(jsonData), &req); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid JSON data"))
+		render.JSON(w, r, resp.Error(r, "invalid JSON data"))
 		return
 	}
 
 	if err := c.validate.Struct(req); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+
+	version, err := optimistic.Expected(r, req.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, optimistic.ErrVersionRequired):
+			w.WriteHeader(http.StatusPreconditionRequired)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		render.JSON(w, r, resp.Error(r, err.Error()))
 		return
 	}
 
@@ -233,14 +267,14 @@ func (c *Controller) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 		log.Info("file is missing")
 		file = nil
 	case err != nil:
-		log.Error("failed to get file from form", err)
+		log.Error("failed to get file from form", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("failed to get file from form"))
+		render.JSON(w, r, resp.Error(r, "failed to get file from form"))
 	default:
 		defer func() {
 			if file != nil {
 				if err := file.Close(); err != nil {
-					log.Error("failed to close poster file", err)
+					log.Error("failed to close poster file", "error", err)
 				}
 			}
 		}()
@@ -249,7 +283,7 @@ func (c *Controller) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 	releaseDate, err := time.Parse("2006-01-02", req.ReleaseDate)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid release_date"))
+		render.JSON(w, r, resp.Error(r, "invalid release_date"))
 		return
 	}
 
@@ -259,28 +293,135 @@ func (c *Controller) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 		Synopsis:     req.Synopsis,
 		ReleaseDate:  releaseDate,
 		Runtime:      req.Runtime,
-		Producer:     req.Producer,
 		GenreIDs:     req.GenreIDs,
-		ActorIDs:     req.ActorIDs,
+		Credits:      toCredits(req.Credits),
 		RemovePoster: req.RemovePoster,
+		Version:      version,
 	}
+	applyMetadata(filmDTO, &req)
 
-	if err := c.filmUseCase.UpdateFilm(filmDTO, &file); err != nil {
+	if err := c.filmUseCase.UpdateFilm(r.Context(), filmDTO, &file); err != nil {
 		switch {
+		case errors.Is(err, optimistic.ErrConflict):
+			// в ответе текущее состояние, чтобы клиент мог показать расхождение и повторить изменение
+			current, getErr := c.filmUseCase.GetFilmByID(id)
+			if getErr != nil {
+				log.Error("failed to get film after conflict", "error", getErr)
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error(r, f.ErrInternal.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Conflict(r, err.Error(), resp.Films(current).Data))
 		case errors.Is(err, f.ErrFilmNotFound) || errors.Is(err, f.ErrInvalidFilmData) || errors.Is(err, f.ErrGenreNotFound) ||
-			errors.Is(err, f.ErrActorNotFound) || errors.Is(err, f.ErrFilmPosterNotFound):
+			errors.Is(err, f.ErrPersonNotFound) || errors.Is(err, f.ErrFilmPosterNotFound):
 			w.WriteHeader(http.StatusNotFound)
-			render.JSON(w, r, resp.Error(err.Error()))
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		case errors.Is(err, avatarManager.ErrInvalidTypePoster) || errors.Is(err, avatarManager.ErrInvalidResolutionPoster):
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, err.Error()))
 		default:
 			log.Error("failed to update film", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(f.ErrInternal.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrInternal.Error()))
 		}
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	render.JSON(w, r, resp.OK())
+	render.JSON(w, r, resp.Version(filmDTO.Version))
+	return
+}
+
+// PatchFilm - Частичное изменение фильма
+// @Summary Частично изменить фильм
+// @Description Меняет только переданные поля (JSON Merge Patch, RFC 7396), null очищает поле.
+// @Description genre_ids и credits заменяют списки целиком, add_genre_ids, remove_genre_ids, add_credits и remove_credits меняют отдельные связи.
+// @Description Возвращает фильм целиком с новой версией
+// @Tags film
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param json body PatchFilmRequest true "Изменяемые поля"
+// @Param If-Match header string false "Версия фильма, на которой основано изменение, например \"3\". Без заголовка берется поле version"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 415 {object} response.Response
+// @Failure 428 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id} [patch]
+func (c *Controller) PatchFilm(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "PatchFilm")
+
+	id, err := strToUint(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid id"))
+		return
+	}
+
+	var req PatchFilmRequest
+	if err := patch.Decode(r, &req); err != nil {
+		switch {
+		case errors.Is(err, patch.ErrUnsupportedContentType):
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		render.JSON(w, r, resp.Error(r, err.Error()))
+		return
+	}
+
+	if err := validatePatch(c.validate, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+
+	version, err := optimistic.Expected(r, req.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, optimistic.ErrVersionRequired):
+			w.WriteHeader(http.StatusPreconditionRequired)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		render.JSON(w, r, resp.Error(r, err.Error()))
+		return
+	}
+
+	filmPatch := toFilmPatch(id, &req)
+	filmPatch.Version = version
+
+	film, err := c.filmUseCase.PatchFilm(r.Context(), filmPatch)
+	if err != nil {
+		switch {
+		case errors.Is(err, optimistic.ErrConflict):
+			// в ответе текущее состояние, чтобы клиент мог показать расхождение и повторить изменение
+			current, getErr := c.filmUseCase.GetFilmByID(id)
+			if getErr != nil {
+				log.Error("failed to get film after conflict", "error", getErr)
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error(r, f.ErrInternal.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Conflict(r, err.Error(), resp.Films(current).Data))
+		case errors.Is(err, f.ErrFilmNotFound) || errors.Is(err, f.ErrGenreNotFound) || errors.Is(err, f.ErrPersonNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		default:
+			log.Error("failed to patch film", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, f.ErrInternal.Error()))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Films(film))
 	return
 }
 
@@ -299,19 +440,19 @@ func (c *Controller) DeleteFilm(w http.ResponseWriter, r *http.Request) {
 	id, err := strToUint(chi.URLParam(r, "id"))
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid id"))
+		render.JSON(w, r, resp.Error(r, "invalid id"))
 		return
 	}
 
-	if err := c.filmUseCase.DeleteFilm(id); err != nil {
+	if err := c.filmUseCase.DeleteFilm(r.Context(), id); err != nil {
 		switch {
 		case errors.Is(err, f.ErrFilmNotFound):
 			w.WriteHeader(http.StatusNotFound)
-			render.JSON(w, r, resp.Error(f.ErrFilmNotFound.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrFilmNotFound.Error()))
 		default:
 			log.Error("failed to delete film", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(f.ErrInternal.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrInternal.Error()))
 		}
 		return
 	}
@@ -336,24 +477,28 @@ func (c *Controller) SearchFilms(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("query")
 	if query == "" {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("query parameter is required"))
+		render.JSON(w, r, resp.Error(r, "query parameter is required"))
 		return
 	}
 
-	films, err := c.filmUseCase.SearchFilms(query)
+	lang := locale.FromRequest(r)
+
+	films, err := c.filmUseCase.SearchFilms(query, lang)
 	if err != nil {
 		switch {
 		case errors.Is(err, f.ErrFilmSearchFailed):
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(f.ErrFilmSearchFailed.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrFilmSearchFailed.Error()))
 		default:
 			log.Error("failed to search films", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(f.ErrInternal.Error()))
+			render.JSON(w, r, resp.Error(r, f.ErrInternal.Error()))
 		}
 		return
 	}
 
+	c.filmUseCase.LocalizeFilms(films, lang)
+
 	w.WriteHeader(http.StatusOK)
 	render.JSON(w, r, resp.Films(films))
 	return
@@ -361,17 +506,34 @@ func (c *Controller) SearchFilms(w http.ResponseWriter, r *http.Request) {
 
 // GetFilms - Получение списка фильмов с фильтрацией и пагинацией
 // @Summary Получить список фильмов с фильтрацией и пагинацией
-// @Description Возвращает список фильмов с возможностью фильтрации по жанрам, актерам, продюсеру, рейтингу, дате выпуска, длительности и сортировке
+// @Description Возвращает список фильмов с возможностью фильтрации по жанрам, актерам, режиссерам, продюсеру, рейтингу, дате выпуска, длительности, странам, языкам, возрастному рейтингу, бюджету, сборам, внешним идентификаторам, типу контента и сортировке
 // @Tags film
 // @Param genre_ids query []uint false "Список FilmId жанров"
-// @Param actor_ids query []uint false "Список FilmId актеров"
-// @Param producer query string false "Продюсер"
+// @Param actor_ids query []uint false "Список Id персон в актерском составе"
+// @Param director_ids query []uint false "Список Id режиссеров"
+// @Param director query string false "Имя режиссера (поиск по подстроке)"
+// @Param producer query string false "Имя продюсера (поиск по подстроке)"
 // @Param min_rating query float64 false "Минимальный рейтинг"
 // @Param max_rating query float64 false "Максимальный рейтинг"
 // @Param min_date query string false "Минимальная дата выпуска (формат: 2006-01-02)"
 // @Param max_date query string false "Максимальная дата выпуска (формат: 2006-01-02)"
 // @Param min_duration query string false "Минимальная длительность (пример: 2h30m, 90m)"
 // @Param max_duration query string false "Максимальная длительность (пример: 2h30m, 90m)"
+// @Param original_title query string false "Оригинальное или альтернативное название (поиск по подстроке)"
+// @Param tagline query string false "Слоган (поиск по подстроке)"
+// @Param countries query []string false "Страны производства, ISO 3166-1 alpha-2 (пример: US,RU)"
+// @Param languages query []string false "Языки, ISO 639-1 (пример: en,ru)"
+// @Param age_ratings query []string false "Возрастные рейтинги (пример: PG-13,18+)"
+// @Param min_budget query int false "Минимальный бюджет"
+// @Param max_budget query int false "Максимальный бюджет"
+// @Param min_box_office query int false "Минимальные сборы"
+// @Param max_box_office query int false "Максимальные сборы"
+// @Param currency query string false "Валюта бюджета и сборов, ISO 4217"
+// @Param imdb_id query string false "IMDb ID (пример: tt0111161)"
+// @Param kinopoisk_id query string false "Кинопоиск ID"
+// @Param tmdb_id query string false "TMDB ID"
+// @Param type query []string false "Тип контента: movie, series, mini_series (пример: series,mini_series)"
+// @Param tags query []string false "Slug тегов, фильм должен быть отмечен всеми (пример: time-travel,heist)"
 // @Param sort_by query string false "Поле для сортировки (rating, release_date, runtime)"
 // @Param order query string false "Порядок сортировки (asc, desc)"
 // @Param page query int false "Номер страницы"
@@ -389,7 +551,7 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 		ids, err := strToUintSlice(genreIDs)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid genre_ids format"))
+			render.JSON(w, r, resp.Error(r, "invalid genre_ids format"))
 			return
 		}
 		filters.GenreIDs = ids
@@ -399,12 +561,26 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 		ids, err := strToUintSlice(actorIDs)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid actor_ids format"))
+			render.JSON(w, r, resp.Error(r, "invalid actor_ids format"))
 			return
 		}
 		filters.ActorIDs = ids
 	}
 
+	if directorIDs := r.URL.Query().Get("director_ids"); directorIDs != "" {
+		ids, err := strToUintSlice(directorIDs)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid director_ids format"))
+			return
+		}
+		filters.DirectorIDs = ids
+	}
+
+	if director := r.URL.Query().Get("director"); director != "" {
+		filters.Director = director
+	}
+
 	if producer := r.URL.Query().Get("producer"); producer != "" {
 		filters.Producer = producer
 	}
@@ -413,7 +589,7 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 		rating, err := strToFloat(minRating)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid min_rating format"))
+			render.JSON(w, r, resp.Error(r, "invalid min_rating format"))
 			return
 		}
 		filters.MinRating = rating
@@ -423,7 +599,7 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 		rating, err := strToFloat(maxRating)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid max_rating format"))
+			render.JSON(w, r, resp.Error(r, "invalid max_rating format"))
 			return
 		}
 		filters.MaxRating = rating
@@ -433,7 +609,7 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 		date, err := strToTime(minDate)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid min_date format, expected RFC3339"))
+			render.JSON(w, r, resp.Error(r, "invalid min_date format, expected RFC3339"))
 			return
 		}
 		filters.MinDate = date
@@ -443,7 +619,7 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 		date, err := strToTime(maxDate)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid max_date format, expected RFC3339"))
+			render.JSON(w, r, resp.Error(r, "invalid max_date format, expected RFC3339"))
 			return
 		}
 		filters.MaxDate = date
@@ -453,7 +629,7 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 		duration, err := time.ParseDuration(minDuration)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid min_duration format, examples: 2h30m, 90m"))
+			render.JSON(w, r, resp.Error(r, "invalid min_duration format, examples: 2h30m, 90m"))
 			return
 		}
 		filters.MinDuration = duration
@@ -463,17 +639,99 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 		duration, err := time.ParseDuration(maxDuration)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid max_duration format, examples: 2h30m, 90m"))
+			render.JSON(w, r, resp.Error(r, "invalid max_duration format, examples: 2h30m, 90m"))
 			return
 		}
 		filters.MaxDuration = duration
 	}
 
+	if originalTitle := r.URL.Query().Get("original_title"); originalTitle != "" {
+		filters.OriginalTitle = originalTitle
+	}
+
+	if tagline := r.URL.Query().Get("tagline"); tagline != "" {
+		filters.Tagline = tagline
+	}
+
+	if countries := r.URL.Query().Get("countries"); countries != "" {
+		filters.Countries = uniqueStrings(strings.Split(countries, ","), func(s string) string {
+			return strings.ToUpper(strings.TrimSpace(s))
+		})
+	}
+
+	if languages := r.URL.Query().Get("languages"); languages != "" {
+		filters.Languages = uniqueStrings(strings.Split(languages, ","), func(s string) string {
+			return strings.ToLower(strings.TrimSpace(s))
+		})
+	}
+
+	if ageRatings := r.URL.Query().Get("age_ratings"); ageRatings != "" {
+		filters.AgeRatings = uniqueStrings(strings.Split(ageRatings, ","), strings.TrimSpace)
+	}
+
+	if minBudget := r.URL.Query().Get("min_budget"); minBudget != "" {
+		amount, err := strToAmount(minBudget)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid min_budget format, expected non-negative integer"))
+			return
+		}
+		filters.MinBudget = amount
+	}
+
+	if maxBudget := r.URL.Query().Get("max_budget"); maxBudget != "" {
+		amount, err := strToAmount(maxBudget)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid max_budget format, expected non-negative integer"))
+			return
+		}
+		filters.MaxBudget = amount
+	}
+
+	if minBoxOffice := r.URL.Query().Get("min_box_office"); minBoxOffice != "" {
+		amount, err := strToAmount(minBoxOffice)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid min_box_office format, expected non-negative integer"))
+			return
+		}
+		filters.MinBoxOffice = amount
+	}
+
+	if maxBoxOffice := r.URL.Query().Get("max_box_office"); maxBoxOffice != "" {
+		amount, err := strToAmount(maxBoxOffice)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid max_box_office format, expected non-negative integer"))
+			return
+		}
+		filters.MaxBoxOffice = amount
+	}
+
+	if currency := r.URL.Query().Get("currency"); currency != "" {
+		filters.Currency = strings.ToUpper(currency)
+	}
+
+	filters.IMDbID = r.URL.Query().Get("imdb_id")
+	filters.KinopoiskID = r.URL.Query().Get("kinopoisk_id")
+	filters.TMDBID = r.URL.Query().Get("tmdb_id")
+
+	if contentTypes := r.URL.Query().Get("type"); contentTypes != "" {
+		filters.ContentTypes = uniqueStrings(strings.Split(contentTypes, ","), func(s string) string {
+			return strings.ToLower(strings.TrimSpace(s))
+		})
+	}
+
+	if tags := r.URL.Query().Get("tags"); tags != "" {
+		filters.Tags = uniqueStrings(strings.Split(tags, ","), slug.Slugify)
+	}
+
 	if page := r.URL.Query().Get("page"); page != "" {
 		pageNum, err := strconv.Atoi(page)
 		if err != nil || pageNum < 1 {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid page format, expected positive integer"))
+			render.JSON(w, r, resp.Error(r, "invalid page format, expected positive integer"))
 			return
 		}
 		filters.Page = pageNum
@@ -483,7 +741,7 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 		size, err := strconv.Atoi(pageSize)
 		if err != nil || size < 1 || size > 100 {
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("invalid page_size format, expected positive integer between 1 and 100"))
+			render.JSON(w, r, resp.Error(r, "invalid page_size format, expected positive integer between 1 and 100"))
 			return
 		}
 		filters.PageSize = size
@@ -491,7 +749,7 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 
 	if err := c.validate.Struct(filters); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+		render.JSON(w, r, resp.ValidationError(r, err))
 		return
 	}
 
@@ -502,7 +760,7 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 
 	if err := c.validate.Struct(sort); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+		render.JSON(w, r, resp.ValidationError(r, err))
 		return
 	}
 
@@ -510,14 +768,217 @@ func (c *Controller) GetFilms(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Error("failed to get films", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		render.JSON(w, r, resp.Error(f.ErrInternal.Error()))
+		render.JSON(w, r, resp.Error(r, f.ErrInternal.Error()))
 		return
 	}
 
+	c.filmUseCase.LocalizeFilms(films, locale.FromRequest(r))
+
 	w.WriteHeader(http.StatusOK)
 	render.JSON(w, r, resp.Films(films))
 }
 
+// GetSimilarFilms - Получение похожих фильмов
+// @Summary Получить похожие фильмы
+// @Description Возвращает ранжированный список фильмов, похожих на указанный, с причинами похожести (общие актеры, жанры, похожее описание, похожие оценки)
+// @Tags film
+// @Param id path string true "FilmId фильма"
+// @Param limit query int false "Количество фильмов (1-50, по умолчанию 10)"
+// @Success 200 {array} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/similar [get]
+func (c *Controller) GetSimilarFilms(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetSimilarFilms")
+
+	id, err := strToUint(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid id"))
+		return
+	}
+
+	limit := 10
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 50 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid limit format, expected positive integer between 1 and 50"))
+			return
+		}
+	}
+
+	similar, err := c.filmUseCase.GetSimilarFilms(id, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, f.ErrFilmNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(r, f.ErrFilmNotFound.Error()))
+		default:
+			log.Error("failed to get similar films", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, f.ErrInternal.Error()))
+		}
+		return
+	}
+
+	films := make([]*f.FilmDTO, 0, len(similar))
+	for _, s := range similar {
+		films = append(films, s.Film)
+	}
+	c.filmUseCase.LocalizeFilms(films, locale.FromRequest(r))
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.SimilarFilms(similar))
+}
+
+// applyMetadata переносит расширенные метаданные из запроса в DTO,
+// приводя коды стран и языков к каноничному регистру и убирая дубликаты
+func applyMetadata(film *f.FilmDTO, req *CreateFilmRequest) {
+	film.ContentType = req.ContentType
+	if film.ContentType == "" {
+		film.ContentType = f.ContentTypeMovie
+	}
+	film.OriginalTitle = strings.TrimSpace(req.OriginalTitle)
+	film.AltTitles = uniqueStrings(req.AltTitles, strings.TrimSpace)
+	film.Tagline = strings.TrimSpace(req.Tagline)
+	film.Countries = uniqueStrings(req.Countries, strings.ToUpper)
+	film.Languages = uniqueStrings(req.Languages, strings.ToLower)
+	film.AgeRating = strings.TrimSpace(req.AgeRating)
+	film.Budget = req.Budget
+	film.BoxOffice = req.BoxOffice
+	film.Currency = strings.ToUpper(req.Currency)
+	film.ExternalIDs = f.FilmExternalIDs{
+		IMDb:      req.ExternalIDs.IMDb,
+		Kinopoisk: req.ExternalIDs.Kinopoisk,
+		TMDB:      req.ExternalIDs.TMDB,
+	}
+}
+
+// toFilmPatch переносит переданные поля с той же нормализацией, что и applyMetadata при создании
+// patchValidator - проверки для ParsePatch, которой не нужен контроллер
+var patchValidator = newValidator()
+
+// ParsePatch разбирает тело правки фильма по правилам PATCH /films/{id}: те же поля, проверки и нормализация.
+// Нужна модулям, которые хранят правку и применяют ее позже (предложения правок). Версия из тела не берется
+func ParsePatch(id uint, body []byte) (*f.FilmPatch, error) {
+	var req PatchFilmRequest
+	if err := patch.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	if err := validatePatch(patchValidator, &req); err != nil {
+		return nil, err
+	}
+	return toFilmPatch(id, &req), nil
+}
+
+// validatePatch проверяет значения полей и что обязательные поля не очищаются
+func validatePatch(validate *validator.Validate, req *PatchFilmRequest) error {
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+	return patch.Required(map[string]patch.Clearable{
+		"title":        req.Title,
+		"release_date": req.ReleaseDate,
+		"content_type": req.ContentType,
+	})
+}
+
+func toFilmPatch(id uint, req *PatchFilmRequest) *f.FilmPatch {
+	p := &f.FilmPatch{
+		ID:          id,
+		ContentType: req.ContentType,
+		Title:       req.Title,
+		Synopsis:    req.Synopsis,
+		Runtime:     req.Runtime,
+
+		OriginalTitle: mapField(req.OriginalTitle, strings.TrimSpace),
+		Tagline:       mapField(req.Tagline, strings.TrimSpace),
+		AgeRating:     mapField(req.AgeRating, strings.TrimSpace),
+		Budget:        req.Budget,
+		BoxOffice:     req.BoxOffice,
+		Currency:      mapField(req.Currency, strings.ToUpper),
+		AltTitles: mapField(req.AltTitles, func(v []string) []string {
+			return uniqueStrings(v, strings.TrimSpace)
+		}),
+		Countries: mapField(req.Countries, func(v []string) []string {
+			return uniqueStrings(v, strings.ToUpper)
+		}),
+		Languages: mapField(req.Languages, func(v []string) []string {
+			return uniqueStrings(v, strings.ToLower)
+		}),
+
+		GenreIDs:       req.GenreIDs,
+		AddGenreIDs:    req.AddGenreIDs,
+		RemoveGenreIDs: req.RemoveGenreIDs,
+		Credits: patch.Field[[]f.CreditDTO]{
+			Set:   req.Credits.Set,
+			Null:  req.Credits.Null,
+			Value: toCredits(req.Credits.Value),
+		},
+		AddCredits: toCredits(req.AddCredits),
+	}
+
+	if req.ReleaseDate.Present() {
+		// формат уже проверен тегом datetime
+		releaseDate, _ := time.Parse("2006-01-02", req.ReleaseDate.Value)
+		p.ReleaseDate = patch.Of(releaseDate)
+	}
+
+	// external_ids: null очищает все идентификаторы, объект меняет только переданные
+	switch {
+	case req.ExternalIDs.IsNull():
+		cleared := patch.Field[string]{Set: true, Null: true}
+		p.IMDbID, p.KinopoiskID, p.TMDBID = cleared, cleared, cleared
+	case req.ExternalIDs.Present():
+		p.IMDbID = req.ExternalIDs.Value.IMDb
+		p.KinopoiskID = req.ExternalIDs.Value.Kinopoisk
+		p.TMDBID = req.ExternalIDs.Value.TMDB
+	}
+
+	for _, credit := range req.RemoveCredits {
+		p.RemoveCredits = append(p.RemoveCredits, f.CreditDTO{PersonID: credit.PersonID, Role: credit.Role})
+	}
+
+	return p
+}
+
+// mapField нормализует переданное значение поля
+func mapField[T any](field patch.Field[T], normalize func(T) T) patch.Field[T] {
+	if field.Present() {
+		field.Value = normalize(field.Value)
+	}
+	return field
+}
+
+func toCredits(credits []CreditRequest) []f.CreditDTO {
+	result := make([]f.CreditDTO, 0, len(credits))
+	for _, credit := range credits {
+		result = append(result, f.CreditDTO{
+			PersonID:     credit.PersonID,
+			Role:         credit.Role,
+			Character:    strings.TrimSpace(credit.Character),
+			BillingOrder: credit.BillingOrder,
+		})
+	}
+	return result
+}
+
+func uniqueStrings(values []string, normalize func(string) string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		v = normalize(v)
+		if _, ok := seen[v]; ok || v == "" {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
+
 func strToUint(s string) (uint, error) {
 	val, err := strconv.ParseUint(s, 10, 64)
 	if err != nil {
@@ -526,6 +987,17 @@ func strToUint(s string) (uint, error) {
 	return uint(val), nil
 }
 
+func strToAmount(s string) (int64, error) {
+	val, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if val < 0 {
+		return 0, fmt.Errorf("negative amount: %d", val)
+	}
+	return val, nil
+}
+
 func strToUintSlice(s string) ([]uint, error) {
 	parts := strings.Split(s, ",")
 	var result []uint
@@ -3,6 +3,7 @@ package controller
 import (
 	"github.com/go-playground/validator/v10"
 	"regexp"
+	"server/pkg/lib/patch"
 )
 
 // controller.CreateFilmRequest
@@ -11,10 +12,82 @@ type CreateFilmRequest struct {
 	Synopsis     string `json:"synopsis" validate:"required"`
 	ReleaseDate  string `json:"release_date" validate:"required"`
 	Runtime      string `json:"runtime" validate:"required,runtime_format"`
-	Producer     string `json:"producer" validate:"required"`
 	GenreIDs     []uint `json:"genre_ids" validate:"required,min=1"`
-	ActorIDs     []uint `json:"actor_ids" validate:"required,min=1"`
 	RemovePoster bool   `json:"remove_poster" validate:"boolean"`
+
+	Credits []CreditRequest `json:"credits" validate:"required,min=1,dive"`
+
+	OriginalTitle string             `json:"original_title" validate:"omitempty,max=500"`
+	AltTitles     []string           `json:"alt_titles" validate:"omitempty,dive,min=1,max=500"`
+	Tagline       string             `json:"tagline" validate:"omitempty,max=500"`
+	Countries     []string           `json:"countries" validate:"omitempty,dive,iso3166_1_alpha2"`
+	Languages     []string           `json:"languages" validate:"omitempty,dive,len=2,lowercase,alpha"`
+	AgeRating     string             `json:"age_rating" validate:"omitempty,max=16"`
+	Budget        int64              `json:"budget" validate:"omitempty,min=0"`
+	BoxOffice     int64              `json:"box_office" validate:"omitempty,min=0"`
+	Currency      string             `json:"currency" validate:"required_with=Budget BoxOffice,omitempty,iso4217"`
+	ExternalIDs   ExternalIDsRequest `json:"external_ids"`
+
+	ContentType string `json:"content_type" validate:"omitempty,oneof=movie series mini_series"`
+
+	// Версия для изменения, при создании не используется
+	Version int `json:"version" validate:"omitempty,min=1"`
+}
+
+type CreditRequest struct {
+	PersonID     uint   `json:"person_id" validate:"required,min=1"`
+	Role         string `json:"role" validate:"required,oneof=cast director writer producer composer"`
+	Character    string `json:"character" validate:"omitempty,max=500"`
+	BillingOrder int    `json:"billing_order" validate:"min=0"`
+}
+
+type ExternalIDsRequest struct {
+	IMDb      string `json:"imdb" validate:"omitempty,imdb_id"`
+	Kinopoisk string `json:"kinopoisk" validate:"omitempty,numeric,max=16"`
+	TMDB      string `json:"tmdb" validate:"omitempty,numeric,max=16"`
+}
+
+// PatchFilmRequest - тело PATCH /films/{id} (RFC 7396): поля, которых нет в теле, не меняются, null очищает поле.
+// genre_ids и credits заменяют списки целиком, add_* и remove_* добавляют и убирают отдельные связи
+type PatchFilmRequest struct {
+	Title       patch.Field[string] `json:"title" validate:"omitempty,max=500"`
+	Synopsis    patch.Field[string] `json:"synopsis"`
+	ReleaseDate patch.Field[string] `json:"release_date" validate:"omitempty,datetime=2006-01-02"`
+	Runtime     patch.Field[string] `json:"runtime" validate:"omitempty,runtime_format"`
+	ContentType patch.Field[string] `json:"content_type" validate:"omitempty,oneof=movie series mini_series"`
+
+	OriginalTitle patch.Field[string]                  `json:"original_title" validate:"omitempty,max=500"`
+	AltTitles     patch.Field[[]string]                `json:"alt_titles" validate:"omitempty,dive,min=1,max=500"`
+	Tagline       patch.Field[string]                  `json:"tagline" validate:"omitempty,max=500"`
+	Countries     patch.Field[[]string]                `json:"countries" validate:"omitempty,dive,iso3166_1_alpha2"`
+	Languages     patch.Field[[]string]                `json:"languages" validate:"omitempty,dive,len=2,lowercase,alpha"`
+	AgeRating     patch.Field[string]                  `json:"age_rating" validate:"omitempty,max=16"`
+	Budget        patch.Field[int64]                   `json:"budget" validate:"omitempty,min=0"`
+	BoxOffice     patch.Field[int64]                   `json:"box_office" validate:"omitempty,min=0"`
+	Currency      patch.Field[string]                  `json:"currency" validate:"omitempty,iso4217"`
+	ExternalIDs   patch.Field[PatchExternalIDsRequest] `json:"external_ids"`
+
+	GenreIDs       patch.Field[[]uint] `json:"genre_ids" validate:"omitempty,dive,min=1"`
+	AddGenreIDs    []uint              `json:"add_genre_ids" validate:"omitempty,dive,min=1"`
+	RemoveGenreIDs []uint              `json:"remove_genre_ids" validate:"omitempty,dive,min=1"`
+
+	Credits       patch.Field[[]CreditRequest] `json:"credits" validate:"omitempty,dive"`
+	AddCredits    []CreditRequest              `json:"add_credits" validate:"omitempty,dive"`
+	RemoveCredits []RemoveCreditRequest        `json:"remove_credits" validate:"omitempty,dive"`
+
+	Version int `json:"version" validate:"omitempty,min=1"`
+}
+
+type PatchExternalIDsRequest struct {
+	IMDb      patch.Field[string] `json:"imdb" validate:"omitempty,imdb_id"`
+	Kinopoisk patch.Field[string] `json:"kinopoisk" validate:"omitempty,numeric,max=16"`
+	TMDB      patch.Field[string] `json:"tmdb" validate:"omitempty,numeric,max=16"`
+}
+
+// RemoveCreditRequest - участие для удаления, без роли удаляются все участия персоны в фильме
+type RemoveCreditRequest struct {
+	PersonID uint   `json:"person_id" validate:"required,min=1"`
+	Role     string `json:"role" validate:"omitempty,oneof=cast director writer producer composer"`
 }
 
 func validateRuntimeFormat(fl validator.FieldLevel) bool {
@@ -25,3 +98,9 @@ func validateRuntimeFormat(fl validator.FieldLevel) bool {
 
 	return matched
 }
+
+func validateImdbID(fl validator.FieldLevel) bool {
+	matched, _ := regexp.MatchString(`^tt\d{7,10}$`, fl.Field().String())
+
+	return matched
+}
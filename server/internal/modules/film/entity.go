@@ -1,22 +1,45 @@
 package film
 
 import (
+	"context"
 	"mime/multipart"
 	"net/http"
-	"server/internal/modules/actor"
 	g "server/internal/modules/genre"
+	"server/pkg/lib/patch"
 	"time"
 )
 
+// Типы контента
+const (
+	ContentTypeMovie      = "movie"
+	ContentTypeSeries     = "series"
+	ContentTypeMiniSeries = "mini_series"
+)
+
 type FilmDTO struct {
 	ID          uint      `json:"id"`
+	ContentType string    `json:"content_type"`
 	Title       string    `json:"title"`
-	PosterURL   string    `json:"poster_url"`
+	PosterURL   string    `json:"poster_url"` // Папка с размерами постера (avatarManager.PosterProfile)
 	Synopsis    string    `json:"synopsis"`
 	ReleaseDate time.Time `json:"release_date"`
 	Runtime     string    `json:"runtime"`
-	Producer    string    `json:"producer"`
 	CreateAt    time.Time `json:"create_at"`
+	Version     int       `json:"version"` // Счетчик изменений, см. pkg/lib/optimistic
+
+	PosterBlurhash string `json:"poster_blurhash"` // Заглушка на время загрузки постера
+	PosterColor    string `json:"poster_color"`    // Средний цвет постера, #rrggbb
+
+	OriginalTitle string          `json:"original_title"`
+	AltTitles     []string        `json:"alt_titles"`
+	Tagline       string          `json:"tagline"`
+	Countries     []string        `json:"countries"` // ISO 3166-1 alpha-2
+	Languages     []string        `json:"languages"` // ISO 639-1
+	AgeRating     string          `json:"age_rating"`
+	Budget        int64           `json:"budget"`
+	BoxOffice     int64           `json:"box_office"`
+	Currency      string          `json:"currency"` // ISO 4217, общая для бюджета и сборов
+	ExternalIDs   FilmExternalIDs `json:"external_ids"`
 
 	AvgRating          float64 `json:"avg_rating"`
 	TotalReviews       uint    `json:"total_reviews"`
@@ -27,26 +50,156 @@ type FilmDTO struct {
 	CountRatings81_100 uint    `json:"count_ratings_81_100"`
 
 	GenreIDs []uint `json:"genre_ids"` // Только ID жанров
-	ActorIDs []uint `json:"actor_ids"` // Только ID актеров
 
-	Genres []g.GenreDTO     `json:"genres"`
-	Actors []actor.ActorDTO `json:"actors"`
+	Genres  []g.GenreDTO `json:"genres"`
+	Credits []CreditDTO  `json:"credits"` // Актерский состав и съемочная группа
+
+	Collection *FilmCollectionDTO `json:"collection"` // Франшиза, nil если фильм не входит в коллекцию
+
+	Tags []FilmTagDTO `json:"tags"` // Одобренные теги, самые релевантные по голосам первыми
+
+	Backdrop *FilmBackdropDTO `json:"backdrop"` // Основной фон из галереи фильма, nil если фонов нет
+	Trailer  *FilmTrailerDTO  `json:"trailer"`  // Основной трейлер: официальный и самый свежий, nil если трейлеров нет
 
 	RemovePoster bool `json:"remove_poster"`
 }
 
+// CreditDTO - участие персоны в фильме: роль (person.Role*), персонаж для актеров и порядок в титрах
+type CreditDTO struct {
+	PersonID     uint    `json:"person_id"`
+	Name         string  `json:"name"`
+	AvatarUrl    *string `json:"avatar_url"`
+	Role         string  `json:"role"`
+	Character    string  `json:"character"`
+	BillingOrder int     `json:"billing_order"`
+
+	AvatarBlurhash string `json:"avatar_blurhash"`
+	AvatarColor    string `json:"avatar_color"`
+}
+
+// FilmCollectionDTO - коллекция фильма и его место в ней
+type FilmCollectionDTO struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+// FilmTagDTO - одобренный тег фильма (модуль tag)
+type FilmTagDTO struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// FilmBackdropDTO - основной фон фильма (модуль media), URL - папка с размерами (avatarManager.BackdropProfile)
+type FilmBackdropDTO struct {
+	URL      string `json:"url"`
+	Blurhash string `json:"blurhash"`
+	Color    string `json:"color"`
+}
+
+// FilmTrailerDTO - основной трейлер фильма (модуль media), Key - id видео у провайдера (videolink.Provider*)
+type FilmTrailerDTO struct {
+	ID       uint   `json:"id"`
+	Provider string `json:"provider"`
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Language string `json:"language"`
+}
+
+type FilmExternalIDs struct {
+	IMDb      string `json:"imdb"`
+	Kinopoisk string `json:"kinopoisk"`
+	TMDB      string `json:"tmdb"`
+}
+
+// FilmPatch - частичное изменение фильма (PATCH, RFC 7396): меняются только переданные поля, null очищает поле.
+// Списки заменяются целиком, Add*/Remove* добавляют и убирают отдельные жанры и участия после замены
+type FilmPatch struct {
+	ID      uint
+	Version int // Версия, которую видел клиент, после изменения - новая версия
+
+	ContentType patch.Field[string]
+	Title       patch.Field[string]
+	Synopsis    patch.Field[string]
+	ReleaseDate patch.Field[time.Time]
+	Runtime     patch.Field[string] // В формате FilmDTO.Runtime, null - 0 минут
+
+	OriginalTitle patch.Field[string]
+	AltTitles     patch.Field[[]string]
+	Tagline       patch.Field[string]
+	Countries     patch.Field[[]string]
+	Languages     patch.Field[[]string]
+	AgeRating     patch.Field[string]
+	Budget        patch.Field[int64]
+	BoxOffice     patch.Field[int64]
+	Currency      patch.Field[string]
+	IMDbID        patch.Field[string]
+	KinopoiskID   patch.Field[string]
+	TMDBID        patch.Field[string]
+
+	GenreIDs       patch.Field[[]uint]
+	AddGenreIDs    []uint
+	RemoveGenreIDs []uint
+
+	Credits       patch.Field[[]CreditDTO]
+	AddCredits    []CreditDTO // Участие персоны в той же роли заменяется
+	RemoveCredits []CreditDTO // По персоне и роли, пустая роль - все участия персоны
+}
+
 type FilmFilters struct {
 	GenreIDs    []uint        `validate:"omitempty,dive,min=1"`
 	ActorIDs    []uint        `validate:"omitempty,dive,min=1"`
-	Producer    string        `validate:"omitempty,min=1"`
+	DirectorIDs []uint        `validate:"omitempty,dive,min=1"`
+	Director    string        `validate:"omitempty,min=1"` // имя режиссера, поиск по подстроке
+	Producer    string        `validate:"omitempty,min=1"` // имя продюсера, поиск по подстроке
 	MinRating   float64       `validate:"omitempty,min=0,max=100"`
 	MaxRating   float64       `validate:"omitempty,min=0,max=100"`
 	MinDate     time.Time     `validate:"omitempty"`
 	MaxDate     time.Time     `validate:"omitempty"`
 	MinDuration time.Duration `validate:"omitempty"`
 	MaxDuration time.Duration `validate:"omitempty"`
-	Page        int           `validate:"required,min=1"`
-	PageSize    int           `validate:"required,min=1,max=100"`
+
+	OriginalTitle string   `validate:"omitempty,min=1"` // ищет и по альтернативным названиям
+	Tagline       string   `validate:"omitempty,min=1"`
+	Countries     []string `validate:"omitempty,dive,iso3166_1_alpha2"`
+	Languages     []string `validate:"omitempty,dive,len=2,lowercase,alpha"`
+	AgeRatings    []string `validate:"omitempty,dive,min=1,max=16"`
+	MinBudget     int64    `validate:"omitempty,min=0"`
+	MaxBudget     int64    `validate:"omitempty,min=0"`
+	MinBoxOffice  int64    `validate:"omitempty,min=0"`
+	MaxBoxOffice  int64    `validate:"omitempty,min=0"`
+	Currency      string   `validate:"omitempty,iso4217"`
+	IMDbID        string   `validate:"omitempty,imdb_id"`
+	KinopoiskID   string   `validate:"omitempty,numeric"`
+	TMDBID        string   `validate:"omitempty,numeric"`
+
+	ContentTypes []string `validate:"omitempty,dive,oneof=movie series mini_series"`
+
+	Tags []string `validate:"omitempty,dive,min=1,max=100"` // slug тегов, у фильма должны быть все
+
+	Page     int `validate:"required,min=1"`
+	PageSize int `validate:"required,min=1,max=100"`
+}
+
+type SimilarFilmDTO struct {
+	Film    *FilmDTO `json:"film"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// FilmOverlap - количество общих жанров и актеров с другим фильмом
+type FilmOverlap struct {
+	FilmID       uint `gorm:"column:film_id"`
+	SharedGenres int  `gorm:"column:shared_genres"`
+	SharedActors int  `gorm:"column:shared_actors"`
+}
+
+// FilmCoRating - похожесть оценок фильмов у общих рецензентов
+type FilmCoRating struct {
+	FilmID          uint    `gorm:"column:film_id"`
+	CommonReviewers int     `gorm:"column:common_reviewers"`
+	Similarity      float64 `gorm:"column:similarity"`
 }
 
 type FilmSort struct {
@@ -58,39 +211,65 @@ type Controller interface {
 	GetFilmByID(w http.ResponseWriter, r *http.Request)
 	CreateFilm(w http.ResponseWriter, r *http.Request)
 	UpdateFilm(w http.ResponseWriter, r *http.Request)
+	PatchFilm(w http.ResponseWriter, r *http.Request)
 	DeleteFilm(w http.ResponseWriter, r *http.Request)
 	SearchFilms(w http.ResponseWriter, r *http.Request)
 	GetFilms(w http.ResponseWriter, r *http.Request)
+	GetSimilarFilms(w http.ResponseWriter, r *http.Request)
 }
 
 type UseCase interface {
 	GetFilmByID(id uint) (*FilmDTO, error)
-	CreateFilm(film *FilmDTO, poster *multipart.File) error
-	UpdateFilm(film *FilmDTO, poster *multipart.File) error
-	DeleteFilm(id uint) error
-	SearchFilms(query string) ([]*FilmDTO, error)
+	CreateFilm(ctx context.Context, film *FilmDTO, poster *multipart.File) error
+	UpdateFilm(ctx context.Context, film *FilmDTO, poster *multipart.File) error
+	PatchFilm(ctx context.Context, patch *FilmPatch) (*FilmDTO, error)
+	DeleteFilm(ctx context.Context, id uint) error
+	SearchFilms(query string, lang string) ([]*FilmDTO, error)
 	GetFilms(filters FilmFilters, sort FilmSort) ([]*FilmDTO, error)
+	GetSimilarFilms(id uint, limit int) ([]*SimilarFilmDTO, error)
+	ReindexFilm(id uint) error
+	InvalidateFilmCache(id uint)
+	CleanupFilm(id uint)
+	LocalizeFilms(films []*FilmDTO, lang string)
 }
 
+// PosterUpload загружает постер в папку фильма и проставляет film адрес, заглушку и цвет.
+// Репозиторий вызывает ее в транзакции, когда у фильма уже есть id, а версия проверена
+type PosterUpload func(film *FilmDTO) error
+
 type Repo interface {
 	//DB
 	GetFilmByID(id uint) (*FilmDTO, error)
-	CreateFilm(film *FilmDTO) (uint, error)
-	UpdateFilm(film *FilmDTO) error
-	DeleteFilm(id uint) error
+	CreateFilm(ctx context.Context, film *FilmDTO, upload PosterUpload) (uint, error)
+	UpdateFilm(ctx context.Context, film *FilmDTO, upload PosterUpload) error
+	PatchFilm(ctx context.Context, patch *FilmPatch) error
+	UpdatePoster(ctx context.Context, film *FilmDTO) error
+	DeleteFilm(ctx context.Context, id uint) error
 	GetFilms(filters FilmFilters, sort FilmSort) ([]*FilmDTO, error)
+	GetFilmOverlaps(filmID uint, limit int) ([]*FilmOverlap, error)
+	GetFilmCoRatings(filmID uint, minReviewers int, limit int) ([]*FilmCoRating, error)
+	GetEpisodeTitles(filmID uint) ([]string, error)
+	GetFilmCollections(filmIDs []uint) (map[uint]*FilmCollectionDTO, error)
+	GetFilmTags(filmIDs []uint) (map[uint][]FilmTagDTO, error)
+	GetFilmBackdrops(filmIDs []uint) (map[uint]*FilmBackdropDTO, error)
+	GetFilmTrailers(filmIDs []uint) (map[uint]*FilmTrailerDTO, error)
 
 	//ES
-	SearchFilms(query string) ([]uint, error)
-	IndexFilm(film *FilmDTO) error
+	SearchFilms(query string, lang string) ([]uint, error)
+	SearchSimilarFilms(filmID uint, limit int) (map[uint]float64, error)
+	IndexFilm(film *FilmDTO, episodeTitles []string, localized map[string]map[string]string) error
 
 	//Cache
-	GetFilmsFromCache(key string) ([]*FilmDTO, error)
-	SetFilmsToCache(key string, films interface{}, ttl time.Duration) error
-	DeleteFilmFromCache(key string) error
+	LoadFilm(id uint, ttl time.Duration, load func() (*FilmDTO, error)) (*FilmDTO, error)
+	LoadFilms(key string, ttl time.Duration, load func() ([]*FilmDTO, error)) ([]*FilmDTO, error)
+	LoadSimilarFilms(id uint, ttl time.Duration, load func() ([]*SimilarFilmDTO, error)) ([]*SimilarFilmDTO, error)
+	InvalidateFilm(id uint) error
+	InvalidateFilmLists() error
 
 	//S3
-	UploadPoster(filmID uint, file []byte) (string, error)
+	UploadPoster(filmID uint, variants map[string][]byte) (string, error)
 	DeletePoster(filmID uint) error
+	DeleteImages(filmID uint) error
+	DefaultPosterURL() string
 	DeleteFilmFromIndex(filmID uint) error
 }
@@ -2,6 +2,7 @@ package film
 
 import (
 	"fmt"
+	"gorm.io/gorm"
 	"strconv"
 	"strings"
 	"time"
@@ -31,38 +32,104 @@ func (FilmGenre) TableName() string {
 	return "film_genre" // или "film_genres", в зависимости от базы данных
 }
 
-type FilmActor struct {
-	FilmID  uint `gorm:"primaryKey;column:film_id"`
-	ActorID uint `gorm:"primaryKey;column:actor_id"`
+type FilmCredit struct {
+	CreditID      uint   `gorm:"primaryKey;column:credit_id;autoIncrement"`
+	FilmID        uint   `gorm:"column:film_id;not null"`
+	PersonID      uint   `gorm:"column:person_id;not null"`
+	Role          string `gorm:"column:role;type:varchar(16);not null"`
+	CharacterName string `gorm:"column:character_name;type:text;not null;default:''"`
+	BillingOrder  int    `gorm:"column:billing_order;not null;default:0"`
+}
+
+func (FilmCredit) TableName() string {
+	return "film_credits"
+}
+
+type FilmAltTitle struct {
+	FilmID uint   `gorm:"primaryKey;column:film_id"`
+	Title  string `gorm:"primaryKey;column:title"`
+}
+
+func (FilmAltTitle) TableName() string {
+	return "film_alt_titles"
+}
+
+type FilmCountry struct {
+	FilmID      uint   `gorm:"primaryKey;column:film_id"`
+	CountryCode string `gorm:"primaryKey;column:country_code"`
+}
+
+func (FilmCountry) TableName() string {
+	return "film_countries"
+}
+
+type FilmLanguage struct {
+	FilmID       uint   `gorm:"primaryKey;column:film_id"`
+	LanguageCode string `gorm:"primaryKey;column:language_code"`
 }
 
-func (FilmActor) TableName() string {
-	return "film_actor" // или "film_genres", в зависимости от базы данных
+func (FilmLanguage) TableName() string {
+	return "film_languages"
 }
 
 type Film struct {
-	FilmId      uint        `gorm:"primaryKey;column:film_id;autoIncrement"`
-	Title       string      `gorm:"column:title;type:text;not null;default:'фильмец под чипсики'"`
-	PosterURL   string      `gorm:"default:'https://filmposter.storage-173.s3hoster.by/default/';column:poster_url"`
-	Synopsis    string      `gorm:"column:synopsis;type:text;not null;default:'-'"`
-	ReleaseDate time.Time   `gorm:"column:release_date;type:date;not null"`
-	Runtime     int         `gorm:"column:runtime;type:int;not null;default:90"`
-	Producer    string      `gorm:"column:producer;type:varchar(255);not null;default:'Неизвестен'"`
-	CreatedAt   time.Time   `gorm:"column:create_at"`
-	Genres      []FilmGenre `gorm:"many2many:film_genre;"` // Связь с жанрами
-	Actors      []FilmActor `gorm:"many2many:film_actor;"` // Связь с актерами
+	FilmId      uint           `gorm:"primaryKey;column:film_id;autoIncrement"`
+	ContentType string         `gorm:"column:content_type;type:varchar(16);not null;default:'movie'"`
+	Title       string         `gorm:"column:title;type:text;not null"`
+	PosterURL   string         `gorm:"default:'https://filmposter.storage-173.s3hoster.by/default/';column:poster_url"`
+	Synopsis    string         `gorm:"column:synopsis;type:text;not null"`
+	ReleaseDate time.Time      `gorm:"column:release_date;type:date;not null"`
+	Runtime     int            `gorm:"column:runtime;type:int;not null;default:90"`
+	CreatedAt   time.Time      `gorm:"column:create_at"`
+	Version     int            `gorm:"column:version;->"`                   // меняется только через optimistic.Bump
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at"`                   // удаленный фильм лежит в корзине до очистки
+	Genres      []FilmGenre    `gorm:"many2many:film_genre;"`               // Связь с жанрами
+	Credits     []FilmCredit   `gorm:"foreignKey:FilmID;references:FilmId"` // Актерский состав и съемочная группа
+
+	PosterBlurhash string `gorm:"column:poster_blurhash;type:varchar(64);not null;default:''"`
+	PosterColor    string `gorm:"column:poster_color;type:varchar(7);not null;default:''"`
+
+	OriginalTitle string `gorm:"column:original_title;type:text;not null;default:''"`
+	Tagline       string `gorm:"column:tagline;type:text;not null;default:''"`
+	AgeRating     string `gorm:"column:age_rating;type:varchar(16);not null;default:''"`
+	Budget        int64  `gorm:"column:budget;not null;default:0"`
+	BoxOffice     int64  `gorm:"column:box_office;not null;default:0"`
+	Currency      string `gorm:"column:currency;type:char(3);not null;default:''"`
+	IMDbID        string `gorm:"column:imdb_id;type:varchar(16);not null;default:''"`
+	KinopoiskID   string `gorm:"column:kinopoisk_id;type:varchar(16);not null;default:''"`
+	TMDBID        string `gorm:"column:tmdb_id;type:varchar(16);not null;default:''"`
+
+	AltTitles []FilmAltTitle `gorm:"foreignKey:FilmID;references:FilmId"`
+	Countries []FilmCountry  `gorm:"foreignKey:FilmID;references:FilmId"`
+	Languages []FilmLanguage `gorm:"foreignKey:FilmID;references:FilmId"`
 }
 
 func (f *Film) ToDTO(stats *FilmStatsModel) *FilmDTO {
 	filmDTO := &FilmDTO{
 		ID:          f.FilmId,
+		ContentType: f.ContentType,
 		Title:       f.Title,
 		PosterURL:   f.PosterURL,
 		Synopsis:    f.Synopsis,
 		ReleaseDate: f.ReleaseDate,
-		Runtime:     minutesToDurationString(f.Runtime),
-		Producer:    f.Producer,
+		Runtime:     MinutesToDurationString(f.Runtime),
 		CreateAt:    f.CreatedAt,
+		Version:     f.Version,
+
+		PosterBlurhash: f.PosterBlurhash,
+		PosterColor:    f.PosterColor,
+
+		OriginalTitle: f.OriginalTitle,
+		Tagline:       f.Tagline,
+		AgeRating:     f.AgeRating,
+		Budget:        f.Budget,
+		BoxOffice:     f.BoxOffice,
+		Currency:      strings.TrimSpace(f.Currency),
+		ExternalIDs: FilmExternalIDs{
+			IMDb:      f.IMDbID,
+			Kinopoisk: f.KinopoiskID,
+			TMDB:      f.TMDBID,
+		},
 
 		AvgRating:          stats.AvgRating,
 		TotalReviews:       stats.TotalReviews,
@@ -78,9 +145,23 @@ func (f *Film) ToDTO(stats *FilmStatsModel) *FilmDTO {
 		filmDTO.GenreIDs = append(filmDTO.GenreIDs, genre.GenreID)
 	}
 
-	// Сохраняем FilmId актеров
-	for _, actor := range f.Actors {
-		filmDTO.ActorIDs = append(filmDTO.ActorIDs, actor.ActorID)
+	for _, credit := range f.Credits {
+		filmDTO.Credits = append(filmDTO.Credits, CreditDTO{
+			PersonID:     credit.PersonID,
+			Role:         credit.Role,
+			Character:    credit.CharacterName,
+			BillingOrder: credit.BillingOrder,
+		})
+	}
+
+	for _, altTitle := range f.AltTitles {
+		filmDTO.AltTitles = append(filmDTO.AltTitles, altTitle.Title)
+	}
+	for _, country := range f.Countries {
+		filmDTO.Countries = append(filmDTO.Countries, country.CountryCode)
+	}
+	for _, language := range f.Languages {
+		filmDTO.Languages = append(filmDTO.Languages, language.LanguageCode)
 	}
 
 	return filmDTO
@@ -89,13 +170,26 @@ func (f *Film) ToDTO(stats *FilmStatsModel) *FilmDTO {
 func (f *FilmDTO) ToModel() (*Film, *FilmStatsModel) {
 	film := &Film{
 		FilmId:      f.ID,
+		ContentType: f.ContentType,
 		Title:       f.Title,
 		PosterURL:   f.PosterURL,
 		Synopsis:    f.Synopsis,
 		ReleaseDate: f.ReleaseDate,
-		Runtime:     durationStringToMinutes(f.Runtime),
-		Producer:    f.Producer,
+		Runtime:     DurationStringToMinutes(f.Runtime),
 		CreatedAt:   f.CreateAt,
+
+		PosterBlurhash: f.PosterBlurhash,
+		PosterColor:    f.PosterColor,
+
+		OriginalTitle: f.OriginalTitle,
+		Tagline:       f.Tagline,
+		AgeRating:     f.AgeRating,
+		Budget:        f.Budget,
+		BoxOffice:     f.BoxOffice,
+		Currency:      f.Currency,
+		IMDbID:        f.ExternalIDs.IMDb,
+		KinopoiskID:   f.ExternalIDs.Kinopoisk,
+		TMDBID:        f.ExternalIDs.TMDB,
 	}
 
 	// Восстанавливаем связи с жанрами
@@ -105,10 +199,28 @@ func (f *FilmDTO) ToModel() (*Film, *FilmStatsModel) {
 		})
 	}
 
-	// Восстанавливаем связи с актерами
-	for _, actorID := range f.ActorIDs {
-		film.Actors = append(film.Actors, FilmActor{
-			ActorID: actorID,
+	for _, credit := range f.Credits {
+		film.Credits = append(film.Credits, FilmCredit{
+			PersonID:      credit.PersonID,
+			Role:          credit.Role,
+			CharacterName: credit.Character,
+			BillingOrder:  credit.BillingOrder,
+		})
+	}
+
+	for _, title := range f.AltTitles {
+		film.AltTitles = append(film.AltTitles, FilmAltTitle{
+			Title: title,
+		})
+	}
+	for _, code := range f.Countries {
+		film.Countries = append(film.Countries, FilmCountry{
+			CountryCode: code,
+		})
+	}
+	for _, code := range f.Languages {
+		film.Languages = append(film.Languages, FilmLanguage{
+			LanguageCode: code,
 		})
 	}
 
@@ -126,14 +238,14 @@ func (f *FilmDTO) ToModel() (*Film, *FilmStatsModel) {
 	return film, filmStats
 }
 
-func minutesToDurationString(minutes int) string {
+func MinutesToDurationString(minutes int) string {
 	hours := minutes / 60
 	remainingMinutes := minutes % 60
 
 	return fmt.Sprintf("%dh%dm", hours, remainingMinutes)
 }
 
-func durationStringToMinutes(durationString string) int {
+func DurationStringToMinutes(durationString string) int {
 	durationString = strings.ReplaceAll(durationString, " ", "")
 	hours := 0
 	minutes := 0
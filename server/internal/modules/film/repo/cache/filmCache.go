@@ -1,61 +1,84 @@
 package cache
 
 import (
-	"context"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"github.com/go-redis/redis/v8"
 	"server/internal/init/cache"
 	f "server/internal/modules/film"
 	"time"
 )
 
+// FilmCache - каждая запись помечена тегами всех фильмов, которые в нее входят, поэтому изменение
+// фильма сбрасывает и его карточку, и все закэшированные списки и подборки похожих с ним
 type FilmCache struct {
-	ch *cache.Cache
+	ch      *cache.Cache
+	films   *cache.Typed[*f.FilmDTO]
+	lists   *cache.Typed[[]*f.FilmDTO]
+	similar *cache.Typed[[]*f.SimilarFilmDTO]
 }
 
 func NewFilmCache(ch *cache.Cache) *FilmCache {
 	return &FilmCache{
-		ch: ch,
+		ch:      ch,
+		films:   cache.NewTyped[*f.FilmDTO](ch),
+		lists:   cache.NewTyped[[]*f.FilmDTO](ch),
+		similar: cache.NewTyped[[]*f.SimilarFilmDTO](ch),
 	}
 }
 
-func (c *FilmCache) SetFilmsToCache(key string, films interface{}, ttl time.Duration) error {
-	switch v := films.(type) {
-	case *f.FilmDTO:
-		films = []*f.FilmDTO{v}
-	case []*f.FilmDTO:
-		films = v
-	default:
-		return fmt.Errorf("invalid film type")
-	}
+func (c *FilmCache) LoadFilm(id uint, ttl time.Duration, load func() (*f.FilmDTO, error)) (*f.FilmDTO, error) {
+	return c.films.GetOrLoad(cache.Key("film", id), ttl, func() (*f.FilmDTO, []string, error) {
+		film, err := load()
+		return film, []string{filmTag(id)}, err
+	})
+}
 
-	data, err := json.Marshal(films)
-	if err != nil {
-		return err
-	}
+// LoadFilms - key строится по фильтрам списка. Список помечается еще и общим тегом списков:
+// новый фильм может попасть в любой из них
+func (c *FilmCache) LoadFilms(key string, ttl time.Duration, load func() ([]*f.FilmDTO, error)) ([]*f.FilmDTO, error) {
+	return c.lists.GetOrLoad(key, ttl, func() ([]*f.FilmDTO, []string, error) {
+		films, err := load()
+		if err != nil {
+			return nil, nil, err
+		}
 
-	return c.ch.Client.Set(context.Background(), key, data, ttl).Err()
+		tags := make([]string, 0, len(films)+1)
+		tags = append(tags, listsTag())
+		for _, film := range films {
+			tags = append(tags, filmTag(film.ID))
+		}
+		return films, tags, nil
+	})
 }
 
-func (c *FilmCache) GetFilmsFromCache(key string) ([]*f.FilmDTO, error) {
-	data, err := c.ch.Client.Get(context.Background(), key).Result()
-	if errors.Is(err, redis.Nil) {
-		return nil, f.ErrFilmCacheMiss
-	} else if err != nil {
-		return nil, err
-	}
+func (c *FilmCache) LoadSimilarFilms(id uint, ttl time.Duration, load func() ([]*f.SimilarFilmDTO, error)) ([]*f.SimilarFilmDTO, error) {
+	return c.similar.GetOrLoad(cache.Key("film", id, "similar"), ttl, func() ([]*f.SimilarFilmDTO, []string, error) {
+		similar, err := load()
+		if err != nil {
+			return nil, nil, err
+		}
 
-	var result []*f.FilmDTO
-	err = json.Unmarshal([]byte(data), &result)
-	if err != nil {
-		return nil, err
-	}
+		tags := make([]string, 0, len(similar)+1)
+		tags = append(tags, filmTag(id))
+		for _, s := range similar {
+			tags = append(tags, filmTag(s.Film.ID))
+		}
+		return similar, tags, nil
+	})
+}
+
+// InvalidateFilm сбрасывает все записи, в которые входит фильм
+func (c *FilmCache) InvalidateFilm(id uint) error {
+	return c.ch.InvalidateTags(filmTag(id))
+}
+
+// InvalidateFilmLists сбрасывает все списки фильмов
+func (c *FilmCache) InvalidateFilmLists() error {
+	return c.ch.InvalidateTags(listsTag())
+}
 
-	return result, nil
+func filmTag(id uint) string {
+	return cache.Tag("film", id)
 }
 
-func (c *FilmCache) DeleteFilmFromCache(key string) error {
-	return c.ch.Client.Del(context.Background(), key).Err()
+func listsTag() string {
+	return cache.Tag("films")
 }
@@ -1,12 +1,18 @@
 package database
 
 import (
+	"context"
 	"errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"log/slog"
-	"server/internal/modules/actor"
 	f "server/internal/modules/film"
 	g "server/internal/modules/genre"
+	per "server/internal/modules/person"
+	"server/pkg/lib/audit"
+	"server/pkg/lib/optimistic"
+	"server/pkg/lib/patch"
+	"server/pkg/lib/revision"
 )
 
 type FilmDatabase struct {
@@ -23,7 +29,7 @@ func NewFilmDatabase(db *gorm.DB, log *slog.Logger) *FilmDatabase {
 
 func (db *FilmDatabase) GetFilmByID(id uint) (*f.FilmDTO, error) {
 	var film f.Film
-	if err := db.db.First(&film, id).Error; err != nil {
+	if err := db.db.Scopes(preloadMetadata).First(&film, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, f.ErrFilmNotFound
 		}
@@ -50,21 +56,42 @@ func (db *FilmDatabase) GetFilmByID(id uint) (*f.FilmDTO, error) {
 		}
 	}
 
-	if len(filmDTO.ActorIDs) > 0 {
-		var actors []actor.Actor
-		if err := db.db.Where("id IN ?", filmDTO.ActorIDs).Find(&actors).Error; err != nil {
-			return nil, err
-		}
-		for _, act := range actors {
-			actor := actor.ToDTO(&act)
-			filmDTO.Actors = append(filmDTO.Actors, *actor)
-		}
+	credits, err := db.getCredits([]uint{id})
+	if err != nil {
+		return nil, err
+	}
+	filmDTO.Credits = credits[id]
+
+	collections, err := db.GetFilmCollections([]uint{id})
+	if err != nil {
+		return nil, err
+	}
+	filmDTO.Collection = collections[id]
+
+	tags, err := db.GetFilmTags([]uint{id})
+	if err != nil {
+		return nil, err
+	}
+	filmDTO.Tags = tags[id]
+
+	backdrops, err := db.GetFilmBackdrops([]uint{id})
+	if err != nil {
+		return nil, err
+	}
+	filmDTO.Backdrop = backdrops[id]
+
+	trailers, err := db.GetFilmTrailers([]uint{id})
+	if err != nil {
+		return nil, err
 	}
+	filmDTO.Trailer = trailers[id]
 
 	return filmDTO, nil
 }
 
-func (db *FilmDatabase) CreateFilm(film *f.FilmDTO) (uint, error) {
+// CreateFilm создает фильм. upload, если передан, загружает постер в папку уже созданного фильма,
+// и адрес постера пишется в той же транзакции, без второго изменения с новой версией
+func (db *FilmDatabase) CreateFilm(ctx context.Context, film *f.FilmDTO, upload f.PosterUpload) (uint, error) {
 	filmModel, _ := film.ToModel()
 
 	// Start a transaction
@@ -79,7 +106,7 @@ func (db *FilmDatabase) CreateFilm(film *f.FilmDTO) (uint, error) {
 	db.log.Debug("film model before creation", "film", filmModel)
 
 	// Create the Film record without automatically creating associations
-	if err := tx.Omit("Genres", "Actors").Create(filmModel).Error; err != nil {
+	if err := tx.Omit("Genres", "Credits", "AltTitles", "Countries", "Languages").Create(filmModel).Error; err != nil {
 		tx.Rollback()
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
 			return 0, f.ErrFilmAlreadyExists
@@ -91,17 +118,13 @@ func (db *FilmDatabase) CreateFilm(film *f.FilmDTO) (uint, error) {
 	// Debug: Log the film model after creation
 	db.log.Debug("film model after creation", "filmID", filmModel.FilmId, "film", filmModel)
 
-	// Set FilmID for Genres and Actors
+	// Set FilmID for Genres
 	for i := range filmModel.Genres {
 		filmModel.Genres[i].FilmID = filmModel.FilmId
 	}
-	for i := range filmModel.Actors {
-		filmModel.Actors[i].FilmID = filmModel.FilmId
-	}
 
-	// Debug: Log the genres and actors after setting FilmID
+	// Debug: Log the genres after setting FilmID
 	db.log.Debug("film genres after setting FilmID", "genres", filmModel.Genres)
-	db.log.Debug("film actors after setting FilmID", "actors", filmModel.Actors)
 
 	// Create associated FilmGenre records
 	if len(filmModel.Genres) > 0 {
@@ -112,15 +135,46 @@ func (db *FilmDatabase) CreateFilm(film *f.FilmDTO) (uint, error) {
 		}
 	}
 
-	// Create associated FilmActor records
-	if len(filmModel.Actors) > 0 {
-		if err := tx.Create(&filmModel.Actors).Error; err != nil {
+	// Create associated FilmCredit records
+	if err := replaceFilmCredits(tx, filmModel.FilmId, filmModel.Credits); err != nil {
+		tx.Rollback()
+		db.log.Error("failed to create film credits", "error", err, "film", film)
+		return 0, f.ErrPersonNotFound
+	}
+
+	if err := replaceFilmMetadata(tx, filmModel.FilmId, filmModel); err != nil {
+		tx.Rollback()
+		db.log.Error("failed to create film metadata", "error", err, "film", film)
+		return 0, f.ErrInternal
+	}
+
+	if upload != nil {
+		film.ID = filmModel.FilmId
+		if err := upload(film); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if err := updatePosterColumns(tx, film); err != nil {
 			tx.Rollback()
-			db.log.Error("failed to create film actors", "error", err, "film", film)
-			return 0, f.ErrActorNotFound
+			db.log.Error("failed to set film poster", "error", err, "filmID", film.ID)
+			return 0, f.ErrInternal
 		}
 	}
 
+	if err := audit.Film.Record(ctx, tx, audit.ActionCreate, filmModel.FilmId, nil); err != nil {
+		tx.Rollback()
+		db.log.Error("failed to write audit log", "error", err, "filmID", filmModel.FilmId)
+		return 0, f.ErrInternal
+	}
+
+	// новая запись создается с первой версией
+	created, err := db.saveRevision(ctx, tx, filmModel.FilmId, 1)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	film.Version = created.Version
+
 	// Commit the transaction
 	if err := tx.Commit().Error; err != nil {
 		tx.Rollback()
@@ -131,9 +185,9 @@ func (db *FilmDatabase) CreateFilm(film *f.FilmDTO) (uint, error) {
 	return filmModel.FilmId, nil
 }
 
-func (db *FilmDatabase) UpdateFilm(film *f.FilmDTO) error {
-	filmModel, _ := film.ToModel()
-
+// UpdateFilm - upload, если передан, загружает новый постер после проверки версии, пока строка фильма
+// заблокирована, поэтому параллельное изменение не оставит загруженный постер без фильма
+func (db *FilmDatabase) UpdateFilm(ctx context.Context, film *f.FilmDTO, upload f.PosterUpload) error {
 	// Start a transaction
 	tx := db.db.Begin()
 	defer func() {
@@ -142,15 +196,63 @@ func (db *FilmDatabase) UpdateFilm(film *f.FilmDTO) error {
 		}
 	}()
 
+	before, err := audit.Film.Snapshot(tx, film.ID)
+	if err != nil {
+		tx.Rollback()
+		db.log.Error("failed to read film for audit log", "error", err, "filmID", film.ID)
+		return f.ErrInternal
+	}
+	// фильм в корзине не меняется, Bump корзину не учитывает
+	if before == nil {
+		tx.Rollback()
+		return f.ErrFilmNotFound
+	}
+
+	version, err := optimistic.Bump(tx, "films", "film_id", film.ID, film.Version)
+	if err != nil {
+		tx.Rollback()
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return f.ErrFilmNotFound
+		case errors.Is(err, optimistic.ErrConflict):
+			return err
+		}
+		db.log.Error("failed to bump film version", "error", err, "filmID", film.ID)
+		return f.ErrInternal
+	}
+
+	if upload != nil {
+		if err := upload(film); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	filmModel, _ := film.ToModel()
+
 	// Update the Film record
-	if err := tx.Model(&f.Film{}).Where("film_id = ?", film.ID).Updates(filmModel).Error; err != nil {
+	if err := tx.Model(&f.Film{}).Where("film_id = ?", film.ID).Omit("Credits", "AltTitles", "Countries", "Languages").Updates(filmModel).Error; err != nil {
 		tx.Rollback()
 		db.log.Error("failed to update film", "error", err, "film", film)
 		return f.ErrInternal
 	}
 
-	// Update Genres
-	if len(film.Genres) > 0 {
+	// Updates по структуре пропускает пустые значения, а у постера по умолчанию заглушки нет
+	if film.PosterURL != "" {
+		if err := updatePosterColumns(tx, film); err != nil {
+			tx.Rollback()
+			db.log.Error("failed to update film poster", "error", err, "filmID", film.ID)
+			return f.ErrInternal
+		}
+	}
+
+	if err := replaceFilmMetadata(tx, film.ID, filmModel); err != nil {
+		tx.Rollback()
+		db.log.Error("failed to update film metadata", "error", err, "filmID", film.ID)
+		return f.ErrInternal
+	}
+
+	// Update Genres: контроллер передает только FilmId жанров, они в filmModel.Genres
+	if len(filmModel.Genres) > 0 {
 		// Delete existing genres for the film
 		if err := tx.Where("film_id = ?", film.ID).Delete(&f.FilmGenre{}).Error; err != nil {
 			tx.Rollback()
@@ -169,24 +271,24 @@ func (db *FilmDatabase) UpdateFilm(film *f.FilmDTO) error {
 		}
 	}
 
-	// Update Actors
-	if len(film.Actors) > 0 {
-		// Delete existing actors for the film
-		if err := tx.Where("film_id = ?", film.ID).Delete(&f.FilmActor{}).Error; err != nil {
+	// Update Credits
+	if len(film.Credits) > 0 {
+		if err := replaceFilmCredits(tx, film.ID, filmModel.Credits); err != nil {
 			tx.Rollback()
-			db.log.Error("failed to delete existing actors", "error", err, "filmID", film.ID)
-			return f.ErrInternal
+			db.log.Error("failed to update film credits", "error", err, "filmID", film.ID)
+			return f.ErrPersonNotFound
 		}
+	}
 
-		// Add new actors
-		for _, actor := range filmModel.Actors {
-			actor.FilmID = film.ID // Ensure FilmID is set
-			if err := tx.Create(&actor).Error; err != nil {
-				tx.Rollback()
-				db.log.Error("failed to add actor to film", "error", err, "filmID", film.ID, "actorID", actor.ActorID)
-				return f.ErrInternal
-			}
-		}
+	if err := audit.Film.Record(ctx, tx, audit.ActionUpdate, film.ID, before); err != nil {
+		tx.Rollback()
+		db.log.Error("failed to write audit log", "error", err, "filmID", film.ID)
+		return f.ErrInternal
+	}
+
+	if _, err := db.saveRevision(ctx, tx, film.ID, version); err != nil {
+		tx.Rollback()
+		return err
 	}
 
 	// Commit the transaction
@@ -196,17 +298,157 @@ func (db *FilmDatabase) UpdateFilm(film *f.FilmDTO) error {
 		return f.ErrInternal
 	}
 
+	film.Version = version
+	return nil
+}
+
+// PatchFilm применяет частичное изменение в одной транзакции: меняются только переданные колонки и связи
+func (db *FilmDatabase) PatchFilm(ctx context.Context, p *f.FilmPatch) error {
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.Film.Snapshot(tx, p.ID)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return gorm.ErrRecordNotFound
+		}
+
+		version, err := optimistic.Bump(tx, "films", "film_id", p.ID, p.Version)
+		if err != nil {
+			return err
+		}
+
+		columns := make(map[string]interface{})
+		patch.Put(columns, "content_type", p.ContentType)
+		patch.Put(columns, "title", p.Title)
+		patch.Put(columns, "synopsis", p.Synopsis)
+		patch.Put(columns, "release_date", p.ReleaseDate)
+		if p.Runtime.Set {
+			columns["runtime"] = f.DurationStringToMinutes(p.Runtime.Value)
+		}
+		patch.Put(columns, "original_title", p.OriginalTitle)
+		patch.Put(columns, "tagline", p.Tagline)
+		patch.Put(columns, "age_rating", p.AgeRating)
+		patch.Put(columns, "budget", p.Budget)
+		patch.Put(columns, "box_office", p.BoxOffice)
+		patch.Put(columns, "currency", p.Currency)
+		patch.Put(columns, "imdb_id", p.IMDbID)
+		patch.Put(columns, "kinopoisk_id", p.KinopoiskID)
+		patch.Put(columns, "tmdb_id", p.TMDBID)
+		if len(columns) > 0 {
+			if err := tx.Model(&f.Film{}).Where("film_id = ?", p.ID).Updates(columns).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := patchFilmMetadata(tx, p); err != nil {
+			return err
+		}
+		if err := patchFilmGenres(tx, p); err != nil {
+			return err
+		}
+		if err := patchFilmCredits(tx, p); err != nil {
+			return err
+		}
+
+		if err := audit.Film.Record(ctx, tx, audit.ActionUpdate, p.ID, before); err != nil {
+			return err
+		}
+
+		if _, err := db.saveRevision(ctx, tx, p.ID, version); err != nil {
+			return err
+		}
+
+		p.Version = version
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return f.ErrFilmNotFound
+		case errors.Is(err, optimistic.ErrConflict) || errors.Is(err, f.ErrGenreNotFound) || errors.Is(err, f.ErrPersonNotFound):
+			return err
+		}
+		db.log.Error("failed to patch film", "error", err, "filmID", p.ID)
+		return f.ErrInternal
+	}
+
 	return nil
 }
 
-func (db *FilmDatabase) DeleteFilm(id uint) error {
-	result := db.db.Delete(&f.Film{}, id)
-	if result.Error != nil {
-		db.log.Error("failed to delete film", "error", result.Error, "id", id)
+// UpdatePoster сохраняет только постер фильма: адрес папки, заглушку и цвет. Версия растет без проверки,
+// после изменения в film новая версия
+func (db *FilmDatabase) UpdatePoster(ctx context.Context, film *f.FilmDTO) error {
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.Film.Snapshot(tx, film.ID)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return f.ErrFilmNotFound
+		}
+
+		version, err := optimistic.Bump(tx, "films", "film_id", film.ID, 0)
+		if err != nil {
+			return err
+		}
+
+		if err := updatePosterColumns(tx, film); err != nil {
+			return err
+		}
+
+		if err := audit.Film.Record(ctx, tx, audit.ActionUpdate, film.ID, before); err != nil {
+			return err
+		}
+
+		if _, err := db.saveRevision(ctx, tx, film.ID, version); err != nil {
+			return err
+		}
+
+		film.Version = version
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, f.ErrFilmNotFound) || errors.Is(err, f.ErrInternal) {
+			return err
+		}
+		db.log.Error("failed to update film poster", "error", err, "filmID", film.ID)
 		return f.ErrInternal
 	}
-	if result.RowsAffected == 0 {
-		return f.ErrFilmNotFound
+	return nil
+}
+
+// updatePosterColumns пишет адрес папки постера, заглушку и цвет, не меняя версию фильма
+func updatePosterColumns(tx *gorm.DB, film *f.FilmDTO) error {
+	return tx.Model(&f.Film{}).Where("film_id = ?", film.ID).Updates(map[string]interface{}{
+		"poster_url":      film.PosterURL,
+		"poster_blurhash": film.PosterBlurhash,
+		"poster_color":    film.PosterColor,
+	}).Error
+}
+
+func (db *FilmDatabase) DeleteFilm(ctx context.Context, id uint) error {
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.Film.Snapshot(tx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return f.ErrFilmNotFound
+		}
+
+		if err := tx.Delete(&f.Film{}, id).Error; err != nil {
+			return err
+		}
+
+		return audit.Film.Record(ctx, tx, audit.ActionDelete, id, before)
+	})
+	if err != nil {
+		if errors.Is(err, f.ErrFilmNotFound) {
+			return err
+		}
+		db.log.Error("failed to delete film", "error", err, "id", id)
+		return f.ErrInternal
 	}
 	return nil
 }
@@ -214,16 +456,42 @@ func (db *FilmDatabase) DeleteFilm(id uint) error {
 func (db *FilmDatabase) GetFilms(filters f.FilmFilters, sort f.FilmSort) ([]*f.FilmDTO, error) {
 	query := db.db.Model(&f.Film{}).Joins("LEFT JOIN film_stats ON film_stats.film_id = films.film_id")
 
+	if len(filters.ContentTypes) > 0 {
+		query = query.Where("content_type IN ?", filters.ContentTypes)
+	}
+
+	// фильтр по родительскому жанру включает фильмы всех его поджанров
 	if len(filters.GenreIDs) > 0 {
-		query = query.Joins("JOIN film_genre ON film_genre.film_id = films.id").
-			Where("film_genre.genre_id IN ?", filters.GenreIDs)
+		query = query.Where(`EXISTS (
+			WITH RECURSIVE genre_tree AS (
+				SELECT genre_id FROM genres WHERE genre_id IN ? AND deleted_at IS NULL
+				UNION
+				SELECT g.genre_id FROM genres g JOIN genre_tree t ON g.parent_id = t.genre_id
+				WHERE g.deleted_at IS NULL
+			)
+			SELECT 1 FROM film_genre fg JOIN genre_tree t ON t.genre_id = fg.genre_id
+			WHERE fg.film_id = films.film_id)`, filters.GenreIDs)
 	}
 	if len(filters.ActorIDs) > 0 {
-		query = query.Joins("JOIN film_actor ON film_actor.film_id = films.id").
-			Where("film_actor.actor_id IN ?", filters.ActorIDs)
+		query = query.Where("EXISTS (SELECT 1 FROM film_credits fc WHERE fc.film_id = films.film_id AND fc.role = ? AND fc.person_id IN ?)",
+			per.RoleCast, filters.ActorIDs)
+	}
+	if len(filters.DirectorIDs) > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM film_credits fc WHERE fc.film_id = films.film_id AND fc.role = ? AND fc.person_id IN ?)",
+			per.RoleDirector, filters.DirectorIDs)
+	}
+	if filters.Director != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM film_credits fc JOIN persons p ON p.person_id = fc.person_id AND p.deleted_at IS NULL
+			WHERE fc.film_id = films.film_id AND fc.role = ? AND p.name ILIKE ?)`, per.RoleDirector, "%"+filters.Director+"%")
 	}
 	if filters.Producer != "" {
-		query = query.Where("producer = ?", filters.Producer)
+		query = query.Where(`EXISTS (SELECT 1 FROM film_credits fc JOIN persons p ON p.person_id = fc.person_id AND p.deleted_at IS NULL
+			WHERE fc.film_id = films.film_id AND fc.role = ? AND p.name ILIKE ?)`, per.RoleProducer, "%"+filters.Producer+"%")
+	}
+	if len(filters.Tags) > 0 {
+		// фильм должен быть отмечен всеми тегами, slug тегов уникальны
+		query = query.Where(`(SELECT COUNT(*) FROM film_tags ft JOIN tags t ON t.tag_id = ft.tag_id
+			WHERE ft.film_id = films.film_id AND ft.status = 'approved' AND t.slug IN ?) = ?`, filters.Tags, len(filters.Tags))
 	}
 	if filters.MinRating > 0 {
 		query = query.Where("film_stats.avg_rating >= ?", filters.MinRating)
@@ -243,6 +511,46 @@ func (db *FilmDatabase) GetFilms(filters f.FilmFilters, sort f.FilmSort) ([]*f.F
 	if filters.MaxDuration > 0 {
 		query = query.Where("runtime <= ?", filters.MaxDuration)
 	}
+	if filters.OriginalTitle != "" {
+		pattern := "%" + filters.OriginalTitle + "%"
+		query = query.Where("(original_title ILIKE ? OR EXISTS (SELECT 1 FROM film_alt_titles fat WHERE fat.film_id = films.film_id AND fat.title ILIKE ?))", pattern, pattern)
+	}
+	if filters.Tagline != "" {
+		query = query.Where("tagline ILIKE ?", "%"+filters.Tagline+"%")
+	}
+	if len(filters.Countries) > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM film_countries fc WHERE fc.film_id = films.film_id AND fc.country_code IN ?)", filters.Countries)
+	}
+	if len(filters.Languages) > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM film_languages fl WHERE fl.film_id = films.film_id AND fl.language_code IN ?)", filters.Languages)
+	}
+	if len(filters.AgeRatings) > 0 {
+		query = query.Where("age_rating IN ?", filters.AgeRatings)
+	}
+	if filters.MinBudget > 0 {
+		query = query.Where("budget >= ?", filters.MinBudget)
+	}
+	if filters.MaxBudget > 0 {
+		query = query.Where("budget <= ?", filters.MaxBudget)
+	}
+	if filters.MinBoxOffice > 0 {
+		query = query.Where("box_office >= ?", filters.MinBoxOffice)
+	}
+	if filters.MaxBoxOffice > 0 {
+		query = query.Where("box_office <= ?", filters.MaxBoxOffice)
+	}
+	if filters.Currency != "" {
+		query = query.Where("currency = ?", filters.Currency)
+	}
+	if filters.IMDbID != "" {
+		query = query.Where("imdb_id = ?", filters.IMDbID)
+	}
+	if filters.KinopoiskID != "" {
+		query = query.Where("kinopoisk_id = ?", filters.KinopoiskID)
+	}
+	if filters.TMDBID != "" {
+		query = query.Where("tmdb_id = ?", filters.TMDBID)
+	}
 
 	if sort.By != "" {
 		order := sort.By
@@ -258,11 +566,36 @@ func (db *FilmDatabase) GetFilms(filters f.FilmFilters, sort f.FilmSort) ([]*f.F
 	}
 
 	var films []*f.Film
-	if err := query.Find(&films).Error; err != nil {
+	if err := query.Scopes(preloadMetadata).Find(&films).Error; err != nil {
 		db.log.Error("failed to get films", "error", err, "filters", filters, "sort", sort)
 		return nil, f.ErrInternal
 	}
 
+	filmIDs := make([]uint, 0, len(films))
+	for _, film := range films {
+		filmIDs = append(filmIDs, film.FilmId)
+	}
+	credits, err := db.getCredits(filmIDs)
+	if err != nil {
+		return nil, err
+	}
+	collections, err := db.GetFilmCollections(filmIDs)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := db.GetFilmTags(filmIDs)
+	if err != nil {
+		return nil, err
+	}
+	backdrops, err := db.GetFilmBackdrops(filmIDs)
+	if err != nil {
+		return nil, err
+	}
+	trailers, err := db.GetFilmTrailers(filmIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	var filmDTOs []*f.FilmDTO
 	for _, film := range films {
 		var stats f.FilmStatsModel
@@ -275,12 +608,529 @@ func (db *FilmDatabase) GetFilms(filters f.FilmFilters, sort f.FilmSort) ([]*f.F
 		for _, genre := range film.Genres {
 			filmDTO.GenreIDs = append(filmDTO.GenreIDs, genre.GenreID)
 		}
-		for _, actor := range film.Actors {
-			filmDTO.ActorIDs = append(filmDTO.ActorIDs, actor.ActorID)
-		}
+		filmDTO.Credits = credits[film.FilmId]
+		filmDTO.Collection = collections[film.FilmId]
+		filmDTO.Tags = tags[film.FilmId]
+		filmDTO.Backdrop = backdrops[film.FilmId]
+		filmDTO.Trailer = trailers[film.FilmId]
 
 		filmDTOs = append(filmDTOs, filmDTO)
 	}
 
 	return filmDTOs, nil
 }
+
+type collectionRow struct {
+	FilmID       uint   `gorm:"column:film_id"`
+	CollectionID uint   `gorm:"column:collection_id"`
+	Name         string `gorm:"column:name"`
+	Position     int    `gorm:"column:position"`
+}
+
+// GetFilmCollections возвращает коллекции, в которые входят фильмы, по FilmId
+func (db *FilmDatabase) GetFilmCollections(filmIDs []uint) (map[uint]*f.FilmCollectionDTO, error) {
+	collections := make(map[uint]*f.FilmCollectionDTO, len(filmIDs))
+	if len(filmIDs) == 0 {
+		return collections, nil
+	}
+
+	var rows []collectionRow
+	err := db.db.Table("collection_films cf").
+		Select("cf.film_id, cf.collection_id, c.name, cf.position").
+		Joins("JOIN collections c ON c.collection_id = cf.collection_id").
+		Where("cf.film_id IN ?", filmIDs).
+		Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get film collections", "error", err)
+		return nil, f.ErrInternal
+	}
+
+	for _, row := range rows {
+		collections[row.FilmID] = &f.FilmCollectionDTO{
+			ID:       row.CollectionID,
+			Name:     row.Name,
+			Position: row.Position,
+		}
+	}
+
+	return collections, nil
+}
+
+type tagRow struct {
+	FilmID uint   `gorm:"column:film_id"`
+	TagID  uint   `gorm:"column:tag_id"`
+	Name   string `gorm:"column:name"`
+	Slug   string `gorm:"column:slug"`
+}
+
+// GetFilmTags возвращает одобренные теги фильмов по FilmId, теги с большим числом голосов "за" идут первыми
+func (db *FilmDatabase) GetFilmTags(filmIDs []uint) (map[uint][]f.FilmTagDTO, error) {
+	tags := make(map[uint][]f.FilmTagDTO, len(filmIDs))
+	if len(filmIDs) == 0 {
+		return tags, nil
+	}
+
+	var rows []tagRow
+	err := db.db.Raw(`
+		SELECT ft.film_id, t.tag_id, t.name, t.slug
+		FROM film_tags ft
+		JOIN tags t ON t.tag_id = ft.tag_id
+		LEFT JOIN film_tag_votes v ON v.film_id = ft.film_id AND v.tag_id = ft.tag_id
+		WHERE ft.film_id IN ? AND ft.status = 'approved'
+		GROUP BY ft.film_id, t.tag_id
+		ORDER BY ft.film_id, COALESCE(SUM(v.vote), 0) DESC, t.name`, filmIDs).Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get film tags", "error", err)
+		return nil, f.ErrInternal
+	}
+
+	for _, row := range rows {
+		tags[row.FilmID] = append(tags[row.FilmID], f.FilmTagDTO{
+			ID:   row.TagID,
+			Name: row.Name,
+			Slug: row.Slug,
+		})
+	}
+
+	return tags, nil
+}
+
+type backdropRow struct {
+	FilmID   uint   `gorm:"column:film_id"`
+	URL      string `gorm:"column:url"`
+	Blurhash string `gorm:"column:blurhash"`
+	Color    string `gorm:"column:color"`
+}
+
+// GetFilmBackdrops возвращает основные фоны фильмов из галереи по FilmId
+func (db *FilmDatabase) GetFilmBackdrops(filmIDs []uint) (map[uint]*f.FilmBackdropDTO, error) {
+	backdrops := make(map[uint]*f.FilmBackdropDTO, len(filmIDs))
+	if len(filmIDs) == 0 {
+		return backdrops, nil
+	}
+
+	var rows []backdropRow
+	err := db.db.Raw(`
+		SELECT film_id, url, blurhash, color
+		FROM film_images
+		WHERE film_id IN ? AND image_type = 'backdrop' AND is_primary AND url <> ''`, filmIDs).Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get film backdrops", "error", err)
+		return nil, f.ErrInternal
+	}
+
+	for _, row := range rows {
+		backdrops[row.FilmID] = &f.FilmBackdropDTO{
+			URL:      row.URL,
+			Blurhash: row.Blurhash,
+			Color:    row.Color,
+		}
+	}
+
+	return backdrops, nil
+}
+
+type trailerRow struct {
+	FilmID   uint   `gorm:"column:film_id"`
+	VideoID  uint   `gorm:"column:video_id"`
+	Provider string `gorm:"column:provider"`
+	VideoKey string `gorm:"column:video_key"`
+	Name     string `gorm:"column:name"`
+	Language string `gorm:"column:language"`
+}
+
+// GetFilmTrailers возвращает основные трейлеры фильмов по FilmId: официальный и самый свежий трейлер фильма
+func (db *FilmDatabase) GetFilmTrailers(filmIDs []uint) (map[uint]*f.FilmTrailerDTO, error) {
+	trailers := make(map[uint]*f.FilmTrailerDTO, len(filmIDs))
+	if len(filmIDs) == 0 {
+		return trailers, nil
+	}
+
+	var rows []trailerRow
+	err := db.db.Raw(`
+		SELECT DISTINCT ON (film_id) film_id, video_id, provider, video_key, name, language
+		FROM film_videos
+		WHERE film_id IN ? AND video_type = 'trailer'
+		ORDER BY film_id, official DESC, published_at DESC NULLS LAST, video_id`, filmIDs).Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get film trailers", "error", err)
+		return nil, f.ErrInternal
+	}
+
+	for _, row := range rows {
+		trailers[row.FilmID] = &f.FilmTrailerDTO{
+			ID:       row.VideoID,
+			Provider: row.Provider,
+			Key:      row.VideoKey,
+			Name:     row.Name,
+			Language: row.Language,
+		}
+	}
+
+	return trailers, nil
+}
+
+type creditRow struct {
+	FilmID        uint    `gorm:"column:film_id"`
+	PersonID      uint    `gorm:"column:person_id"`
+	Name          string  `gorm:"column:name"`
+	AvatarURL     *string `gorm:"column:avatar_url"`
+	Role          string  `gorm:"column:role"`
+	CharacterName string  `gorm:"column:character_name"`
+	BillingOrder  int     `gorm:"column:billing_order"`
+
+	AvatarBlurhash string `gorm:"column:avatar_blurhash"`
+	AvatarColor    string `gorm:"column:avatar_color"`
+}
+
+// getCredits загружает титры фильмов вместе с именами и аватарами персон, сгруппированные по FilmId
+func (db *FilmDatabase) getCredits(filmIDs []uint) (map[uint][]f.CreditDTO, error) {
+	credits := make(map[uint][]f.CreditDTO, len(filmIDs))
+	if len(filmIDs) == 0 {
+		return credits, nil
+	}
+
+	var rows []creditRow
+	err := db.db.Raw(`
+		SELECT fc.film_id, fc.person_id, p.name, p.avatar_url, p.avatar_blurhash, p.avatar_color, fc.role, fc.character_name, fc.billing_order
+		FROM film_credits fc
+		JOIN persons p ON p.person_id = fc.person_id AND p.deleted_at IS NULL
+		WHERE fc.film_id IN ?
+		ORDER BY fc.film_id, fc.role, fc.billing_order, fc.credit_id`, filmIDs).Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get film credits", "error", err, "filmIDs", filmIDs)
+		return nil, f.ErrInternal
+	}
+
+	for _, row := range rows {
+		credits[row.FilmID] = append(credits[row.FilmID], f.CreditDTO{
+			PersonID:     row.PersonID,
+			Name:         row.Name,
+			AvatarUrl:    row.AvatarURL,
+			Role:         row.Role,
+			Character:    row.CharacterName,
+			BillingOrder: row.BillingOrder,
+
+			AvatarBlurhash: row.AvatarBlurhash,
+			AvatarColor:    row.AvatarColor,
+		})
+	}
+
+	return credits, nil
+}
+
+// replaceFilmCredits заменяет титры фильма на переданные
+func replaceFilmCredits(tx *gorm.DB, filmID uint, credits []f.FilmCredit) error {
+	if err := tx.Where("film_id = ?", filmID).Delete(&f.FilmCredit{}).Error; err != nil {
+		return err
+	}
+
+	for i := range credits {
+		credits[i].FilmID = filmID
+	}
+
+	if len(credits) > 0 {
+		if err := tx.Create(&credits).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchFilmMetadata заменяет переданные списки альтернативных названий, стран и языков, null очищает список
+func patchFilmMetadata(tx *gorm.DB, p *f.FilmPatch) error {
+	if p.AltTitles.Set {
+		rows := make([]f.FilmAltTitle, 0, len(p.AltTitles.Value))
+		for _, title := range p.AltTitles.Value {
+			rows = append(rows, f.FilmAltTitle{FilmID: p.ID, Title: title})
+		}
+		if err := replaceFilmRows(tx, p.ID, rows); err != nil {
+			return err
+		}
+	}
+	if p.Countries.Set {
+		rows := make([]f.FilmCountry, 0, len(p.Countries.Value))
+		for _, code := range p.Countries.Value {
+			rows = append(rows, f.FilmCountry{FilmID: p.ID, CountryCode: code})
+		}
+		if err := replaceFilmRows(tx, p.ID, rows); err != nil {
+			return err
+		}
+	}
+	if p.Languages.Set {
+		rows := make([]f.FilmLanguage, 0, len(p.Languages.Value))
+		for _, code := range p.Languages.Value {
+			rows = append(rows, f.FilmLanguage{FilmID: p.ID, LanguageCode: code})
+		}
+		if err := replaceFilmRows(tx, p.ID, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchFilmGenres заменяет жанры, если передан список, затем убирает и добавляет отдельные жанры
+func patchFilmGenres(tx *gorm.DB, p *f.FilmPatch) error {
+	if p.GenreIDs.Set {
+		if err := requireIDs(tx, "genres", "genre_id", p.GenreIDs.Value, f.ErrGenreNotFound); err != nil {
+			return err
+		}
+		rows := make([]f.FilmGenre, 0, len(p.GenreIDs.Value))
+		for _, genreID := range p.GenreIDs.Value {
+			rows = append(rows, f.FilmGenre{FilmID: p.ID, GenreID: genreID})
+		}
+		if err := replaceFilmRows(tx, p.ID, rows); err != nil {
+			return err
+		}
+	}
+
+	if len(p.RemoveGenreIDs) > 0 {
+		if err := tx.Where("film_id = ? AND genre_id IN ?", p.ID, p.RemoveGenreIDs).Delete(&f.FilmGenre{}).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(p.AddGenreIDs) > 0 {
+		if err := requireIDs(tx, "genres", "genre_id", p.AddGenreIDs, f.ErrGenreNotFound); err != nil {
+			return err
+		}
+		rows := make([]f.FilmGenre, 0, len(p.AddGenreIDs))
+		for _, genreID := range p.AddGenreIDs {
+			rows = append(rows, f.FilmGenre{FilmID: p.ID, GenreID: genreID})
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchFilmCredits заменяет участия, если передан список, затем убирает и добавляет отдельные участия.
+// Добавленное участие заменяет участие той же персоны в той же роли, поэтому повтор запроса ничего не дублирует
+func patchFilmCredits(tx *gorm.DB, p *f.FilmPatch) error {
+	if p.Credits.Set {
+		if err := requireIDs(tx, "persons", "person_id", creditPersonIDs(p.Credits.Value), f.ErrPersonNotFound); err != nil {
+			return err
+		}
+		if err := replaceFilmCredits(tx, p.ID, toCreditModels(p.Credits.Value)); err != nil {
+			return err
+		}
+	}
+
+	for _, credits := range [][]f.CreditDTO{p.RemoveCredits, p.AddCredits} {
+		for _, credit := range credits {
+			query := tx.Where("film_id = ? AND person_id = ?", p.ID, credit.PersonID)
+			if credit.Role != "" {
+				query = query.Where("role = ?", credit.Role)
+			}
+			if err := query.Delete(&f.FilmCredit{}).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(p.AddCredits) > 0 {
+		if err := requireIDs(tx, "persons", "person_id", creditPersonIDs(p.AddCredits), f.ErrPersonNotFound); err != nil {
+			return err
+		}
+		credits := toCreditModels(p.AddCredits)
+		for i := range credits {
+			credits[i].FilmID = p.ID
+		}
+		if err := tx.Create(&credits).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceFilmRows удаляет строки фильма из таблицы модели T и вставляет rows
+func replaceFilmRows[T any](tx *gorm.DB, filmID uint, rows []T) error {
+	var model T
+	if err := tx.Where("film_id = ?", filmID).Delete(&model).Error; err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Create(&rows).Error
+}
+
+// requireIDs возвращает notFound, если каких-то id нет в таблице
+func requireIDs(tx *gorm.DB, table, column string, ids []uint, notFound error) error {
+	unique := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+
+	var count int64
+	// жанры и персоны из корзины считаются отсутствующими
+	if err := tx.Table(table).Where(column+" IN ? AND deleted_at IS NULL", ids).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(unique) {
+		return notFound
+	}
+	return nil
+}
+
+func creditPersonIDs(credits []f.CreditDTO) []uint {
+	ids := make([]uint, 0, len(credits))
+	for _, credit := range credits {
+		ids = append(ids, credit.PersonID)
+	}
+	return ids
+}
+
+func toCreditModels(credits []f.CreditDTO) []f.FilmCredit {
+	models := make([]f.FilmCredit, 0, len(credits))
+	for _, credit := range credits {
+		models = append(models, f.FilmCredit{
+			PersonID:      credit.PersonID,
+			Role:          credit.Role,
+			CharacterName: credit.Character,
+			BillingOrder:  credit.BillingOrder,
+		})
+	}
+	return models
+}
+
+// preloadMetadata подгружает альтернативные названия, страны и языки фильма
+func preloadMetadata(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("AltTitles").Preload("Countries").Preload("Languages")
+}
+
+// replaceFilmMetadata заменяет альтернативные названия, страны и языки фильма на переданные в модели
+func replaceFilmMetadata(tx *gorm.DB, filmID uint, film *f.Film) error {
+	if err := tx.Where("film_id = ?", filmID).Delete(&f.FilmAltTitle{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("film_id = ?", filmID).Delete(&f.FilmCountry{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("film_id = ?", filmID).Delete(&f.FilmLanguage{}).Error; err != nil {
+		return err
+	}
+
+	for i := range film.AltTitles {
+		film.AltTitles[i].FilmID = filmID
+	}
+	for i := range film.Countries {
+		film.Countries[i].FilmID = filmID
+	}
+	for i := range film.Languages {
+		film.Languages[i].FilmID = filmID
+	}
+
+	if len(film.AltTitles) > 0 {
+		if err := tx.Create(&film.AltTitles).Error; err != nil {
+			return err
+		}
+	}
+	if len(film.Countries) > 0 {
+		if err := tx.Create(&film.Countries).Error; err != nil {
+			return err
+		}
+	}
+	if len(film.Languages) > 0 {
+		if err := tx.Create(&film.Languages).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *FilmDatabase) GetFilmOverlaps(filmID uint, limit int) ([]*f.FilmOverlap, error) {
+	var overlaps []*f.FilmOverlap
+
+	err := db.db.Raw(`
+		SELECT film_id, SUM(shared_genres) AS shared_genres, SUM(shared_actors) AS shared_actors
+		FROM (
+			SELECT fg2.film_id, COUNT(*) AS shared_genres, 0 AS shared_actors
+			FROM film_genre fg1
+			JOIN film_genre fg2 ON fg2.genre_id = fg1.genre_id AND fg2.film_id <> fg1.film_id
+			WHERE fg1.film_id = ?
+			GROUP BY fg2.film_id
+			UNION ALL
+			SELECT fc2.film_id, 0 AS shared_genres, COUNT(DISTINCT fc2.person_id) AS shared_actors
+			FROM film_credits fc1
+			JOIN film_credits fc2 ON fc2.person_id = fc1.person_id AND fc2.film_id <> fc1.film_id AND fc2.role = fc1.role
+			WHERE fc1.film_id = ? AND fc1.role = 'cast'
+			GROUP BY fc2.film_id
+		) overlaps
+		WHERE film_id IN (SELECT film_id FROM films WHERE deleted_at IS NULL)
+		GROUP BY film_id
+		ORDER BY SUM(shared_actors) DESC, SUM(shared_genres) DESC
+		LIMIT ?`, filmID, filmID, limit).Scan(&overlaps).Error
+	if err != nil {
+		db.log.Error("failed to get film overlaps", "error", err, "filmID", filmID)
+		return nil, f.ErrInternal
+	}
+
+	return overlaps, nil
+}
+
+// GetFilmCoRatings считает косинусную похожесть оценок (центрированных относительно 50)
+// у пользователей, которые оценили оба фильма. Учитываются только отзывы на фильм или сериал целиком.
+func (db *FilmDatabase) GetFilmCoRatings(filmID uint, minReviewers int, limit int) ([]*f.FilmCoRating, error) {
+	var coRatings []*f.FilmCoRating
+
+	err := db.db.Raw(`
+		SELECT r2.film_id,
+		       COUNT(*) AS common_reviewers,
+		       COALESCE(SUM((r1.rating - 50) * (r2.rating - 50)) /
+		           NULLIF(SQRT(SUM((r1.rating - 50) ^ 2)) * SQRT(SUM((r2.rating - 50) ^ 2)), 0), 0) AS similarity
+		FROM reviews r1
+		JOIN reviews r2 ON r2.user_id = r1.user_id AND r2.film_id <> r1.film_id
+		                AND r2.season_id IS NULL AND r2.episode_id IS NULL
+		WHERE r1.film_id = ? AND r1.season_id IS NULL AND r1.episode_id IS NULL
+		  AND r2.film_id IN (SELECT film_id FROM films WHERE deleted_at IS NULL)
+		GROUP BY r2.film_id
+		HAVING COUNT(*) >= ?
+		ORDER BY similarity DESC
+		LIMIT ?`, filmID, minReviewers, limit).Scan(&coRatings).Error
+	if err != nil {
+		db.log.Error("failed to get film co-ratings", "error", err, "filmID", filmID)
+		return nil, f.ErrInternal
+	}
+
+	return coRatings, nil
+}
+
+// GetEpisodeTitles возвращает названия серий сериала в порядке выхода
+func (db *FilmDatabase) GetEpisodeTitles(filmID uint) ([]string, error) {
+	var titles []string
+
+	err := db.db.Raw(`
+		SELECT e.title
+		FROM episodes e
+		JOIN seasons s ON s.season_id = e.season_id
+		WHERE s.film_id = ? AND e.title <> ''
+		ORDER BY s.season_number, e.episode_number`, filmID).Scan(&titles).Error
+	if err != nil {
+		db.log.Error("failed to get episode titles", "error", err, "filmID", filmID)
+		return nil, f.ErrInternal
+	}
+
+	return titles, nil
+}
+
+// saveRevision сохраняет в транзакции изменения снимок фильма со всеми связями на версии version
+func (db *FilmDatabase) saveRevision(ctx context.Context, tx *gorm.DB, id uint, version int) (*f.FilmDTO, error) {
+	film, err := (&FilmDatabase{db: tx, log: db.log}).GetFilmByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := revision.Save(ctx, tx, revision.EntityFilm, id, version, film); err != nil {
+		db.log.Error("failed to save film revision", "error", err, "filmID", id)
+		return nil, f.ErrInternal
+	}
+	return film, nil
+}
@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"server/internal/init/elasticsearch"
 	f "server/internal/modules/film"
+	per "server/internal/modules/person"
+	"server/pkg/middleware/locale"
 )
 
 type FilmEs struct {
@@ -22,26 +24,123 @@ func NewFilmEs(log *slog.Logger, s *elasticsearch.Search) *FilmEs {
 }
 
 type FilmSearchDTO struct {
-	ID       uint     `json:"id"`
-	Title    string   `json:"title"`
-	Synopsis string   `json:"synopsis"`
-	Producer string   `json:"producer"`
-	Genres   []string `json:"genres"`
-	Actors   []string `json:"actors"`
+	ID            uint     `json:"id"`
+	Title         string   `json:"title"`
+	OriginalTitle string   `json:"original_title"`
+	AltTitles     []string `json:"alt_titles"`
+	Tagline       string   `json:"tagline"`
+	Synopsis      string   `json:"synopsis"`
+	Genres        []string `json:"genres"`
+	Tags          []string `json:"tags"`
+	Actors        []string `json:"actors"`
+	Characters    []string `json:"characters"`
+	Directors     []string `json:"directors"`
+	Writers       []string `json:"writers"`
+	Producers     []string `json:"producers"`
+	Composers     []string `json:"composers"`
+	Countries     []string `json:"countries"`
+	Languages     []string `json:"languages"`
+	AgeRating     string   `json:"age_rating"`
+	ExternalIDs   []string `json:"external_ids"`
+	ContentType   string   `json:"content_type"`
+	EpisodeTitles []string `json:"episode_titles"`
+
+	// Поля на каждом языке, анализаторы для них задаются при старте (init/elasticsearch)
+	TitleRu    string `json:"title_ru,omitempty"`
+	TitleEn    string `json:"title_en,omitempty"`
+	TaglineRu  string `json:"tagline_ru,omitempty"`
+	TaglineEn  string `json:"tagline_en,omitempty"`
+	SynopsisRu string `json:"synopsis_ru,omitempty"`
+	SynopsisEn string `json:"synopsis_en,omitempty"`
+}
+
+// searchFields - поля полнотекстового поиска с весами
+var searchFields = []string{
+	"title^3", "original_title^3", "alt_titles^2", "tagline", "synopsis", "genres", "tags^2",
+	"actors", "characters", "directors^2", "writers", "producers", "composers",
+	"countries", "languages", "age_rating", "external_ids", "episode_titles",
+}
+
+// localizedSearchFields - поля на каждом языке, поля на языке запроса весят больше
+func localizedSearchFields(lang string) []string {
+	fields := make([]string, 0, len(locale.Supported)*3)
+	for _, l := range locale.Supported {
+		if l == lang {
+			fields = append(fields, "title_"+l+"^4", "tagline_"+l+"^2", "synopsis_"+l+"^2")
+			continue
+		}
+		fields = append(fields, "title_"+l+"^3", "tagline_"+l, "synopsis_"+l)
+	}
+	return fields
 }
 
-func (es *FilmEs) SearchFilms(query string) ([]uint, error) {
+func (es *FilmEs) SearchFilms(query string, lang string) ([]uint, error) {
 	// Создаем JSON-запрос для поиска
 	searchQuery := map[string]interface{}{
 		"query": map[string]interface{}{
 			"multi_match": map[string]interface{}{
 				"query":  query,
-				"fields": []string{"title", "synopsis", "producer", "genres", "actors"},
+				"fields": append(localizedSearchFields(lang), searchFields...),
 			},
 		},
 		"_source": []string{"id"}, // Запрашиваем только FilmId
 	}
 
+	hits, err := es.search(searchQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.Source.ID)
+	}
+
+	es.log.Info("Успешный поиск", slog.Int("найдено фильмов", len(ids)))
+	return ids, nil
+}
+
+// SearchSimilarFilms ищет фильмы, похожие на указанный по названию и описанию (more_like_this).
+// Возвращает FilmId найденных фильмов и их релевантность.
+func (es *FilmEs) SearchSimilarFilms(filmID uint, limit int) (map[uint]float64, error) {
+	searchQuery := map[string]interface{}{
+		"query": map[string]interface{}{
+			"more_like_this": map[string]interface{}{
+				"fields": []string{"title", "original_title", "tagline", "synopsis", "tags", "title_ru", "title_en", "synopsis_ru", "synopsis_en"},
+				"like": []map[string]interface{}{
+					{"_index": es.s.Index, "_id": fmt.Sprint(filmID)},
+				},
+				"min_term_freq":   1,
+				"min_doc_freq":    1,
+				"max_query_terms": 25,
+			},
+		},
+		"size":    limit,
+		"_source": []string{"id"},
+	}
+
+	hits, err := es.search(searchQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[uint]float64, len(hits))
+	for _, hit := range hits {
+		scores[hit.Source.ID] = hit.Score
+	}
+
+	return scores, nil
+}
+
+type searchHit struct {
+	Score  float64 `json:"_score"`
+	Source struct {
+		ID uint `json:"id"`
+	} `json:"_source"`
+}
+
+// search выполняет запрос к индексу фильмов и возвращает найденные документы
+func (es *FilmEs) search(searchQuery map[string]interface{}) ([]searchHit, error) {
 	// Преобразуем запрос в JSON
 	queryJSON, err := json.Marshal(searchQuery)
 	if err != nil {
@@ -73,43 +172,75 @@ func (es *FilmEs) SearchFilms(query string) ([]uint, error) {
 	}
 
 	// Разбираем результаты поиска
-	var result map[string]interface{}
+	var result struct {
+		Hits struct {
+			Hits []searchHit `json:"hits"`
+		} `json:"hits"`
+	}
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
 		es.log.Error("Ошибка при разборе результатов поиска", slog.Any("error", err))
 		return nil, err
 	}
 
-	// Извлекаем FilmId фильмов из ответа
-	hits := result["hits"].(map[string]interface{})["hits"].([]interface{})
-	ids := make([]uint, 0, len(hits))
-
-	for _, hit := range hits {
-		source := hit.(map[string]interface{})["_source"]
-		id := uint(source.(map[string]interface{})["id"].(float64))
-		ids = append(ids, id)
-	}
-
-	es.log.Info("Успешный поиск", slog.Int("найдено фильмов", len(ids)))
-	return ids, nil
+	return result.Hits.Hits, nil
 }
 
-func (es *FilmEs) IndexFilm(film *f.FilmDTO) error {
+// IndexFilm индексирует фильм. Для сериалов episodeTitles - названия серий, чтобы сериал находился по ним.
+// localized - поля title, tagline и synopsis на каждом языке
+func (es *FilmEs) IndexFilm(film *f.FilmDTO, episodeTitles []string, localized map[string]map[string]string) error {
 	// Создаем структуру для индексации
 	filmSearch := FilmSearchDTO{
-		ID:       film.ID,
-		Title:    film.Title,
-		Synopsis: film.Synopsis,
-		Producer: film.Producer,
-		Genres:   make([]string, 0, len(film.Genres)),
-		Actors:   make([]string, 0, len(film.Actors)),
+		ID:            film.ID,
+		Title:         film.Title,
+		OriginalTitle: film.OriginalTitle,
+		AltTitles:     film.AltTitles,
+		Tagline:       film.Tagline,
+		Synopsis:      film.Synopsis,
+		Genres:        make([]string, 0, len(film.Genres)),
+		Tags:          make([]string, 0, len(film.Tags)),
+		Countries:     film.Countries,
+		Languages:     film.Languages,
+		AgeRating:     film.AgeRating,
+		ContentType:   film.ContentType,
+		EpisodeTitles: episodeTitles,
+		TitleRu:       localized[locale.RU]["title"],
+		TitleEn:       localized[locale.EN]["title"],
+		TaglineRu:     localized[locale.RU]["tagline"],
+		TaglineEn:     localized[locale.EN]["tagline"],
+		SynopsisRu:    localized[locale.RU]["synopsis"],
+		SynopsisEn:    localized[locale.EN]["synopsis"],
+	}
+
+	// Внешние идентификаторы индексируются как есть, чтобы фильм находился по "tt0111161"
+	for _, externalID := range []string{film.ExternalIDs.IMDb, film.ExternalIDs.Kinopoisk, film.ExternalIDs.TMDB} {
+		if externalID != "" {
+			filmSearch.ExternalIDs = append(filmSearch.ExternalIDs, externalID)
+		}
 	}
 
-	// Преобразуем жанры и актеров в строки
+	// Преобразуем жанры, теги и титры в строки
 	for _, genre := range film.Genres {
 		filmSearch.Genres = append(filmSearch.Genres, genre.Name)
 	}
-	for _, actor := range film.Actors {
-		filmSearch.Actors = append(filmSearch.Actors, actor.Name)
+	for _, tag := range film.Tags {
+		filmSearch.Tags = append(filmSearch.Tags, tag.Name)
+	}
+	for _, credit := range film.Credits {
+		switch credit.Role {
+		case per.RoleCast:
+			filmSearch.Actors = append(filmSearch.Actors, credit.Name)
+			if credit.Character != "" {
+				filmSearch.Characters = append(filmSearch.Characters, credit.Character)
+			}
+		case per.RoleDirector:
+			filmSearch.Directors = append(filmSearch.Directors, credit.Name)
+		case per.RoleWriter:
+			filmSearch.Writers = append(filmSearch.Writers, credit.Name)
+		case per.RoleProducer:
+			filmSearch.Producers = append(filmSearch.Producers, credit.Name)
+		case per.RoleComposer:
+			filmSearch.Composers = append(filmSearch.Composers, credit.Name)
+		}
 	}
 
 	// Преобразуем фильм в JSON
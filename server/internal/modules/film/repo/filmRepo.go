@@ -1,32 +1,47 @@
 package repo
 
 import (
+	"context"
 	f "server/internal/modules/film"
 	"time"
 )
 
 type FilmDB interface {
 	GetFilmByID(id uint) (*f.FilmDTO, error)
-	CreateFilm(film *f.FilmDTO) (uint, error)
-	UpdateFilm(film *f.FilmDTO) error
-	DeleteFilm(id uint) error
+	CreateFilm(ctx context.Context, film *f.FilmDTO, upload f.PosterUpload) (uint, error)
+	UpdateFilm(ctx context.Context, film *f.FilmDTO, upload f.PosterUpload) error
+	PatchFilm(ctx context.Context, patch *f.FilmPatch) error
+	UpdatePoster(ctx context.Context, film *f.FilmDTO) error
+	DeleteFilm(ctx context.Context, id uint) error
 	GetFilms(filters f.FilmFilters, sort f.FilmSort) ([]*f.FilmDTO, error)
+	GetFilmOverlaps(filmID uint, limit int) ([]*f.FilmOverlap, error)
+	GetFilmCoRatings(filmID uint, minReviewers int, limit int) ([]*f.FilmCoRating, error)
+	GetEpisodeTitles(filmID uint) ([]string, error)
+	GetFilmCollections(filmIDs []uint) (map[uint]*f.FilmCollectionDTO, error)
+	GetFilmTags(filmIDs []uint) (map[uint][]f.FilmTagDTO, error)
+	GetFilmBackdrops(filmIDs []uint) (map[uint]*f.FilmBackdropDTO, error)
+	GetFilmTrailers(filmIDs []uint) (map[uint]*f.FilmTrailerDTO, error)
 }
 
 type FilmCache interface {
-	GetFilmsFromCache(key string) ([]*f.FilmDTO, error)
-	SetFilmsToCache(key string, films interface{}, ttl time.Duration) error
-	DeleteFilmFromCache(key string) error
+	LoadFilm(id uint, ttl time.Duration, load func() (*f.FilmDTO, error)) (*f.FilmDTO, error)
+	LoadFilms(key string, ttl time.Duration, load func() ([]*f.FilmDTO, error)) ([]*f.FilmDTO, error)
+	LoadSimilarFilms(id uint, ttl time.Duration, load func() ([]*f.SimilarFilmDTO, error)) ([]*f.SimilarFilmDTO, error)
+	InvalidateFilm(id uint) error
+	InvalidateFilmLists() error
 }
 
 type FilmS3 interface {
-	UploadPoster(filmID uint, file []byte) (string, error)
+	UploadPoster(filmID uint, variants map[string][]byte) (string, error)
 	DeletePoster(filmID uint) error
+	DeleteImages(filmID uint) error
+	DefaultPosterURL() string
 }
 
 type FilmES interface {
-	SearchFilms(query string) ([]uint, error)
-	IndexFilm(film *f.FilmDTO) error
+	SearchFilms(query string, lang string) ([]uint, error)
+	SearchSimilarFilms(filmID uint, limit int) (map[uint]float64, error)
+	IndexFilm(film *f.FilmDTO, episodeTitles []string, localized map[string]map[string]string) error
 	DeleteFilmFromIndex(filmID uint) error
 }
 
@@ -50,50 +65,106 @@ func (r *Repo) GetFilmByID(id uint) (*f.FilmDTO, error) {
 	return r.db.GetFilmByID(id)
 }
 
-func (r *Repo) CreateFilm(film *f.FilmDTO) (uint, error) {
-	return r.db.CreateFilm(film)
+func (r *Repo) CreateFilm(ctx context.Context, film *f.FilmDTO, upload f.PosterUpload) (uint, error) {
+	return r.db.CreateFilm(ctx, film, upload)
 }
 
-func (r *Repo) UpdateFilm(film *f.FilmDTO) error {
-	return r.db.UpdateFilm(film)
+func (r *Repo) UpdateFilm(ctx context.Context, film *f.FilmDTO, upload f.PosterUpload) error {
+	return r.db.UpdateFilm(ctx, film, upload)
 }
 
-func (r *Repo) DeleteFilm(id uint) error {
-	return r.db.DeleteFilm(id)
+func (r *Repo) PatchFilm(ctx context.Context, patch *f.FilmPatch) error {
+	return r.db.PatchFilm(ctx, patch)
+}
+
+func (r *Repo) UpdatePoster(ctx context.Context, film *f.FilmDTO) error {
+	return r.db.UpdatePoster(ctx, film)
+}
+
+func (r *Repo) DeleteFilm(ctx context.Context, id uint) error {
+	return r.db.DeleteFilm(ctx, id)
 }
 
 func (r *Repo) GetFilms(filters f.FilmFilters, sort f.FilmSort) ([]*f.FilmDTO, error) {
 	return r.db.GetFilms(filters, sort)
 }
 
-func (r *Repo) SearchFilms(query string) ([]uint, error) {
-	return r.es.SearchFilms(query)
+func (r *Repo) GetFilmOverlaps(filmID uint, limit int) ([]*f.FilmOverlap, error) {
+	return r.db.GetFilmOverlaps(filmID, limit)
+}
+
+func (r *Repo) GetFilmCoRatings(filmID uint, minReviewers int, limit int) ([]*f.FilmCoRating, error) {
+	return r.db.GetFilmCoRatings(filmID, minReviewers, limit)
+}
+
+func (r *Repo) GetEpisodeTitles(filmID uint) ([]string, error) {
+	return r.db.GetEpisodeTitles(filmID)
+}
+
+func (r *Repo) GetFilmCollections(filmIDs []uint) (map[uint]*f.FilmCollectionDTO, error) {
+	return r.db.GetFilmCollections(filmIDs)
+}
+
+func (r *Repo) GetFilmTags(filmIDs []uint) (map[uint][]f.FilmTagDTO, error) {
+	return r.db.GetFilmTags(filmIDs)
+}
+
+func (r *Repo) GetFilmBackdrops(filmIDs []uint) (map[uint]*f.FilmBackdropDTO, error) {
+	return r.db.GetFilmBackdrops(filmIDs)
 }
 
-func (r *Repo) IndexFilm(film *f.FilmDTO) error {
-	return r.es.IndexFilm(film)
+func (r *Repo) GetFilmTrailers(filmIDs []uint) (map[uint]*f.FilmTrailerDTO, error) {
+	return r.db.GetFilmTrailers(filmIDs)
+}
+
+func (r *Repo) SearchFilms(query string, lang string) ([]uint, error) {
+	return r.es.SearchFilms(query, lang)
+}
+
+func (r *Repo) SearchSimilarFilms(filmID uint, limit int) (map[uint]float64, error) {
+	return r.es.SearchSimilarFilms(filmID, limit)
+}
+
+func (r *Repo) IndexFilm(film *f.FilmDTO, episodeTitles []string, localized map[string]map[string]string) error {
+	return r.es.IndexFilm(film, episodeTitles, localized)
 }
 
 func (r *Repo) DeleteFilmFromIndex(filmID uint) error {
 	return r.es.DeleteFilmFromIndex(filmID)
 }
 
-func (r *Repo) GetFilmsFromCache(key string) ([]*f.FilmDTO, error) {
-	return r.ch.GetFilmsFromCache(key)
+func (r *Repo) LoadFilm(id uint, ttl time.Duration, load func() (*f.FilmDTO, error)) (*f.FilmDTO, error) {
+	return r.ch.LoadFilm(id, ttl, load)
+}
+
+func (r *Repo) LoadFilms(key string, ttl time.Duration, load func() ([]*f.FilmDTO, error)) ([]*f.FilmDTO, error) {
+	return r.ch.LoadFilms(key, ttl, load)
+}
+
+func (r *Repo) LoadSimilarFilms(id uint, ttl time.Duration, load func() ([]*f.SimilarFilmDTO, error)) ([]*f.SimilarFilmDTO, error) {
+	return r.ch.LoadSimilarFilms(id, ttl, load)
 }
 
-func (r *Repo) SetFilmsToCache(key string, films interface{}, ttl time.Duration) error {
-	return r.ch.SetFilmsToCache(key, films, ttl)
+func (r *Repo) InvalidateFilm(id uint) error {
+	return r.ch.InvalidateFilm(id)
 }
 
-func (r *Repo) DeleteFilmFromCache(key string) error {
-	return r.ch.DeleteFilmFromCache(key)
+func (r *Repo) InvalidateFilmLists() error {
+	return r.ch.InvalidateFilmLists()
 }
 
-func (r *Repo) UploadPoster(filmID uint, file []byte) (string, error) {
-	return r.s3.UploadPoster(filmID, file)
+func (r *Repo) UploadPoster(filmID uint, variants map[string][]byte) (string, error) {
+	return r.s3.UploadPoster(filmID, variants)
 }
 
 func (r *Repo) DeletePoster(filmID uint) error {
-	return r.db.DeleteFilm(filmID)
+	return r.s3.DeletePoster(filmID)
+}
+
+func (r *Repo) DeleteImages(filmID uint) error {
+	return r.s3.DeleteImages(filmID)
+}
+
+func (r *Repo) DefaultPosterURL() string {
+	return r.s3.DefaultPosterURL()
 }
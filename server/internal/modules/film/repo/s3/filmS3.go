@@ -1,52 +1,54 @@
 package s3
 
 import (
-	"bytes"
-	"context"
 	"fmt"
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"log/slog"
-	s3Storage "server/internal/init/s3"
+	"server/internal/init/blob"
+	avatarManager "server/pkg/lib/avatarMenager"
 )
 
 type FilmS3 struct {
 	log    *slog.Logger
-	s3     *s3Storage.S3Storage
+	store  blob.Store
 	bucket string
 }
 
-func NewFilmS3(log *slog.Logger, s3 *s3Storage.S3Storage) *FilmS3 {
-	return &FilmS3{log: log, s3: s3, bucket: "filmposter"}
+func NewFilmS3(log *slog.Logger, store blob.Store) *FilmS3 {
+	return &FilmS3{log: log, store: store, bucket: "filmposter"}
 }
 
-func (s *FilmS3) UploadPoster(filmID uint, file []byte) (string, error) {
-	objectKey := fmt.Sprintf("/posters/%d", filmID)
+// UploadPoster загружает все размеры постера в папку фильма с хэшем содержимого и возвращает адрес папки.
+// Прежняя папка остается, пока ее не удалит cron (см. media_objects)
+func (s *FilmS3) UploadPoster(filmID uint, variants map[string][]byte) (string, error) {
+	folder := fmt.Sprintf("posters/%d/%s/", filmID, avatarManager.ContentHash(variants))
 
-	uploadInput := &s3.PutObjectInput{
-		Bucket:      aws.String(s.bucket),
-		Key:         aws.String(objectKey),
-		Body:        bytes.NewReader(file),
-		ContentType: aws.String("image/webp"),
+	objects := make(map[string][]byte, len(variants))
+	for name, data := range variants {
+		objects[folder+avatarManager.VariantKey(name)] = data
 	}
 
-	_, err := s.s3.Client.PutObject(context.TODO(), uploadInput)
-	if err != nil {
+	if err := blob.PutImages(s.store, s.bucket, objects); err != nil {
 		return "", err
 	}
 
-	posterUrl := fmt.Sprintf("https://%s.%s%s", s.bucket, s.s3.Endpoint, objectKey)
-	return posterUrl, err
+	return s.store.URL(s.bucket, folder), nil
 }
 
+// DeletePoster удаляет все папки с размерами постера и постер старого формата одним файлом
 func (s *FilmS3) DeletePoster(filmID uint) error {
-	objectKey := fmt.Sprintf("/posters/%d", filmID)
-
-	deleteInput := &s3.DeleteObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(objectKey),
+	if err := s.store.DeletePrefix(s.bucket, fmt.Sprintf("posters/%d/", filmID)); err != nil {
+		return err
 	}
 
-	_, err := s.s3.Client.DeleteObject(context.TODO(), deleteInput)
-	return err
+	return s.store.Delete(s.bucket, fmt.Sprintf("/posters/%d", filmID))
+}
+
+// DeleteImages удаляет папку галереи фильма со всеми изображениями (модуль media)
+func (s *FilmS3) DeleteImages(filmID uint) error {
+	return s.store.DeletePrefix(s.bucket, fmt.Sprintf("images/%d/", filmID))
+}
+
+// DefaultPosterURL - постер по умолчанию, загруженный при создании бакета
+func (s *FilmS3) DefaultPosterURL() string {
+	return s.store.URL(s.bucket, "default/800x1200.webp")
 }
@@ -1,143 +1,336 @@
 package usecase
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"mime/multipart"
 	f "server/internal/modules/film"
+	tr "server/internal/modules/translation"
 	avatarManager "server/pkg/lib/avatarMenager"
+	"server/pkg/middleware/locale"
+	"sort"
 	"strings"
 	"time"
 )
 
+const (
+	similarCandidatesLimit = 50
+	similarMinCoReviewers  = 3
+
+	similarWeightText     = 0.4
+	similarWeightGenres   = 0.2
+	similarWeightActors   = 0.3
+	similarWeightCoRating = 0.1
+)
+
+// Translator - переводы полей фильмов, жанров и персон (модуль translation)
+type Translator interface {
+	ResolveTranslations(entityType string, entityIDs []uint, locales []string) (map[uint]map[string]string, error)
+}
+
 type FilmUseCase struct {
 	log *slog.Logger
 	rp  f.Repo
+	tr  Translator
 }
 
-func NewFilmUsecase(rp f.Repo, l *slog.Logger) *FilmUseCase {
+func NewFilmUsecase(rp f.Repo, l *slog.Logger, translator Translator) *FilmUseCase {
 	return &FilmUseCase{
 		log: l,
 		rp:  rp,
+		tr:  translator,
 	}
 }
 
 func (uc *FilmUseCase) GetFilmByID(id uint) (*f.FilmDTO, error) {
-	cacheKey := fmt.Sprintf("film:%d", id)
-	if film, err := uc.rp.GetFilmsFromCache(cacheKey); err == nil {
-		return film[0], nil
+	return uc.rp.LoadFilm(id, time.Hour, func() (*f.FilmDTO, error) {
+		return uc.rp.GetFilmByID(id)
+	})
+}
+
+func (uc *FilmUseCase) CreateFilm(ctx context.Context, film *f.FilmDTO, poster *multipart.File) error {
+	if film.PosterURL == "" {
+		film.PosterURL = uc.rp.DefaultPosterURL()
 	}
 
-	film, err := uc.rp.GetFilmByID(id)
+	// постер проверяется до создания фильма, чтобы неподходящая картинка не оставила фильм без постера
+	var upload f.PosterUpload
+	if *poster != nil {
+		img, err := avatarManager.Process(poster, avatarManager.PosterProfile)
+		if err != nil {
+			return err
+		}
+		upload = func(film *f.FilmDTO) error {
+			return uc.uploadPoster(film, img)
+		}
+	}
+
+	id, err := uc.rp.CreateFilm(ctx, film, upload)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	film.ID = id
 
-	if err := uc.rp.SetFilmsToCache(cacheKey, []*f.FilmDTO{film}, time.Hour); err != nil {
-		uc.log.Error("failed to cache film", "error", err)
+	film, err = uc.GetFilmByID(film.ID)
+	if err != nil {
+		return err
 	}
 
-	return film, nil
-}
+	if err := uc.indexFilm(film); err != nil {
+		uc.log.Error("failed to index film in Elasticsearch", "error", err)
+	}
 
-func (uc *FilmUseCase) CreateFilm(film *f.FilmDTO, poster *multipart.File) error {
-	if film.PosterURL == "" {
-		film.PosterURL = "https://filmposter.storage-173.s3hoster.by/default/800x1200.webp"
+	// новый фильм может попасть в любой закэшированный список
+	if err := uc.rp.InvalidateFilmLists(); err != nil {
+		uc.log.Error("failed to invalidate film lists cache", "error", err)
 	}
-	id, err := uc.rp.CreateFilm(film)
-	if err != nil {
-		return err
+
+	return nil
+}
+
+// UpdateFilm - film.Version - версия, которую видел клиент, после обновления в ней новая версия.
+// Картинка проверяется до транзакции, а загружается в ней после проверки версии
+func (uc *FilmUseCase) UpdateFilm(ctx context.Context, film *f.FilmDTO, poster *multipart.File) error {
+	// прежний постер не удаляется: триггер media_objects выведет его в транзакции изменения,
+	// а файлы удалит PurgeRetiredMedia после grace period
+	if film.RemovePoster {
+		film.PosterURL = uc.rp.DefaultPosterURL()
 	}
-	film.ID = id
 
+	var upload f.PosterUpload
 	if *poster != nil {
-		_, posterBytes, err := avatarManager.ParsingPosterImage(poster)
+		img, err := avatarManager.Process(poster, avatarManager.PosterProfile)
 		if err != nil {
 			return err
 		}
-		posterUrl, err := uc.rp.UploadPoster(film.ID, posterBytes)
-		if err != nil {
-			return f.ErrFilmPosterUploadFailed
+		upload = func(film *f.FilmDTO) error {
+			return uc.uploadPoster(film, img)
 		}
-		film.PosterURL = posterUrl
 	}
 
-	if err := uc.rp.UpdateFilm(film); err != nil {
+	if err := uc.rp.UpdateFilm(ctx, film, upload); err != nil {
 		return err
 	}
 
-	film, err = uc.GetFilmByID(film.ID)
+	if err := uc.indexFilm(film); err != nil {
+		uc.log.Error("failed to index film in Elasticsearch", "error", err)
+	}
+
+	uc.InvalidateFilmCache(film.ID)
+	return nil
+}
+
+// PatchFilm применяет частичное изменение и возвращает фильм целиком. Файлы PATCH не трогает,
+// поэтому версия проверяется только в транзакции изменения
+func (uc *FilmUseCase) PatchFilm(ctx context.Context, patch *f.FilmPatch) (*f.FilmDTO, error) {
+	if err := uc.rp.PatchFilm(ctx, patch); err != nil {
+		return nil, err
+	}
+	uc.InvalidateFilmCache(patch.ID)
+
+	film, err := uc.GetFilmByID(patch.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := uc.rp.IndexFilm(film); err != nil {
+	if err := uc.indexFilm(film); err != nil {
 		uc.log.Error("failed to index film in Elasticsearch", "error", err)
 	}
 
-	return nil
+	return film, nil
 }
 
-func (uc *FilmUseCase) UpdateFilm(film *f.FilmDTO, poster *multipart.File) error {
-	if film.RemovePoster {
-		if err := uc.rp.DeletePoster(film.ID); err != nil {
-			return f.ErrFilmPosterNotFound
-		}
+// ReindexFilm переиндексирует фильм в Elasticsearch, например после изменения серий сериала
+func (uc *FilmUseCase) ReindexFilm(id uint) error {
+	film, err := uc.GetFilmByID(id)
+	if err != nil {
+		return err
 	}
 
-	if *poster != nil {
-		_, posterBytes, err := avatarManager.ParsingAvatarImage(poster)
+	return uc.indexFilm(film)
+}
+
+// InvalidateFilmCache сбрасывает кэш фильма и всех списков, в которые он входит,
+// в том числе когда меняются связанные с ним данные других модулей
+func (uc *FilmUseCase) InvalidateFilmCache(id uint) {
+	if err := uc.rp.InvalidateFilm(id); err != nil {
+		uc.log.Error("failed to invalidate film cache", "error", err, "filmID", id)
+	}
+}
+
+// indexFilm индексирует фильм вместе с названиями серий, если это сериал,
+// и с переводами названия и описаний на все поддерживаемые языки
+func (uc *FilmUseCase) indexFilm(film *f.FilmDTO) error {
+	var episodeTitles []string
+	if film.ContentType != f.ContentTypeMovie {
+		titles, err := uc.rp.GetEpisodeTitles(film.ID)
 		if err != nil {
 			return err
 		}
-		posterUrl, err := uc.rp.UploadPoster(film.ID, posterBytes)
+		episodeTitles = titles
+	}
+
+	localized := make(map[string]map[string]string, len(locale.Supported))
+	for _, lang := range locale.Supported {
+		translations, err := uc.tr.ResolveTranslations(tr.EntityFilm, []uint{film.ID}, []string{lang})
 		if err != nil {
-			return f.ErrFilmPosterUploadFailed
+			return err
+		}
+		fields := translations[film.ID]
+		if fields == nil {
+			fields = make(map[string]string)
 		}
-		film.PosterURL = posterUrl
+		// основные поля фильма написаны на языке по умолчанию
+		if lang == locale.Default {
+			for field, value := range map[string]string{"title": film.Title, "tagline": film.Tagline, "synopsis": film.Synopsis} {
+				if _, ok := fields[field]; !ok {
+					fields[field] = value
+				}
+			}
+		}
+		localized[lang] = fields
 	}
 
-	if err := uc.rp.UpdateFilm(film); err != nil {
-		return err
+	return uc.rp.IndexFilm(film, episodeTitles, localized)
+}
+
+// LocalizeFilms подставляет переводы названия, слогана и описания фильмов, названий жанров и имен персон
+// на язык lang. Если перевода нет, используется язык по умолчанию, затем основное значение поля.
+// Ошибка получения переводов не мешает ответу: фильмы остаются на основном языке
+func (uc *FilmUseCase) LocalizeFilms(films []*f.FilmDTO, lang string) {
+	if len(films) == 0 {
+		return
+	}
+	locales := locale.Fallbacks(lang)
+
+	var filmIDs, genreIDs, personIDs []uint
+	for _, film := range films {
+		filmIDs = append(filmIDs, film.ID)
+		for _, genre := range film.Genres {
+			genreIDs = append(genreIDs, genre.GenreId)
+		}
+		for _, credit := range film.Credits {
+			personIDs = append(personIDs, credit.PersonID)
+		}
+	}
+
+	filmTr, err := uc.tr.ResolveTranslations(tr.EntityFilm, filmIDs, locales)
+	if err != nil {
+		uc.log.Error("failed to resolve film translations", "error", err, "lang", lang)
+		return
+	}
+	genreTr, err := uc.tr.ResolveTranslations(tr.EntityGenre, genreIDs, locales)
+	if err != nil {
+		uc.log.Error("failed to resolve genre translations", "error", err, "lang", lang)
+		return
+	}
+	personTr, err := uc.tr.ResolveTranslations(tr.EntityPerson, personIDs, locales)
+	if err != nil {
+		uc.log.Error("failed to resolve person translations", "error", err, "lang", lang)
+		return
+	}
+
+	for _, film := range films {
+		fields := filmTr[film.ID]
+		translate(&film.Title, fields, "title")
+		translate(&film.Tagline, fields, "tagline")
+		translate(&film.Synopsis, fields, "synopsis")
+
+		for i := range film.Genres {
+			fields := genreTr[film.Genres[i].GenreId]
+			translate(&film.Genres[i].Name, fields, "name")
+			translate(&film.Genres[i].Description, fields, "description")
+		}
+		for i := range film.Credits {
+			translate(&film.Credits[i].Name, personTr[film.Credits[i].PersonID], "name")
+		}
+	}
+}
+
+// translate заменяет value переводом поля field, если он есть
+func translate(value *string, fields map[string]string, field string) {
+	if translated, ok := fields[field]; ok && translated != "" {
+		*value = translated
+	}
+}
+
+// SetPosterImage заменяет постер фильма уже обработанным изображением, например загруженным напрямую в S3,
+// и возвращает фильм с новой версией
+func (uc *FilmUseCase) SetPosterImage(ctx context.Context, id uint, img *avatarManager.Image) (*f.FilmDTO, error) {
+	if _, err := uc.GetFilmByID(id); err != nil {
+		return nil, err
 	}
 
-	if err := uc.rp.IndexFilm(film); err != nil {
+	poster := &f.FilmDTO{ID: id}
+	if err := uc.uploadPoster(poster, img); err != nil {
+		return nil, err
+	}
+
+	if err := uc.rp.UpdatePoster(ctx, poster); err != nil {
+		return nil, err
+	}
+
+	uc.InvalidateFilmCache(id)
+	film, err := uc.GetFilmByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.indexFilm(film); err != nil {
 		uc.log.Error("failed to index film in Elasticsearch", "error", err)
 	}
 
-	cacheKey := fmt.Sprintf("film:%d", film.ID)
-	if err := uc.rp.DeleteFilmFromCache(cacheKey); err != nil {
-		uc.log.Error("failed to delete film from cache", "error", err)
+	return film, nil
+}
+
+// uploadPoster загружает все размеры постера и проставляет фильму адрес папки, заглушку и цвет
+func (uc *FilmUseCase) uploadPoster(film *f.FilmDTO, img *avatarManager.Image) error {
+	posterUrl, err := uc.rp.UploadPoster(film.ID, img.Variants)
+	if err != nil {
+		uc.log.Error("failed to upload film poster", "error", err, "filmID", film.ID)
+		return f.ErrFilmPosterUploadFailed
 	}
 
+	film.PosterURL = posterUrl
+	film.PosterBlurhash = img.Blurhash
+	film.PosterColor = img.DominantColor
 	return nil
 }
 
-func (uc *FilmUseCase) DeleteFilm(id uint) error {
-	if err := uc.rp.DeleteFilm(id); err != nil {
+// DeleteFilm переносит фильм в корзину. Постер и кадры остаются в S3 до очистки корзины, чтобы фильм можно было вернуть
+func (uc *FilmUseCase) DeleteFilm(ctx context.Context, id uint) error {
+	if err := uc.rp.DeleteFilm(ctx, id); err != nil {
 		return err
 	}
 
+	uc.InvalidateFilmCache(id)
+
+	if err := uc.rp.DeleteFilmFromIndex(id); err != nil {
+		uc.log.Error("failed to delete film from Elasticsearch index", "error", err)
+	}
+
+	return nil
+}
+
+// CleanupFilm удаляет файлы и документ поиска окончательно удаленного фильма
+func (uc *FilmUseCase) CleanupFilm(id uint) {
 	if err := uc.rp.DeletePoster(id); err != nil {
 		uc.log.Error("failed to delete poster from S3", "error", err)
 	}
 
-	cacheKey := fmt.Sprintf("film:%d", id)
-	if err := uc.rp.DeleteFilmFromCache(cacheKey); err != nil {
-		uc.log.Error("failed to delete film from cache", "error", err)
+	if err := uc.rp.DeleteImages(id); err != nil {
+		uc.log.Error("failed to delete film images from S3", "error", err)
 	}
 
 	if err := uc.rp.DeleteFilmFromIndex(id); err != nil {
 		uc.log.Error("failed to delete film from Elasticsearch index", "error", err)
 	}
-
-	return nil
 }
 
-func (uc *FilmUseCase) SearchFilms(query string) ([]*f.FilmDTO, error) {
-	ids, err := uc.rp.SearchFilms(query)
+func (uc *FilmUseCase) SearchFilms(query string, lang string) ([]*f.FilmDTO, error) {
+	ids, err := uc.rp.SearchFilms(query, lang)
 	if err != nil {
 		return nil, f.ErrFilmSearchFailed
 	}
@@ -162,33 +355,180 @@ func (uc *FilmUseCase) SearchFilms(query string) ([]*f.FilmDTO, error) {
 }
 
 func (uc *FilmUseCase) GetFilms(filters f.FilmFilters, sort f.FilmSort) ([]*f.FilmDTO, error) {
-	cacheKey := generateCacheKey(filters, sort)
+	return uc.rp.LoadFilms(generateCacheKey(filters, sort), time.Minute*5, func() ([]*f.FilmDTO, error) {
+		return uc.rp.GetFilms(filters, sort)
+	})
+}
 
-	if films, err := uc.rp.GetFilmsFromCache(cacheKey); err == nil {
-		return films, nil
+// GetSimilarFilms возвращает фильмы, похожие на указанный. Кандидаты собираются из
+// more_like_this по названию и описанию, общих жанров и актеров и похожести оценок
+// у общих рецензентов, после чего ранжируются по взвешенной сумме.
+func (uc *FilmUseCase) GetSimilarFilms(id uint, limit int) ([]*f.SimilarFilmDTO, error) {
+	similar, err := uc.rp.LoadSimilarFilms(id, time.Hour*6, func() ([]*f.SimilarFilmDTO, error) {
+		return uc.rankSimilarFilms(id)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	films, err := uc.rp.GetFilms(filters, sort)
+	return truncateSimilar(similar, limit), nil
+}
+
+func (uc *FilmUseCase) rankSimilarFilms(id uint) ([]*f.SimilarFilmDTO, error) {
+	film, err := uc.GetFilmByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := uc.rp.SetFilmsToCache(cacheKey, films, time.Minute*5); err != nil {
-		uc.log.Error("failed to cache films", "error", err)
+	candidates := make(map[uint]*similarCandidate)
+	candidate := func(filmID uint) *similarCandidate {
+		c, ok := candidates[filmID]
+		if !ok {
+			c = &similarCandidate{filmID: filmID}
+			candidates[filmID] = c
+		}
+		return c
 	}
 
-	return films, nil
+	textScores, err := uc.rp.SearchSimilarFilms(id, similarCandidatesLimit)
+	if err != nil {
+		uc.log.Error("failed to search similar films in Elasticsearch", "error", err, "filmID", id)
+	}
+	var maxTextScore float64
+	for _, score := range textScores {
+		maxTextScore = max(maxTextScore, score)
+	}
+	for filmID, score := range textScores {
+		if filmID != id && maxTextScore > 0 {
+			candidate(filmID).text = score / maxTextScore
+		}
+	}
+
+	overlaps, err := uc.rp.GetFilmOverlaps(id, similarCandidatesLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, overlap := range overlaps {
+		c := candidate(overlap.FilmID)
+		c.sharedGenres = overlap.SharedGenres
+		c.sharedActors = overlap.SharedActors
+	}
+
+	coRatings, err := uc.rp.GetFilmCoRatings(id, similarMinCoReviewers, similarCandidatesLimit)
+	if err != nil {
+		uc.log.Error("failed to get film co-ratings", "error", err, "filmID", id)
+	}
+	for _, coRating := range coRatings {
+		if coRating.Similarity > 0 {
+			candidate(coRating.FilmID).coRating = coRating.Similarity
+		}
+	}
+
+	ranked := make([]*similarCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		c.score = c.rank(len(film.GenreIDs))
+		if c.score > 0 {
+			ranked = append(ranked, c)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score == ranked[j].score {
+			return ranked[i].filmID < ranked[j].filmID
+		}
+		return ranked[i].score > ranked[j].score
+	})
+	if len(ranked) > similarCandidatesLimit {
+		ranked = ranked[:similarCandidatesLimit]
+	}
+
+	similar := make([]*f.SimilarFilmDTO, 0, len(ranked))
+	for _, c := range ranked {
+		similarFilm, err := uc.GetFilmByID(c.filmID)
+		if err != nil {
+			uc.log.Error("failed to get similar film", "error", err, "filmID", c.filmID)
+			continue
+		}
+		similar = append(similar, &f.SimilarFilmDTO{
+			Film:    similarFilm,
+			Score:   c.score,
+			Reasons: c.reasons(),
+		})
+	}
+
+	return similar, nil
+}
+
+type similarCandidate struct {
+	filmID       uint
+	text         float64
+	sharedGenres int
+	sharedActors int
+	coRating     float64
+	score        float64
+}
+
+func (c *similarCandidate) rank(genresCount int) float64 {
+	score := c.text*similarWeightText + c.coRating*similarWeightCoRating
+	if genresCount > 0 {
+		score += float64(min(c.sharedGenres, genresCount)) / float64(genresCount) * similarWeightGenres
+	}
+	// Три и более общих актера считаем максимальным совпадением по касту
+	score += float64(min(c.sharedActors, 3)) / 3 * similarWeightActors
+	return score
+}
+
+func (c *similarCandidate) reasons() []string {
+	var reasons []string
+	if c.sharedActors > 0 {
+		reasons = append(reasons, pluralize(c.sharedActors, "actor"))
+	}
+	if c.sharedGenres > 0 {
+		reasons = append(reasons, pluralize(c.sharedGenres, "genre"))
+	}
+	if c.text >= 0.5 {
+		reasons = append(reasons, "similar title and synopsis")
+	}
+	if c.coRating >= 0.5 {
+		reasons = append(reasons, "rated similarly by the same reviewers")
+	}
+	return reasons
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("shares 1 %s", noun)
+	}
+	return fmt.Sprintf("shares %d %ss", count, noun)
+}
+
+func truncateSimilar(similar []*f.SimilarFilmDTO, limit int) []*f.SimilarFilmDTO {
+	if limit > 0 && len(similar) > limit {
+		return similar[:limit]
+	}
+	return similar
 }
 
 func generateCacheKey(filters f.FilmFilters, sort f.FilmSort) string {
 	var keyParts []string
 
+	if len(filters.ContentTypes) > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("content_types=%v", filters.ContentTypes))
+	}
 	if len(filters.GenreIDs) > 0 {
 		keyParts = append(keyParts, fmt.Sprintf("genre_ids=%v", filters.GenreIDs))
 	}
+	if len(filters.Tags) > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("tags=%v", filters.Tags))
+	}
 	if len(filters.ActorIDs) > 0 {
 		keyParts = append(keyParts, fmt.Sprintf("actor_ids=%v", filters.ActorIDs))
 	}
+	if len(filters.DirectorIDs) > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("director_ids=%v", filters.DirectorIDs))
+	}
+	if filters.Director != "" {
+		keyParts = append(keyParts, fmt.Sprintf("director=%s", filters.Director))
+	}
 	if filters.Producer != "" {
 		keyParts = append(keyParts, fmt.Sprintf("producer=%s", filters.Producer))
 	}
@@ -210,6 +550,45 @@ func generateCacheKey(filters f.FilmFilters, sort f.FilmSort) string {
 	if filters.MaxDuration > 0 {
 		keyParts = append(keyParts, fmt.Sprintf("max_duration=%s", filters.MaxDuration.String()))
 	}
+	if filters.OriginalTitle != "" {
+		keyParts = append(keyParts, fmt.Sprintf("original_title=%s", filters.OriginalTitle))
+	}
+	if filters.Tagline != "" {
+		keyParts = append(keyParts, fmt.Sprintf("tagline=%s", filters.Tagline))
+	}
+	if len(filters.Countries) > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("countries=%v", filters.Countries))
+	}
+	if len(filters.Languages) > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("languages=%v", filters.Languages))
+	}
+	if len(filters.AgeRatings) > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("age_ratings=%v", filters.AgeRatings))
+	}
+	if filters.MinBudget > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("min_budget=%d", filters.MinBudget))
+	}
+	if filters.MaxBudget > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("max_budget=%d", filters.MaxBudget))
+	}
+	if filters.MinBoxOffice > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("min_box_office=%d", filters.MinBoxOffice))
+	}
+	if filters.MaxBoxOffice > 0 {
+		keyParts = append(keyParts, fmt.Sprintf("max_box_office=%d", filters.MaxBoxOffice))
+	}
+	if filters.Currency != "" {
+		keyParts = append(keyParts, fmt.Sprintf("currency=%s", filters.Currency))
+	}
+	if filters.IMDbID != "" {
+		keyParts = append(keyParts, fmt.Sprintf("imdb_id=%s", filters.IMDbID))
+	}
+	if filters.KinopoiskID != "" {
+		keyParts = append(keyParts, fmt.Sprintf("kinopoisk_id=%s", filters.KinopoiskID))
+	}
+	if filters.TMDBID != "" {
+		keyParts = append(keyParts, fmt.Sprintf("tmdb_id=%s", filters.TMDBID))
+	}
 	if sort.By != "" {
 		keyParts = append(keyParts, fmt.Sprintf("sort_by=%s", sort.By))
 	}
@@ -1,17 +1,28 @@
 package controller
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	g "server/internal/modules/genre"
+	avatarManager "server/pkg/lib/avatarMenager"
+	"server/pkg/lib/optimistic"
+	"server/pkg/lib/patch"
 	resp "server/pkg/lib/response"
+	"server/pkg/middleware/locale"
 	"strconv"
 )
 
+const (
+	defaultTopFilms = 10
+	maxTopFilms     = 50
+)
+
 type GenreController struct {
 	log      *slog.Logger
 	uc       g.UseCase
@@ -20,6 +31,8 @@ type GenreController struct {
 
 func NewGenreController(log *slog.Logger, uc g.UseCase) *GenreController {
 	validate := validator.New()
+	validate.RegisterValidation("slug", validateSlug)
+	patch.RegisterValidation(validate)
 
 	return &GenreController{
 		log:      log,
@@ -30,44 +43,46 @@ func NewGenreController(log *slog.Logger, uc g.UseCase) *GenreController {
 
 // CreateGenre - Создание нового жанра
 // @Summary Создание нового жанра
-// @Description Создает новый жанр с указанным названием
+// @Description Создает новый жанр с названием, slug, описанием, родительским жанром и обложкой
 // @Tags         genre
-// @Accept       json
+// @Accept       multipart/form-data
 // @Produce      json
-// @Param        json body CreateGenreRequest true "Название жанра"
+// @Param        data formData string true "Данные жанра в формате JSON (CreateGenreRequest)"
+// @Param        cover formData file false "Обложка жанра, не меньше 640x360"
 // @Success 201 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /genres [post]
 func (c *GenreController) CreateGenre(w http.ResponseWriter, r *http.Request) {
 	log := c.log.With("op", "CreateGenre")
 
 	var req CreateGenreRequest
-
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		log.Error("failed to decode request body", err)
-		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("failed to decode request"))
+	if !c.decodeForm(w, r, log, &req) {
 		return
 	}
 
-	if err := c.validate.Struct(req); err != nil {
-		log.Error("failed to validate request", err)
-		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+	file, ok := c.formCover(w, r, log)
+	if !ok {
 		return
 	}
+	if file != nil {
+		defer func() {
+			if err := file.Close(); err != nil {
+				log.Error("failed to close cover file", "error", err)
+			}
+		}()
+	}
 
-	if _, err := c.uc.CreateGenre(req.Name); err != nil {
-		switch {
-		case errors.Is(err, g.ErrGenreExists):
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(g.ErrGenreExists.Error()))
-		default:
-			log.Error("failed to create genre", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(g.ErrInternalServer.Error()))
-		}
+	genre := &g.GenreDTO{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+		ParentID:    req.ParentID,
+	}
+
+	if _, err := c.uc.CreateGenre(r.Context(), genre, &file); err != nil {
+		c.writeError(w, r, log, err)
 		return
 	}
 
@@ -78,47 +93,140 @@ func (c *GenreController) CreateGenre(w http.ResponseWriter, r *http.Request) {
 
 // UpdateGenre - Обновление жанра
 // @Summary Обновление жанра
-// @Description Обновляет название существующего жанра
+// @Description Обновляет название, slug, описание, родительский жанр и обложку существующего жанра
 // @Tags         genre
-// @Accept       json
+// @Accept       multipart/form-data
 // @Produce      json
-// @Param        json body UpdateGenreRequest true "FilmId жанра и новое название"
+// @Param        data formData string true "Данные жанра в формате JSON (UpdateGenreRequest)"
+// @Param        cover formData file false "Новая обложка жанра, не меньше 640x360"
+// @Param        If-Match header string false "Версия жанра, на которой основано изменение, например \"3\". Без заголовка берется поле version"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /genres [put]
 func (c *GenreController) UpdateGenre(w http.ResponseWriter, r *http.Request) {
 	log := c.log.With("op", "UpdateGenre")
 
 	var req UpdateGenreRequest
+	if !c.decodeForm(w, r, log, &req) {
+		return
+	}
+
+	version, err := optimistic.Expected(r, req.Version)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	file, ok := c.formCover(w, r, log)
+	if !ok {
+		return
+	}
+	if file != nil {
+		defer func() {
+			if err := file.Close(); err != nil {
+				log.Error("failed to close cover file", "error", err)
+			}
+		}()
+	}
+
+	genre := &g.GenreDTO{
+		GenreId:     req.GenreId,
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+		ParentID:    req.ParentID,
+		RemoveCover: req.RemoveCover,
+		Version:     version,
+	}
 
-	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		log.Error("failed to decode request body", err)
+	if err := c.uc.UpdateGenre(r.Context(), genre, &file); err != nil {
+		if errors.Is(err, optimistic.ErrConflict) {
+			c.writeConflict(w, r, log, genre.GenreId, err)
+			return
+		}
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Version(genre.Version))
+	return
+}
+
+// PatchGenre - Частичное изменение жанра
+// @Summary Частичное изменение жанра
+// @Description Меняет только переданные поля (JSON Merge Patch, RFC 7396). null в slug создает его заново из названия,
+// @Description null в parent_id делает жанр корневым. Обложка меняется через PUT. Возвращает жанр целиком с новой версией
+// @Tags         genre
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path string true "Id жанра"
+// @Param        json body PatchGenreRequest true "Изменяемые поля"
+// @Param        If-Match header string false "Версия жанра, на которой основано изменение, например \"3\". Без заголовка берется поле version"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 415 {object} response.Response
+// @Failure 428 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /genres/{id} [patch]
+func (c *GenreController) PatchGenre(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("op", "PatchGenre")
+
+	genreIdUint64, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("failed to decode request"))
+		render.JSON(w, r, resp.Error(r, "invalid genre id"))
+		return
+	}
+	genreId := uint(genreIdUint64)
+
+	var req PatchGenreRequest
+	if err := patch.Decode(r, &req); err != nil {
+		c.writeError(w, r, log, err)
+		return
 	}
 
 	if err := c.validate.Struct(req); err != nil {
-		log.Error("failed to validate request", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+	if err := patch.Required(map[string]patch.Clearable{"name": req.Name}); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
 	}
 
-	if err := c.uc.UpdateGenre(req.GenreId, req.Name); err != nil {
-		switch {
-		case errors.Is(err, g.ErrNoSuchGenre):
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(g.ErrNoSuchGenre.Error()))
-		default:
-			log.Error("failed to update genre", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(g.ErrInternalServer.Error()))
+	version, err := optimistic.Expected(r, req.Version)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	genre, err := c.uc.PatchGenre(r.Context(), &g.GenrePatch{
+		GenreId:     genreId,
+		Version:     version,
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+		ParentID:    req.ParentID,
+	})
+	if err != nil {
+		if errors.Is(err, optimistic.ErrConflict) {
+			c.writeConflict(w, r, log, genreId, err)
+			return
 		}
+		c.writeError(w, r, log, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	render.JSON(w, r, resp.OK())
+	render.JSON(w, r, resp.Genres(genre))
 	return
 }
 
@@ -138,15 +246,17 @@ func (c *GenreController) GetGenres(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, g.ErrNoSuchGenre):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(g.ErrNoSuchGenre.Error()))
+			render.JSON(w, r, resp.Error(r, g.ErrNoSuchGenre.Error()))
 		default:
-			log.Error("failed to get genres", err)
+			log.Error("failed to get genres", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(g.ErrInternalServer.Error()))
+			render.JSON(w, r, resp.Error(r, g.ErrInternalServer.Error()))
 		}
 		return
 	}
 
+	c.uc.LocalizeGenres(genres, locale.FromRequest(r))
+
 	w.WriteHeader(http.StatusOK)
 	render.JSON(w, r, resp.Genres(genres))
 	return
@@ -157,7 +267,7 @@ func (c *GenreController) GetGenres(w http.ResponseWriter, r *http.Request) {
 // @Description Возвращает информацию о жанре по указанному FilmId
 // @Tags         genre
 // @Produce      json
-// @Param        id query string true "FilmId жанра"
+// @Param        id path string true "FilmId жанра"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
@@ -166,12 +276,12 @@ func (c *GenreController) GetGenres(w http.ResponseWriter, r *http.Request) {
 func (c *GenreController) GetGenre(w http.ResponseWriter, r *http.Request) {
 	log := c.log.With("op", "GetGenre")
 
-	genreIdStr := r.URL.Query().Get("id")
+	genreIdStr := chi.URLParam(r, "id")
 
 	genreIdUint64, err := strconv.ParseUint(genreIdStr, 10, 32)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid actor id"))
+		render.JSON(w, r, resp.Error(r, "invalid actor id"))
 		return
 	}
 
@@ -182,20 +292,60 @@ func (c *GenreController) GetGenre(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, g.ErrNoSuchGenre):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(g.ErrNoSuchGenre.Error()))
+			render.JSON(w, r, resp.Error(r, g.ErrNoSuchGenre.Error()))
 		default:
-			log.Error("failed to get genre", err)
+			log.Error("failed to get genre", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(g.ErrInternalServer.Error()))
+			render.JSON(w, r, resp.Error(r, g.ErrInternalServer.Error()))
 		}
 		return
 	}
 
+	c.uc.LocalizeGenres([]*g.GenreDTO{genre}, locale.FromRequest(r))
+
+	optimistic.SetETag(w, genre.Version)
 	w.WriteHeader(http.StatusOK)
 	render.JSON(w, r, resp.Genres(genre))
 	return
 }
 
+// GetGenrePage - Страница жанра
+// @Summary Страница жанра по slug
+// @Description Возвращает жанр, цепочку родительских жанров, поджанры и лучшие фильмы жанра вместе с поджанрами
+// @Tags         genre
+// @Produce      json
+// @Param        slug path string true "Slug жанра"
+// @Param        limit query int false "Количество фильмов (по умолчанию 10, максимум 50)"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /genres/slug/{slug} [get]
+func (c *GenreController) GetGenrePage(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("op", "GetGenrePage")
+
+	limit := defaultTopFilms
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 || l > maxTopFilms {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid limit"))
+			return
+		}
+		limit = l
+	}
+
+	page, err := c.uc.GetGenrePage(chi.URLParam(r, "slug"), limit, locale.FromRequest(r))
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.GenrePage(page))
+	return
+}
+
 // DeleteGenre - Удаление жанра
 // @Summary Удаление жанра
 // @Description Удаляет жанр по указанному FilmId
@@ -214,21 +364,21 @@ func (c *GenreController) DeleteGenre(w http.ResponseWriter, r *http.Request) {
 	genreIdUint64, err := strconv.ParseUint(genreIdStr, 10, 32)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid genre id"))
+		render.JSON(w, r, resp.Error(r, "invalid genre id"))
 		return
 	}
 
 	genreId := uint(genreIdUint64)
 
-	if err := c.uc.DeleteGenre(genreId); err != nil {
+	if err := c.uc.DeleteGenre(r.Context(), genreId); err != nil {
 		switch {
 		case errors.Is(err, g.ErrNoSuchGenre):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(g.ErrNoSuchGenre.Error()))
+			render.JSON(w, r, resp.Error(r, g.ErrNoSuchGenre.Error()))
 		default:
-			log.Error("failed to delete genre", err)
+			log.Error("failed to delete genre", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(g.ErrInternalServer.Error()))
+			render.JSON(w, r, resp.Error(r, g.ErrInternalServer.Error()))
 		}
 		return
 	}
@@ -237,3 +387,88 @@ func (c *GenreController) DeleteGenre(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, resp.OK())
 	return
 }
+
+// decodeForm разбирает multipart форму с JSON в поле data и валидирует его
+func (c *GenreController) decodeForm(w http.ResponseWriter, r *http.Request, log *slog.Logger, req interface{}) bool {
+	if err := r.ParseMultipartForm(5 << 20); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "unable to parse form"))
+		return false
+	}
+
+	jsonData := r.FormValue("data")
+	if jsonData == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "missing JSON data"))
+		return false
+	}
+
+	if err := json.Unmarshal([]byte(jsonData), req); err != nil {
+		log.Error("failed to unmarshal json", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid JSON data"))
+		return false
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		log.Error("failed to validate request", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return false
+	}
+
+	return true
+}
+
+// formCover достает необязательный файл обложки из формы
+func (c *GenreController) formCover(w http.ResponseWriter, r *http.Request, log *slog.Logger) (multipart.File, bool) {
+	file, _, err := r.FormFile("cover")
+	switch {
+	case errors.Is(err, http.ErrMissingFile):
+		return nil, true
+	case err != nil:
+		log.Error("failed to get file from form", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to get file from form"))
+		return nil, false
+	}
+	return file, true
+}
+
+func (c *GenreController) writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, g.ErrNoSuchGenre):
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, g.ErrGenreExists) || errors.Is(err, g.ErrSlugExists):
+		w.WriteHeader(http.StatusConflict)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, g.ErrNoSuchParentGenre) || errors.Is(err, g.ErrGenreCycle) ||
+		errors.Is(err, avatarManager.ErrInvalidTypeCover) || errors.Is(err, avatarManager.ErrInvalidResolutionCover) ||
+		errors.Is(err, optimistic.ErrInvalidIfMatch) || errors.Is(err, patch.ErrInvalidPatch):
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, patch.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, optimistic.ErrVersionRequired):
+		w.WriteHeader(http.StatusPreconditionRequired)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	default:
+		log.Error("genre request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, g.ErrInternalServer.Error()))
+	}
+}
+
+// writeConflict отвечает 409 с текущим состоянием жанра, чтобы клиент мог показать расхождение и повторить изменение
+func (c *GenreController) writeConflict(w http.ResponseWriter, r *http.Request, log *slog.Logger, genreId uint, err error) {
+	current, getErr := c.uc.GetGenre(genreId)
+	if getErr != nil {
+		c.writeError(w, r, log, getErr)
+		return
+	}
+
+	w.WriteHeader(http.StatusConflict)
+	render.JSON(w, r, resp.Conflict(r, err.Error(), resp.Genres(current).Data))
+}
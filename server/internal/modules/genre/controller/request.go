@@ -1,10 +1,40 @@
 package controller
 
+import (
+	"github.com/go-playground/validator/v10"
+	"regexp"
+	"server/pkg/lib/patch"
+)
+
 type CreateGenreRequest struct {
-	Name string `json:"name"`
+	Name        string `json:"name" validate:"required,max=200"`
+	Slug        string `json:"slug" validate:"omitempty,max=200,slug"`
+	Description string `json:"description" validate:"max=5000"`
+	ParentID    *uint  `json:"parent_id" validate:"omitempty,min=1"`
 }
 
 type UpdateGenreRequest struct {
-	GenreId uint   `json:"genre_id"`
-	Name    string `json:"name"`
+	GenreId     uint   `json:"genre_id" validate:"required,min=1"`
+	Name        string `json:"name" validate:"required,max=200"`
+	Slug        string `json:"slug" validate:"omitempty,max=200,slug"`
+	Description string `json:"description" validate:"max=5000"`
+	ParentID    *uint  `json:"parent_id" validate:"omitempty,min=1"`
+	RemoveCover bool   `json:"remove_cover"`
+	Version     int    `json:"version" validate:"omitempty,min=1"`
+}
+
+// PatchGenreRequest - тело PATCH /genres/{id} (RFC 7396): поля, которых нет в теле, не меняются.
+// null в slug создает его заново из названия, в parent_id делает жанр корневым
+type PatchGenreRequest struct {
+	Name        patch.Field[string] `json:"name" validate:"omitempty,max=200"`
+	Slug        patch.Field[string] `json:"slug" validate:"omitempty,max=200,slug"`
+	Description patch.Field[string] `json:"description" validate:"omitempty,max=5000"`
+	ParentID    patch.Field[uint]   `json:"parent_id" validate:"omitempty,min=1"`
+	Version     int                 `json:"version" validate:"omitempty,min=1"`
+}
+
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func validateSlug(fl validator.FieldLevel) bool {
+	return slugRegexp.MatchString(fl.Field().String())
 }
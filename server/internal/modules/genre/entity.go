@@ -1,39 +1,103 @@
 package genre
 
 import (
+	"context"
+	"mime/multipart"
 	"net/http"
+	"server/pkg/lib/patch"
 	"time"
 )
 
 type GenreDTO struct {
-	GenreId  uint      `json:"genre_id"`
-	Name     string    `json:"name"`
-	CreateAt time.Time `json:"create_at"`
+	GenreId     uint      `json:"genre_id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	CoverURL    string    `json:"cover_url,omitempty"` // Папка с размерами обложки (avatarManager.CoverProfile)
+	ParentID    *uint     `json:"parent_id,omitempty"`
+	CreateAt    time.Time `json:"create_at"`
+	Version     int       `json:"version"` // Счетчик изменений, см. pkg/lib/optimistic
+	RemoveCover bool      `json:"-"`
+
+	CoverBlurhash string `json:"cover_blurhash,omitempty"`
+	CoverColor    string `json:"cover_color,omitempty"`
+}
+
+// GenrePatch - частичное изменение жанра (PATCH, RFC 7396): меняются только переданные поля.
+// null в slug создает его заново из названия, в parent_id делает жанр корневым
+type GenrePatch struct {
+	GenreId uint
+	Version int // Версия, которую видел клиент, после изменения - новая версия
+
+	Name        patch.Field[string]
+	Slug        patch.Field[string]
+	Description patch.Field[string]
+	ParentID    patch.Field[uint]
+}
+
+// GenrePageDTO страница жанра: сам жанр, цепочка родителей от корня, дочерние жанры и лучшие фильмы
+type GenrePageDTO struct {
+	Genre     *GenreDTO
+	Ancestors []*GenreDTO
+	Children  []*GenreDTO
+	TopFilms  []*TopFilmDTO
+}
+
+// TopFilmDTO фильм жанра или любого из его дочерних жанров
+type TopFilmDTO struct {
+	FilmID       uint      `gorm:"column:film_id"`
+	Title        string    `gorm:"column:title"`
+	PosterURL    string    `gorm:"column:poster_url"`
+	ReleaseDate  time.Time `gorm:"column:release_date"`
+	AvgRating    float64   `gorm:"column:avg_rating"`
+	TotalReviews int       `gorm:"column:total_count_reviews"`
+
+	PosterBlurhash string `gorm:"column:poster_blurhash"`
+	PosterColor    string `gorm:"column:poster_color"`
 }
 
 type Controller interface {
 	CreateGenre(w http.ResponseWriter, r *http.Request)
 	UpdateGenre(w http.ResponseWriter, r *http.Request)
+	PatchGenre(w http.ResponseWriter, r *http.Request)
 	GetGenres(w http.ResponseWriter, r *http.Request)
 	GetGenre(w http.ResponseWriter, r *http.Request)
+	GetGenrePage(w http.ResponseWriter, r *http.Request)
 	DeleteGenre(w http.ResponseWriter, r *http.Request)
 }
 
 type UseCase interface {
-	CreateGenre(name string) (uint, error)
-	UpdateGenre(genreID uint, newName string) error
+	CreateGenre(ctx context.Context, genre *GenreDTO, cover *multipart.File) (uint, error)
+	UpdateGenre(ctx context.Context, genre *GenreDTO, cover *multipart.File) error
+	PatchGenre(ctx context.Context, patch *GenrePatch) (*GenreDTO, error)
 	GetGenre(genreID uint) (*GenreDTO, error)
 	GetGenres() ([]*GenreDTO, error)
-	DeleteGenre(genreID uint) error
+	GetGenrePage(slug string, limit int, lang string) (*GenrePageDTO, error)
+	DeleteGenre(ctx context.Context, genreID uint) error
+	InvalidateGenreCache(genreID uint)
+	CleanupGenre(genreID uint)
+	LocalizeGenres(genres []*GenreDTO, lang string)
 }
 
 type Repo interface {
-	CreateGenre(name string) (uint, error)
-	UpdateGenre(genre *GenreDTO) error
+	//DB
+	CreateGenre(ctx context.Context, genre *GenreDTO) (uint, error)
+	UpdateGenre(ctx context.Context, genre *GenreDTO) error
 	GetGenre(genreID uint) (*GenreDTO, error)
+	GetGenreBySlug(slug string) (*GenreDTO, error)
 	GetGenres() ([]*GenreDTO, error)
-	DeleteGenre(genreID uint) error
-	SetCacheGenre(key string, value interface{}, ttl time.Duration) error
-	GetCacheGenre(key string) ([]*GenreDTO, error)
-	DeleteCacheGenre(key string) error
+	GetGenreAncestors(genreID uint) ([]*GenreDTO, error)
+	GetGenreChildren(genreID uint) ([]*GenreDTO, error)
+	GetTopFilms(genreID uint, limit int) ([]*TopFilmDTO, error)
+	DeleteGenre(ctx context.Context, genreID uint) error
+
+	//Cache
+	LoadGenre(id uint, ttl time.Duration, load func() (*GenreDTO, error)) (*GenreDTO, error)
+	LoadGenres(ttl time.Duration, load func() ([]*GenreDTO, error)) ([]*GenreDTO, error)
+	InvalidateGenre(id uint) error
+	InvalidateGenreLists() error
+
+	//S3
+	UploadCover(genreID uint, variants map[string][]byte) (string, error)
+	DeleteCover(genreID uint) error
 }
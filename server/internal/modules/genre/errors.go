@@ -3,8 +3,11 @@ package genre
 import "errors"
 
 var (
-	ErrInternalServer = errors.New("internal server error")
-	ErrNoSuchGenre    = errors.New("no such genre")
-	ErrGenreExists    = errors.New("genre already exists")
-	ErrMissCache      = errors.New("miss cache error")
+	ErrInternalServer    = errors.New("internal server error")
+	ErrNoSuchGenre       = errors.New("no such genre")
+	ErrGenreExists       = errors.New("genre already exists")
+	ErrSlugExists        = errors.New("genre with this slug already exists")
+	ErrNoSuchParentGenre = errors.New("no such parent genre")
+	ErrGenreCycle        = errors.New("genre cannot be nested into itself or its subgenre")
+	ErrCoverUploadFailed = errors.New("failed to upload genre cover")
 )
@@ -1,25 +1,49 @@
 package genre
 
-import "time"
+import (
+	"gorm.io/gorm"
+	"time"
+)
 
 type Genre struct {
-	GenreID  uint      `gorm:"primaryKey;autoIncrement;column:genre_id" json:"genre_id"`
-	Name     string    `gorm:"size:200;unique;not null;column:name" json:"name"`
-	CreateAt time.Time `gorm:"default:CURRENT_DATE;column:create_at" json:"created_at"`
+	GenreID       uint           `gorm:"primaryKey;autoIncrement;column:genre_id" json:"genre_id"`
+	Name          string         `gorm:"size:200;unique;not null;column:name" json:"name"`
+	Slug          string         `gorm:"size:200;unique;not null;column:slug" json:"slug"`
+	Description   string         `gorm:"column:description" json:"description"`
+	CoverURL      string         `gorm:"column:cover_url" json:"cover_url"`
+	CoverBlurhash string         `gorm:"column:cover_blurhash;not null;default:''" json:"cover_blurhash"`
+	CoverColor    string         `gorm:"column:cover_color;not null;default:''" json:"cover_color"`
+	ParentID      *uint          `gorm:"column:parent_id" json:"parent_id"`
+	CreateAt      time.Time      `gorm:"default:CURRENT_DATE;column:create_at" json:"created_at"`
+	Version       int            `gorm:"column:version;->" json:"version"` // меняется только через optimistic.Bump
+	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at" json:"-"`
 }
 
 func FromDTO(DTO *GenreDTO) *Genre {
 	return &Genre{
-		GenreID:  DTO.GenreId,
-		Name:     DTO.Name,
-		CreateAt: DTO.CreateAt,
+		GenreID:       DTO.GenreId,
+		Name:          DTO.Name,
+		Slug:          DTO.Slug,
+		Description:   DTO.Description,
+		CoverURL:      DTO.CoverURL,
+		CoverBlurhash: DTO.CoverBlurhash,
+		CoverColor:    DTO.CoverColor,
+		ParentID:      DTO.ParentID,
+		CreateAt:      DTO.CreateAt,
 	}
 }
 
 func ToDTO(genre *Genre) *GenreDTO {
 	return &GenreDTO{
-		GenreId:  genre.GenreID,
-		Name:     genre.Name,
-		CreateAt: genre.CreateAt,
+		GenreId:       genre.GenreID,
+		Name:          genre.Name,
+		Slug:          genre.Slug,
+		Description:   genre.Description,
+		CoverURL:      genre.CoverURL,
+		CoverBlurhash: genre.CoverBlurhash,
+		CoverColor:    genre.CoverColor,
+		ParentID:      genre.ParentID,
+		CreateAt:      genre.CreateAt,
+		Version:       genre.Version,
 	}
 }
@@ -1,64 +1,66 @@
 package cache
 
 import (
-	"context"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"github.com/go-redis/redis/v8"
 	"log/slog"
 	"server/internal/init/cache"
 	g "server/internal/modules/genre"
 	"time"
 )
 
+// GenreCache - список жанров помечен тегами всех жанров в нем, изменение жанра сбрасывает и его, и список
 type GenreCache struct {
-	log *slog.Logger
-	ch  *cache.Cache
+	log    *slog.Logger
+	ch     *cache.Cache
+	genres *cache.Typed[*g.GenreDTO]
+	lists  *cache.Typed[[]*g.GenreDTO]
 }
 
 func NewGenreCache(log *slog.Logger, ch *cache.Cache) *GenreCache {
 	return &GenreCache{
-		log: log,
-		ch:  ch,
+		log:    log,
+		ch:     ch,
+		genres: cache.NewTyped[*g.GenreDTO](ch),
+		lists:  cache.NewTyped[[]*g.GenreDTO](ch),
 	}
 }
 
-func (c *GenreCache) SetCacheGenre(key string, value interface{}, ttl time.Duration) error {
-	switch v := value.(type) {
-	case *g.GenreDTO:
-		value = []*g.GenreDTO{v}
-	case []*g.GenreDTO:
-		value = v
-	default:
-		return fmt.Errorf("invalid actor type")
-	}
+func (c *GenreCache) LoadGenre(id uint, ttl time.Duration, load func() (*g.GenreDTO, error)) (*g.GenreDTO, error) {
+	return c.genres.GetOrLoad(cache.Key("genre", id), ttl, func() (*g.GenreDTO, []string, error) {
+		genre, err := load()
+		return genre, []string{genreTag(id)}, err
+	})
+}
 
-	data, err := json.Marshal(value)
-	if err != nil {
-		return err
-	}
+func (c *GenreCache) LoadGenres(ttl time.Duration, load func() ([]*g.GenreDTO, error)) ([]*g.GenreDTO, error) {
+	return c.lists.GetOrLoad(cache.Key("genres"), ttl, func() ([]*g.GenreDTO, []string, error) {
+		genres, err := load()
+		if err != nil {
+			return nil, nil, err
+		}
 
-	return c.ch.Client.Set(context.Background(), key, data, ttl).Err()
+		tags := make([]string, 0, len(genres)+1)
+		tags = append(tags, listsTag())
+		for _, genre := range genres {
+			tags = append(tags, genreTag(genre.GenreId))
+		}
+		return genres, tags, nil
+	})
 }
 
-func (c *GenreCache) GetCacheGenre(key string) ([]*g.GenreDTO, error) {
-	data, err := c.ch.Client.Get(context.Background(), key).Result()
-	if errors.Is(err, redis.Nil) {
-		return nil, g.ErrMissCache
-	} else if err != nil {
-		return nil, err
-	}
+// InvalidateGenre сбрасывает жанр и все списки, в которые он входит
+func (c *GenreCache) InvalidateGenre(id uint) error {
+	return c.ch.InvalidateTags(genreTag(id))
+}
 
-	var genres []*g.GenreDTO
-	err = json.Unmarshal([]byte(data), &genres)
-	if err != nil {
-		return nil, err
-	}
+// InvalidateGenreLists сбрасывает списки жанров, например после создания жанра
+func (c *GenreCache) InvalidateGenreLists() error {
+	return c.ch.InvalidateTags(listsTag())
+}
 
-	return genres, nil
+func genreTag(id uint) string {
+	return cache.Tag("genre", id)
 }
 
-func (c *GenreCache) DeleteCacheGenre(key string) error {
-	return c.ch.Client.Del(context.Background(), key).Err()
+func listsTag() string {
+	return cache.Tag("genres")
 }
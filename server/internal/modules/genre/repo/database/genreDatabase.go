@@ -1,13 +1,24 @@
 package database
 
 import (
+	"context"
 	"errors"
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 	"log/slog"
 	g "server/internal/modules/genre"
+	"server/pkg/lib/audit"
+	"server/pkg/lib/optimistic"
 )
 
+// genreTree - рекурсивный CTE со всеми потомками жанра, включая его самого
+const genreTree = `
+	WITH RECURSIVE genre_tree AS (
+		SELECT genre_id FROM genres WHERE genre_id = ?
+		UNION
+		SELECT g.genre_id FROM genres g JOIN genre_tree t ON g.parent_id = t.genre_id
+	)`
+
 type GenreDatabase struct {
 	db  *gorm.DB
 	log *slog.Logger
@@ -20,38 +31,69 @@ func NewGenreDatabase(db *gorm.DB, log *slog.Logger) *GenreDatabase {
 	}
 }
 
-func (db *GenreDatabase) CreateGenre(name string) (uint, error) {
-	genre := &g.Genre{
-		Name: name,
-	}
+func (db *GenreDatabase) CreateGenre(ctx context.Context, genre *g.GenreDTO) (uint, error) {
+	genreM := g.FromDTO(genre)
 
-	if err := db.db.Create(genre).Error; err != nil {
-		var PgxErr *pgconn.PgError
-		if errors.As(err, &PgxErr) {
-			if PgxErr.Code == "23505" {
-				return 0, g.ErrGenreExists
-			}
-		}
-		if errors.Is(err, gorm.ErrDuplicatedKey) {
-			return 0, g.ErrGenreExists
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(genreM).Error; err != nil {
+			return mapError(err)
 		}
+		return audit.Genre.Record(ctx, tx, audit.ActionCreate, genreM.GenreID, nil)
+	})
+	if err != nil {
 		return 0, err
 	}
 
-	return genre.GenreID, nil
+	return genreM.GenreID, nil
 }
 
-func (db *GenreDatabase) UpdateGenre(genre *g.GenreDTO) error {
+func (db *GenreDatabase) UpdateGenre(ctx context.Context, genre *g.GenreDTO) error {
 	genreM := g.FromDTO(genre)
 
-	if err := db.db.Save(genreM).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.Genre.Snapshot(tx, genre.GenreId)
+		if err != nil {
+			return err
+		}
+		if before == nil {
 			return g.ErrNoSuchGenre
 		}
-		return err
-	}
 
-	return nil
+		version, err := optimistic.Bump(tx, "genres", "genre_id", genre.GenreId, genre.Version)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return g.ErrNoSuchGenre
+			}
+			return err
+		}
+
+		if genre.ParentID != nil {
+			var cycle bool
+			if err := tx.Raw(genreTree+` SELECT EXISTS (SELECT 1 FROM genre_tree WHERE genre_id = ?)`,
+				genre.GenreId, *genre.ParentID).Scan(&cycle).Error; err != nil {
+				return err
+			}
+			if cycle {
+				return g.ErrGenreCycle
+			}
+		}
+
+		result := tx.Model(&g.Genre{}).Where("genre_id = ?", genre.GenreId).
+			Select("name", "slug", "description", "cover_url", "cover_blurhash", "cover_color", "parent_id").Updates(genreM)
+		if result.Error != nil {
+			return mapError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return g.ErrNoSuchGenre
+		}
+
+		if err := audit.Genre.Record(ctx, tx, audit.ActionUpdate, genre.GenreId, before); err != nil {
+			return err
+		}
+
+		genre.Version = version
+		return nil
+	})
 }
 
 func (db *GenreDatabase) GetGenre(genreID uint) (*g.GenreDTO, error) {
@@ -67,10 +109,23 @@ func (db *GenreDatabase) GetGenre(genreID uint) (*g.GenreDTO, error) {
 	return g.ToDTO(genre), nil
 }
 
+func (db *GenreDatabase) GetGenreBySlug(slug string) (*g.GenreDTO, error) {
+	var genre *g.Genre
+
+	if err := db.db.Where("slug = ?", slug).First(&genre).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, g.ErrNoSuchGenre
+		}
+		return nil, err
+	}
+
+	return g.ToDTO(genre), nil
+}
+
 func (db *GenreDatabase) GetGenres() ([]*g.GenreDTO, error) {
 	var genres []*g.Genre
 
-	if err := db.db.Find(&genres).Error; err != nil {
+	if err := db.db.Order("genre_id").Find(&genres).Error; err != nil {
 		return nil, err
 	}
 	if len(genres) == 0 {
@@ -84,12 +139,103 @@ func (db *GenreDatabase) GetGenres() ([]*g.GenreDTO, error) {
 	return genresDTO, nil
 }
 
-func (db *GenreDatabase) DeleteGenre(genreID uint) error {
-	if err := db.db.Delete(&g.Genre{GenreID: genreID}).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return g.ErrNoSuchGenre
+// GetGenreAncestors возвращает родителей жанра от корня к непосредственному родителю
+func (db *GenreDatabase) GetGenreAncestors(genreID uint) ([]*g.GenreDTO, error) {
+	var genres []*g.Genre
+
+	err := db.db.Raw(`
+		WITH RECURSIVE chain AS (
+			SELECT parent_id, 1 AS depth FROM genres WHERE genre_id = ?
+			UNION ALL
+			SELECT g.parent_id, c.depth + 1 FROM genres g JOIN chain c ON g.genre_id = c.parent_id
+			WHERE c.depth < 32
+		)
+		SELECT g.* FROM genres g JOIN chain c ON g.genre_id = c.parent_id
+		WHERE g.deleted_at IS NULL
+		ORDER BY c.depth DESC`, genreID).Scan(&genres).Error
+	if err != nil {
+		return nil, err
+	}
+
+	genresDTO := make([]*g.GenreDTO, 0, len(genres))
+	for _, genre := range genres {
+		genresDTO = append(genresDTO, g.ToDTO(genre))
+	}
+
+	return genresDTO, nil
+}
+
+func (db *GenreDatabase) GetGenreChildren(genreID uint) ([]*g.GenreDTO, error) {
+	var genres []*g.Genre
+
+	if err := db.db.Where("parent_id = ?", genreID).Order("name").Find(&genres).Error; err != nil {
+		return nil, err
+	}
+
+	genresDTO := make([]*g.GenreDTO, 0, len(genres))
+	for _, genre := range genres {
+		genresDTO = append(genresDTO, g.ToDTO(genre))
+	}
+
+	return genresDTO, nil
+}
+
+// GetTopFilms возвращает лучшие по рейтингу фильмы жанра вместе с фильмами всех его поджанров
+func (db *GenreDatabase) GetTopFilms(genreID uint, limit int) ([]*g.TopFilmDTO, error) {
+	var films []*g.TopFilmDTO
+
+	err := db.db.Raw(genreTree+`
+		SELECT f.film_id, f.title, f.poster_url, f.poster_blurhash, f.poster_color, f.release_date, fs.avg_rating, fs.total_count_reviews
+		FROM films f
+		JOIN film_stats fs ON fs.film_id = f.film_id
+		WHERE f.deleted_at IS NULL AND EXISTS (
+			SELECT 1 FROM film_genre fg JOIN genre_tree t ON t.genre_id = fg.genre_id
+			WHERE fg.film_id = f.film_id
+		)
+		ORDER BY fs.avg_rating DESC, fs.total_count_reviews DESC, f.film_id
+		LIMIT ?`, genreID, limit).Scan(&films).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return films, nil
+}
+
+func (db *GenreDatabase) DeleteGenre(ctx context.Context, genreID uint) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.Genre.Snapshot(tx, genreID)
+		if err != nil {
+			return err
 		}
-		return err
+		if before == nil {
+			return nil
+		}
+
+		if err := tx.Delete(&g.Genre{GenreID: genreID}).Error; err != nil {
+			return err
+		}
+
+		return audit.Genre.Record(ctx, tx, audit.ActionDelete, genreID, before)
+	})
+}
+
+func mapError(err error) error {
+	var PgxErr *pgconn.PgError
+	if errors.As(err, &PgxErr) {
+		switch PgxErr.Code {
+		case "23505":
+			if PgxErr.ConstraintName == "genres_slug_key" {
+				return g.ErrSlugExists
+			}
+			return g.ErrGenreExists
+		case "23503":
+			return g.ErrNoSuchParentGenre
+		case "23514":
+			return g.ErrGenreCycle
+		}
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return g.ErrGenreExists
 	}
-	return nil
+	return err
 }
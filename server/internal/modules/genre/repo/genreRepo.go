@@ -1,62 +1,103 @@
 package repo
 
 import (
+	"context"
 	g "server/internal/modules/genre"
 	"time"
 )
 
 type GenreDB interface {
-	CreateGenre(name string) (uint, error)
-	UpdateGenre(genre *g.GenreDTO) error
+	CreateGenre(ctx context.Context, genre *g.GenreDTO) (uint, error)
+	UpdateGenre(ctx context.Context, genre *g.GenreDTO) error
 	GetGenre(genreID uint) (*g.GenreDTO, error)
+	GetGenreBySlug(slug string) (*g.GenreDTO, error)
 	GetGenres() ([]*g.GenreDTO, error)
-	DeleteGenre(genreID uint) error
+	GetGenreAncestors(genreID uint) ([]*g.GenreDTO, error)
+	GetGenreChildren(genreID uint) ([]*g.GenreDTO, error)
+	GetTopFilms(genreID uint, limit int) ([]*g.TopFilmDTO, error)
+	DeleteGenre(ctx context.Context, genreID uint) error
 }
 
 type GenreCh interface {
-	SetCacheGenre(key string, value interface{}, ttl time.Duration) error
-	GetCacheGenre(key string) ([]*g.GenreDTO, error)
-	DeleteCacheGenre(key string) error
+	LoadGenre(id uint, ttl time.Duration, load func() (*g.GenreDTO, error)) (*g.GenreDTO, error)
+	LoadGenres(ttl time.Duration, load func() ([]*g.GenreDTO, error)) ([]*g.GenreDTO, error)
+	InvalidateGenre(id uint) error
+	InvalidateGenreLists() error
+}
+
+type GenreS3 interface {
+	UploadCover(genreID uint, variants map[string][]byte) (string, error)
+	DeleteCover(genreID uint) error
 }
 
 type Repo struct {
 	db GenreDB
 	ch GenreCh
+	s3 GenreS3
 }
 
-func NewGenreRepo(db GenreDB, ch GenreCh) *Repo {
+func NewGenreRepo(db GenreDB, ch GenreCh, s3 GenreS3) *Repo {
 	return &Repo{db: db,
-		ch: ch}
+		ch: ch,
+		s3: s3}
 }
 
-func (r *Repo) CreateGenre(name string) (uint, error) {
-	return r.db.CreateGenre(name)
+func (r *Repo) CreateGenre(ctx context.Context, genre *g.GenreDTO) (uint, error) {
+	return r.db.CreateGenre(ctx, genre)
 }
 
-func (r *Repo) UpdateGenre(genre *g.GenreDTO) error {
-	return r.db.UpdateGenre(genre)
+func (r *Repo) UpdateGenre(ctx context.Context, genre *g.GenreDTO) error {
+	return r.db.UpdateGenre(ctx, genre)
 }
 
 func (r *Repo) GetGenre(genreID uint) (*g.GenreDTO, error) {
 	return r.db.GetGenre(genreID)
 }
 
+func (r *Repo) GetGenreBySlug(slug string) (*g.GenreDTO, error) {
+	return r.db.GetGenreBySlug(slug)
+}
+
 func (r *Repo) GetGenres() ([]*g.GenreDTO, error) {
 	return r.db.GetGenres()
 }
 
-func (r *Repo) DeleteGenre(genreID uint) error {
-	return r.db.DeleteGenre(genreID)
+func (r *Repo) GetGenreAncestors(genreID uint) ([]*g.GenreDTO, error) {
+	return r.db.GetGenreAncestors(genreID)
+}
+
+func (r *Repo) GetGenreChildren(genreID uint) ([]*g.GenreDTO, error) {
+	return r.db.GetGenreChildren(genreID)
+}
+
+func (r *Repo) GetTopFilms(genreID uint, limit int) ([]*g.TopFilmDTO, error) {
+	return r.db.GetTopFilms(genreID, limit)
+}
+
+func (r *Repo) DeleteGenre(ctx context.Context, genreID uint) error {
+	return r.db.DeleteGenre(ctx, genreID)
+}
+
+func (r *Repo) LoadGenre(id uint, ttl time.Duration, load func() (*g.GenreDTO, error)) (*g.GenreDTO, error) {
+	return r.ch.LoadGenre(id, ttl, load)
+}
+
+func (r *Repo) LoadGenres(ttl time.Duration, load func() ([]*g.GenreDTO, error)) ([]*g.GenreDTO, error) {
+	return r.ch.LoadGenres(ttl, load)
+}
+
+func (r *Repo) InvalidateGenre(id uint) error {
+	return r.ch.InvalidateGenre(id)
 }
 
-func (r *Repo) SetCacheGenre(key string, value interface{}, ttl time.Duration) error {
-	return r.ch.SetCacheGenre(key, value, ttl)
+func (r *Repo) InvalidateGenreLists() error {
+	return r.ch.InvalidateGenreLists()
 }
 
-func (r *Repo) GetCacheGenre(key string) ([]*g.GenreDTO, error) {
-	return r.ch.GetCacheGenre(key)
+func (r *Repo) UploadCover(genreID uint, variants map[string][]byte) (string, error) {
+	return r.s3.UploadCover(genreID, variants)
 }
 
-func (r *Repo) DeleteCacheGenre(key string) error {
-	return r.ch.DeleteCacheGenre(key)
+func (r *Repo) DeleteCover(genreID uint) error {
+	return r.s3.DeleteCover(genreID)
 }
@@ -0,0 +1,43 @@
+package s3
+
+import (
+	"fmt"
+	"log/slog"
+	"server/internal/init/blob"
+	avatarManager "server/pkg/lib/avatarMenager"
+)
+
+type GenreS3 struct {
+	log    *slog.Logger
+	store  blob.Store
+	bucket string
+}
+
+func NewGenreS3(log *slog.Logger, store blob.Store) *GenreS3 {
+	return &GenreS3{log: log, store: store, bucket: "genrecover"}
+}
+
+// UploadCover загружает все размеры обложки в папку жанра с хэшем содержимого и возвращает адрес папки
+func (s *GenreS3) UploadCover(genreID uint, variants map[string][]byte) (string, error) {
+	folder := fmt.Sprintf("covers/%d/%s/", genreID, avatarManager.ContentHash(variants))
+
+	objects := make(map[string][]byte, len(variants))
+	for name, data := range variants {
+		objects[folder+avatarManager.VariantKey(name)] = data
+	}
+
+	if err := blob.PutImages(s.store, s.bucket, objects); err != nil {
+		return "", err
+	}
+
+	return s.store.URL(s.bucket, folder), nil
+}
+
+// DeleteCover удаляет все папки с размерами обложки и обложку старого формата одним файлом
+func (s *GenreS3) DeleteCover(genreID uint) error {
+	if err := s.store.DeletePrefix(s.bucket, fmt.Sprintf("covers/%d/", genreID)); err != nil {
+		return err
+	}
+
+	return s.store.Delete(s.bucket, fmt.Sprintf("/covers/%d", genreID))
+}
@@ -1,97 +1,274 @@
 package usecase
 
 import (
+	"context"
 	"log/slog"
+	"mime/multipart"
 	g "server/internal/modules/genre"
-	"strconv"
+	tr "server/internal/modules/translation"
+	avatarManager "server/pkg/lib/avatarMenager"
+	"server/pkg/lib/optimistic"
+	"server/pkg/lib/slug"
+	"server/pkg/middleware/locale"
 	"time"
 )
 
+// Translator - переводы полей жанров и фильмов (модуль translation)
+type Translator interface {
+	ResolveTranslations(entityType string, entityIDs []uint, locales []string) (map[uint]map[string]string, error)
+}
+
 type GenreUsecase struct {
 	log *slog.Logger
 	rp  g.Repo
+	tr  Translator
 }
 
-func NewGenreUsecase(rp g.Repo, l *slog.Logger) *GenreUsecase {
+func NewGenreUsecase(rp g.Repo, l *slog.Logger, translator Translator) *GenreUsecase {
 	return &GenreUsecase{
 		log: l,
 		rp:  rp,
+		tr:  translator,
 	}
 }
 
-func (uc *GenreUsecase) CreateGenre(name string) (uint, error) {
-	_ = uc.rp.DeleteCacheGenre("genres")
-	return uc.rp.CreateGenre(name)
-}
+func (uc *GenreUsecase) CreateGenre(ctx context.Context, genre *g.GenreDTO, cover *multipart.File) (uint, error) {
+	if genre.Slug == "" {
+		genre.Slug = slug.Slugify(genre.Name)
+	}
+
+	var coverImage *avatarManager.Image
+	if *cover != nil {
+		img, err := avatarManager.Process(cover, avatarManager.CoverProfile)
+		if err != nil {
+			return 0, err
+		}
+		coverImage = img
+	}
+
+	id, err := uc.rp.CreateGenre(ctx, genre)
+	if err != nil {
+		return 0, err
+	}
+	genre.GenreId = id
 
-func (uc *GenreUsecase) UpdateGenre(genreID uint, newName string) error {
-	genre := &g.GenreDTO{
-		Name:    newName,
-		GenreId: genreID,
+	if coverImage != nil {
+		if err := uc.uploadCover(genre, coverImage); err != nil {
+			return id, err
+		}
+
+		if err := uc.rp.UpdateGenre(ctx, genre); err != nil {
+			return id, err
+		}
 	}
 
-	err := uc.rp.UpdateGenre(genre)
+	_ = uc.rp.InvalidateGenreLists()
+	return id, nil
+}
+
+func (uc *GenreUsecase) UpdateGenre(ctx context.Context, genre *g.GenreDTO, cover *multipart.File) error {
+	current, err := uc.rp.GetGenre(genre.GenreId)
 	if err != nil {
 		return err
 	}
+	// устаревшая версия отклоняется до удаления и загрузки обложки
+	if err := optimistic.Check(current.Version, genre.Version); err != nil {
+		return err
+	}
+
+	if genre.Slug == "" {
+		genre.Slug = current.Slug
+	}
+	genre.CoverURL = current.CoverURL
+	genre.CoverBlurhash = current.CoverBlurhash
+	genre.CoverColor = current.CoverColor
+
+	// прежнюю обложку удалит PurgeRetiredMedia, когда триггер media_objects выведет ее при изменении
+	if genre.RemoveCover && current.CoverURL != "" {
+		genre.CoverURL, genre.CoverBlurhash, genre.CoverColor = "", "", ""
+	}
+
+	if *cover != nil {
+		img, err := avatarManager.Process(cover, avatarManager.CoverProfile)
+		if err != nil {
+			return err
+		}
+		if err := uc.uploadCover(genre, img); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.rp.UpdateGenre(ctx, genre); err != nil {
+		return err
+	}
 
-	cacheKey := "genre_" + strconv.Itoa(int(genreID))
-	_ = uc.rp.DeleteCacheGenre(cacheKey)
+	_ = uc.rp.InvalidateGenre(genre.GenreId)
 
 	return nil
 }
 
-func (uc *GenreUsecase) GetGenre(genreID uint) (*g.GenreDTO, error) {
-	cacheKey := "genre_" + strconv.Itoa(int(genreID))
-	genreFromCache, err := uc.rp.GetCacheGenre(cacheKey)
-	if err == nil && genreFromCache[0] != nil {
+// PatchGenre накладывает переданные поля на текущий жанр и сохраняет его целиком. Версия клиента
+// проверяется при сохранении, поэтому изменение, сделанное между чтением и записью, не потеряется
+func (uc *GenreUsecase) PatchGenre(ctx context.Context, patch *g.GenrePatch) (*g.GenreDTO, error) {
+	genre, err := uc.rp.GetGenre(patch.GenreId)
+	if err != nil {
 		return nil, err
 	}
 
-	genre, err := uc.rp.GetGenre(genreID)
-	if err != nil {
-		return nil, err
+	patch.Name.Apply(&genre.Name)
+	patch.Description.Apply(&genre.Description)
+	switch {
+	case patch.Slug.IsNull():
+		genre.Slug = slug.Slugify(genre.Name)
+	case patch.Slug.Present():
+		genre.Slug = patch.Slug.Value
 	}
+	switch {
+	case patch.ParentID.IsNull():
+		genre.ParentID = nil
+	case patch.ParentID.Present():
+		parentID := patch.ParentID.Value
+		genre.ParentID = &parentID
+	}
+	genre.Version = patch.Version
 
-	err = uc.rp.SetCacheGenre(cacheKey, genre, time.Hour*24)
-	if err != nil {
+	if err := uc.rp.UpdateGenre(ctx, genre); err != nil {
 		return nil, err
 	}
+	patch.Version = genre.Version
 
+	_ = uc.rp.InvalidateGenre(genre.GenreId)
 	return genre, nil
 }
 
+// uploadCover загружает все размеры обложки и проставляет жанру адрес папки, заглушку и цвет
+func (uc *GenreUsecase) uploadCover(genre *g.GenreDTO, img *avatarManager.Image) error {
+	coverUrl, err := uc.rp.UploadCover(genre.GenreId, img.Variants)
+	if err != nil {
+		uc.log.Error("failed to upload genre cover", "error", err, "genre_id", genre.GenreId)
+		return g.ErrCoverUploadFailed
+	}
+
+	genre.CoverURL = coverUrl
+	genre.CoverBlurhash = img.Blurhash
+	genre.CoverColor = img.DominantColor
+	return nil
+}
+
+func (uc *GenreUsecase) GetGenre(genreID uint) (*g.GenreDTO, error) {
+	return uc.rp.LoadGenre(genreID, time.Hour*24, func() (*g.GenreDTO, error) {
+		return uc.rp.GetGenre(genreID)
+	})
+}
+
 func (uc *GenreUsecase) GetGenres() ([]*g.GenreDTO, error) {
-	cacheKey := "genres"
+	return uc.rp.LoadGenres(time.Hour*24, uc.rp.GetGenres)
+}
 
-	genres, err := uc.rp.GetCacheGenre(cacheKey)
-	if err == nil && genres != nil {
-		return genres, nil
+// GetGenrePage собирает страницу жанра на языке lang. Топ фильмов зависит от рецензий, поэтому не кэшируется
+func (uc *GenreUsecase) GetGenrePage(slug string, limit int, lang string) (*g.GenrePageDTO, error) {
+	genre, err := uc.rp.GetGenreBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := uc.rp.GetGenreAncestors(genre.GenreId)
+	if err != nil {
+		return nil, err
 	}
 
-	genres, err = uc.rp.GetGenres()
+	children, err := uc.rp.GetGenreChildren(genre.GenreId)
 	if err != nil {
 		return nil, err
 	}
 
-	err = uc.rp.SetCacheGenre(cacheKey, genres, time.Hour*24)
+	films, err := uc.rp.GetTopFilms(genre.GenreId, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	return genres, nil
+	genres := append([]*g.GenreDTO{genre}, ancestors...)
+	uc.LocalizeGenres(append(genres, children...), lang)
+
+	filmIDs := make([]uint, 0, len(films))
+	for _, film := range films {
+		filmIDs = append(filmIDs, film.FilmID)
+	}
+	filmTr, err := uc.tr.ResolveTranslations(tr.EntityFilm, filmIDs, locale.Fallbacks(lang))
+	if err != nil {
+		uc.log.Error("failed to resolve film translations", "error", err, "lang", lang)
+	}
+	for _, film := range films {
+		if title := filmTr[film.FilmID]["title"]; title != "" {
+			film.Title = title
+		}
+	}
+
+	return &g.GenrePageDTO{
+		Genre:     genre,
+		Ancestors: ancestors,
+		Children:  children,
+		TopFilms:  films,
+	}, nil
 }
 
-func (uc *GenreUsecase) DeleteGenre(genreID uint) error {
-	err := uc.rp.DeleteGenre(genreID)
+// LocalizeGenres подставляет переводы названий и описаний жанров на язык lang
+// с откатом на язык по умолчанию. При ошибке жанры остаются на основном языке
+func (uc *GenreUsecase) LocalizeGenres(genres []*g.GenreDTO, lang string) {
+	if len(genres) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(genres))
+	for _, genre := range genres {
+		ids = append(ids, genre.GenreId)
+	}
+
+	translations, err := uc.tr.ResolveTranslations(tr.EntityGenre, ids, locale.Fallbacks(lang))
 	if err != nil {
-		return err
+		uc.log.Error("failed to resolve genre translations", "error", err, "lang", lang)
+		return
 	}
 
-	cacheKey := "genre_" + strconv.Itoa(int(genreID))
-	_ = uc.rp.DeleteCacheGenre(cacheKey)
+	for _, genre := range genres {
+		fields := translations[genre.GenreId]
+		if name := fields["name"]; name != "" {
+			genre.Name = name
+		}
+		if description := fields["description"]; description != "" {
+			genre.Description = description
+		}
+	}
+}
 
-	_ = uc.rp.DeleteCacheGenre("genres")
+// DeleteGenre переносит жанр в корзину, обложка удаляется только при очистке корзины
+func (uc *GenreUsecase) DeleteGenre(ctx context.Context, genreID uint) error {
+	err := uc.rp.DeleteGenre(ctx, genreID)
+	if err != nil {
+		return err
+	}
+
+	uc.InvalidateGenreCache(genreID)
 
 	return nil
 }
+
+// InvalidateGenreCache сбрасывает кэш жанра и его дочерних жанров: у них меняется цепочка родителей
+func (uc *GenreUsecase) InvalidateGenreCache(genreID uint) {
+	children, err := uc.rp.GetGenreChildren(genreID)
+	if err != nil {
+		uc.log.Error("failed to get genre children", "error", err, "genre_id", genreID)
+	}
+	for _, child := range children {
+		_ = uc.rp.InvalidateGenre(child.GenreId)
+	}
+
+	_ = uc.rp.InvalidateGenre(genreID)
+}
+
+// CleanupGenre удаляет обложку окончательно удаленного жанра
+func (uc *GenreUsecase) CleanupGenre(genreID uint) {
+	if err := uc.rp.DeleteCover(genreID); err != nil {
+		uc.log.Error("failed to delete genre cover", "error", err, "genre_id", genreID)
+	}
+}
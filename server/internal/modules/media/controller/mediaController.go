@@ -0,0 +1,432 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	md "server/internal/modules/media"
+	avatarManager "server/pkg/lib/avatarMenager"
+	resp "server/pkg/lib/response"
+	"server/pkg/lib/videolink"
+	"strconv"
+)
+
+type Controller struct {
+	log      *slog.Logger
+	uc       md.UseCase
+	validate *validator.Validate
+}
+
+func NewMediaController(log *slog.Logger, uc md.UseCase) *Controller {
+	return &Controller{
+		log:      log,
+		uc:       uc,
+		validate: validator.New(),
+	}
+}
+
+// GetFilmImages - Галерея фильма
+// @Summary Получить изображения фильма
+// @Description Возвращает фоны, кадры, логотипы и альтернативные постеры фильма в порядке галереи
+// @Tags media
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param type query string false "Тип изображений: backdrop, still, logo, poster"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/images [get]
+func (c *Controller) GetFilmImages(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetFilmImages")
+
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	images, err := c.uc.GetFilmImages(filmID, r.URL.Query().Get("type"))
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.FilmImages(images))
+}
+
+// UploadFilmImage - Загрузка изображения фильма
+// @Summary Загрузить изображение фильма
+// @Description Добавляет изображение в конец галереи фильма. Первое изображение типа становится основным,
+// @Description primary=true делает основным новое изображение
+// @Tags media
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param type formData string true "Тип изображения: backdrop, still, logo, poster"
+// @Param primary formData bool false "Сделать изображение основным"
+// @Param image formData file true "Изображение: фон не меньше 1280x720, кадр 640x360, логотип 200x50, постер 400x600"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/images [post]
+func (c *Controller) UploadFilmImage(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "UploadFilmImage")
+
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "unable to parse form"))
+		return
+	}
+
+	var primary bool
+	if primaryStr := r.FormValue("primary"); primaryStr != "" {
+		var err error
+		primary, err = strconv.ParseBool(primaryStr)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid primary format, expected true or false"))
+			return
+		}
+	}
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		log.Error("failed to get file from form", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to get file from form"))
+		return
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Error("failed to close image file", "error", err)
+		}
+	}()
+
+	image := &md.FilmImageDTO{
+		FilmID:    filmID,
+		Type:      r.FormValue("type"),
+		IsPrimary: primary,
+	}
+	if err := c.uc.UploadFilmImage(image, &file); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.FilmImages(image))
+}
+
+// SetPrimaryFilmImage - Выбор основного изображения
+// @Summary Сделать изображение основным
+// @Description Делает изображение основным среди изображений своего типа. Основной фон показывается в карточке фильма
+// @Tags media
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param image_id path string true "Id изображения"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/images/{image_id}/primary [put]
+func (c *Controller) SetPrimaryFilmImage(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "SetPrimaryFilmImage")
+
+	filmID, imageID, ok := parseFilmImage(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.SetPrimaryFilmImage(filmID, imageID); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// ReorderFilmImages - Порядок изображений
+// @Summary Изменить порядок изображений фильма
+// @Description Задает порядок изображений одного типа, в списке должны быть все изображения типа
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param json body ReorderImagesRequest true "Тип и Id изображений в новом порядке"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/images/order [put]
+func (c *Controller) ReorderFilmImages(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "ReorderFilmImages")
+
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req ReorderImagesRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	if err := c.uc.ReorderFilmImages(filmID, req.Type, req.ImageIDs); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// DeleteFilmImage - Удаление изображения фильма
+// @Summary Удалить изображение фильма
+// @Description Удаляет изображение со всеми размерами. Если оно было основным, основным становится следующее по порядку
+// @Tags media
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param image_id path string true "Id изображения"
+// @Success 204 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/images/{image_id} [delete]
+func (c *Controller) DeleteFilmImage(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DeleteFilmImage")
+
+	filmID, imageID, ok := parseFilmImage(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteFilmImage(filmID, imageID); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	render.JSON(w, r, resp.OK())
+}
+
+// GetFilmVideos - Видео фильма
+// @Summary Получить видео фильма
+// @Description Возвращает трейлеры, тизеры и фрагменты фильма со ссылками на страницу видео и плеер для встраивания.
+// @Description Сначала трейлеры, внутри типа официальные и свежие первыми
+// @Tags media
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param type query string false "Тип видео: trailer, teaser, clip"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/videos [get]
+func (c *Controller) GetFilmVideos(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetFilmVideos")
+
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	videos, err := c.uc.GetFilmVideos(filmID, r.URL.Query().Get("type"))
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.FilmVideos(videos))
+}
+
+// CreateFilmVideo - Добавление видео фильма
+// @Summary Добавить видео фильма
+// @Description Добавляет видео по ссылке на YouTube, VK или Rutube. Ссылка проверяется только по виду,
+// @Description из нее сохраняются провайдер и id видео
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param json body FilmVideoRequest true "Данные видео"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/videos [post]
+func (c *Controller) CreateFilmVideo(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "CreateFilmVideo")
+
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req FilmVideoRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	video := req.toDTO(filmID, 0)
+	if err := c.uc.CreateFilmVideo(video, req.URL); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.FilmVideos(video))
+}
+
+// UpdateFilmVideo - Обновление видео фильма
+// @Summary Обновить видео фильма
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param video_id path string true "Id видео"
+// @Param json body FilmVideoRequest true "Данные видео"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/videos/{video_id} [put]
+func (c *Controller) UpdateFilmVideo(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "UpdateFilmVideo")
+
+	filmID, videoID, ok := parseFilmVideo(w, r)
+	if !ok {
+		return
+	}
+
+	var req FilmVideoRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	video := req.toDTO(filmID, videoID)
+	if err := c.uc.UpdateFilmVideo(video, req.URL); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// DeleteFilmVideo - Удаление видео фильма
+// @Summary Удалить видео фильма
+// @Tags media
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param video_id path string true "Id видео"
+// @Success 204 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/videos/{video_id} [delete]
+func (c *Controller) DeleteFilmVideo(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DeleteFilmVideo")
+
+	filmID, videoID, ok := parseFilmVideo(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteFilmVideo(filmID, videoID); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	render.JSON(w, r, resp.OK())
+}
+
+// decode читает и валидирует тело запроса, при ошибке сам отвечает клиенту
+func (c *Controller) decode(w http.ResponseWriter, r *http.Request, log *slog.Logger, req interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		log.Error("failed to decode request body", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
+		return false
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return false
+	}
+
+	return true
+}
+
+func (c *Controller) writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, md.ErrFilmNotFound) || errors.Is(err, md.ErrImageNotFound) || errors.Is(err, md.ErrVideoNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, md.ErrInvalidImageType) || errors.Is(err, md.ErrInvalidImageOrder) ||
+		errors.Is(err, avatarManager.ErrInvalidTypeImage) || errors.Is(err, avatarManager.ErrInvalidTypePoster) ||
+		errors.Is(err, avatarManager.ErrInvalidResolutionBackdrop) || errors.Is(err, avatarManager.ErrInvalidResolutionStill) ||
+		errors.Is(err, avatarManager.ErrInvalidResolutionLogo) || errors.Is(err, avatarManager.ErrInvalidResolutionPoster) ||
+		errors.Is(err, md.ErrInvalidVideoType) || errors.Is(err, videolink.ErrInvalidVideoURL):
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, md.ErrVideoExists):
+		w.WriteHeader(http.StatusConflict)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	default:
+		log.Error("media request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, md.ErrInternal.Error()))
+	}
+}
+
+func parseID(w http.ResponseWriter, r *http.Request, param string) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, param), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid "+param))
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func parseFilmImage(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	imageID, ok := parseID(w, r, "image_id")
+	if !ok {
+		return 0, 0, false
+	}
+
+	return filmID, imageID, true
+}
+
+func parseFilmVideo(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	videoID, ok := parseID(w, r, "video_id")
+	if !ok {
+		return 0, 0, false
+	}
+
+	return filmID, videoID, true
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	md "server/internal/modules/media"
+	"time"
+)
+
+// ReorderImagesRequest - новый порядок изображений одного типа, ImageIDs должен перечислять все изображения типа
+type ReorderImagesRequest struct {
+	Type     string `json:"type" validate:"required,oneof=backdrop still logo poster"`
+	ImageIDs []uint `json:"image_ids" validate:"required,min=1,dive,gt=0"`
+}
+
+// FilmVideoRequest - видео фильма. URL - ссылка на видео YouTube, VK или Rutube в любом из обычных видов,
+// PublishedAt - дата публикации в формате 2006-01-02
+type FilmVideoRequest struct {
+	URL         string `json:"url" validate:"required,max=2048"`
+	Type        string `json:"type" validate:"required,oneof=trailer teaser clip"`
+	Name        string `json:"name" validate:"max=255"`
+	Language    string `json:"language" validate:"omitempty,len=2,lowercase,alpha"`
+	Official    bool   `json:"official"`
+	PublishedAt string `json:"published_at" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// toDTO собирает видео без провайдера и id видео, их заполняет разбор ссылки в usecase.
+// Дата уже проверена валидатором
+func (req *FilmVideoRequest) toDTO(filmID uint, videoID uint) *md.FilmVideoDTO {
+	video := &md.FilmVideoDTO{
+		ID:       videoID,
+		FilmID:   filmID,
+		Type:     req.Type,
+		Name:     req.Name,
+		Language: req.Language,
+		Official: req.Official,
+	}
+	if publishedAt, err := time.Parse("2006-01-02", req.PublishedAt); err == nil {
+		video.PublishedAt = &publishedAt
+	}
+
+	return video
+}
@@ -0,0 +1,131 @@
+package media
+
+import (
+	"mime/multipart"
+	"net/http"
+	avatarManager "server/pkg/lib/avatarMenager"
+	"time"
+)
+
+// Типы изображений фильма
+const (
+	ImageTypeBackdrop = "backdrop" // фон страницы фильма
+	ImageTypeStill    = "still"    // кадр из фильма
+	ImageTypeLogo     = "logo"     // логотип названия
+	ImageTypePoster   = "poster"   // альтернативный постер
+)
+
+// Типы видео фильма
+const (
+	VideoTypeTrailer = "trailer"
+	VideoTypeTeaser  = "teaser"
+	VideoTypeClip    = "clip"
+)
+
+// FilmImageDTO - изображение из галереи фильма. URL - папка со всеми размерами изображения,
+// Position - порядок среди изображений того же типа, начиная с 1
+type FilmImageDTO struct {
+	ID        uint      `json:"id"`
+	FilmID    uint      `json:"film_id"`
+	Type      string    `json:"type"`
+	URL       string    `json:"url"`
+	Width     int       `json:"width"`
+	Height    int       `json:"height"`
+	Blurhash  string    `json:"blurhash"`
+	Color     string    `json:"color"`
+	Position  int       `json:"position"`
+	IsPrimary bool      `json:"is_primary"` // основной фон попадает в FilmDTO
+	CreateAt  time.Time `json:"create_at"`
+}
+
+// FilmVideoDTO - видео фильма у провайдера (videolink.Provider*), Key - id видео у провайдера.
+// Основной трейлер фильма - официальный и самый свежий из трейлеров, он попадает в FilmDTO
+type FilmVideoDTO struct {
+	ID          uint       `json:"id"`
+	FilmID      uint       `json:"film_id"`
+	Type        string     `json:"type"`
+	Provider    string     `json:"provider"`
+	Key         string     `json:"key"`
+	Name        string     `json:"name"`
+	Language    string     `json:"language"`
+	Official    bool       `json:"official"`
+	PublishedAt *time.Time `json:"published_at"`
+	CreateAt    time.Time  `json:"create_at"`
+}
+
+func ValidVideoType(videoType string) bool {
+	switch videoType {
+	case VideoTypeTrailer, VideoTypeTeaser, VideoTypeClip:
+		return true
+	default:
+		return false
+	}
+}
+
+// ImageProfile возвращает настройки обработки для типа изображения, false - неизвестный тип
+func ImageProfile(imageType string) (avatarManager.Profile, bool) {
+	switch imageType {
+	case ImageTypeBackdrop:
+		return avatarManager.BackdropProfile, true
+	case ImageTypeStill:
+		return avatarManager.StillProfile, true
+	case ImageTypeLogo:
+		return avatarManager.LogoProfile, true
+	case ImageTypePoster:
+		return avatarManager.PosterProfile, true
+	default:
+		return avatarManager.Profile{}, false
+	}
+}
+
+type Controller interface {
+	GetFilmImages(w http.ResponseWriter, r *http.Request)
+	UploadFilmImage(w http.ResponseWriter, r *http.Request)
+	SetPrimaryFilmImage(w http.ResponseWriter, r *http.Request)
+	ReorderFilmImages(w http.ResponseWriter, r *http.Request)
+	DeleteFilmImage(w http.ResponseWriter, r *http.Request)
+
+	GetFilmVideos(w http.ResponseWriter, r *http.Request)
+	CreateFilmVideo(w http.ResponseWriter, r *http.Request)
+	UpdateFilmVideo(w http.ResponseWriter, r *http.Request)
+	DeleteFilmVideo(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	GetFilmImages(filmID uint, imageType string) ([]*FilmImageDTO, error)
+	UploadFilmImage(image *FilmImageDTO, file *multipart.File) error
+	SetPrimaryFilmImage(filmID uint, imageID uint) error
+	ReorderFilmImages(filmID uint, imageType string, imageIDs []uint) error
+	DeleteFilmImage(filmID uint, imageID uint) error
+
+	GetFilmVideos(filmID uint, videoType string) ([]*FilmVideoDTO, error)
+	CreateFilmVideo(video *FilmVideoDTO, videoURL string) error
+	UpdateFilmVideo(video *FilmVideoDTO, videoURL string) error
+	DeleteFilmVideo(filmID uint, videoID uint) error
+}
+
+type Repo interface {
+	//DB
+	GetFilmImages(filmID uint) ([]*FilmImageDTO, error)
+	CreateFilmImage(image *FilmImageDTO) error
+	UpdateFilmImageFile(image *FilmImageDTO) error
+	SetPrimaryFilmImage(filmID uint, imageID uint) error
+	ReorderFilmImages(filmID uint, imageType string, imageIDs []uint) error
+	DeleteFilmImage(filmID uint, imageID uint) (*FilmImageDTO, error)
+	GetFilmVideos(filmID uint) ([]*FilmVideoDTO, error)
+	CreateFilmVideo(video *FilmVideoDTO) error
+	UpdateFilmVideo(video *FilmVideoDTO) error
+	DeleteFilmVideo(filmID uint, videoID uint) error
+
+	//Cache
+	GetFilmImagesFromCache(key string) ([]*FilmImageDTO, error)
+	SetFilmImagesToCache(key string, images []*FilmImageDTO, ttl time.Duration) error
+	DeleteFilmImagesFromCache(key string) error
+	GetFilmVideosFromCache(key string) ([]*FilmVideoDTO, error)
+	SetFilmVideosToCache(key string, videos []*FilmVideoDTO, ttl time.Duration) error
+	DeleteFilmVideosFromCache(key string) error
+
+	//S3
+	UploadFilmImage(filmID uint, imageID uint, variants map[string][]byte) (string, error)
+	DeleteFilmImageFiles(filmID uint, imageID uint) error
+}
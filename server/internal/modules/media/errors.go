@@ -0,0 +1,16 @@
+package media
+
+import "errors"
+
+var (
+	ErrInternal          = errors.New("internal server error")
+	ErrMissCache         = errors.New("miss cache error")
+	ErrFilmNotFound      = errors.New("film not found")
+	ErrImageNotFound     = errors.New("film image not found")
+	ErrInvalidImageType  = errors.New("invalid image type, supported types are backdrop, still, logo, poster")
+	ErrInvalidImageOrder = errors.New("image order must list every image of the type exactly once")
+	ErrImageUploadFailed = errors.New("film image upload failed")
+	ErrVideoNotFound     = errors.New("film video not found")
+	ErrVideoExists       = errors.New("video already added to film")
+	ErrInvalidVideoType  = errors.New("invalid video type, supported types are trailer, teaser, clip")
+)
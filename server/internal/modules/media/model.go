@@ -0,0 +1,83 @@
+package media
+
+import "time"
+
+type FilmImage struct {
+	ImageID   uint      `gorm:"primaryKey;column:image_id;autoIncrement"`
+	FilmID    uint      `gorm:"column:film_id;not null"`
+	ImageType string    `gorm:"column:image_type;type:varchar(16);not null"`
+	URL       string    `gorm:"column:url;type:text;not null;default:''"`
+	Width     int       `gorm:"column:width;not null;default:0"`
+	Height    int       `gorm:"column:height;not null;default:0"`
+	Blurhash  string    `gorm:"column:blurhash;type:varchar(64);not null;default:''"`
+	Color     string    `gorm:"column:color;type:varchar(7);not null;default:''"`
+	Position  int       `gorm:"column:position;not null;default:0"`
+	IsPrimary bool      `gorm:"column:is_primary;not null;default:false"`
+	CreatedAt time.Time `gorm:"column:create_at"`
+}
+
+func (FilmImage) TableName() string {
+	return "film_images"
+}
+
+func (i *FilmImage) ToDTO() *FilmImageDTO {
+	return &FilmImageDTO{
+		ID:        i.ImageID,
+		FilmID:    i.FilmID,
+		Type:      i.ImageType,
+		URL:       i.URL,
+		Width:     i.Width,
+		Height:    i.Height,
+		Blurhash:  i.Blurhash,
+		Color:     i.Color,
+		Position:  i.Position,
+		IsPrimary: i.IsPrimary,
+		CreateAt:  i.CreatedAt,
+	}
+}
+
+type FilmVideo struct {
+	VideoID     uint       `gorm:"primaryKey;column:video_id;autoIncrement"`
+	FilmID      uint       `gorm:"column:film_id;not null"`
+	VideoType   string     `gorm:"column:video_type;type:varchar(16);not null"`
+	Provider    string     `gorm:"column:provider;type:varchar(16);not null"`
+	VideoKey    string     `gorm:"column:video_key;type:varchar(64);not null"`
+	Name        string     `gorm:"column:name;type:varchar(255);not null;default:''"`
+	Language    string     `gorm:"column:language;type:varchar(2);not null;default:''"`
+	Official    bool       `gorm:"column:official;not null;default:false"`
+	PublishedAt *time.Time `gorm:"column:published_at;type:date"`
+	CreatedAt   time.Time  `gorm:"column:create_at"`
+}
+
+func (FilmVideo) TableName() string {
+	return "film_videos"
+}
+
+func (v *FilmVideo) ToDTO() *FilmVideoDTO {
+	return &FilmVideoDTO{
+		ID:          v.VideoID,
+		FilmID:      v.FilmID,
+		Type:        v.VideoType,
+		Provider:    v.Provider,
+		Key:         v.VideoKey,
+		Name:        v.Name,
+		Language:    v.Language,
+		Official:    v.Official,
+		PublishedAt: v.PublishedAt,
+		CreateAt:    v.CreatedAt,
+	}
+}
+
+func FilmVideoFromDTO(dto *FilmVideoDTO) *FilmVideo {
+	return &FilmVideo{
+		VideoID:     dto.ID,
+		FilmID:      dto.FilmID,
+		VideoType:   dto.Type,
+		Provider:    dto.Provider,
+		VideoKey:    dto.Key,
+		Name:        dto.Name,
+		Language:    dto.Language,
+		Official:    dto.Official,
+		PublishedAt: dto.PublishedAt,
+	}
+}
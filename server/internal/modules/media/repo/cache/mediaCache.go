@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"errors"
+	"server/internal/init/cache"
+	md "server/internal/modules/media"
+	"time"
+)
+
+type MediaCache struct {
+	ch     *cache.Cache
+	images *cache.Typed[[]*md.FilmImageDTO]
+	videos *cache.Typed[[]*md.FilmVideoDTO]
+}
+
+func NewMediaCache(ch *cache.Cache) *MediaCache {
+	return &MediaCache{
+		ch:     ch,
+		images: cache.NewTyped[[]*md.FilmImageDTO](ch),
+		videos: cache.NewTyped[[]*md.FilmVideoDTO](ch),
+	}
+}
+
+func (c *MediaCache) SetFilmImagesToCache(key string, images []*md.FilmImageDTO, ttl time.Duration) error {
+	return c.images.Set(key, images, ttl)
+}
+
+func (c *MediaCache) GetFilmImagesFromCache(key string) ([]*md.FilmImageDTO, error) {
+	result, err := c.images.Get(key)
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, md.ErrMissCache
+	}
+	return result, err
+}
+
+func (c *MediaCache) DeleteFilmImagesFromCache(key string) error {
+	return c.images.Delete(key)
+}
+
+func (c *MediaCache) SetFilmVideosToCache(key string, videos []*md.FilmVideoDTO, ttl time.Duration) error {
+	return c.videos.Set(key, videos, ttl)
+}
+
+func (c *MediaCache) GetFilmVideosFromCache(key string) ([]*md.FilmVideoDTO, error) {
+	result, err := c.videos.Get(key)
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, md.ErrMissCache
+	}
+	return result, err
+}
+
+func (c *MediaCache) DeleteFilmVideosFromCache(key string) error {
+	return c.videos.Delete(key)
+}
@@ -0,0 +1,314 @@
+package database
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"log/slog"
+	md "server/internal/modules/media"
+)
+
+type MediaDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewMediaDatabase(db *gorm.DB, log *slog.Logger) *MediaDatabase {
+	return &MediaDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetFilmImages возвращает загруженные изображения фильма по типам, внутри типа - по порядку
+func (db *MediaDatabase) GetFilmImages(filmID uint) ([]*md.FilmImageDTO, error) {
+	var models []md.FilmImage
+
+	err := db.db.Where("film_id = ? AND url <> ''", filmID).
+		Order("image_type, position, image_id").
+		Find(&models).Error
+	if err != nil {
+		db.log.Error("failed to get film images", "error", err, "filmID", filmID)
+		return nil, md.ErrInternal
+	}
+
+	images := make([]*md.FilmImageDTO, 0, len(models))
+	for i := range models {
+		images = append(images, models[i].ToDTO())
+	}
+
+	return images, nil
+}
+
+// CreateFilmImage добавляет изображение в конец своего типа. Первое изображение типа или изображение,
+// отмеченное IsPrimary, становится основным. Файлы загружаются после, адрес сохраняет UpdateFilmImageFile
+func (db *MediaDatabase) CreateFilmImage(image *md.FilmImageDTO) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		var stats struct {
+			MaxPosition int  `gorm:"column:max_position"`
+			HasPrimary  bool `gorm:"column:has_primary"`
+		}
+		err := tx.Model(&md.FilmImage{}).
+			Select("COALESCE(MAX(position), 0) AS max_position, COALESCE(BOOL_OR(is_primary), false) AS has_primary").
+			Where("film_id = ? AND image_type = ?", image.FilmID, image.Type).
+			Scan(&stats).Error
+		if err != nil {
+			db.log.Error("failed to get film images position", "error", err, "filmID", image.FilmID)
+			return md.ErrInternal
+		}
+
+		if image.IsPrimary && stats.HasPrimary {
+			if err := resetPrimary(tx, image.FilmID, image.Type); err != nil {
+				db.log.Error("failed to reset primary film image", "error", err, "filmID", image.FilmID)
+				return md.ErrInternal
+			}
+		}
+
+		imageModel := &md.FilmImage{
+			FilmID:    image.FilmID,
+			ImageType: image.Type,
+			Position:  stats.MaxPosition + 1,
+			IsPrimary: image.IsPrimary || !stats.HasPrimary,
+		}
+		if err := tx.Create(imageModel).Error; err != nil {
+			return db.mapError(err, "failed to create film image")
+		}
+
+		*image = *imageModel.ToDTO()
+		return nil
+	})
+}
+
+// UpdateFilmImageFile сохраняет адрес загруженных файлов изображения, его размеры, заглушку и цвет
+func (db *MediaDatabase) UpdateFilmImageFile(image *md.FilmImageDTO) error {
+	result := db.db.Model(&md.FilmImage{}).
+		Where("image_id = ? AND film_id = ?", image.ID, image.FilmID).
+		Updates(map[string]interface{}{
+			"url":      image.URL,
+			"width":    image.Width,
+			"height":   image.Height,
+			"blurhash": image.Blurhash,
+			"color":    image.Color,
+		})
+	if result.Error != nil {
+		db.log.Error("failed to update film image file", "error", result.Error, "imageID", image.ID)
+		return md.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return md.ErrImageNotFound
+	}
+
+	return nil
+}
+
+// SetPrimaryFilmImage делает изображение основным среди изображений его типа
+func (db *MediaDatabase) SetPrimaryFilmImage(filmID uint, imageID uint) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		var image md.FilmImage
+		err := tx.Where("image_id = ? AND film_id = ? AND url <> ''", imageID, filmID).First(&image).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return md.ErrImageNotFound
+		} else if err != nil {
+			db.log.Error("failed to get film image", "error", err, "imageID", imageID)
+			return md.ErrInternal
+		}
+
+		if image.IsPrimary {
+			return nil
+		}
+
+		if err := resetPrimary(tx, filmID, image.ImageType); err != nil {
+			db.log.Error("failed to reset primary film image", "error", err, "filmID", filmID)
+			return md.ErrInternal
+		}
+
+		if err := tx.Model(&image).Update("is_primary", true).Error; err != nil {
+			db.log.Error("failed to set primary film image", "error", err, "imageID", imageID)
+			return md.ErrInternal
+		}
+
+		return nil
+	})
+}
+
+// ReorderFilmImages задает порядок изображений типа: позиция изображения - его индекс в imageIDs, начиная с 1.
+// imageIDs должен содержать все изображения типа ровно по одному разу
+func (db *MediaDatabase) ReorderFilmImages(filmID uint, imageType string, imageIDs []uint) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		var currentIDs []uint
+		err := tx.Model(&md.FilmImage{}).
+			Where("film_id = ? AND image_type = ?", filmID, imageType).
+			Pluck("image_id", &currentIDs).Error
+		if err != nil {
+			db.log.Error("failed to get film images", "error", err, "filmID", filmID)
+			return md.ErrInternal
+		}
+
+		if !sameIDs(currentIDs, imageIDs) {
+			return md.ErrInvalidImageOrder
+		}
+
+		for i, imageID := range imageIDs {
+			err := tx.Model(&md.FilmImage{}).
+				Where("image_id = ?", imageID).
+				Update("position", i+1).Error
+			if err != nil {
+				db.log.Error("failed to update film image position", "error", err, "imageID", imageID)
+				return md.ErrInternal
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteFilmImage удаляет изображение и возвращает его. Если оно было основным,
+// основным становится первое по порядку изображение того же типа
+func (db *MediaDatabase) DeleteFilmImage(filmID uint, imageID uint) (*md.FilmImageDTO, error) {
+	var image md.FilmImage
+
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("image_id = ? AND film_id = ?", imageID, filmID).First(&image).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return md.ErrImageNotFound
+		} else if err != nil {
+			db.log.Error("failed to get film image", "error", err, "imageID", imageID)
+			return md.ErrInternal
+		}
+
+		if err := tx.Delete(&image).Error; err != nil {
+			db.log.Error("failed to delete film image", "error", err, "imageID", imageID)
+			return md.ErrInternal
+		}
+
+		if !image.IsPrimary {
+			return nil
+		}
+
+		err = tx.Exec(`
+			UPDATE film_images SET is_primary = true
+			WHERE image_id = (
+				SELECT image_id FROM film_images
+				WHERE film_id = ? AND image_type = ? AND url <> ''
+				ORDER BY position, image_id
+				LIMIT 1
+			)`, filmID, image.ImageType).Error
+		if err != nil {
+			db.log.Error("failed to promote primary film image", "error", err, "filmID", filmID)
+			return md.ErrInternal
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return image.ToDTO(), nil
+}
+
+// GetFilmVideos возвращает видео фильма: сначала трейлеры, внутри типа официальные и свежие первыми
+func (db *MediaDatabase) GetFilmVideos(filmID uint) ([]*md.FilmVideoDTO, error) {
+	var models []md.FilmVideo
+
+	err := db.db.Where("film_id = ?", filmID).
+		Order("array_position(ARRAY['trailer', 'teaser', 'clip']::varchar[], video_type), official DESC, published_at DESC NULLS LAST, video_id").
+		Find(&models).Error
+	if err != nil {
+		db.log.Error("failed to get film videos", "error", err, "filmID", filmID)
+		return nil, md.ErrInternal
+	}
+
+	videos := make([]*md.FilmVideoDTO, 0, len(models))
+	for i := range models {
+		videos = append(videos, models[i].ToDTO())
+	}
+
+	return videos, nil
+}
+
+func (db *MediaDatabase) CreateFilmVideo(video *md.FilmVideoDTO) error {
+	videoModel := md.FilmVideoFromDTO(video)
+	if err := db.db.Create(videoModel).Error; err != nil {
+		return db.mapError(err, "failed to create film video")
+	}
+	*video = *videoModel.ToDTO()
+
+	return nil
+}
+
+func (db *MediaDatabase) UpdateFilmVideo(video *md.FilmVideoDTO) error {
+	result := db.db.Model(&md.FilmVideo{}).
+		Where("video_id = ? AND film_id = ?", video.ID, video.FilmID).
+		Updates(map[string]interface{}{
+			"video_type":   video.Type,
+			"provider":     video.Provider,
+			"video_key":    video.Key,
+			"name":         video.Name,
+			"language":     video.Language,
+			"official":     video.Official,
+			"published_at": video.PublishedAt,
+		})
+	if result.Error != nil {
+		return db.mapError(result.Error, "failed to update film video")
+	}
+	if result.RowsAffected == 0 {
+		return md.ErrVideoNotFound
+	}
+
+	return nil
+}
+
+func (db *MediaDatabase) DeleteFilmVideo(filmID uint, videoID uint) error {
+	result := db.db.Where("video_id = ? AND film_id = ?", videoID, filmID).Delete(&md.FilmVideo{})
+	if result.Error != nil {
+		db.log.Error("failed to delete film video", "error", result.Error, "videoID", videoID)
+		return md.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return md.ErrVideoNotFound
+	}
+
+	return nil
+}
+
+func resetPrimary(tx *gorm.DB, filmID uint, imageType string) error {
+	return tx.Model(&md.FilmImage{}).
+		Where("film_id = ? AND image_type = ? AND is_primary", filmID, imageType).
+		Update("is_primary", false).Error
+}
+
+// sameIDs проверяет, что b - перестановка a без повторов
+func sameIDs(a []uint, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	seen := make(map[uint]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+
+	return true
+}
+
+// mapError переводит нарушения уникальности и внешних ключей в ошибки модуля
+func (db *MediaDatabase) mapError(err error, msg string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "23503" && pgErr.ConstraintName == "fk_film":
+			return md.ErrFilmNotFound
+		case pgErr.Code == "23505" && pgErr.ConstraintName == "uq_film_video":
+			return md.ErrVideoExists
+		}
+	}
+	db.log.Error(msg, "error", err)
+	return md.ErrInternal
+}
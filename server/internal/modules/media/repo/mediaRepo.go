@@ -0,0 +1,119 @@
+package repo
+
+import (
+	md "server/internal/modules/media"
+	"time"
+)
+
+type MediaDB interface {
+	GetFilmImages(filmID uint) ([]*md.FilmImageDTO, error)
+	CreateFilmImage(image *md.FilmImageDTO) error
+	UpdateFilmImageFile(image *md.FilmImageDTO) error
+	SetPrimaryFilmImage(filmID uint, imageID uint) error
+	ReorderFilmImages(filmID uint, imageType string, imageIDs []uint) error
+	DeleteFilmImage(filmID uint, imageID uint) (*md.FilmImageDTO, error)
+	GetFilmVideos(filmID uint) ([]*md.FilmVideoDTO, error)
+	CreateFilmVideo(video *md.FilmVideoDTO) error
+	UpdateFilmVideo(video *md.FilmVideoDTO) error
+	DeleteFilmVideo(filmID uint, videoID uint) error
+}
+
+type MediaCache interface {
+	GetFilmImagesFromCache(key string) ([]*md.FilmImageDTO, error)
+	SetFilmImagesToCache(key string, images []*md.FilmImageDTO, ttl time.Duration) error
+	DeleteFilmImagesFromCache(key string) error
+	GetFilmVideosFromCache(key string) ([]*md.FilmVideoDTO, error)
+	SetFilmVideosToCache(key string, videos []*md.FilmVideoDTO, ttl time.Duration) error
+	DeleteFilmVideosFromCache(key string) error
+}
+
+type MediaS3 interface {
+	UploadFilmImage(filmID uint, imageID uint, variants map[string][]byte) (string, error)
+	DeleteFilmImageFiles(filmID uint, imageID uint) error
+}
+
+type Repo struct {
+	db MediaDB
+	ch MediaCache
+	s3 MediaS3
+}
+
+func NewMediaRepo(db MediaDB, ch MediaCache, s3 MediaS3) *Repo {
+	return &Repo{
+		db: db,
+		ch: ch,
+		s3: s3,
+	}
+}
+
+func (r *Repo) GetFilmImages(filmID uint) ([]*md.FilmImageDTO, error) {
+	return r.db.GetFilmImages(filmID)
+}
+
+func (r *Repo) CreateFilmImage(image *md.FilmImageDTO) error {
+	return r.db.CreateFilmImage(image)
+}
+
+func (r *Repo) UpdateFilmImageFile(image *md.FilmImageDTO) error {
+	return r.db.UpdateFilmImageFile(image)
+}
+
+func (r *Repo) SetPrimaryFilmImage(filmID uint, imageID uint) error {
+	return r.db.SetPrimaryFilmImage(filmID, imageID)
+}
+
+func (r *Repo) ReorderFilmImages(filmID uint, imageType string, imageIDs []uint) error {
+	return r.db.ReorderFilmImages(filmID, imageType, imageIDs)
+}
+
+func (r *Repo) DeleteFilmImage(filmID uint, imageID uint) (*md.FilmImageDTO, error) {
+	return r.db.DeleteFilmImage(filmID, imageID)
+}
+
+func (r *Repo) GetFilmVideos(filmID uint) ([]*md.FilmVideoDTO, error) {
+	return r.db.GetFilmVideos(filmID)
+}
+
+func (r *Repo) CreateFilmVideo(video *md.FilmVideoDTO) error {
+	return r.db.CreateFilmVideo(video)
+}
+
+func (r *Repo) UpdateFilmVideo(video *md.FilmVideoDTO) error {
+	return r.db.UpdateFilmVideo(video)
+}
+
+func (r *Repo) DeleteFilmVideo(filmID uint, videoID uint) error {
+	return r.db.DeleteFilmVideo(filmID, videoID)
+}
+
+func (r *Repo) GetFilmImagesFromCache(key string) ([]*md.FilmImageDTO, error) {
+	return r.ch.GetFilmImagesFromCache(key)
+}
+
+func (r *Repo) SetFilmImagesToCache(key string, images []*md.FilmImageDTO, ttl time.Duration) error {
+	return r.ch.SetFilmImagesToCache(key, images, ttl)
+}
+
+func (r *Repo) DeleteFilmImagesFromCache(key string) error {
+	return r.ch.DeleteFilmImagesFromCache(key)
+}
+
+func (r *Repo) GetFilmVideosFromCache(key string) ([]*md.FilmVideoDTO, error) {
+	return r.ch.GetFilmVideosFromCache(key)
+}
+
+func (r *Repo) SetFilmVideosToCache(key string, videos []*md.FilmVideoDTO, ttl time.Duration) error {
+	return r.ch.SetFilmVideosToCache(key, videos, ttl)
+}
+
+func (r *Repo) DeleteFilmVideosFromCache(key string) error {
+	return r.ch.DeleteFilmVideosFromCache(key)
+}
+
+func (r *Repo) UploadFilmImage(filmID uint, imageID uint, variants map[string][]byte) (string, error) {
+	return r.s3.UploadFilmImage(filmID, imageID, variants)
+}
+
+func (r *Repo) DeleteFilmImageFiles(filmID uint, imageID uint) error {
+	return r.s3.DeleteFilmImageFiles(filmID, imageID)
+}
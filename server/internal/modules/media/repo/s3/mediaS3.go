@@ -0,0 +1,44 @@
+package s3
+
+import (
+	"fmt"
+	"log/slog"
+	"server/internal/init/blob"
+	avatarManager "server/pkg/lib/avatarMenager"
+)
+
+type MediaS3 struct {
+	log    *slog.Logger
+	store  blob.Store
+	bucket string
+}
+
+// NewMediaS3 - изображения галереи лежат в бакете постеров, папка фильма images/{film_id}/
+// удаляется вместе с фильмом (film.Repo.DeleteImages)
+func NewMediaS3(log *slog.Logger, store blob.Store) *MediaS3 {
+	return &MediaS3{log: log, store: store, bucket: "filmposter"}
+}
+
+// UploadFilmImage загружает все размеры изображения в его папку с хэшем содержимого и возвращает адрес папки
+func (s *MediaS3) UploadFilmImage(filmID uint, imageID uint, variants map[string][]byte) (string, error) {
+	folder := imageFolder(filmID, imageID) + avatarManager.ContentHash(variants) + "/"
+
+	objects := make(map[string][]byte, len(variants))
+	for name, data := range variants {
+		objects[folder+avatarManager.VariantKey(name)] = data
+	}
+
+	if err := blob.PutImages(s.store, s.bucket, objects); err != nil {
+		return "", err
+	}
+
+	return s.store.URL(s.bucket, folder), nil
+}
+
+func (s *MediaS3) DeleteFilmImageFiles(filmID uint, imageID uint) error {
+	return s.store.DeletePrefix(s.bucket, imageFolder(filmID, imageID))
+}
+
+func imageFolder(filmID uint, imageID uint) string {
+	return fmt.Sprintf("images/%d/%d/", filmID, imageID)
+}
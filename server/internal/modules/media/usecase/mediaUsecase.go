@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	f "server/internal/modules/film"
+	md "server/internal/modules/media"
+	avatarManager "server/pkg/lib/avatarMenager"
+	"server/pkg/lib/videolink"
+	"strings"
+	"time"
+)
+
+// FilmService - то, что нужно галерее от фильмов: проверка фильма и сброс его кэша,
+// основной фон и основной трейлер входят в FilmDTO
+type FilmService interface {
+	GetFilmByID(id uint) (*f.FilmDTO, error)
+	InvalidateFilmCache(id uint)
+}
+
+type MediaUseCase struct {
+	log   *slog.Logger
+	rp    md.Repo
+	films FilmService
+}
+
+func NewMediaUseCase(log *slog.Logger, rp md.Repo, films FilmService) *MediaUseCase {
+	return &MediaUseCase{
+		log:   log,
+		rp:    rp,
+		films: films,
+	}
+}
+
+// GetFilmImages возвращает галерею фильма, imageType - только изображения одного типа, пустая строка - все
+func (uc *MediaUseCase) GetFilmImages(filmID uint, imageType string) ([]*md.FilmImageDTO, error) {
+	if imageType != "" {
+		if _, ok := md.ImageProfile(imageType); !ok {
+			return nil, md.ErrInvalidImageType
+		}
+	}
+
+	images, err := uc.filmImages(filmID)
+	if err != nil {
+		return nil, err
+	}
+
+	if imageType == "" {
+		return images, nil
+	}
+
+	filtered := make([]*md.FilmImageDTO, 0, len(images))
+	for _, image := range images {
+		if image.Type == imageType {
+			filtered = append(filtered, image)
+		}
+	}
+
+	return filtered, nil
+}
+
+// UploadFilmImage проверяет и обрабатывает изображение по профилю его типа, загружает все размеры
+// и добавляет изображение в конец галереи. Запись создается до загрузки, чтобы получить id для папки
+func (uc *MediaUseCase) UploadFilmImage(image *md.FilmImageDTO, file *multipart.File) error {
+	profile, ok := md.ImageProfile(image.Type)
+	if !ok {
+		return md.ErrInvalidImageType
+	}
+
+	if err := uc.ensureFilm(image.FilmID); err != nil {
+		return err
+	}
+
+	img, err := avatarManager.Process(file, profile)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.rp.CreateFilmImage(image); err != nil {
+		return err
+	}
+
+	url, err := uc.rp.UploadFilmImage(image.FilmID, image.ID, img.Variants)
+	if err != nil {
+		uc.log.Error("failed to upload film image", "error", err, "filmID", image.FilmID, "imageID", image.ID)
+		if _, err := uc.rp.DeleteFilmImage(image.FilmID, image.ID); err != nil {
+			uc.log.Error("failed to delete film image after upload error", "error", err, "imageID", image.ID)
+		}
+		return md.ErrImageUploadFailed
+	}
+
+	image.URL = url
+	image.Width = img.Width
+	image.Height = img.Height
+	image.Blurhash = img.Blurhash
+	image.Color = img.DominantColor
+	if err := uc.rp.UpdateFilmImageFile(image); err != nil {
+		return err
+	}
+
+	uc.invalidate(image.FilmID)
+	return nil
+}
+
+func (uc *MediaUseCase) SetPrimaryFilmImage(filmID uint, imageID uint) error {
+	if err := uc.rp.SetPrimaryFilmImage(filmID, imageID); err != nil {
+		return err
+	}
+
+	uc.invalidate(filmID)
+	return nil
+}
+
+func (uc *MediaUseCase) ReorderFilmImages(filmID uint, imageType string, imageIDs []uint) error {
+	if _, ok := md.ImageProfile(imageType); !ok {
+		return md.ErrInvalidImageType
+	}
+
+	if err := uc.rp.ReorderFilmImages(filmID, imageType, imageIDs); err != nil {
+		return err
+	}
+
+	uc.invalidate(filmID)
+	return nil
+}
+
+func (uc *MediaUseCase) DeleteFilmImage(filmID uint, imageID uint) error {
+	image, err := uc.rp.DeleteFilmImage(filmID, imageID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.rp.DeleteFilmImageFiles(filmID, image.ID); err != nil {
+		uc.log.Error("failed to delete film image from S3", "error", err, "imageID", image.ID)
+	}
+
+	uc.invalidate(filmID)
+	return nil
+}
+
+// GetFilmVideos возвращает видео фильма, videoType - только видео одного типа, пустая строка - все
+func (uc *MediaUseCase) GetFilmVideos(filmID uint, videoType string) ([]*md.FilmVideoDTO, error) {
+	if videoType != "" && !md.ValidVideoType(videoType) {
+		return nil, md.ErrInvalidVideoType
+	}
+
+	cacheKey := filmVideosCacheKey(filmID)
+	videos, err := uc.rp.GetFilmVideosFromCache(cacheKey)
+	if err != nil {
+		if err := uc.ensureFilm(filmID); err != nil {
+			return nil, err
+		}
+
+		videos, err = uc.rp.GetFilmVideos(filmID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := uc.rp.SetFilmVideosToCache(cacheKey, videos, time.Hour); err != nil {
+			uc.log.Error("failed to cache film videos", "error", err)
+		}
+	}
+
+	if videoType == "" {
+		return videos, nil
+	}
+
+	filtered := make([]*md.FilmVideoDTO, 0, len(videos))
+	for _, video := range videos {
+		if video.Type == videoType {
+			filtered = append(filtered, video)
+		}
+	}
+
+	return filtered, nil
+}
+
+// CreateFilmVideo разбирает ссылку на видео в провайдера и id видео и добавляет видео фильму
+func (uc *MediaUseCase) CreateFilmVideo(video *md.FilmVideoDTO, videoURL string) error {
+	if err := normalizeVideo(video, videoURL); err != nil {
+		return err
+	}
+
+	if err := uc.rp.CreateFilmVideo(video); err != nil {
+		return err
+	}
+
+	uc.invalidateVideos(video.FilmID)
+	return nil
+}
+
+func (uc *MediaUseCase) UpdateFilmVideo(video *md.FilmVideoDTO, videoURL string) error {
+	if err := normalizeVideo(video, videoURL); err != nil {
+		return err
+	}
+
+	if err := uc.rp.UpdateFilmVideo(video); err != nil {
+		return err
+	}
+
+	uc.invalidateVideos(video.FilmID)
+	return nil
+}
+
+func (uc *MediaUseCase) DeleteFilmVideo(filmID uint, videoID uint) error {
+	if err := uc.rp.DeleteFilmVideo(filmID, videoID); err != nil {
+		return err
+	}
+
+	uc.invalidateVideos(filmID)
+	return nil
+}
+
+func (uc *MediaUseCase) filmImages(filmID uint) ([]*md.FilmImageDTO, error) {
+	cacheKey := filmImagesCacheKey(filmID)
+	if images, err := uc.rp.GetFilmImagesFromCache(cacheKey); err == nil {
+		return images, nil
+	}
+
+	if err := uc.ensureFilm(filmID); err != nil {
+		return nil, err
+	}
+
+	images, err := uc.rp.GetFilmImages(filmID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.rp.SetFilmImagesToCache(cacheKey, images, time.Hour); err != nil {
+		uc.log.Error("failed to cache film images", "error", err)
+	}
+
+	return images, nil
+}
+
+func (uc *MediaUseCase) ensureFilm(filmID uint) error {
+	if _, err := uc.films.GetFilmByID(filmID); err != nil {
+		if errors.Is(err, f.ErrFilmNotFound) {
+			return md.ErrFilmNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// invalidate сбрасывает галерею и кэш фильма: основной фон входит в карточку фильма
+func (uc *MediaUseCase) invalidate(filmID uint) {
+	if err := uc.rp.DeleteFilmImagesFromCache(filmImagesCacheKey(filmID)); err != nil {
+		uc.log.Error("failed to delete film images from cache", "error", err)
+	}
+	uc.films.InvalidateFilmCache(filmID)
+}
+
+// invalidateVideos сбрасывает видео и кэш фильма: основной трейлер входит в карточку фильма
+func (uc *MediaUseCase) invalidateVideos(filmID uint) {
+	if err := uc.rp.DeleteFilmVideosFromCache(filmVideosCacheKey(filmID)); err != nil {
+		uc.log.Error("failed to delete film videos from cache", "error", err)
+	}
+	uc.films.InvalidateFilmCache(filmID)
+}
+
+// normalizeVideo проверяет тип видео и заполняет провайдера и id видео из ссылки
+func normalizeVideo(video *md.FilmVideoDTO, videoURL string) error {
+	if !md.ValidVideoType(video.Type) {
+		return md.ErrInvalidVideoType
+	}
+
+	link, err := videolink.Parse(videoURL)
+	if err != nil {
+		return err
+	}
+
+	video.Provider = link.Provider
+	video.Key = link.Key
+	video.Name = strings.TrimSpace(video.Name)
+	return nil
+}
+
+func filmVideosCacheKey(filmID uint) string {
+	return fmt.Sprintf("film:%d:videos", filmID)
+}
+
+func filmImagesCacheKey(filmID uint) string {
+	return fmt.Sprintf("film:%d:images", filmID)
+}
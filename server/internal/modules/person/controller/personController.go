@@ -0,0 +1,633 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	per "server/internal/modules/person"
+	u "server/internal/modules/user"
+	"server/pkg/lib/optimistic"
+	"server/pkg/lib/patch"
+	resp "server/pkg/lib/response"
+	"server/pkg/middleware/locale"
+	"strconv"
+	"strings"
+)
+
+type PersonController struct {
+	log      *slog.Logger
+	uc       per.UseCase
+	validate *validator.Validate
+}
+
+func NewPersonController(log *slog.Logger, uc per.UseCase) *PersonController {
+	return &PersonController{
+		log:      log,
+		uc:       uc,
+		validate: newValidator(),
+	}
+}
+
+func newValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterValidation("wikipedia", func(fl validator.FieldLevel) bool {
+		url := fl.Field().String()
+		return strings.Contains(url, "wikipedia.org")
+	})
+	patch.RegisterValidation(validate)
+	return validate
+}
+
+// CreatePerson - Создание новой персоны
+// @Summary Создание новой персоны
+// @Description Создает новую персону с указанными параметрами. Аватар можно загрузить напрямую в S3
+// @Description через /uploads (purpose person_avatar) после создания персоны
+// @Tags         person
+// @Accept json
+// @Produce json
+// @Param json   formData  string true "Данные персоны"
+// @Param avatar formData file false "Аватар персоны"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /persons [post]
+func (c *PersonController) CreatePerson(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("op", "controller create person")
+
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		if err.Error() == "http: request body too large" {
+			log.Error("request body exceeds maximum allowed size")
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			render.JSON(w, r, resp.Error(r, u.ErrInvalidSizeAvatar.Error()))
+			return
+		}
+		log.Error("failed to parse multipart form", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid multipart form"))
+		return
+	}
+
+	var req CreatePersonRequest
+
+	jsonData := r.FormValue("json")
+	if err := json.Unmarshal([]byte(jsonData), &req); err != nil {
+		log.Error("failed to decode JSON part", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid JSON part"))
+		return
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		log.Info("failed to validate request data", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+
+	file, _, err := r.FormFile("avatar")
+	switch {
+	case errors.Is(err, http.ErrMissingFile):
+		log.Info("file is missing")
+		file = nil
+	case err != nil:
+		log.Error("failed to get file from form", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to get file from form"))
+	default:
+		defer func() {
+			if file != nil {
+				if err := file.Close(); err != nil {
+					log.Error("failed to close avatar file", "error", err)
+				}
+			}
+		}()
+	}
+
+	personDTO := &per.PersonDTO{
+		Name:       req.Name,
+		Department: req.Department,
+		WikiUrl:    req.WikiUrl,
+	}
+
+	if err := c.uc.CreatePerson(r.Context(), personDTO, &file); err != nil {
+		switch {
+		case errors.Is(err, per.ErrInvalidTypeAvatar) || errors.Is(err, per.ErrInvalidResolutionAvatar):
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, per.ErrInternal.Error()))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.OK())
+	return
+}
+
+// GetPerson - Получение информации о персоне
+// @Summary Получить персону по Id
+// @Description Возвращает информацию о персоне по ее Id
+// @Tags         person
+// @Param id query string true "Id персоны"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /persons/{id} [get]
+func (c *PersonController) GetPerson(w http.ResponseWriter, r *http.Request) {
+	personIdStr := chi.URLParam(r, "id")
+
+	personIdUint64, err := strconv.ParseUint(personIdStr, 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid person id"))
+		return
+	}
+
+	personId := uint(personIdUint64)
+
+	person, err := c.uc.GetPerson(personId)
+	if err != nil {
+		switch {
+		case errors.Is(err, per.ErrPersonNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(r, per.ErrPersonNotFound.Error()))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, per.ErrInternal.Error()))
+		}
+		return
+	}
+
+	c.uc.LocalizePersons([]*per.PersonDTO{person}, locale.FromRequest(r))
+
+	optimistic.SetETag(w, person.Version)
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Persons(person))
+	return
+}
+
+// GetPersons - Получение списка персон с фильтрацией и пагинацией
+// @Summary Получить список персон с фильтрацией по различным параметрам
+// @Description Возвращает список персон с возможностью фильтрации по имени, департаменту, дате создания, количеству фильмов, сортировке и пагинации
+// @Tags         person
+// @Param name query string false "Имя персоны"
+// @Param department query string false "Основной департамент (acting, directing, writing, production, sound)"
+// @Param create_at query int false "Год добавления персоны"
+// @Param min_year query int false "Минимальный год создания персоны"
+// @Param max_year query int false "Максимальный год создания персоны"
+// @Param min_movies_count query int false "Минимальное количество фильмов, в которых участвовала персона"
+// @Param max_movies_count query int false "Максимальное количество фильмов, в которых участвовала персона"
+// @Param sort_by query string false "Поле для сортировки (например, 'name', 'created_at')"
+// @Param order query string false "Порядок сортировки (asc или desc)"
+// @Param page query int false "Номер страницы для пагинации"
+// @Param page_size query int false "Количество персон на странице"
+// @Success 200 {array} response.Response
+// @Failure 400 {object} response.Response "Неверные данные запроса"
+// @Failure 500 {object} response.Response "Внутренняя ошибка сервера"
+// @Router /persons [get]
+func (c *PersonController) GetPersons(w http.ResponseWriter, r *http.Request) {
+	req := GetPersonsFilterRequest{
+		Page:     1,
+		PageSize: 10,
+	}
+
+	if name := r.URL.Query().Get("name"); name != "" {
+		req.Name = &name
+	}
+	if department := r.URL.Query().Get("department"); department != "" {
+		req.Department = &department
+	}
+	if createdAt := r.URL.Query().Get("create_at"); createdAt != "" {
+		year, err := strconv.Atoi(createdAt)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid create_at format"))
+			return
+		}
+		req.CreatedAt = &year
+	}
+	if minYear := r.URL.Query().Get("min_year"); minYear != "" {
+		year, err := strconv.Atoi(minYear)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid min_year format"))
+			return
+		}
+		req.MinYear = &year
+	}
+	if maxYear := r.URL.Query().Get("max_year"); maxYear != "" {
+		year, err := strconv.Atoi(maxYear)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid max_year format"))
+			return
+		}
+		req.MaxYear = &year
+	}
+	if minMoviesCount := r.URL.Query().Get("min_movies_count"); minMoviesCount != "" {
+		if count, err := strconv.Atoi(minMoviesCount); err == nil {
+			req.MinMoviesCount = &count
+		}
+	}
+	if maxMoviesCount := r.URL.Query().Get("max_movies_count"); maxMoviesCount != "" {
+		if count, err := strconv.Atoi(maxMoviesCount); err == nil {
+			req.MaxMoviesCount = &count
+		}
+	}
+	if sortBy := r.URL.Query().Get("sort_by"); sortBy != "" {
+		req.SortBy = &sortBy
+	}
+	if order := r.URL.Query().Get("order"); order != "" {
+		req.Order = &order
+	}
+	if page := r.URL.Query().Get("page"); page != "" {
+		if pageInt, err := strconv.Atoi(page); err == nil {
+			req.Page = pageInt
+		}
+	}
+	if pageSize := r.URL.Query().Get("page_size"); pageSize != "" {
+		if pageSizeInt, err := strconv.Atoi(pageSize); err == nil {
+			req.PageSize = pageSizeInt
+		}
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+
+	filter := &per.GetPersonsFilter{
+		Name:           req.Name,
+		Department:     req.Department,
+		CreatedAt:      req.CreatedAt,
+		MinYear:        req.MinYear,
+		MaxYear:        req.MaxYear,
+		MinMoviesCount: req.MinMoviesCount,
+		MaxMoviesCount: req.MaxMoviesCount,
+		SortBy:         req.SortBy,
+		Order:          req.Order,
+		Page:           req.Page,
+		PageSize:       req.PageSize,
+	}
+
+	persons, err := c.uc.GetPersons(filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, per.ErrPersonNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(r, per.ErrPersonNotFound.Error()))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, per.ErrInternal.Error()))
+		}
+		return
+	}
+
+	c.uc.LocalizePersons(persons, locale.FromRequest(r))
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Persons(persons))
+	return
+}
+
+// UpdatePerson - Обновление информации о персоне
+// @Summary Обновить информацию о персоне
+// @Description Обновляет данные персоны, включая аватар
+// @Tags         person
+// @Accept json
+// @Produce      json
+// @Param id query string true "Id персоны"
+// @Param        reset_avatar query     bool   false "Reset avatar to default"
+// @Param        json         formData  string true  "JSON with login data" example={"login":"new_login"}
+// @Param        avatar       formData  file   false "Avatar image file (max 1MB)"
+// @Param        If-Match     header    string false "Версия персоны, на которой основано изменение, например \"3\". Без заголовка берется поле version"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 428 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /persons/{id} [put]
+func (c *PersonController) UpdatePerson(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("op", "UpdatePersonHandler")
+
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		if err.Error() == "http: request body too large" {
+			log.Error("request body exceeds maximum allowed size")
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			render.JSON(w, r, resp.Error(r, per.ErrInvalidSizeAvatar.Error()))
+			return
+		}
+		log.Error("failed to parse multipart form", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid multipart form"))
+		return
+	}
+
+	var req UpdatePersonRequest
+
+	jsonData := r.FormValue("json")
+	if err := json.Unmarshal([]byte(jsonData), &req); err != nil {
+		log.Error("failed to decode JSON part", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid JSON part"))
+		return
+	}
+
+	personIdStr := chi.URLParam(r, "id")
+
+	personIdUint64, err := strconv.ParseUint(personIdStr, 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid person id"))
+		return
+	}
+
+	personId := uint(personIdUint64)
+
+	req.ResetAvatar, _ = strconv.ParseBool(r.URL.Query().Get("reset_avatar"))
+
+	if err := c.validate.Struct(req); err != nil {
+		log.Info("failed to validate request data", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+
+	version, err := optimistic.Expected(r, req.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, optimistic.ErrVersionRequired):
+			w.WriteHeader(http.StatusPreconditionRequired)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		render.JSON(w, r, resp.Error(r, err.Error()))
+		return
+	}
+
+	file, _, err := r.FormFile("avatar")
+	if errors.Is(err, http.ErrMissingFile) {
+		file = nil
+	} else if err != nil {
+		log.Error("failed to get file from form", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to get file from form"))
+	} else {
+		defer func() {
+			if file != nil {
+				if err := file.Close(); err != nil {
+					log.Error("failed to close avatar file", "error", err)
+				}
+			}
+		}()
+	}
+
+	personDTO := &per.PersonDTO{
+		PersonId:    personId,
+		Name:        req.Name,
+		Department:  req.Department,
+		WikiUrl:     req.WikiUrl,
+		ResetAvatar: req.ResetAvatar,
+		Version:     version,
+	}
+
+	if err := c.uc.UpdatePerson(r.Context(), personDTO, &file); err != nil {
+		switch {
+		case errors.Is(err, per.ErrPersonNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		case errors.Is(err, optimistic.ErrConflict):
+			// в ответе текущее состояние, чтобы клиент мог показать расхождение и повторить изменение
+			current, getErr := c.uc.GetPerson(personId)
+			if getErr != nil {
+				log.Error("failed to get person after conflict", "error", getErr)
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error(r, per.ErrInternal.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Conflict(r, err.Error(), resp.Persons(current).Data))
+		default:
+			log.Error("failed to update person", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, per.ErrInternal.Error()))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Version(personDTO.Version))
+	return
+}
+
+// PatchPerson - Частичное изменение персоны
+// @Summary Частично изменить персону
+// @Description Меняет только переданные поля (JSON Merge Patch, RFC 7396), null очищает поле. Аватар меняется через PUT или /uploads.
+// @Description Возвращает персону целиком с новой версией
+// @Tags         person
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id path string true "Id персоны"
+// @Param        json body PatchPersonRequest true "Изменяемые поля"
+// @Param        If-Match header string false "Версия персоны, на которой основано изменение, например \"3\". Без заголовка берется поле version"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 415 {object} response.Response
+// @Failure 428 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /persons/{id} [patch]
+func (c *PersonController) PatchPerson(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("op", "PatchPerson")
+
+	personIdUint64, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid person id"))
+		return
+	}
+	personId := uint(personIdUint64)
+
+	var req PatchPersonRequest
+	if err := patch.Decode(r, &req); err != nil {
+		switch {
+		case errors.Is(err, patch.ErrUnsupportedContentType):
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		render.JSON(w, r, resp.Error(r, err.Error()))
+		return
+	}
+
+	if err := validatePatch(c.validate, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+
+	version, err := optimistic.Expected(r, req.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, optimistic.ErrVersionRequired):
+			w.WriteHeader(http.StatusPreconditionRequired)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		render.JSON(w, r, resp.Error(r, err.Error()))
+		return
+	}
+
+	person, err := c.uc.PatchPerson(r.Context(), &per.PersonPatch{
+		PersonId:   personId,
+		Version:    version,
+		Name:       req.Name,
+		Department: req.Department,
+		WikiUrl:    req.WikiUrl,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, per.ErrPersonNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		case errors.Is(err, optimistic.ErrConflict):
+			// в ответе текущее состояние, чтобы клиент мог показать расхождение и повторить изменение
+			current, getErr := c.uc.GetPerson(personId)
+			if getErr != nil {
+				log.Error("failed to get person after conflict", "error", getErr)
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error(r, per.ErrInternal.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Conflict(r, err.Error(), resp.Persons(current).Data))
+		default:
+			log.Error("failed to patch person", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, per.ErrInternal.Error()))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Persons(person))
+	return
+}
+
+// DeletePerson - Удаление персоны
+// @Summary Удалить персону по Id
+// @Description Удаляет персону по ее Id
+// @Tags         person
+// @Param id query string true "Id персоны"
+// @Success 204 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /persons/{id} [delete]
+func (c *PersonController) DeletePerson(w http.ResponseWriter, r *http.Request) {
+	personIdStr := chi.URLParam(r, "id")
+
+	personIdUint64, err := strconv.ParseUint(personIdStr, 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid person id"))
+		return
+	}
+
+	personId := uint(personIdUint64)
+
+	if err := c.uc.DeletePerson(r.Context(), personId); err != nil {
+		switch {
+		case errors.Is(err, per.ErrPersonNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(r, per.ErrPersonNotFound.Error()))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, per.ErrInternal.Error()))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	render.JSON(w, r, resp.OK())
+	return
+}
+
+// GetFilmography - Фильмография персоны
+// @Summary Получить фильмографию персоны
+// @Description Возвращает фильмы, в которых участвовала персона, сгруппированные по ролям (режиссер, сценарист, продюсер, композитор, актер)
+// @Tags         person
+// @Param id path string true "Id персоны"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /persons/{id}/filmography [get]
+func (c *PersonController) GetFilmography(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("op", "GetFilmographyHandler")
+
+	personIdUint64, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid person id"))
+		return
+	}
+
+	filmography, err := c.uc.GetFilmography(uint(personIdUint64))
+	if err != nil {
+		switch {
+		case errors.Is(err, per.ErrPersonNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(r, per.ErrPersonNotFound.Error()))
+		default:
+			log.Error("failed to get filmography", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, per.ErrInternal.Error()))
+		}
+		return
+	}
+
+	c.uc.LocalizeFilmography(filmography, locale.FromRequest(r))
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Filmography(filmography))
+}
+
+// patchValidator - проверки для ParsePatch, которой не нужен контроллер
+var patchValidator = newValidator()
+
+// ParsePatch разбирает тело правки персоны по правилам PATCH /persons/{id}. Нужна модулям,
+// которые хранят правку и применяют ее позже (предложения правок). Версия из тела не берется
+func ParsePatch(personId uint, body []byte) (*per.PersonPatch, error) {
+	var req PatchPersonRequest
+	if err := patch.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	if err := validatePatch(patchValidator, &req); err != nil {
+		return nil, err
+	}
+	return &per.PersonPatch{
+		PersonId:   personId,
+		Name:       req.Name,
+		Department: req.Department,
+		WikiUrl:    req.WikiUrl,
+	}, nil
+}
+
+// validatePatch проверяет значения полей и что имя и департамент не очищаются
+func validatePatch(validate *validator.Validate, req *PatchPersonRequest) error {
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+	return patch.Required(map[string]patch.Clearable{"name": req.Name, "department": req.Department})
+}
@@ -0,0 +1,39 @@
+package controller
+
+import "server/pkg/lib/patch"
+
+type CreatePersonRequest struct {
+	Name       string `json:"name" validate:"required,min=2,max=100"`
+	Department string `json:"department" validate:"omitempty,oneof=acting directing writing production sound"`
+	WikiUrl    string `json:"wiki_url" validate:"required,url,wikipedia"`
+}
+
+type UpdatePersonRequest struct {
+	Name        string `json:"name" validate:"omitempty,min=2,max=100"`
+	Department  string `json:"department" validate:"omitempty,oneof=acting directing writing production sound"`
+	WikiUrl     string `json:"wiki_url" validate:"omitempty,url,wikipedia"`
+	ResetAvatar bool   `json:"reset_avatar"`
+	Version     int    `json:"version" validate:"omitempty,min=1"`
+}
+
+// PatchPersonRequest - тело PATCH /persons/{id} (RFC 7396): поля, которых нет в теле, не меняются, null очищает поле
+type PatchPersonRequest struct {
+	Name       patch.Field[string] `json:"name" validate:"omitempty,min=2,max=100"`
+	Department patch.Field[string] `json:"department" validate:"omitempty,oneof=acting directing writing production sound"`
+	WikiUrl    patch.Field[string] `json:"wiki_url" validate:"omitempty,url,wikipedia"`
+	Version    int                 `json:"version" validate:"omitempty,min=1"`
+}
+
+type GetPersonsFilterRequest struct {
+	Name           *string `json:"name" validate:"omitempty"`
+	Department     *string `json:"department" validate:"omitempty,oneof=acting directing writing production sound"`
+	CreatedAt      *int    `json:"created_at" validate:"omitempty,min=0"`
+	MinYear        *int    `json:"min_year" validate:"omitempty,min=0"`
+	MaxYear        *int    `json:"max_year" validate:"omitempty,min=0"`
+	MinMoviesCount *int    `json:"min_movies_count" validate:"omitempty,min=0"`
+	MaxMoviesCount *int    `json:"max_movies_count" validate:"omitempty,min=0"`
+	SortBy         *string `json:"sort_by" validate:"omitempty,oneof=create_at movies_count name"`
+	Order          *string `json:"order" validate:"omitempty,oneof=asc desc"`
+	Page           int     `json:"page" validate:"gte=1"`
+	PageSize       int     `json:"page_size" validate:"gte=1,lte=100"`
+}
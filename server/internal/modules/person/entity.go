@@ -0,0 +1,149 @@
+package person
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"server/pkg/lib/patch"
+	"time"
+)
+
+// Департаменты, в которых работает персона (основной указывается в карточке)
+const (
+	DepartmentActing     = "acting"
+	DepartmentDirecting  = "directing"
+	DepartmentWriting    = "writing"
+	DepartmentProduction = "production"
+	DepartmentSound      = "sound"
+)
+
+// Роли персоны в титрах фильма
+const (
+	RoleCast     = "cast"
+	RoleDirector = "director"
+	RoleWriter   = "writer"
+	RoleProducer = "producer"
+	RoleComposer = "composer"
+)
+
+// Roles - роли в порядке, в котором они выводятся в фильмографии
+var Roles = []string{RoleDirector, RoleWriter, RoleProducer, RoleComposer, RoleCast}
+
+// DepartmentOf возвращает департамент, к которому относится роль в титрах
+func DepartmentOf(role string) string {
+	switch role {
+	case RoleDirector:
+		return DepartmentDirecting
+	case RoleWriter:
+		return DepartmentWriting
+	case RoleProducer:
+		return DepartmentProduction
+	case RoleComposer:
+		return DepartmentSound
+	default:
+		return DepartmentActing
+	}
+}
+
+type PersonDTO struct {
+	PersonId    uint      `json:"person_id"`
+	Name        string    `json:"name"`
+	Department  string    `json:"department"`
+	AvatarUrl   *string   `json:"avatar_url"` // Папка с размерами аватара (avatarManager.AvatarProfile)
+	WikiUrl     string    `json:"wiki_url"`
+	CreatedAt   time.Time `json:"create_at"`
+	Version     int       `json:"version"` // Счетчик изменений, см. pkg/lib/optimistic
+	ResetAvatar bool      `json:"reset_avatar"`
+
+	AvatarBlurhash string `json:"avatar_blurhash"`
+	AvatarColor    string `json:"avatar_color"`
+}
+
+// PersonPatch - частичное изменение персоны (PATCH, RFC 7396): меняются только переданные поля, null очищает поле
+type PersonPatch struct {
+	PersonId uint
+	Version  int // Версия, которую видел клиент, после изменения - новая версия
+
+	Name       patch.Field[string]
+	Department patch.Field[string]
+	WikiUrl    patch.Field[string]
+}
+
+// FilmographyEntryDTO - участие персоны в фильме в определенной роли
+type FilmographyEntryDTO struct {
+	FilmID       uint      `json:"film_id" gorm:"column:film_id"`
+	Title        string    `json:"title" gorm:"column:title"`
+	PosterURL    string    `json:"poster_url" gorm:"column:poster_url"`
+	ReleaseDate  time.Time `json:"release_date" gorm:"column:release_date"`
+	Role         string    `json:"role" gorm:"column:role"`
+	Character    string    `json:"character" gorm:"column:character_name"`
+	BillingOrder int       `json:"billing_order" gorm:"column:billing_order"`
+
+	PosterBlurhash string `json:"poster_blurhash" gorm:"column:poster_blurhash"`
+	PosterColor    string `json:"poster_color" gorm:"column:poster_color"`
+}
+
+type FilmographyGroupDTO struct {
+	Role  string                 `json:"role"`
+	Films []*FilmographyEntryDTO `json:"films"`
+}
+
+type GetPersonsFilter struct {
+	Name           *string
+	Department     *string
+	CreatedAt      *int
+	MinYear        *int
+	MaxYear        *int
+	MinMoviesCount *int
+	MaxMoviesCount *int
+	SortBy         *string
+	Order          *string
+	Page           int
+	PageSize       int
+}
+
+type Controller interface {
+	CreatePerson(w http.ResponseWriter, r *http.Request)
+	GetPerson(w http.ResponseWriter, r *http.Request)
+	GetPersons(w http.ResponseWriter, r *http.Request)
+	UpdatePerson(w http.ResponseWriter, r *http.Request)
+	PatchPerson(w http.ResponseWriter, r *http.Request)
+	DeletePerson(w http.ResponseWriter, r *http.Request)
+	GetFilmography(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	CreatePerson(ctx context.Context, person *PersonDTO, avatar *multipart.File) error
+	GetPerson(personId uint) (*PersonDTO, error)
+	GetPersons(filter *GetPersonsFilter) ([]*PersonDTO, error)
+	UpdatePerson(ctx context.Context, person *PersonDTO, avatar *multipart.File) error
+	PatchPerson(ctx context.Context, patch *PersonPatch) (*PersonDTO, error)
+	DeletePerson(ctx context.Context, personId uint) error
+	InvalidatePersonCache(personId uint)
+	CleanupPerson(personId uint, name string)
+	GetFilmography(personId uint) ([]*FilmographyGroupDTO, error)
+	LocalizePersons(persons []*PersonDTO, lang string)
+	LocalizeFilmography(filmography []*FilmographyGroupDTO, lang string)
+}
+
+// AvatarUpload загружает аватар в папку персоны и проставляет person адрес, заглушку и цвет.
+// Репозиторий вызывает ее в транзакции, когда у персоны уже есть id, а версия проверена
+type AvatarUpload func(person *PersonDTO) error
+
+type Repo interface {
+	CreatePerson(ctx context.Context, person *PersonDTO, upload AvatarUpload) (uint, error)
+	GetPerson(personId uint) (*PersonDTO, error)
+	GetPersons(filter *GetPersonsFilter) ([]*PersonDTO, error)
+	UpdatePerson(ctx context.Context, person *PersonDTO, upload AvatarUpload) error
+	PatchPerson(ctx context.Context, patch *PersonPatch) error
+	DeletePerson(ctx context.Context, personId uint) error
+	GetFilmography(personId uint) ([]*FilmographyEntryDTO, error)
+	UploadAvatar(variants map[string][]byte, personId uint) (*string, error)
+	DeleteAvatar(name string, personId uint) error
+	DefaultAvatarURL() string
+	LoadPerson(id uint, ttl time.Duration, load func() (*PersonDTO, error)) (*PersonDTO, error)
+	LoadPersons(key string, ttl time.Duration, load func() ([]*PersonDTO, error)) ([]*PersonDTO, error)
+	LoadFilmography(id uint, ttl time.Duration, load func() ([]*FilmographyGroupDTO, error)) ([]*FilmographyGroupDTO, error)
+	InvalidatePerson(id uint) error
+	InvalidatePersonLists() error
+}
@@ -1,12 +1,11 @@
-package actor
+package person
 
 import "errors"
 
 var (
 	ErrInternal                = errors.New("internal server error")
-	ErrActorNotFound           = errors.New("actor not found")
-	ErrMissCache               = errors.New("miss cache error")
+	ErrPersonNotFound          = errors.New("person not found")
 	ErrInvalidSizeAvatar       = errors.New("invalid sizeAvatar error")
 	ErrInvalidTypeAvatar       = errors.New("invalid type avatar, supported avatar formats are jpg, jpeg, png, webp, or no animated gif")
-	ErrInvalidResolutionAvatar = errors.New("invalid resolution avatar, supported avatar resolution 1x1")
+	ErrInvalidResolutionAvatar = errors.New("invalid resolution avatar, minimal avatar resolution 128x128")
 )
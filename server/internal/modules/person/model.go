@@ -0,0 +1,61 @@
+package person
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type Person struct {
+	PersonID   uint    `gorm:"primaryKey;column:person_id"`
+	Name       string  `gorm:"size:200;not null;column:name"`
+	Department string  `gorm:"size:32;not null;default:'acting';column:department"`
+	AvatarURL  *string `gorm:"default:'https://actoravatar.storage-173.s3hoster.by/default/';column:avatar_url"`
+	// Заглушка и цвет меняются только вместе с аватаром, поэтому тоже указатели
+	AvatarBlurhash *string        `gorm:"default:'';not null;column:avatar_blurhash"`
+	AvatarColor    *string        `gorm:"default:'';not null;column:avatar_color"`
+	WikiURL        string         `gorm:"default:'';not null;column:wiki_url"`
+	CreatedAt      time.Time      `gorm:"column:create_at"`
+	Version        int            `gorm:"column:version;->"` // меняется только через optimistic.Bump
+	DeletedAt      gorm.DeletedAt `gorm:"column:deleted_at"`
+}
+
+func (Person) TableName() string {
+	return "persons"
+}
+
+func ToDTO(person *Person) *PersonDTO {
+	dto := &PersonDTO{
+		PersonId:   person.PersonID,
+		Name:       person.Name,
+		Department: person.Department,
+		AvatarUrl:  person.AvatarURL,
+		WikiUrl:    person.WikiURL,
+		CreatedAt:  person.CreatedAt,
+		Version:    person.Version,
+	}
+	if person.AvatarBlurhash != nil {
+		dto.AvatarBlurhash = *person.AvatarBlurhash
+	}
+	if person.AvatarColor != nil {
+		dto.AvatarColor = *person.AvatarColor
+	}
+
+	return dto
+}
+
+func FromDTO(dto *PersonDTO) *Person {
+	person := &Person{
+		PersonID:   dto.PersonId,
+		Name:       dto.Name,
+		Department: dto.Department,
+		AvatarURL:  dto.AvatarUrl,
+		WikiURL:    dto.WikiUrl,
+		CreatedAt:  dto.CreatedAt,
+	}
+	if dto.AvatarUrl != nil {
+		person.AvatarBlurhash = &dto.AvatarBlurhash
+		person.AvatarColor = &dto.AvatarColor
+	}
+
+	return person
+}
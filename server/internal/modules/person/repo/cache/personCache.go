@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"server/internal/init/cache"
+	per "server/internal/modules/person"
+	"time"
+)
+
+// PersonCahce - списки помечены тегами всех персон в них, фильмография - тегами персоны и ее фильмов,
+// поэтому ее сбрасывает и изменение фильма
+type PersonCahce struct {
+	ch          *cache.Cache
+	persons     *cache.Typed[*per.PersonDTO]
+	lists       *cache.Typed[[]*per.PersonDTO]
+	filmography *cache.Typed[[]*per.FilmographyGroupDTO]
+}
+
+func NewPersonCahce(ch *cache.Cache) *PersonCahce {
+	return &PersonCahce{
+		ch:          ch,
+		persons:     cache.NewTyped[*per.PersonDTO](ch),
+		lists:       cache.NewTyped[[]*per.PersonDTO](ch),
+		filmography: cache.NewTyped[[]*per.FilmographyGroupDTO](ch),
+	}
+}
+
+func (c *PersonCahce) LoadPerson(id uint, ttl time.Duration, load func() (*per.PersonDTO, error)) (*per.PersonDTO, error) {
+	return c.persons.GetOrLoad(cache.Key("person", id), ttl, func() (*per.PersonDTO, []string, error) {
+		person, err := load()
+		return person, []string{personTag(id)}, err
+	})
+}
+
+// LoadPersons - key строится по фильтрам списка
+func (c *PersonCahce) LoadPersons(key string, ttl time.Duration, load func() ([]*per.PersonDTO, error)) ([]*per.PersonDTO, error) {
+	return c.lists.GetOrLoad(cache.Key("persons", key), ttl, func() ([]*per.PersonDTO, []string, error) {
+		persons, err := load()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tags := make([]string, 0, len(persons)+1)
+		tags = append(tags, listsTag())
+		for _, person := range persons {
+			tags = append(tags, personTag(person.PersonId))
+		}
+		return persons, tags, nil
+	})
+}
+
+func (c *PersonCahce) LoadFilmography(id uint, ttl time.Duration, load func() ([]*per.FilmographyGroupDTO, error)) ([]*per.FilmographyGroupDTO, error) {
+	return c.filmography.GetOrLoad(cache.Key("person", id, "filmography"), ttl, func() ([]*per.FilmographyGroupDTO, []string, error) {
+		filmography, err := load()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tags := []string{personTag(id)}
+		for _, group := range filmography {
+			for _, film := range group.Films {
+				tags = append(tags, cache.Tag("film", film.FilmID))
+			}
+		}
+		return filmography, tags, nil
+	})
+}
+
+// InvalidatePerson сбрасывает персону, ее фильмографию и все списки, в которые она входит
+func (c *PersonCahce) InvalidatePerson(id uint) error {
+	return c.ch.InvalidateTags(personTag(id))
+}
+
+// InvalidatePersonLists сбрасывает списки персон, например после создания персоны
+func (c *PersonCahce) InvalidatePersonLists() error {
+	return c.ch.InvalidateTags(listsTag())
+}
+
+func personTag(id uint) string {
+	return cache.Tag("person", id)
+}
+
+func listsTag() string {
+	return cache.Tag("persons")
+}
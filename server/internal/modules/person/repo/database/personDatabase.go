@@ -0,0 +1,296 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"log/slog"
+	per "server/internal/modules/person"
+	"server/pkg/lib/audit"
+	"server/pkg/lib/optimistic"
+	"server/pkg/lib/patch"
+	"server/pkg/lib/revision"
+)
+
+type PersonDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewPersonDatabase(db *gorm.DB, log *slog.Logger) *PersonDatabase {
+	return &PersonDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+// CreatePerson создает персону. upload, если передан, загружает аватар в папку уже созданной персоны,
+// и адрес аватара пишется в той же транзакции, без второго изменения с новой версией
+func (db *PersonDatabase) CreatePerson(ctx context.Context, personDTO *per.PersonDTO, upload per.AvatarUpload) (uint, error) {
+	personModel := per.FromDTO(personDTO)
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(personModel).Error; err != nil {
+			return err
+		}
+
+		if upload != nil {
+			personDTO.PersonId = personModel.PersonID
+			if err := upload(personDTO); err != nil {
+				return err
+			}
+			if err := updateAvatarColumns(tx, personDTO); err != nil {
+				return err
+			}
+		}
+
+		if err := audit.Person.Record(ctx, tx, audit.ActionCreate, personModel.PersonID, nil); err != nil {
+			return err
+		}
+
+		// новая запись создается с первой версией
+		personDTO.Version = 1
+		return db.saveRevision(ctx, tx, personModel.PersonID, personDTO.Version)
+	})
+	if err != nil {
+		if errors.Is(err, per.ErrInternal) {
+			return 0, err
+		}
+		db.log.Error("failed to create person", "error", err)
+		return 0, per.ErrInternal
+	}
+	return personModel.PersonID, nil
+}
+
+// updateAvatarColumns пишет адрес папки аватара, заглушку и цвет, не меняя версию персоны
+func updateAvatarColumns(tx *gorm.DB, personDTO *per.PersonDTO) error {
+	return tx.Model(&per.Person{}).Where("person_id = ?", personDTO.PersonId).Updates(map[string]interface{}{
+		"avatar_url":      personDTO.AvatarUrl,
+		"avatar_blurhash": personDTO.AvatarBlurhash,
+		"avatar_color":    personDTO.AvatarColor,
+	}).Error
+}
+
+func (db *PersonDatabase) GetPerson(personId uint) (*per.PersonDTO, error) {
+	var personModel per.Person
+	if err := db.db.First(&personModel, personId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, per.ErrPersonNotFound
+		}
+		return nil, per.ErrInternal
+	}
+	return per.ToDTO(&personModel), nil
+}
+
+func (db *PersonDatabase) GetPersons(filters *per.GetPersonsFilter) ([]*per.PersonDTO, error) {
+	query := db.db.Table("persons").
+		Select("persons.*, COUNT(DISTINCT film_credits.film_id) as movies_count").
+		Joins("LEFT JOIN film_credits ON persons.person_id = film_credits.person_id").
+		Where("persons.deleted_at IS NULL").
+		Group("persons.person_id")
+
+	if filters.Name != nil {
+		query = query.Where("LOWER(persons.name) LIKE LOWER(?)", "%"+*filters.Name+"%")
+	}
+	if filters.Department != nil {
+		query = query.Where("persons.department = ?", *filters.Department)
+	}
+	if filters.CreatedAt != nil {
+		query = query.Where("EXTRACT(YEAR FROM persons.create_at) = ?", filters.CreatedAt)
+	}
+	if filters.MinYear != nil {
+		query = query.Where("EXTRACT(YEAR FROM persons.create_at) >= ?", *filters.MinYear)
+	}
+	if filters.MaxYear != nil {
+		query = query.Where("EXTRACT(YEAR FROM persons.create_at) <= ?", *filters.MaxYear)
+	}
+	if filters.MinMoviesCount != nil {
+		query = query.Having("COUNT(DISTINCT film_credits.film_id) >= ?", *filters.MinMoviesCount)
+	}
+	if filters.MaxMoviesCount != nil {
+		query = query.Having("COUNT(DISTINCT film_credits.film_id) <= ?", *filters.MaxMoviesCount)
+	}
+	if filters.SortBy != nil {
+		order := "asc"
+		if filters.Order != nil && *filters.Order == "desc" {
+			order = "desc"
+		}
+		switch *filters.SortBy {
+		case "name", "create_at", "movies_count":
+			query = query.Order(*filters.SortBy + " " + order)
+		default:
+			query = query.Order("persons.person_id " + order)
+		}
+	}
+
+	offset := (filters.Page - 1) * filters.PageSize
+	query = query.Offset(offset).Limit(filters.PageSize)
+
+	var persons []*per.Person
+	if err := query.Find(&persons).Error; err != nil {
+		return nil, err
+	}
+	if len(persons) == 0 {
+		return nil, per.ErrPersonNotFound
+	}
+
+	var DtoPersons []*per.PersonDTO
+	for _, person := range persons {
+		DtoPersons = append(DtoPersons, per.ToDTO(person))
+	}
+
+	return DtoPersons, nil
+}
+
+// UpdatePerson - upload, если передан, загружает новый аватар после проверки версии, пока строка персоны
+// заблокирована, поэтому параллельное изменение не оставит загруженный аватар без персоны
+func (db *PersonDatabase) UpdatePerson(ctx context.Context, personDTO *per.PersonDTO, upload per.AvatarUpload) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.Person.Snapshot(tx, personDTO.PersonId)
+		if err != nil {
+			db.log.Error("failed to read person for audit log", "error", err, "personId", personDTO.PersonId)
+			return per.ErrInternal
+		}
+		if before == nil {
+			return per.ErrPersonNotFound
+		}
+
+		version, err := optimistic.Bump(tx, "persons", "person_id", personDTO.PersonId, personDTO.Version)
+		if err != nil {
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				return per.ErrPersonNotFound
+			case errors.Is(err, optimistic.ErrConflict):
+				return err
+			}
+			db.log.Error("failed to bump person version", "error", err, "personId", personDTO.PersonId)
+			return per.ErrInternal
+		}
+
+		if upload != nil {
+			if err := upload(personDTO); err != nil {
+				return err
+			}
+		}
+
+		personModel := per.FromDTO(personDTO)
+		if err := tx.Model(&per.Person{}).Where("person_id = ?", personDTO.PersonId).Updates(personModel).Error; err != nil {
+			return per.ErrInternal
+		}
+
+		if err := audit.Person.Record(ctx, tx, audit.ActionUpdate, personDTO.PersonId, before); err != nil {
+			db.log.Error("failed to write audit log", "error", err, "personId", personDTO.PersonId)
+			return per.ErrInternal
+		}
+
+		if err := db.saveRevision(ctx, tx, personDTO.PersonId, version); err != nil {
+			return err
+		}
+
+		personDTO.Version = version
+		return nil
+	})
+}
+
+// PatchPerson меняет только переданные колонки
+func (db *PersonDatabase) PatchPerson(ctx context.Context, p *per.PersonPatch) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.Person.Snapshot(tx, p.PersonId)
+		if err != nil {
+			db.log.Error("failed to read person for audit log", "error", err, "personId", p.PersonId)
+			return per.ErrInternal
+		}
+		if before == nil {
+			return per.ErrPersonNotFound
+		}
+
+		version, err := optimistic.Bump(tx, "persons", "person_id", p.PersonId, p.Version)
+		if err != nil {
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				return per.ErrPersonNotFound
+			case errors.Is(err, optimistic.ErrConflict):
+				return err
+			}
+			db.log.Error("failed to bump person version", "error", err, "personId", p.PersonId)
+			return per.ErrInternal
+		}
+
+		columns := make(map[string]interface{})
+		patch.Put(columns, "name", p.Name)
+		patch.Put(columns, "department", p.Department)
+		patch.Put(columns, "wiki_url", p.WikiUrl)
+		if len(columns) > 0 {
+			if err := tx.Model(&per.Person{}).Where("person_id = ?", p.PersonId).Updates(columns).Error; err != nil {
+				db.log.Error("failed to patch person", "error", err, "personId", p.PersonId)
+				return per.ErrInternal
+			}
+		}
+
+		if err := audit.Person.Record(ctx, tx, audit.ActionUpdate, p.PersonId, before); err != nil {
+			db.log.Error("failed to write audit log", "error", err, "personId", p.PersonId)
+			return per.ErrInternal
+		}
+
+		if err := db.saveRevision(ctx, tx, p.PersonId, version); err != nil {
+			return err
+		}
+
+		p.Version = version
+		return nil
+	})
+}
+
+func (db *PersonDatabase) DeletePerson(ctx context.Context, personId uint) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.Person.Snapshot(tx, personId)
+		if err != nil {
+			return per.ErrInternal
+		}
+		if before == nil {
+			return per.ErrPersonNotFound
+		}
+
+		if err := tx.Delete(&per.Person{}, personId).Error; err != nil {
+			return per.ErrInternal
+		}
+
+		if err := audit.Person.Record(ctx, tx, audit.ActionDelete, personId, before); err != nil {
+			db.log.Error("failed to write audit log", "error", err, "personId", personId)
+			return per.ErrInternal
+		}
+		return nil
+	})
+}
+
+// GetFilmography возвращает все участия персоны в фильмах, от новых к старым
+func (db *PersonDatabase) GetFilmography(personId uint) ([]*per.FilmographyEntryDTO, error) {
+	var entries []*per.FilmographyEntryDTO
+
+	err := db.db.Raw(`
+		SELECT f.film_id, f.title, f.poster_url, f.poster_blurhash, f.poster_color, f.release_date,
+		       fc.role, fc.character_name, fc.billing_order
+		FROM film_credits fc
+		JOIN films f ON f.film_id = fc.film_id AND f.deleted_at IS NULL
+		WHERE fc.person_id = ?
+		ORDER BY f.release_date DESC NULLS LAST, fc.billing_order`, personId).Scan(&entries).Error
+	if err != nil {
+		db.log.Error("failed to get filmography", "error", err, "personId", personId)
+		return nil, per.ErrInternal
+	}
+
+	return entries, nil
+}
+
+// saveRevision сохраняет в транзакции изменения снимок персоны на версии version
+func (db *PersonDatabase) saveRevision(ctx context.Context, tx *gorm.DB, personId uint, version int) error {
+	var personModel per.Person
+	if err := tx.First(&personModel, personId).Error; err != nil {
+		db.log.Error("failed to read person for revision", "error", err, "personId", personId)
+		return per.ErrInternal
+	}
+	if err := revision.Save(ctx, tx, revision.EntityPerson, personId, version, per.ToDTO(&personModel)); err != nil {
+		db.log.Error("failed to save person revision", "error", err, "personId", personId)
+		return per.ErrInternal
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"server/internal/modules/person"
+	"time"
+)
+
+type PersonDb interface {
+	CreatePerson(ctx context.Context, person *person.PersonDTO, upload person.AvatarUpload) (uint, error)
+	GetPerson(personId uint) (*person.PersonDTO, error)
+	GetPersons(filter *person.GetPersonsFilter) ([]*person.PersonDTO, error)
+	UpdatePerson(ctx context.Context, person *person.PersonDTO, upload person.AvatarUpload) error
+	PatchPerson(ctx context.Context, patch *person.PersonPatch) error
+	DeletePerson(ctx context.Context, personId uint) error
+	GetFilmography(personId uint) ([]*person.FilmographyEntryDTO, error)
+}
+
+type PersonS3 interface {
+	UploadAvatar(variants map[string][]byte, personId uint) (*string, error)
+	DeleteAvatar(name string, personId uint) error
+	DefaultAvatarURL() string
+}
+
+type PersonCache interface {
+	LoadPerson(id uint, ttl time.Duration, load func() (*person.PersonDTO, error)) (*person.PersonDTO, error)
+	LoadPersons(key string, ttl time.Duration, load func() ([]*person.PersonDTO, error)) ([]*person.PersonDTO, error)
+	LoadFilmography(id uint, ttl time.Duration, load func() ([]*person.FilmographyGroupDTO, error)) ([]*person.FilmographyGroupDTO, error)
+	InvalidatePerson(id uint) error
+	InvalidatePersonLists() error
+}
+
+type Repo struct {
+	db PersonDb
+	s3 PersonS3
+	ch PersonCache
+}
+
+func NewPersonRepo(db PersonDb, s3 PersonS3, ch PersonCache) *Repo {
+	return &Repo{
+		db: db,
+		s3: s3,
+		ch: ch,
+	}
+}
+
+func (r *Repo) CreatePerson(ctx context.Context, person *person.PersonDTO, upload person.AvatarUpload) (uint, error) {
+	return r.db.CreatePerson(ctx, person, upload)
+}
+
+func (r *Repo) GetPerson(personId uint) (*person.PersonDTO, error) {
+	return r.db.GetPerson(personId)
+}
+
+func (r *Repo) GetPersons(filter *person.GetPersonsFilter) ([]*person.PersonDTO, error) {
+	return r.db.GetPersons(filter)
+}
+
+func (r *Repo) UpdatePerson(ctx context.Context, person *person.PersonDTO, upload person.AvatarUpload) error {
+	return r.db.UpdatePerson(ctx, person, upload)
+}
+
+func (r *Repo) PatchPerson(ctx context.Context, patch *person.PersonPatch) error {
+	return r.db.PatchPerson(ctx, patch)
+}
+
+func (r *Repo) DeletePerson(ctx context.Context, personId uint) error {
+	return r.db.DeletePerson(ctx, personId)
+}
+
+func (r *Repo) GetFilmography(personId uint) ([]*person.FilmographyEntryDTO, error) {
+	return r.db.GetFilmography(personId)
+}
+
+func (r *Repo) UploadAvatar(variants map[string][]byte, personId uint) (*string, error) {
+	return r.s3.UploadAvatar(variants, personId)
+}
+
+func (r *Repo) DeleteAvatar(name string, personId uint) error {
+	return r.s3.DeleteAvatar(name, personId)
+}
+
+func (r *Repo) DefaultAvatarURL() string {
+	return r.s3.DefaultAvatarURL()
+}
+
+func (r *Repo) LoadPerson(id uint, ttl time.Duration, load func() (*person.PersonDTO, error)) (*person.PersonDTO, error) {
+	return r.ch.LoadPerson(id, ttl, load)
+}
+
+func (r *Repo) LoadPersons(key string, ttl time.Duration, load func() ([]*person.PersonDTO, error)) ([]*person.PersonDTO, error) {
+	return r.ch.LoadPersons(key, ttl, load)
+}
+
+func (r *Repo) LoadFilmography(id uint, ttl time.Duration, load func() ([]*person.FilmographyGroupDTO, error)) ([]*person.FilmographyGroupDTO, error) {
+	return r.ch.LoadFilmography(id, ttl, load)
+}
+
+func (r *Repo) InvalidatePerson(id uint) error {
+	return r.ch.InvalidatePerson(id)
+}
+
+func (r *Repo) InvalidatePersonLists() error {
+	return r.ch.InvalidatePersonLists()
+}
@@ -0,0 +1,58 @@
+package s3
+
+import (
+	"fmt"
+	"log/slog"
+	"server/internal/init/blob"
+	avatarManager "server/pkg/lib/avatarMenager"
+	"strings"
+)
+
+type PersonS3 struct {
+	log    *slog.Logger
+	store  blob.Store
+	bucket string
+}
+
+func NewPersonS3(log *slog.Logger, store blob.Store) *PersonS3 {
+	return &PersonS3{
+		log:    log,
+		store:  store,
+		bucket: "actoravatar",
+	}
+}
+
+// UploadAvatar загружает все размеры аватара в папку персоны с хэшем содержимого и возвращает адрес папки.
+// Папка не зависит от имени, поэтому при переименовании персоны аватар переносить не нужно
+func (s *PersonS3) UploadAvatar(variants map[string][]byte, personId uint) (*string, error) {
+	folder := fmt.Sprintf("avatars/%d/%s/", personId, avatarManager.ContentHash(variants))
+
+	objects := make(map[string][]byte, len(variants))
+	for name, data := range variants {
+		objects[folder+avatarManager.VariantKey(name)] = data
+	}
+
+	if err := blob.PutImages(s.store, s.bucket, objects); err != nil {
+		return nil, err
+	}
+
+	avatarUrl := s.store.URL(s.bucket, folder)
+	return &avatarUrl, nil
+}
+
+// DeleteAvatar удаляет все папки с размерами аватара и аватар старого формата, лежавший одним файлом по имени персоны
+func (s *PersonS3) DeleteAvatar(name string, personId uint) error {
+	if err := s.store.DeletePrefix(s.bucket, fmt.Sprintf("avatars/%d/", personId)); err != nil {
+		return err
+	}
+
+	name = strings.ReplaceAll(name, " ", "")
+	objectKey := fmt.Sprintf("/%s%d", name, personId)
+
+	return s.store.Delete(s.bucket, objectKey)
+}
+
+// DefaultAvatarURL - папка аватаров по умолчанию, загруженных при создании бакета
+func (s *PersonS3) DefaultAvatarURL() string {
+	return s.store.URL(s.bucket, "default")
+}
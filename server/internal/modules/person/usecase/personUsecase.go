@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	per "server/internal/modules/person"
+	tr "server/internal/modules/translation"
+	avatarManager "server/pkg/lib/avatarMenager"
+	"server/pkg/middleware/locale"
+	"strings"
+	"time"
+)
+
+// Translator - переводы имен персон и названий фильмов (модуль translation)
+type Translator interface {
+	ResolveTranslations(entityType string, entityIDs []uint, locales []string) (map[uint]map[string]string, error)
+}
+
+type PersonUseCase struct {
+	log *slog.Logger
+	rp  per.Repo
+	tr  Translator
+}
+
+func NewPersonUseCase(log *slog.Logger, rp per.Repo, translator Translator) *PersonUseCase {
+	return &PersonUseCase{
+		log: log,
+		rp:  rp,
+		tr:  translator,
+	}
+}
+
+func (uc *PersonUseCase) CreatePerson(ctx context.Context, person *per.PersonDTO, avatar *multipart.File) error {
+	// аватар проверяется до создания персоны, а загружается в транзакции создания
+	var upload per.AvatarUpload
+	if *avatar != nil {
+		img, err := uc.processAvatar(avatar)
+		if err != nil {
+			return err
+		}
+		upload = func(person *per.PersonDTO) error {
+			return uc.uploadAvatar(person, img)
+		}
+	}
+
+	if _, err := uc.rp.CreatePerson(ctx, person, upload); err != nil {
+		return err
+	}
+
+	_ = uc.rp.InvalidatePersonLists()
+	return nil
+}
+
+// uploadAvatar загружает все размеры аватара и проставляет персоне адрес папки, заглушку и цвет
+func (uc *PersonUseCase) uploadAvatar(person *per.PersonDTO, img *avatarManager.Image) error {
+	avatarUrl, err := uc.rp.UploadAvatar(img.Variants, person.PersonId)
+	if err != nil {
+		uc.log.Error("failed to upload avatar", "error", err, "personId", person.PersonId)
+		return per.ErrInternal
+	}
+
+	person.AvatarUrl = avatarUrl
+	person.AvatarBlurhash = img.Blurhash
+	person.AvatarColor = img.DominantColor
+	return nil
+}
+
+// processAvatar строит размеры аватара и переводит ошибки обработки в ошибки модуля
+func (uc *PersonUseCase) processAvatar(avatar *multipart.File) (*avatarManager.Image, error) {
+	img, err := avatarManager.Process(avatar, avatarManager.AvatarProfile)
+	if err != nil {
+		uc.log.Error("failed to parse avatar image", "error", err)
+		switch {
+		case errors.Is(err, avatarManager.ErrInvalidTypeAvatar):
+			return nil, per.ErrInvalidTypeAvatar
+		case errors.Is(err, avatarManager.ErrInvalidResolutionAvatar):
+			return nil, per.ErrInvalidResolutionAvatar
+		default:
+			return nil, per.ErrInternal
+		}
+	}
+
+	return img, nil
+}
+
+// SetAvatarImage заменяет аватар персоны уже обработанным изображением, например загруженным напрямую в S3,
+// и возвращает персону с новой версией
+func (uc *PersonUseCase) SetAvatarImage(ctx context.Context, personId uint, img *avatarManager.Image) (*per.PersonDTO, error) {
+	err := uc.rp.UpdatePerson(ctx, &per.PersonDTO{PersonId: personId}, func(person *per.PersonDTO) error {
+		return uc.uploadAvatar(person, img)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_ = uc.rp.InvalidatePerson(personId)
+
+	return uc.rp.GetPerson(personId)
+}
+
+func (uc *PersonUseCase) GetPerson(personId uint) (*per.PersonDTO, error) {
+	return uc.rp.LoadPerson(personId, time.Hour, func() (*per.PersonDTO, error) {
+		return uc.rp.GetPerson(personId)
+	})
+}
+
+func (uc *PersonUseCase) GetPersons(filter *per.GetPersonsFilter) ([]*per.PersonDTO, error) {
+	return uc.rp.LoadPersons(generateCacheKey(filter), time.Minute*15, func() ([]*per.PersonDTO, error) {
+		return uc.rp.GetPersons(filter)
+	})
+}
+
+func generateCacheKey(filter *per.GetPersonsFilter) string {
+	var keyParts []string
+
+	if filter.Name != nil {
+		keyParts = append(keyParts, fmt.Sprintf("name=%s", *filter.Name))
+	}
+	if filter.Department != nil {
+		keyParts = append(keyParts, fmt.Sprintf("department=%s", *filter.Department))
+	}
+	if filter.CreatedAt != nil {
+		keyParts = append(keyParts, fmt.Sprintf("created_at=%d", *filter.CreatedAt))
+	}
+	if filter.MinYear != nil {
+		keyParts = append(keyParts, fmt.Sprintf("min_year=%d", *filter.MinYear))
+	}
+	if filter.MaxYear != nil {
+		keyParts = append(keyParts, fmt.Sprintf("max_year=%d", *filter.MaxYear))
+	}
+	if filter.MinMoviesCount != nil {
+		keyParts = append(keyParts, fmt.Sprintf("min_movies_count=%d", *filter.MinMoviesCount))
+	}
+	if filter.MaxMoviesCount != nil {
+		keyParts = append(keyParts, fmt.Sprintf("max_movies_count=%d", *filter.MaxMoviesCount))
+	}
+	if filter.SortBy != nil {
+		keyParts = append(keyParts, fmt.Sprintf("sort_by=%s", *filter.SortBy))
+	}
+	if filter.Order != nil {
+		keyParts = append(keyParts, fmt.Sprintf("order=%s", *filter.Order))
+	}
+	keyParts = append(keyParts, fmt.Sprintf("page=%d", filter.Page))
+	keyParts = append(keyParts, fmt.Sprintf("page_size=%d", filter.PageSize))
+
+	keyString := strings.Join(keyParts, "&")
+
+	hash := sha256.New()
+	hash.Write([]byte(keyString))
+	hashedKey := hash.Sum(nil)
+
+	return hex.EncodeToString(hashedKey)
+}
+
+// UpdatePerson - аватар проверяется до транзакции, а загружается в ней после проверки версии
+func (uc *PersonUseCase) UpdatePerson(ctx context.Context, person *per.PersonDTO, avatar *multipart.File) error {
+	// прежний аватар удалит PurgeRetiredMedia, когда триггер media_objects выведет его при изменении
+	if person.ResetAvatar {
+		defaultAvatar := uc.rp.DefaultAvatarURL()
+		person.AvatarUrl = &defaultAvatar
+	}
+
+	var upload per.AvatarUpload
+	if *avatar != nil {
+		img, err := uc.processAvatar(avatar)
+		if err != nil {
+			return err
+		}
+		upload = func(person *per.PersonDTO) error {
+			return uc.uploadAvatar(person, img)
+		}
+	}
+
+	if err := uc.rp.UpdatePerson(ctx, person, upload); err != nil {
+		return err
+	}
+
+	_ = uc.rp.InvalidatePerson(person.PersonId)
+
+	return nil
+}
+
+// PatchPerson применяет частичное изменение и возвращает персону целиком
+func (uc *PersonUseCase) PatchPerson(ctx context.Context, patch *per.PersonPatch) (*per.PersonDTO, error) {
+	if err := uc.rp.PatchPerson(ctx, patch); err != nil {
+		return nil, err
+	}
+	_ = uc.rp.InvalidatePerson(patch.PersonId)
+
+	return uc.GetPerson(patch.PersonId)
+}
+
+// DeletePerson переносит персону в корзину, аватар удаляется только при очистке корзины
+func (uc *PersonUseCase) DeletePerson(ctx context.Context, personId uint) error {
+	if err := uc.rp.DeletePerson(ctx, personId); err != nil {
+		return err
+	}
+
+	uc.InvalidatePersonCache(personId)
+
+	return nil
+}
+
+// InvalidatePersonCache сбрасывает кэш персоны, например после возврата из корзины
+func (uc *PersonUseCase) InvalidatePersonCache(personId uint) {
+	_ = uc.rp.InvalidatePerson(personId)
+}
+
+// CleanupPerson удаляет аватар окончательно удаленной персоны
+func (uc *PersonUseCase) CleanupPerson(personId uint, name string) {
+	if err := uc.rp.DeleteAvatar(name, personId); err != nil {
+		uc.log.Error("failed to delete person avatar from S3", "error", err, "personId", personId)
+	}
+}
+
+func (uc *PersonUseCase) GetFilmography(personId uint) ([]*per.FilmographyGroupDTO, error) {
+	return uc.rp.LoadFilmography(personId, time.Minute*15, func() ([]*per.FilmographyGroupDTO, error) {
+		return uc.groupFilmography(personId)
+	})
+}
+
+func (uc *PersonUseCase) groupFilmography(personId uint) ([]*per.FilmographyGroupDTO, error) {
+	if _, err := uc.GetPerson(personId); err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.rp.GetFilmography(personId)
+	if err != nil {
+		return nil, err
+	}
+
+	byRole := make(map[string][]*per.FilmographyEntryDTO)
+	for _, entry := range entries {
+		byRole[entry.Role] = append(byRole[entry.Role], entry)
+	}
+
+	filmography := make([]*per.FilmographyGroupDTO, 0, len(byRole))
+	for _, role := range per.Roles {
+		if films, ok := byRole[role]; ok {
+			filmography = append(filmography, &per.FilmographyGroupDTO{
+				Role:  role,
+				Films: films,
+			})
+		}
+	}
+
+	return filmography, nil
+}
+
+// LocalizePersons подставляет переводы имен персон на язык lang с откатом на язык по умолчанию
+func (uc *PersonUseCase) LocalizePersons(persons []*per.PersonDTO, lang string) {
+	if len(persons) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(persons))
+	for _, person := range persons {
+		ids = append(ids, person.PersonId)
+	}
+
+	translations, err := uc.tr.ResolveTranslations(tr.EntityPerson, ids, locale.Fallbacks(lang))
+	if err != nil {
+		uc.log.Error("failed to resolve person translations", "error", err, "lang", lang)
+		return
+	}
+
+	for _, person := range persons {
+		if name := translations[person.PersonId]["name"]; name != "" {
+			person.Name = name
+		}
+	}
+}
+
+// LocalizeFilmography подставляет переводы названий фильмов фильмографии на язык lang
+func (uc *PersonUseCase) LocalizeFilmography(filmography []*per.FilmographyGroupDTO, lang string) {
+	var ids []uint
+	for _, group := range filmography {
+		for _, entry := range group.Films {
+			ids = append(ids, entry.FilmID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	translations, err := uc.tr.ResolveTranslations(tr.EntityFilm, ids, locale.Fallbacks(lang))
+	if err != nil {
+		uc.log.Error("failed to resolve film translations", "error", err, "lang", lang)
+		return
+	}
+
+	for _, group := range filmography {
+		for _, entry := range group.Films {
+			if title := translations[entry.FilmID]["title"]; title != "" {
+				entry.Title = title
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	rec "server/internal/modules/recommendation"
+	resp "server/pkg/lib/response"
+	"strconv"
+)
+
+type Controller struct {
+	log *slog.Logger
+	uc  rec.UseCase
+}
+
+func NewRecommendationController(log *slog.Logger, uc rec.UseCase) *Controller {
+	return &Controller{
+		log: log,
+		uc:  uc,
+	}
+}
+
+// GetRecommendations - Персональные рекомендации
+// @Summary Получить персональные рекомендации
+// @Description Возвращает фильмы, которые пользователь еще не оценивал, ранжированные по оценкам похожих пользователей и предпочтениям по жанрам и актерам. Для пользователей без отзывов возвращаются фильмы с наивысшим рейтингом
+// @Tags recommendation
+// @Produce json
+// @Param limit query int false "Количество фильмов (1-100, по умолчанию 20)"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /recommendations [get]
+// @Security ApiKeyAuth
+func (c *Controller) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetRecommendations")
+
+	userId, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		log.Error("can't get userId from context")
+		w.WriteHeader(http.StatusUnauthorized)
+		render.JSON(w, r, resp.Error(r, "unauthorized"))
+		return
+	}
+
+	limit := 20
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 100 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid limit format, expected positive integer between 1 and 100"))
+			return
+		}
+	}
+
+	recommendations, err := c.uc.GetRecommendations(userId, limit)
+	if err != nil {
+		log.Error("failed to get recommendations", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, rec.ErrInternal.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Recommendations(recommendations))
+}
@@ -0,0 +1,49 @@
+package recommendation
+
+import (
+	"net/http"
+	f "server/internal/modules/film"
+	"time"
+)
+
+type RecommendationDTO struct {
+	Film    *f.FilmDTO `json:"film"`
+	Score   float64    `json:"score"`
+	Reasons []string   `json:"reasons"`
+}
+
+// CollaborativeScore - предсказанное отклонение оценки пользователя от его средней по похожим фильмам
+type CollaborativeScore struct {
+	FilmID     uint    `gorm:"column:film_id"`
+	Predicted  float64 `gorm:"column:predicted"`
+	Neighbours int     `gorm:"column:neighbours"`
+	BecauseOf  uint    `gorm:"column:because_of"`
+}
+
+// AffinityScore - насколько жанры и актеры фильма совпадают с теми, что пользователь оценивает выше среднего
+type AffinityScore struct {
+	FilmID        uint    `gorm:"column:film_id"`
+	GenreAffinity float64 `gorm:"column:genre_affinity"`
+	ActorAffinity float64 `gorm:"column:actor_affinity"`
+}
+
+type Controller interface {
+	GetRecommendations(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	GetRecommendations(userID uint, limit int) ([]*RecommendationDTO, error)
+	RecomputeSimilarities()
+}
+
+type Repo interface {
+	//DB
+	RecomputeSimilarities(minCommonReviewers int, neighboursPerFilm int) (int64, error)
+	CountUserReviews(userID uint) (int64, error)
+	GetCollaborativeScores(userID uint, limit int) ([]*CollaborativeScore, error)
+	GetAffinityScores(userID uint, limit int) ([]*AffinityScore, error)
+	GetTopRatedFilmIDs(userID uint, limit int) ([]uint, error)
+
+	//Cache
+	LoadRecommendations(userID uint, ttl time.Duration, load func() ([]*RecommendationDTO, error)) ([]*RecommendationDTO, error)
+}
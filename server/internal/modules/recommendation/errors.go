@@ -0,0 +1,7 @@
+package recommendation
+
+import "errors"
+
+var (
+	ErrInternal = errors.New("internal server error")
+)
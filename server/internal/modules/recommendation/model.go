@@ -0,0 +1,15 @@
+package recommendation
+
+import "time"
+
+type FilmSimilarity struct {
+	FilmID          uint      `gorm:"primaryKey;column:film_id"`
+	SimilarFilmID   uint      `gorm:"primaryKey;column:similar_film_id"`
+	Score           float64   `gorm:"column:score"`
+	CommonReviewers int       `gorm:"column:common_reviewers"`
+	UpdatedAt       time.Time `gorm:"column:updated_at"`
+}
+
+func (FilmSimilarity) TableName() string {
+	return "film_similarity"
+}
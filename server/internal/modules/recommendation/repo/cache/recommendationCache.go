@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"server/internal/init/cache"
+	rec "server/internal/modules/recommendation"
+	"time"
+)
+
+// RecommendationCache - подборка помечена тегами всех фильмов в ней и тегом отзывов пользователя:
+// изменение или корзина фильма и любой отзыв пользователя сбрасывают ее
+type RecommendationCache struct {
+	recommendations *cache.Typed[[]*rec.RecommendationDTO]
+}
+
+func NewRecommendationCache(ch *cache.Cache) *RecommendationCache {
+	return &RecommendationCache{
+		recommendations: cache.NewTyped[[]*rec.RecommendationDTO](ch),
+	}
+}
+
+func (c *RecommendationCache) LoadRecommendations(userID uint, ttl time.Duration, load func() ([]*rec.RecommendationDTO, error)) ([]*rec.RecommendationDTO, error) {
+	return c.recommendations.GetOrLoad(cache.Key("user", userID, "recommendations"), ttl, func() ([]*rec.RecommendationDTO, []string, error) {
+		recommendations, err := load()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// тег ставит и сбрасывает модуль отзывов, см. ReviewCache.InvalidateReviewer
+		tags := make([]string, 0, len(recommendations)+1)
+		tags = append(tags, cache.Tag("user", userID, "reviews"))
+		for _, recommendation := range recommendations {
+			tags = append(tags, cache.Tag("film", recommendation.Film.ID))
+		}
+		return recommendations, tags, nil
+	})
+}
@@ -0,0 +1,189 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"log/slog"
+	rec "server/internal/modules/recommendation"
+)
+
+type RecommendationDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewRecommendationDatabase(db *gorm.DB, log *slog.Logger) *RecommendationDatabase {
+	return &RecommendationDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+// RecomputeSimilarities пересобирает film_similarity: item-item adjusted cosine по оценкам,
+// центрированным относительно средней оценки каждого пользователя. Отзывы на отдельные сезоны и серии
+// не учитываются, чтобы у пользователя была одна оценка на фильм. Для каждого фильма
+// сохраняются только neighboursPerFilm лучших соседей с положительной похожестью.
+func (db *RecommendationDatabase) RecomputeSimilarities(minCommonReviewers int, neighboursPerFilm int) (int64, error) {
+	var inserted int64
+
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DELETE FROM film_similarity`).Error; err != nil {
+			return err
+		}
+
+		res := tx.Exec(`
+			WITH centered AS (
+				SELECT r.user_id, r.film_id,
+				       r.rating - AVG(r.rating) OVER (PARTITION BY r.user_id) AS delta
+				FROM reviews r
+				WHERE r.season_id IS NULL AND r.episode_id IS NULL
+			),
+			pairs AS (
+				SELECT c1.film_id, c2.film_id AS similar_film_id,
+				       COUNT(*) AS common_reviewers,
+				       SUM(c1.delta * c2.delta) /
+				           NULLIF(SQRT(SUM(c1.delta ^ 2)) * SQRT(SUM(c2.delta ^ 2)), 0) AS score
+				FROM centered c1
+				JOIN centered c2 ON c2.user_id = c1.user_id AND c2.film_id <> c1.film_id
+				GROUP BY c1.film_id, c2.film_id
+				HAVING COUNT(*) >= ?
+			),
+			ranked AS (
+				SELECT film_id, similar_film_id, common_reviewers, score,
+				       ROW_NUMBER() OVER (PARTITION BY film_id ORDER BY score DESC) AS rn
+				FROM pairs
+				WHERE score > 0
+			)
+			INSERT INTO film_similarity (film_id, similar_film_id, score, common_reviewers, updated_at)
+			SELECT film_id, similar_film_id, score, common_reviewers, NOW()
+			FROM ranked
+			WHERE rn <= ?`, minCommonReviewers, neighboursPerFilm)
+		if res.Error != nil {
+			return res.Error
+		}
+		inserted = res.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		db.log.Error("failed to recompute film similarities", "error", err)
+		return 0, rec.ErrInternal
+	}
+
+	return inserted, nil
+}
+
+func (db *RecommendationDatabase) CountUserReviews(userID uint) (int64, error) {
+	var count int64
+	if err := db.db.Table("reviews").Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		db.log.Error("failed to count user reviews", "error", err, "userID", userID)
+		return 0, rec.ErrInternal
+	}
+
+	return count, nil
+}
+
+// GetCollaborativeScores предсказывает отклонение оценки пользователя от его средней
+// для фильмов, которые он еще не оценивал, по соседям из film_similarity.
+// because_of - оцененный пользователем фильм, внесший наибольший вклад.
+func (db *RecommendationDatabase) GetCollaborativeScores(userID uint, limit int) ([]*rec.CollaborativeScore, error) {
+	var scores []*rec.CollaborativeScore
+
+	err := db.db.Raw(`
+		WITH user_reviews AS (
+			SELECT film_id, rating - AVG(rating) OVER () AS delta
+			FROM reviews
+			WHERE user_id = ? AND season_id IS NULL AND episode_id IS NULL
+		)
+		SELECT s.similar_film_id AS film_id,
+		       SUM(s.score * ur.delta) / NULLIF(SUM(ABS(s.score)), 0) AS predicted,
+		       COUNT(*) AS neighbours,
+		       (ARRAY_AGG(ur.film_id ORDER BY s.score * ur.delta DESC))[1] AS because_of
+		FROM user_reviews ur
+		JOIN film_similarity s ON s.film_id = ur.film_id
+		WHERE s.similar_film_id NOT IN (SELECT film_id FROM user_reviews)
+		  AND s.similar_film_id IN (SELECT film_id FROM films WHERE deleted_at IS NULL)
+		GROUP BY s.similar_film_id
+		HAVING SUM(s.score * ur.delta) > 0
+		ORDER BY predicted DESC
+		LIMIT ?`, userID, limit).Scan(&scores).Error
+	if err != nil {
+		db.log.Error("failed to get collaborative scores", "error", err, "userID", userID)
+		return nil, rec.ErrInternal
+	}
+
+	return scores, nil
+}
+
+// GetAffinityScores оценивает неоцененные пользователем фильмы по тому, насколько их жанры и актеры
+// совпадают с жанрами и актерами фильмов, которые пользователь оценил выше своей средней оценки.
+func (db *RecommendationDatabase) GetAffinityScores(userID uint, limit int) ([]*rec.AffinityScore, error) {
+	var scores []*rec.AffinityScore
+
+	err := db.db.Raw(`
+		WITH user_reviews AS (
+			SELECT film_id, rating - AVG(rating) OVER () AS delta
+			FROM reviews
+			WHERE user_id = ? AND season_id IS NULL AND episode_id IS NULL
+		),
+		genre_pref AS (
+			SELECT fg.genre_id, AVG(ur.delta) AS pref
+			FROM user_reviews ur
+			JOIN film_genre fg ON fg.film_id = ur.film_id
+			GROUP BY fg.genre_id
+			HAVING AVG(ur.delta) > 0
+		),
+		actor_pref AS (
+			SELECT fc.person_id, AVG(ur.delta) AS pref
+			FROM user_reviews ur
+			JOIN film_credits fc ON fc.film_id = ur.film_id AND fc.role = 'cast'
+			GROUP BY fc.person_id
+			HAVING AVG(ur.delta) > 0
+		),
+		genre_scores AS (
+			SELECT fg.film_id, SUM(gp.pref) AS genre_affinity
+			FROM film_genre fg
+			JOIN genre_pref gp ON gp.genre_id = fg.genre_id
+			GROUP BY fg.film_id
+		),
+		actor_scores AS (
+			SELECT fc.film_id, SUM(ap.pref) AS actor_affinity
+			FROM film_credits fc
+			JOIN actor_pref ap ON ap.person_id = fc.person_id AND fc.role = 'cast'
+			GROUP BY fc.film_id
+		)
+		SELECT COALESCE(gs.film_id, acs.film_id) AS film_id,
+		       COALESCE(gs.genre_affinity, 0) AS genre_affinity,
+		       COALESCE(acs.actor_affinity, 0) AS actor_affinity
+		FROM genre_scores gs
+		FULL OUTER JOIN actor_scores acs ON acs.film_id = gs.film_id
+		WHERE COALESCE(gs.film_id, acs.film_id) NOT IN (SELECT film_id FROM user_reviews)
+		  AND COALESCE(gs.film_id, acs.film_id) IN (SELECT film_id FROM films WHERE deleted_at IS NULL)
+		ORDER BY COALESCE(gs.genre_affinity, 0) + COALESCE(acs.actor_affinity, 0) DESC
+		LIMIT ?`, userID, limit).Scan(&scores).Error
+	if err != nil {
+		db.log.Error("failed to get affinity scores", "error", err, "userID", userID)
+		return nil, rec.ErrInternal
+	}
+
+	return scores, nil
+}
+
+// GetTopRatedFilmIDs - запасной вариант для пользователей без истории оценок
+func (db *RecommendationDatabase) GetTopRatedFilmIDs(userID uint, limit int) ([]uint, error) {
+	var ids []uint
+
+	err := db.db.Raw(`
+		SELECT fs.film_id
+		FROM film_stats fs
+		WHERE fs.total_count_reviews > 0
+		  AND fs.film_id NOT IN (SELECT film_id FROM reviews WHERE user_id = ?)
+		  AND fs.film_id IN (SELECT film_id FROM films WHERE deleted_at IS NULL)
+		ORDER BY fs.avg_rating DESC, fs.total_count_reviews DESC
+		LIMIT ?`, userID, limit).Scan(&ids).Error
+	if err != nil {
+		db.log.Error("failed to get top rated films", "error", err, "userID", userID)
+		return nil, rec.ErrInternal
+	}
+
+	return ids, nil
+}
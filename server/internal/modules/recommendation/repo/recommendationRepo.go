@@ -0,0 +1,54 @@
+package repo
+
+import (
+	rec "server/internal/modules/recommendation"
+	"time"
+)
+
+type RecommendationDB interface {
+	RecomputeSimilarities(minCommonReviewers int, neighboursPerFilm int) (int64, error)
+	CountUserReviews(userID uint) (int64, error)
+	GetCollaborativeScores(userID uint, limit int) ([]*rec.CollaborativeScore, error)
+	GetAffinityScores(userID uint, limit int) ([]*rec.AffinityScore, error)
+	GetTopRatedFilmIDs(userID uint, limit int) ([]uint, error)
+}
+
+type RecommendationCache interface {
+	LoadRecommendations(userID uint, ttl time.Duration, load func() ([]*rec.RecommendationDTO, error)) ([]*rec.RecommendationDTO, error)
+}
+
+type Repo struct {
+	db RecommendationDB
+	ch RecommendationCache
+}
+
+func NewRecommendationRepo(db RecommendationDB, ch RecommendationCache) *Repo {
+	return &Repo{
+		db: db,
+		ch: ch,
+	}
+}
+
+func (r *Repo) RecomputeSimilarities(minCommonReviewers int, neighboursPerFilm int) (int64, error) {
+	return r.db.RecomputeSimilarities(minCommonReviewers, neighboursPerFilm)
+}
+
+func (r *Repo) CountUserReviews(userID uint) (int64, error) {
+	return r.db.CountUserReviews(userID)
+}
+
+func (r *Repo) GetCollaborativeScores(userID uint, limit int) ([]*rec.CollaborativeScore, error) {
+	return r.db.GetCollaborativeScores(userID, limit)
+}
+
+func (r *Repo) GetAffinityScores(userID uint, limit int) ([]*rec.AffinityScore, error) {
+	return r.db.GetAffinityScores(userID, limit)
+}
+
+func (r *Repo) GetTopRatedFilmIDs(userID uint, limit int) ([]uint, error) {
+	return r.db.GetTopRatedFilmIDs(userID, limit)
+}
+
+func (r *Repo) LoadRecommendations(userID uint, ttl time.Duration, load func() ([]*rec.RecommendationDTO, error)) ([]*rec.RecommendationDTO, error) {
+	return r.ch.LoadRecommendations(userID, ttl, load)
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"server/config"
+	f "server/internal/modules/film"
+	rec "server/internal/modules/recommendation"
+	"sort"
+	"time"
+)
+
+const (
+	recommendationCandidatesLimit = 100
+	recommendationCacheTTL        = 30 * time.Minute
+
+	recommendationWeightCF    = 0.6
+	recommendationWeightGenre = 0.25
+	recommendationWeightActor = 0.15
+
+	// оценки в отзывах по шкале 0-100, отклонения от средней нормируются на половину шкалы
+	ratingDeltaScale = 50.0
+)
+
+// FilmService - источник карточек фильмов для рекомендаций
+type FilmService interface {
+	GetFilmByID(id uint) (*f.FilmDTO, error)
+}
+
+type RecommendationUseCase struct {
+	log   *slog.Logger
+	rp    rec.Repo
+	films FilmService
+	cfg   config.RecommendationConfig
+}
+
+func NewRecommendationUseCase(log *slog.Logger, rp rec.Repo, films FilmService, cfg config.RecommendationConfig) *RecommendationUseCase {
+	return &RecommendationUseCase{
+		log:   log,
+		rp:    rp,
+		films: films,
+		cfg:   cfg,
+	}
+}
+
+// RecomputeSimilarities - фоновая задача для cron, пересчитывает item-item похожесть фильмов
+func (uc *RecommendationUseCase) RecomputeSimilarities() {
+	log := uc.log.With("op", "RecomputeSimilarities")
+
+	start := time.Now()
+	count, err := uc.rp.RecomputeSimilarities(uc.cfg.MinCommonReviewers, uc.cfg.NeighboursPerFilm)
+	if err != nil {
+		log.Error("failed to recompute film similarities", "error", err)
+		return
+	}
+
+	log.Info("film similarities recomputed", "pairs", count, "duration", time.Since(start))
+}
+
+func (uc *RecommendationUseCase) GetRecommendations(userID uint, limit int) ([]*rec.RecommendationDTO, error) {
+	recommendations, err := uc.rp.LoadRecommendations(userID, recommendationCacheTTL, func() ([]*rec.RecommendationDTO, error) {
+		return uc.rankRecommendations(userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return truncateRecommendations(recommendations, limit), nil
+}
+
+func (uc *RecommendationUseCase) rankRecommendations(userID uint) ([]*rec.RecommendationDTO, error) {
+	reviewsCount, err := uc.rp.CountUserReviews(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[uint]*recommendationCandidate)
+	candidate := func(id uint) *recommendationCandidate {
+		c, ok := candidates[id]
+		if !ok {
+			c = &recommendationCandidate{filmID: id}
+			candidates[id] = c
+		}
+		return c
+	}
+
+	if reviewsCount > 0 {
+		cfScores, err := uc.rp.GetCollaborativeScores(userID, recommendationCandidatesLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range cfScores {
+			c := candidate(s.FilmID)
+			c.cf = clamp(s.Predicted / ratingDeltaScale)
+			c.becauseOf = s.BecauseOf
+		}
+
+		affinity, err := uc.rp.GetAffinityScores(userID, recommendationCandidatesLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range affinity {
+			c := candidate(s.FilmID)
+			c.genre = clamp(s.GenreAffinity / ratingDeltaScale)
+			c.actor = clamp(s.ActorAffinity / ratingDeltaScale)
+		}
+	}
+
+	ranked := make([]*recommendationCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		ranked = append(ranked, c)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score() == ranked[j].score() {
+			return ranked[i].filmID < ranked[j].filmID
+		}
+		return ranked[i].score() > ranked[j].score()
+	})
+
+	// холодный старт и добивка: лучшие по рейтингу фильмы, которые пользователь еще не оценил
+	if len(ranked) < recommendationCandidatesLimit {
+		topRated, err := uc.rp.GetTopRatedFilmIDs(userID, recommendationCandidatesLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range topRated {
+			if len(ranked) >= recommendationCandidatesLimit {
+				break
+			}
+			if _, ok := candidates[id]; ok {
+				continue
+			}
+			c := candidate(id)
+			c.topRated = true
+			ranked = append(ranked, c)
+		}
+	}
+
+	titles := make(map[uint]string)
+	recommendations := make([]*rec.RecommendationDTO, 0, len(ranked))
+	for _, c := range ranked {
+		film, err := uc.films.GetFilmByID(c.filmID)
+		if err != nil {
+			if errors.Is(err, f.ErrFilmNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		var becauseOf string
+		if c.becauseOf != 0 {
+			if _, ok := titles[c.becauseOf]; !ok {
+				if source, err := uc.films.GetFilmByID(c.becauseOf); err == nil {
+					titles[c.becauseOf] = source.Title
+				}
+			}
+			becauseOf = titles[c.becauseOf]
+		}
+
+		recommendations = append(recommendations, &rec.RecommendationDTO{
+			Film:    film,
+			Score:   c.score(),
+			Reasons: c.reasons(becauseOf),
+		})
+	}
+
+	return recommendations, nil
+}
+
+type recommendationCandidate struct {
+	filmID    uint
+	cf        float64
+	genre     float64
+	actor     float64
+	becauseOf uint
+	topRated  bool
+}
+
+func (c *recommendationCandidate) score() float64 {
+	return c.cf*recommendationWeightCF +
+		c.genre*recommendationWeightGenre +
+		c.actor*recommendationWeightActor
+}
+
+func (c *recommendationCandidate) reasons(becauseOf string) []string {
+	reasons := make([]string, 0, 3)
+	if c.cf > 0 && becauseOf != "" {
+		reasons = append(reasons, fmt.Sprintf("because you rated %q highly", becauseOf))
+	} else if c.cf > 0 {
+		reasons = append(reasons, "liked by reviewers with similar taste")
+	}
+	if c.genre > 0 {
+		reasons = append(reasons, "matches genres you like")
+	}
+	if c.actor > 0 {
+		reasons = append(reasons, "features actors you like")
+	}
+	if c.topRated {
+		reasons = append(reasons, "top rated by other viewers")
+	}
+	return reasons
+}
+
+func clamp(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+func truncateRecommendations(recommendations []*rec.RecommendationDTO, limit int) []*rec.RecommendationDTO {
+	if limit > 0 && len(recommendations) > limit {
+		return recommendations[:limit]
+	}
+	return recommendations
+}
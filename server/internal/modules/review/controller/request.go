@@ -3,6 +3,8 @@ package controller
 type CreateReviewRequest struct {
 	UserID     uint   `json:"user_id" validate:"required"`
 	FilmID     uint   `json:"film_id" validate:"required"`
+	SeasonID   *uint  `json:"season_id" validate:"omitempty,min=1"`
+	EpisodeID  *uint  `json:"episode_id" validate:"omitempty,min=1"`
 	Rating     int    `json:"rating" validate:"required,min=0,max=100"`
 	ReviewText string `json:"review_text" validate:"required"`
 }
@@ -11,6 +13,9 @@ type UpdateReviewRequest struct {
 	ReviewID   uint   `json:"review_id" validate:"required"`
 	UserID     uint   `json:"user_id" validate:"required"`
 	FilmID     uint   `json:"film_id" validate:"required"`
+	SeasonID   *uint  `json:"season_id" validate:"omitempty,min=1"`
+	EpisodeID  *uint  `json:"episode_id" validate:"omitempty,min=1"`
 	Rating     int    `json:"rating" validate:"required,min=0,max=100"`
 	ReviewText string `json:"review_text" validate:"required"`
+	Version    int    `json:"version" validate:"omitempty,min=1"`
 }
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 	r "server/internal/modules/review"
+	"server/pkg/lib/optimistic"
 	resp "server/pkg/lib/response"
 	"strconv"
 )
@@ -36,6 +37,7 @@ func NewReviewController(log *slog.Logger, uc r.UseCase) *ReviewController {
 // @Param        json body CreateReviewRequest true "Данные отзыва"
 // @Success 201 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /reviews [post]
 func (c *ReviewController) CreateReview(w http.ResponseWriter, req *http.Request) {
@@ -43,35 +45,43 @@ func (c *ReviewController) CreateReview(w http.ResponseWriter, req *http.Request
 
 	var request CreateReviewRequest
 	if err := render.DecodeJSON(req.Body, &request); err != nil {
-		log.Error("failed to decode request body", err)
+		log.Error("failed to decode request body", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, req, resp.Error("failed to decode request"))
+		render.JSON(w, req, resp.Error(req, "failed to decode request"))
 		return
 	}
 
 	if err := c.validate.Struct(request); err != nil {
-		log.Error("failed to validate request", err)
+		log.Error("failed to validate request", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, req, resp.ValidationError(err))
+		render.JSON(w, req, resp.ValidationError(req, err))
 		return
 	}
 
 	review := &r.ReviewDTO{
 		UserID:     request.UserID,
 		FilmID:     request.FilmID,
+		SeasonID:   request.SeasonID,
+		EpisodeID:  request.EpisodeID,
 		Rating:     request.Rating,
 		ReviewText: request.ReviewText,
 	}
 
-	if err := c.uc.CreateReview(review); err != nil {
+	if err := c.uc.CreateReview(req.Context(), review); err != nil {
 		switch {
 		case errors.Is(err, r.ErrReviewExists):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, req, resp.Error(r.ErrReviewExists.Error()))
+			render.JSON(w, req, resp.Error(req, r.ErrReviewExists.Error()))
+		case errors.Is(err, r.ErrInvalidTarget):
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, req, resp.Error(req, r.ErrInvalidTarget.Error()))
+		case errors.Is(err, r.ErrFilmNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, req, resp.Error(req, r.ErrFilmNotFound.Error()))
 		default:
-			log.Error("failed to create review", err)
+			log.Error("failed to create review", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, req, resp.Error(r.ErrInternal.Error()))
+			render.JSON(w, req, resp.Error(req, r.ErrInternal.Error()))
 		}
 		return
 	}
@@ -99,7 +109,7 @@ func (c *ReviewController) GetReview(w http.ResponseWriter, req *http.Request) {
 	reviewID, err := strconv.ParseUint(reviewIDStr, 10, 32)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, req, resp.Error("invalid review FilmId"))
+		render.JSON(w, req, resp.Error(req, "invalid review FilmId"))
 		return
 	}
 
@@ -108,11 +118,11 @@ func (c *ReviewController) GetReview(w http.ResponseWriter, req *http.Request) {
 		switch {
 		case errors.Is(err, r.ErrNoSuchReview):
 			w.WriteHeader(http.StatusNotFound)
-			render.JSON(w, req, resp.Error(r.ErrNoSuchReview.Error()))
+			render.JSON(w, req, resp.Error(req, r.ErrNoSuchReview.Error()))
 		default:
-			log.Error("failed to get review", err)
+			log.Error("failed to get review", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, req, resp.Error(r.ErrInternal.Error()))
+			render.JSON(w, req, resp.Error(req, r.ErrInternal.Error()))
 		}
 		return
 	}
@@ -129,9 +139,12 @@ func (c *ReviewController) GetReview(w http.ResponseWriter, req *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Param        json body UpdateReviewRequest true "Данные отзыва"
+// @Param        If-Match header string false "Версия отзыва, на которой основано изменение, например \"3\". Без заголовка берется поле version"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /reviews [put]
 func (c *ReviewController) UpdateReview(w http.ResponseWriter, req *http.Request) {
@@ -139,16 +152,28 @@ func (c *ReviewController) UpdateReview(w http.ResponseWriter, req *http.Request
 
 	var request UpdateReviewRequest
 	if err := render.DecodeJSON(req.Body, &request); err != nil {
-		log.Error("failed to decode request body", err)
+		log.Error("failed to decode request body", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, req, resp.Error("failed to decode request"))
+		render.JSON(w, req, resp.Error(req, "failed to decode request"))
 		return
 	}
 
 	if err := c.validate.Struct(request); err != nil {
-		log.Error("failed to validate request", err)
+		log.Error("failed to validate request", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, req, resp.ValidationError(err))
+		render.JSON(w, req, resp.ValidationError(req, err))
+		return
+	}
+
+	version, err := optimistic.Expected(req, request.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, optimistic.ErrVersionRequired):
+			w.WriteHeader(http.StatusPreconditionRequired)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		render.JSON(w, req, resp.Error(req, err.Error()))
 		return
 	}
 
@@ -156,25 +181,45 @@ func (c *ReviewController) UpdateReview(w http.ResponseWriter, req *http.Request
 		ReviewID:   request.ReviewID,
 		UserID:     request.UserID,
 		FilmID:     request.FilmID,
+		SeasonID:   request.SeasonID,
+		EpisodeID:  request.EpisodeID,
 		Rating:     request.Rating,
 		ReviewText: request.ReviewText,
+		Version:    version,
 	}
 
-	if err := c.uc.UpdateReview(review); err != nil {
+	if err := c.uc.UpdateReview(req.Context(), review); err != nil {
 		switch {
+		case errors.Is(err, optimistic.ErrConflict):
+			// в ответе текущее состояние, чтобы клиент мог показать расхождение и повторить изменение
+			current, getErr := c.uc.GetReview(review.ReviewID)
+			if getErr != nil {
+				log.Error("failed to get review after conflict", "error", getErr)
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, req, resp.Error(req, r.ErrInternal.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, req, resp.Conflict(req, err.Error(), resp.Reviews(current).Data))
 		case errors.Is(err, r.ErrNoSuchReview):
 			w.WriteHeader(http.StatusNotFound)
-			render.JSON(w, req, resp.Error(r.ErrNoSuchReview.Error()))
+			render.JSON(w, req, resp.Error(req, r.ErrNoSuchReview.Error()))
+		case errors.Is(err, r.ErrInvalidTarget):
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, req, resp.Error(req, r.ErrInvalidTarget.Error()))
+		case errors.Is(err, r.ErrFilmNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, req, resp.Error(req, r.ErrFilmNotFound.Error()))
 		default:
-			log.Error("failed to update review", err)
+			log.Error("failed to update review", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, req, resp.Error(r.ErrInternal.Error()))
+			render.JSON(w, req, resp.Error(req, r.ErrInternal.Error()))
 		}
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	render.JSON(w, req, resp.OK())
+	render.JSON(w, req, resp.Version(review.Version))
 	return
 }
 
@@ -195,19 +240,19 @@ func (c *ReviewController) DeleteReview(w http.ResponseWriter, req *http.Request
 	reviewID, err := strconv.ParseUint(reviewIDStr, 10, 32)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, req, resp.Error("invalid review FilmId"))
+		render.JSON(w, req, resp.Error(req, "invalid review FilmId"))
 		return
 	}
 
-	if err := c.uc.DeleteReview(uint(reviewID)); err != nil {
+	if err := c.uc.DeleteReview(req.Context(), uint(reviewID)); err != nil {
 		switch {
 		case errors.Is(err, r.ErrNoSuchReview):
 			w.WriteHeader(http.StatusNotFound)
-			render.JSON(w, req, resp.Error(r.ErrNoSuchReview.Error()))
+			render.JSON(w, req, resp.Error(req, r.ErrNoSuchReview.Error()))
 		default:
-			log.Error("failed to delete review", err)
+			log.Error("failed to delete review", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, req, resp.Error(r.ErrInternal.Error()))
+			render.JSON(w, req, resp.Error(req, r.ErrInternal.Error()))
 		}
 		return
 	}
@@ -233,15 +278,15 @@ func (c *ReviewController) GetReviewsByFilmID(w http.ResponseWriter, req *http.R
 	filmID, err := strconv.ParseUint(filmIDStr, 10, 32)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, req, resp.Error("invalid film FilmId"))
+		render.JSON(w, req, resp.Error(req, "invalid film FilmId"))
 		return
 	}
 
 	reviews, err := c.uc.GetReviewsByFilmID(uint(filmID))
 	if err != nil {
-		log.Error("failed to get reviews by film FilmId", err)
+		log.Error("failed to get reviews by film FilmId", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		render.JSON(w, req, resp.Error(r.ErrInternal.Error()))
+		render.JSON(w, req, resp.Error(req, r.ErrInternal.Error()))
 		return
 	}
 
@@ -266,15 +311,117 @@ func (c *ReviewController) GetReviewsByReviewerID(w http.ResponseWriter, req *ht
 	reviewerID, err := strconv.ParseUint(reviewerIDStr, 10, 32)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, req, resp.Error("invalid reviewer UserId"))
+		render.JSON(w, req, resp.Error(req, "invalid reviewer UserId"))
 		return
 	}
 
 	reviews, err := c.uc.GetReviewsByReviewerID(uint(reviewerID))
 	if err != nil {
-		log.Error("failed to get reviews by reviewer FilmId", err)
+		log.Error("failed to get reviews by reviewer FilmId", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		render.JSON(w, req, resp.Error(r.ErrInternal.Error()))
+		render.JSON(w, req, resp.Error(req, r.ErrInternal.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, req, resp.Reviews(reviews))
+}
+
+// GetReviewsBySeason - Получение отзывов на сезон сериала
+// @Summary Получение отзывов на сезон сериала
+// @Description Возвращает отзывы на сезон сериала, включая отзывы на его серии
+// @Tags         review
+// @Produce      json
+// @Param        id path string true "FilmId сериала"
+// @Param        season path int true "Номер сезона"
+// @Success 200 {array} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons/{season}/reviews [get]
+func (c *ReviewController) GetReviewsBySeason(w http.ResponseWriter, req *http.Request) {
+	log := c.log.With("op", "GetReviewsBySeason")
+
+	filmID, err := strconv.ParseUint(chi.URLParam(req, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, req, resp.Error(req, "invalid film FilmId"))
+		return
+	}
+
+	seasonNumber, err := strconv.Atoi(chi.URLParam(req, "season"))
+	if err != nil || seasonNumber < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, req, resp.Error(req, "invalid season number"))
+		return
+	}
+
+	reviews, err := c.uc.GetReviewsBySeason(uint(filmID), seasonNumber)
+	if err != nil {
+		switch {
+		case errors.Is(err, r.ErrNoSuchReview):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, req, resp.Error(req, r.ErrNoSuchReview.Error()))
+		default:
+			log.Error("failed to get reviews by season", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, req, resp.Error(req, r.ErrInternal.Error()))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, req, resp.Reviews(reviews))
+}
+
+// GetReviewsByEpisode - Получение отзывов на серию сериала
+// @Summary Получение отзывов на серию сериала
+// @Description Возвращает отзывы на серию сериала
+// @Tags         review
+// @Produce      json
+// @Param        id path string true "FilmId сериала"
+// @Param        season path int true "Номер сезона"
+// @Param        episode path int true "Номер серии"
+// @Success 200 {array} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons/{season}/episodes/{episode}/reviews [get]
+func (c *ReviewController) GetReviewsByEpisode(w http.ResponseWriter, req *http.Request) {
+	log := c.log.With("op", "GetReviewsByEpisode")
+
+	filmID, err := strconv.ParseUint(chi.URLParam(req, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, req, resp.Error(req, "invalid film FilmId"))
+		return
+	}
+
+	seasonNumber, err := strconv.Atoi(chi.URLParam(req, "season"))
+	if err != nil || seasonNumber < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, req, resp.Error(req, "invalid season number"))
+		return
+	}
+
+	episodeNumber, err := strconv.Atoi(chi.URLParam(req, "episode"))
+	if err != nil || episodeNumber < 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, req, resp.Error(req, "invalid episode number"))
+		return
+	}
+
+	reviews, err := c.uc.GetReviewsByEpisode(uint(filmID), seasonNumber, episodeNumber)
+	if err != nil {
+		switch {
+		case errors.Is(err, r.ErrNoSuchReview):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, req, resp.Error(req, r.ErrNoSuchReview.Error()))
+		default:
+			log.Error("failed to get reviews by episode", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, req, resp.Error(req, r.ErrInternal.Error()))
+		}
 		return
 	}
 
@@ -1,6 +1,7 @@
 package review
 
 import (
+	"context"
 	"net/http"
 	"time"
 )
@@ -9,9 +10,12 @@ type ReviewDTO struct {
 	ReviewID   uint      `json:"review_id"`
 	UserID     uint      `json:"user_id"`
 	FilmID     uint      `json:"film_id"`
+	SeasonID   *uint     `json:"season_id"`  // отзыв на сезон сериала
+	EpisodeID  *uint     `json:"episode_id"` // отзыв на серию, season_id заполняется по серии
 	Rating     int       `json:"rating"`
 	ReviewText string    `json:"review_text"`
 	CreateAt   time.Time `json:"create_at"`
+	Version    int       `json:"version"` // Счетчик изменений, см. pkg/lib/optimistic
 }
 
 type Controller interface {
@@ -21,25 +25,34 @@ type Controller interface {
 	DeleteReview(w http.ResponseWriter, r *http.Request)
 	GetReviewsByFilmID(w http.ResponseWriter, r *http.Request)
 	GetReviewsByReviewerID(w http.ResponseWriter, r *http.Request)
+	GetReviewsBySeason(w http.ResponseWriter, r *http.Request)
+	GetReviewsByEpisode(w http.ResponseWriter, r *http.Request)
 }
 
 type UseCase interface {
-	CreateReview(review *ReviewDTO) error
+	CreateReview(ctx context.Context, review *ReviewDTO) error
 	GetReview(reviewID uint) (*ReviewDTO, error)
-	UpdateReview(review *ReviewDTO) error
-	DeleteReview(reviewID uint) error
+	UpdateReview(ctx context.Context, review *ReviewDTO) error
+	DeleteReview(ctx context.Context, reviewID uint) error
 	GetReviewsByFilmID(filmID uint) ([]*ReviewDTO, error)
 	GetReviewsByReviewerID(reviewerID uint) ([]*ReviewDTO, error)
+	GetReviewsBySeason(filmID uint, seasonNumber int) ([]*ReviewDTO, error)
+	GetReviewsByEpisode(filmID uint, seasonNumber int, episodeNumber int) ([]*ReviewDTO, error)
 }
 
 type Repo interface {
-	CreateReview(review *ReviewDTO) error
+	CreateReview(ctx context.Context, review *ReviewDTO) error
 	GetReview(reviewID uint) (*ReviewDTO, error)
-	UpdateReview(review *ReviewDTO) error
-	DeleteReview(reviewID uint) error
+	UpdateReview(ctx context.Context, review *ReviewDTO) error
+	DeleteReview(ctx context.Context, reviewID uint) (*ReviewDTO, error)
 	GetReviewsByFilmID(filmID uint) ([]*ReviewDTO, error)
 	GetReviewsByReviewerID(reviewerID uint) ([]*ReviewDTO, error)
-	SetCache(key string, value interface{}, ttl time.Duration) error
-	GetCache(key string) ([]*ReviewDTO, error)
-	DeleteCache(key string) error
+	GetReviewsBySeason(filmID uint, seasonNumber int) ([]*ReviewDTO, error)
+	GetReviewsByEpisode(filmID uint, seasonNumber int, episodeNumber int) ([]*ReviewDTO, error)
+	LoadReview(id uint, ttl time.Duration, load func() (*ReviewDTO, error)) (*ReviewDTO, error)
+	LoadFilmReviews(filmID uint, ttl time.Duration, load func() ([]*ReviewDTO, error)) ([]*ReviewDTO, error)
+	LoadReviewerReviews(userID uint, ttl time.Duration, load func() ([]*ReviewDTO, error)) ([]*ReviewDTO, error)
+	InvalidateReview(id uint) error
+	InvalidateReviewLists(filmID, userID uint) error
+	InvalidateReviewer(userID uint) error
 }
@@ -3,8 +3,9 @@ package review
 import "errors"
 
 var (
-	ErrMissCache    = errors.New("miss cache error")
-	ErrInternal     = errors.New("internal server error")
-	ErrNoSuchReview = errors.New("no such review")
-	ErrReviewExists = errors.New("review exists")
+	ErrInternal      = errors.New("internal server error")
+	ErrNoSuchReview  = errors.New("no such review")
+	ErrReviewExists  = errors.New("review exists")
+	ErrInvalidTarget = errors.New("season or episode does not belong to the film")
+	ErrFilmNotFound  = errors.New("film not found")
 )
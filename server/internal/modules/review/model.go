@@ -4,11 +4,14 @@ import "time"
 
 type Review struct {
 	ReviewID   uint      `gorm:"primaryKey;column:review_id"`
-	UserID     uint      `gorm:"column:user_id;uniqueIndex:idx_user_film"`
-	FilmID     uint      `gorm:"column:film_id;uniqueIndex:idx_user_film"`
+	UserID     uint      `gorm:"column:user_id"`
+	FilmID     uint      `gorm:"column:film_id"`
+	SeasonID   *uint     `gorm:"column:season_id"`
+	EpisodeID  *uint     `gorm:"column:episode_id"`
 	Rating     int       `gorm:"column:rating"`
 	ReviewText string    `gorm:"column:review_text"`
 	CreatedAt  time.Time `gorm:"column:create_at"`
+	Version    int       `gorm:"column:version;->"` // меняется только через optimistic.Bump
 }
 
 func (r *Review) ToDTO() *ReviewDTO {
@@ -16,9 +19,12 @@ func (r *Review) ToDTO() *ReviewDTO {
 		ReviewID:   r.ReviewID,
 		UserID:     r.UserID,
 		FilmID:     r.FilmID,
+		SeasonID:   r.SeasonID,
+		EpisodeID:  r.EpisodeID,
 		Rating:     r.Rating,
 		ReviewText: r.ReviewText,
 		CreateAt:   r.CreatedAt,
+		Version:    r.Version,
 	}
 }
 
@@ -27,6 +33,8 @@ func (r *ReviewDTO) ToModel() *Review {
 		ReviewID:   r.ReviewID,
 		UserID:     r.UserID,
 		FilmID:     r.FilmID,
+		SeasonID:   r.SeasonID,
+		EpisodeID:  r.EpisodeID,
 		Rating:     r.Rating,
 		ReviewText: r.ReviewText,
 		CreatedAt:  r.CreateAt,
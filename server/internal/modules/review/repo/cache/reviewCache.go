@@ -1,65 +1,87 @@
 package cache
 
 import (
-	"context"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"github.com/go-redis/redis/v8"
 	"log/slog"
 	"server/internal/init/cache"
 	r "server/internal/modules/review"
 	"time"
 )
 
+// ReviewCache - списки отзывов помечены тегами всех отзывов в них, изменение отзыва сбрасывает
+// и сам отзыв, и списки фильма и автора. Записи помечены и тегами фильмов, чтобы корзина фильма сбрасывала их
 type ReviewCache struct {
-	log *slog.Logger
-	ch  *cache.Cache
+	log     *slog.Logger
+	ch      *cache.Cache
+	reviews *cache.Typed[*r.ReviewDTO]
+	lists   *cache.Typed[[]*r.ReviewDTO]
 }
 
 func NewReviewCache(log *slog.Logger, ch *cache.Cache) *ReviewCache {
 	return &ReviewCache{
-		log: log,
-		ch:  ch,
+		log:     log,
+		ch:      ch,
+		reviews: cache.NewTyped[*r.ReviewDTO](ch),
+		lists:   cache.NewTyped[[]*r.ReviewDTO](ch),
 	}
 }
 
-func (c *ReviewCache) SetCache(key string, value interface{}, ttl time.Duration) error {
-	var data []byte
-	var err error
+func (c *ReviewCache) LoadReview(id uint, ttl time.Duration, load func() (*r.ReviewDTO, error)) (*r.ReviewDTO, error) {
+	return c.reviews.GetOrLoad(cache.Key("review", id), ttl, func() (*r.ReviewDTO, []string, error) {
+		review, err := load()
+		if err != nil {
+			return nil, nil, err
+		}
+		return review, []string{reviewTag(id), cache.Tag("film", review.FilmID)}, nil
+	})
+}
 
-	switch v := value.(type) {
-	case *r.ReviewDTO:
-		data, err = json.Marshal([]*r.ReviewDTO{v})
-	case []*r.ReviewDTO:
-		data, err = json.Marshal(v)
-	default:
-		return fmt.Errorf("invalid type for caching")
-	}
+func (c *ReviewCache) LoadFilmReviews(filmID uint, ttl time.Duration, load func() ([]*r.ReviewDTO, error)) ([]*r.ReviewDTO, error) {
+	return c.loadList(cache.Key("film", filmID, "reviews"), filmReviewsTag(filmID), ttl, load)
+}
 
-	if err != nil {
-		return err
-	}
+func (c *ReviewCache) LoadReviewerReviews(userID uint, ttl time.Duration, load func() ([]*r.ReviewDTO, error)) ([]*r.ReviewDTO, error) {
+	return c.loadList(cache.Key("user", userID, "reviews"), reviewerReviewsTag(userID), ttl, load)
+}
+
+func (c *ReviewCache) loadList(key, listTag string, ttl time.Duration, load func() ([]*r.ReviewDTO, error)) ([]*r.ReviewDTO, error) {
+	return c.lists.GetOrLoad(key, ttl, func() ([]*r.ReviewDTO, []string, error) {
+		reviews, err := load()
+		if err != nil {
+			return nil, nil, err
+		}
 
-	return c.ch.Client.Set(context.Background(), key, data, ttl).Err()
+		tags := make([]string, 0, 2*len(reviews)+1)
+		tags = append(tags, listTag)
+		for _, review := range reviews {
+			tags = append(tags, reviewTag(review.ReviewID), cache.Tag("film", review.FilmID))
+		}
+		return reviews, tags, nil
+	})
 }
 
-func (c *ReviewCache) GetCache(key string) ([]*r.ReviewDTO, error) {
-	data, err := c.ch.Client.Get(context.Background(), key).Result()
-	if errors.Is(err, redis.Nil) {
-		return nil, r.ErrMissCache
-	} else if err != nil {
-		return nil, err
-	}
+// InvalidateReview сбрасывает отзыв и все списки, в которые он входит
+func (c *ReviewCache) InvalidateReview(id uint) error {
+	return c.ch.InvalidateTags(reviewTag(id))
+}
 
-	var reviews []*r.ReviewDTO
-	if err := json.Unmarshal([]byte(data), &reviews); err != nil {
-		return nil, err
-	}
+// InvalidateReviewLists сбрасывает списки отзывов фильма и автора, например после создания отзыва
+func (c *ReviewCache) InvalidateReviewLists(filmID, userID uint) error {
+	return c.ch.InvalidateTags(filmReviewsTag(filmID), reviewerReviewsTag(userID))
+}
+
+// InvalidateReviewer сбрасывает списки автора и все, что посчитано по его отзывам, например рекомендации
+func (c *ReviewCache) InvalidateReviewer(userID uint) error {
+	return c.ch.InvalidateTags(reviewerReviewsTag(userID))
+}
+
+func reviewTag(id uint) string {
+	return cache.Tag("review", id)
+}
 
-	return reviews, nil
+func filmReviewsTag(filmID uint) string {
+	return cache.Tag("film", filmID, "reviews")
 }
 
-func (c *ReviewCache) DeleteCache(key string) error {
-	return c.ch.Client.Del(context.Background(), key).Err()
+func reviewerReviewsTag(userID uint) string {
+	return cache.Tag("user", userID, "reviews")
 }
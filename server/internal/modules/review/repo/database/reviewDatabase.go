@@ -1,14 +1,21 @@
 package database
 
 import (
+	"context"
 	"errors"
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"log/slog"
 	r "server/internal/modules/review"
+	"server/pkg/lib/audit"
+	"server/pkg/lib/optimistic"
+	"time"
 )
 
+// activeFilm оставляет только отзывы фильмов, которых нет в корзине
+const activeFilm = "JOIN films ON films.film_id = reviews.film_id AND films.deleted_at IS NULL"
+
 type ReviewDatabase struct {
 	db  *gorm.DB
 	log *slog.Logger
@@ -21,9 +28,20 @@ func NewReviewDatabase(db *gorm.DB, log *slog.Logger) *ReviewDatabase {
 	}
 }
 
-func (db *ReviewDatabase) CreateReview(review *r.ReviewDTO) error {
-	reviewModel := review.ToModel()
-	if err := db.db.Create(reviewModel).Error; err != nil {
+func (db *ReviewDatabase) CreateReview(ctx context.Context, review *r.ReviewDTO) error {
+	var reviewModel *r.Review
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		if err := db.resolveTarget(tx, review); err != nil {
+			return err
+		}
+		reviewModel = review.ToModel()
+
+		if err := tx.Create(reviewModel).Error; err != nil {
+			return err
+		}
+		return audit.Review.Record(ctx, tx, audit.ActionCreate, reviewModel.ReviewID, nil)
+	})
+	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			if pgErr.Code == "23505" {
@@ -36,20 +54,111 @@ func (db *ReviewDatabase) CreateReview(review *r.ReviewDTO) error {
 	return nil
 }
 
-func (db *ReviewDatabase) UpdateReview(review *r.ReviewDTO) error {
-	reviewModel := review.ToModel()
+func (db *ReviewDatabase) UpdateReview(ctx context.Context, review *r.ReviewDTO) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		if err := db.resolveTarget(tx, review); err != nil {
+			return err
+		}
+
+		before, err := audit.Review.Snapshot(tx, review.ReviewID)
+		if err != nil {
+			db.log.Error("failed to read review for audit log", "error", err, "reviewID", review.ReviewID)
+			return r.ErrInternal
+		}
+
+		version, err := optimistic.Bump(tx, "reviews", "review_id", review.ReviewID, review.Version)
+		if err != nil {
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				return r.ErrNoSuchReview
+			case errors.Is(err, optimistic.ErrConflict):
+				return err
+			}
+			db.log.Error("failed to bump review version", "error", err, "reviewID", review.ReviewID)
+			return r.ErrInternal
+		}
 
-	result := db.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "user_id"}, {Name: "film_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"rating", "review_text", "create_at"}),
-	}).Create(reviewModel)
+		// меняется только строка, версия которой проверена, и только если отзыв написан этим пользователем на ту же цель
+		result := tx.Model(&r.Review{}).
+			Where("review_id = ? AND user_id = ? AND film_id = ?", review.ReviewID, review.UserID, review.FilmID).
+			Where("season_id IS NOT DISTINCT FROM ? AND episode_id IS NOT DISTINCT FROM ?", review.SeasonID, review.EpisodeID).
+			Updates(map[string]interface{}{
+				"rating":      review.Rating,
+				"review_text": review.ReviewText,
+				"create_at":   time.Now(),
+			})
+		if result.Error != nil {
+			db.log.Error("failed to update review", "error", result.Error, "reviewID", review.ReviewID)
+			return r.ErrInternal
+		}
+		if result.RowsAffected == 0 {
+			return r.ErrNoSuchReview
+		}
+
+		if err := audit.Review.Record(ctx, tx, audit.ActionUpdate, review.ReviewID, before); err != nil {
+			db.log.Error("failed to write audit log", "error", err, "reviewID", review.ReviewID)
+			return r.ErrInternal
+		}
+
+		review.Version = version
+		return nil
+	})
+}
+
+// resolveTarget проверяет, что фильм не в корзине, а сезон и серия отзыва относятся к нему,
+// и для отзыва на серию заполняет season_id по серии. Строка фильма блокируется до конца транзакции,
+// чтобы его не убрали в корзину, пока пишется отзыв
+func (db *ReviewDatabase) resolveTarget(tx *gorm.DB, review *r.ReviewDTO) error {
+	var films []uint
+	err := tx.Raw("SELECT film_id FROM films WHERE film_id = ? AND deleted_at IS NULL FOR SHARE", review.FilmID).
+		Scan(&films).Error
+	if err != nil {
+		db.log.Error("failed to get film", "error", err)
+		return r.ErrInternal
+	}
+	if len(films) == 0 {
+		return r.ErrFilmNotFound
+	}
+
+	switch {
+	case review.EpisodeID != nil:
+		var target struct {
+			SeasonID uint
+			FilmID   uint
+		}
+		err := tx.Raw(`
+			SELECT e.season_id, s.film_id
+			FROM episodes e
+			JOIN seasons s ON s.season_id = e.season_id
+			WHERE e.episode_id = ?`, *review.EpisodeID).Scan(&target).Error
+		if err != nil {
+			db.log.Error("failed to get episode", "error", err)
+			return r.ErrInternal
+		}
+		if target.FilmID != review.FilmID || (review.SeasonID != nil && *review.SeasonID != target.SeasonID) {
+			return r.ErrInvalidTarget
+		}
+		review.SeasonID = &target.SeasonID
+	case review.SeasonID != nil:
+		var count int64
+		err := tx.Table("seasons").
+			Where("season_id = ? AND film_id = ?", *review.SeasonID, review.FilmID).
+			Count(&count).Error
+		if err != nil {
+			db.log.Error("failed to get season", "error", err)
+			return r.ErrInternal
+		}
+		if count == 0 {
+			return r.ErrInvalidTarget
+		}
+	}
 
-	return result.Error
+	return nil
 }
 
 func (db *ReviewDatabase) GetReview(reviewID uint) (*r.ReviewDTO, error) {
 	var review r.Review
-	if err := db.db.First(&review, reviewID).Error; err != nil {
+	if err := db.db.Joins(activeFilm).First(&review, reviewID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, r.ErrNoSuchReview
 		}
@@ -60,7 +169,7 @@ func (db *ReviewDatabase) GetReview(reviewID uint) (*r.ReviewDTO, error) {
 
 func (db *ReviewDatabase) GetReviewsByFilmID(filmID uint) ([]*r.ReviewDTO, error) {
 	var reviews []*r.Review
-	if err := db.db.Where("film_id = ?", filmID).Find(&reviews).Error; err != nil {
+	if err := db.db.Joins(activeFilm).Where("reviews.film_id = ?", filmID).Find(&reviews).Error; err != nil {
 		return nil, err
 	}
 	if len(reviews) == 0 {
@@ -71,7 +180,7 @@ func (db *ReviewDatabase) GetReviewsByFilmID(filmID uint) ([]*r.ReviewDTO, error
 
 func (db *ReviewDatabase) GetReviewsByReviewerID(reviewerID uint) ([]*r.ReviewDTO, error) {
 	var reviews []*r.Review
-	if err := db.db.Where("user_id = ?", reviewerID).Find(&reviews).Error; err != nil {
+	if err := db.db.Joins(activeFilm).Where("reviews.user_id = ?", reviewerID).Find(&reviews).Error; err != nil {
 		return nil, err
 	}
 	if len(reviews) == 0 {
@@ -80,15 +189,62 @@ func (db *ReviewDatabase) GetReviewsByReviewerID(reviewerID uint) ([]*r.ReviewDT
 	return r.ToDTOList(reviews), nil
 }
 
-func (db *ReviewDatabase) DeleteReview(reviewID uint) error {
-	result := db.db.Delete(&r.Review{}, reviewID)
+// GetReviewsBySeason возвращает отзывы на сезон и на его серии
+func (db *ReviewDatabase) GetReviewsBySeason(filmID uint, seasonNumber int) ([]*r.ReviewDTO, error) {
+	var reviews []*r.Review
+	err := db.db.Joins(activeFilm).
+		Joins("JOIN seasons ON seasons.season_id = reviews.season_id").
+		Where("seasons.film_id = ? AND seasons.season_number = ?", filmID, seasonNumber).
+		Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return nil, r.ErrNoSuchReview
+	}
+	return r.ToDTOList(reviews), nil
+}
 
-	if result.Error != nil {
-		return r.ErrInternal
+func (db *ReviewDatabase) GetReviewsByEpisode(filmID uint, seasonNumber int, episodeNumber int) ([]*r.ReviewDTO, error) {
+	var reviews []*r.Review
+	err := db.db.Joins(activeFilm).
+		Joins("JOIN episodes ON episodes.episode_id = reviews.episode_id").
+		Joins("JOIN seasons ON seasons.season_id = episodes.season_id").
+		Where("seasons.film_id = ? AND seasons.season_number = ? AND episodes.episode_number = ?", filmID, seasonNumber, episodeNumber).
+		Find(&reviews).Error
+	if err != nil {
+		return nil, err
 	}
-	if result.RowsAffected == 0 {
-		return r.ErrNoSuchReview
+	if len(reviews) == 0 {
+		return nil, r.ErrNoSuchReview
 	}
+	return r.ToDTOList(reviews), nil
+}
 
-	return nil
+// DeleteReview возвращает удаленный отзыв, чтобы сбросить кэш его автора
+func (db *ReviewDatabase) DeleteReview(ctx context.Context, reviewID uint) (*r.ReviewDTO, error) {
+	var review r.Review
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.Review.Snapshot(tx, reviewID)
+		if err != nil {
+			return r.ErrInternal
+		}
+		if before == nil {
+			return r.ErrNoSuchReview
+		}
+
+		if err := tx.Clauses(clause.Returning{}).Delete(&review, reviewID).Error; err != nil {
+			return r.ErrInternal
+		}
+
+		if err := audit.Review.Record(ctx, tx, audit.ActionDelete, reviewID, before); err != nil {
+			db.log.Error("failed to write audit log", "error", err, "reviewID", reviewID)
+			return r.ErrInternal
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review.ToDTO(), nil
 }
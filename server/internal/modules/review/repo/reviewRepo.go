@@ -1,23 +1,29 @@
 package repo
 
 import (
+	"context"
 	r "server/internal/modules/review"
 	"time"
 )
 
 type ReviewDB interface {
-	CreateReview(review *r.ReviewDTO) error
-	UpdateReview(review *r.ReviewDTO) error
+	CreateReview(ctx context.Context, review *r.ReviewDTO) error
+	UpdateReview(ctx context.Context, review *r.ReviewDTO) error
 	GetReview(reviewID uint) (*r.ReviewDTO, error)
 	GetReviewsByFilmID(filmID uint) ([]*r.ReviewDTO, error)
 	GetReviewsByReviewerID(reviewerID uint) ([]*r.ReviewDTO, error)
-	DeleteReview(reviewID uint) error
+	GetReviewsBySeason(filmID uint, seasonNumber int) ([]*r.ReviewDTO, error)
+	GetReviewsByEpisode(filmID uint, seasonNumber int, episodeNumber int) ([]*r.ReviewDTO, error)
+	DeleteReview(ctx context.Context, reviewID uint) (*r.ReviewDTO, error)
 }
 
 type ReviewCache interface {
-	SetCache(key string, value interface{}, ttl time.Duration) error
-	GetCache(key string) ([]*r.ReviewDTO, error)
-	DeleteCache(key string) error
+	LoadReview(id uint, ttl time.Duration, load func() (*r.ReviewDTO, error)) (*r.ReviewDTO, error)
+	LoadFilmReviews(filmID uint, ttl time.Duration, load func() ([]*r.ReviewDTO, error)) ([]*r.ReviewDTO, error)
+	LoadReviewerReviews(userID uint, ttl time.Duration, load func() ([]*r.ReviewDTO, error)) ([]*r.ReviewDTO, error)
+	InvalidateReview(id uint) error
+	InvalidateReviewLists(filmID, userID uint) error
+	InvalidateReviewer(userID uint) error
 }
 
 type Repo struct {
@@ -29,12 +35,12 @@ func NewReviewRepo(db ReviewDB, ch ReviewCache) *Repo {
 	return &Repo{db: db, ch: ch}
 }
 
-func (r *Repo) CreateReview(review *r.ReviewDTO) error {
-	return r.db.CreateReview(review)
+func (r *Repo) CreateReview(ctx context.Context, review *r.ReviewDTO) error {
+	return r.db.CreateReview(ctx, review)
 }
 
-func (r *Repo) UpdateReview(review *r.ReviewDTO) error {
-	return r.db.UpdateReview(review)
+func (r *Repo) UpdateReview(ctx context.Context, review *r.ReviewDTO) error {
+	return r.db.UpdateReview(ctx, review)
 }
 
 func (r *Repo) GetReview(reviewID uint) (*r.ReviewDTO, error) {
@@ -49,18 +55,38 @@ func (r *Repo) GetReviewsByReviewerID(reviewerID uint) ([]*r.ReviewDTO, error) {
 	return r.db.GetReviewsByReviewerID(reviewerID)
 }
 
-func (r *Repo) DeleteReview(reviewID uint) error {
-	return r.db.DeleteReview(reviewID)
+func (r *Repo) GetReviewsBySeason(filmID uint, seasonNumber int) ([]*r.ReviewDTO, error) {
+	return r.db.GetReviewsBySeason(filmID, seasonNumber)
 }
 
-func (r *Repo) SetCache(key string, value interface{}, ttl time.Duration) error {
-	return r.ch.SetCache(key, value, ttl)
+func (r *Repo) GetReviewsByEpisode(filmID uint, seasonNumber int, episodeNumber int) ([]*r.ReviewDTO, error) {
+	return r.db.GetReviewsByEpisode(filmID, seasonNumber, episodeNumber)
 }
 
-func (r *Repo) GetCache(key string) ([]*r.ReviewDTO, error) {
-	return r.ch.GetCache(key)
+func (r *Repo) DeleteReview(ctx context.Context, reviewID uint) (*r.ReviewDTO, error) {
+	return r.db.DeleteReview(ctx, reviewID)
 }
 
-func (r *Repo) DeleteCache(key string) error {
-	return r.ch.DeleteCache(key)
+func (r *Repo) LoadReview(id uint, ttl time.Duration, load func() (*r.ReviewDTO, error)) (*r.ReviewDTO, error) {
+	return r.ch.LoadReview(id, ttl, load)
+}
+
+func (r *Repo) LoadFilmReviews(filmID uint, ttl time.Duration, load func() ([]*r.ReviewDTO, error)) ([]*r.ReviewDTO, error) {
+	return r.ch.LoadFilmReviews(filmID, ttl, load)
+}
+
+func (r *Repo) LoadReviewerReviews(userID uint, ttl time.Duration, load func() ([]*r.ReviewDTO, error)) ([]*r.ReviewDTO, error) {
+	return r.ch.LoadReviewerReviews(userID, ttl, load)
+}
+
+func (r *Repo) InvalidateReview(id uint) error {
+	return r.ch.InvalidateReview(id)
+}
+
+func (r *Repo) InvalidateReviewLists(filmID, userID uint) error {
+	return r.ch.InvalidateReviewLists(filmID, userID)
+}
+
+func (r *Repo) InvalidateReviewer(userID uint) error {
+	return r.ch.InvalidateReviewer(userID)
 }
@@ -1,9 +1,9 @@
 package usecase
 
 import (
+	"context"
 	"log/slog"
 	r "server/internal/modules/review"
-	"strconv"
 	"time"
 )
 
@@ -20,91 +20,68 @@ func NewReviewUseCase(rp r.Repo, log *slog.Logger) *ReviewUseCase {
 }
 
 // CreateReview создает новый отзыв
-func (uc *ReviewUseCase) CreateReview(review *r.ReviewDTO) error {
-	cacheKey := "reviews_film_" + strconv.Itoa(int(review.FilmID))
-	_ = uc.rp.DeleteCache(cacheKey) // Инвалидация кэша
+func (uc *ReviewUseCase) CreateReview(ctx context.Context, review *r.ReviewDTO) error {
+	if err := uc.rp.CreateReview(ctx, review); err != nil {
+		return err
+	}
 
-	return uc.rp.CreateReview(review)
+	// списки фильма и автора, а вместе с ними и рекомендации автора
+	_ = uc.rp.InvalidateReviewLists(review.FilmID, review.UserID)
+	return nil
 }
 
 // GetReview возвращает отзыв по FilmId
 func (uc *ReviewUseCase) GetReview(reviewID uint) (*r.ReviewDTO, error) {
-	cacheKey := "review_" + strconv.Itoa(int(reviewID))
-
-	// Попытка получить данные из кэша
-	reviews, err := uc.rp.GetCache(cacheKey)
-	if err == nil && len(reviews) > 0 {
-		return reviews[0], nil
-	}
-
-	// Если данных в кэше нет, запрашиваем из базы
-	review, err := uc.rp.GetReview(reviewID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Кэшируем результат
-	_ = uc.rp.SetCache(cacheKey, []*r.ReviewDTO{review}, time.Hour*24)
-
-	return review, nil
+	return uc.rp.LoadReview(reviewID, time.Hour*24, func() (*r.ReviewDTO, error) {
+		return uc.rp.GetReview(reviewID)
+	})
 }
 
 // UpdateReview обновляет отзыв
-func (uc *ReviewUseCase) UpdateReview(review *r.ReviewDTO) error {
-	cacheKey := "review_" + strconv.Itoa(int(review.ReviewID))
-	_ = uc.rp.DeleteCache(cacheKey) // Инвалидация кэша
+func (uc *ReviewUseCase) UpdateReview(ctx context.Context, review *r.ReviewDTO) error {
+	if err := uc.rp.UpdateReview(ctx, review); err != nil {
+		return err
+	}
 
-	return uc.rp.UpdateReview(review)
+	// Инвалидация отзыва и списков, в которые он входит, и рекомендаций автора
+	_ = uc.rp.InvalidateReview(review.ReviewID)
+	_ = uc.rp.InvalidateReviewer(review.UserID)
+	return nil
 }
 
 // DeleteReview удаляет отзыв по FilmId
-func (uc *ReviewUseCase) DeleteReview(reviewID uint) error {
-	cacheKey := "review_" + strconv.Itoa(int(reviewID))
-	_ = uc.rp.DeleteCache(cacheKey)
+func (uc *ReviewUseCase) DeleteReview(ctx context.Context, reviewID uint) error {
+	review, err := uc.rp.DeleteReview(ctx, reviewID)
+	if err != nil {
+		return err
+	}
 
-	return uc.rp.DeleteReview(reviewID)
+	_ = uc.rp.InvalidateReview(reviewID)
+	_ = uc.rp.InvalidateReviewer(review.UserID)
+	return nil
 }
 
 // GetReviewsByFilmID возвращает отзывы по FilmId фильма
 func (uc *ReviewUseCase) GetReviewsByFilmID(filmID uint) ([]*r.ReviewDTO, error) {
-	cacheKey := "reviews_film_" + strconv.Itoa(int(filmID))
-
-	// Попытка получить данные из кэша
-	reviews, err := uc.rp.GetCache(cacheKey)
-	if err == nil && reviews != nil {
-		return reviews, nil
-	}
-
-	// Если данных в кэше нет, запрашиваем из базы
-	reviews, err = uc.rp.GetReviewsByFilmID(filmID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Кэшируем результат
-	_ = uc.rp.SetCache(cacheKey, reviews, time.Hour*24)
-
-	return reviews, nil
+	return uc.rp.LoadFilmReviews(filmID, time.Hour*24, func() ([]*r.ReviewDTO, error) {
+		return uc.rp.GetReviewsByFilmID(filmID)
+	})
 }
 
 // GetReviewsByReviewerID возвращает отзывы по FilmId пользователя
 func (uc *ReviewUseCase) GetReviewsByReviewerID(reviewerID uint) ([]*r.ReviewDTO, error) {
-	cacheKey := "reviews_reviewer_" + strconv.Itoa(int(reviewerID))
-
-	// Попытка получить данные из кэша
-	reviews, err := uc.rp.GetCache(cacheKey)
-	if err == nil && reviews != nil {
-		return reviews, nil
-	}
-
-	// Если данных в кэше нет, запрашиваем из базы
-	reviews, err = uc.rp.GetReviewsByReviewerID(reviewerID)
-	if err != nil {
-		return nil, err
-	}
+	return uc.rp.LoadReviewerReviews(reviewerID, time.Hour*24, func() ([]*r.ReviewDTO, error) {
+		return uc.rp.GetReviewsByReviewerID(reviewerID)
+	})
+}
 
-	// Кэшируем результат
-	_ = uc.rp.SetCache(cacheKey, reviews, time.Hour*24)
+// GetReviewsBySeason возвращает отзывы на сезон сериала вместе с отзывами на его серии.
+// Не кэшируется: при создании отзыва номер сезона неизвестен, и кэш не получилось бы инвалидировать
+func (uc *ReviewUseCase) GetReviewsBySeason(filmID uint, seasonNumber int) ([]*r.ReviewDTO, error) {
+	return uc.rp.GetReviewsBySeason(filmID, seasonNumber)
+}
 
-	return reviews, nil
+// GetReviewsByEpisode возвращает отзывы на серию сериала
+func (uc *ReviewUseCase) GetReviewsByEpisode(filmID uint, seasonNumber int, episodeNumber int) ([]*r.ReviewDTO, error) {
+	return uc.rp.GetReviewsByEpisode(filmID, seasonNumber, episodeNumber)
 }
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	rv "server/internal/modules/revision"
+	"server/pkg/lib/optimistic"
+	resp "server/pkg/lib/response"
+	"strconv"
+)
+
+type RevisionController struct {
+	log *slog.Logger
+	uc  rv.UseCase
+}
+
+func NewRevisionController(log *slog.Logger, uc rv.UseCase) *RevisionController {
+	return &RevisionController{
+		log: log,
+		uc:  uc,
+	}
+}
+
+// GetRevisions - История версий
+// @Summary Получить историю версий фильма или персоны
+// @Description Возвращает версии от новых к старым: номер версии, автор изменения и время, без снимков
+// @Tags revision
+// @Produce json
+// @Param entity path string true "Тип сущности: film, person"
+// @Param id path int true "Id сущности"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /revisions/{entity}/{id} [get]
+func (c *RevisionController) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetRevisions")
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := c.uc.GetRevisions(chi.URLParam(r, "entity"), id)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Revisions(revisions))
+}
+
+// GetRevision - Версия
+// @Summary Получить версию фильма или персоны
+// @Description Возвращает полный снимок сущности на версии: для фильма FilmDTO вместе с жанрами и участниками
+// @Tags revision
+// @Produce json
+// @Param entity path string true "Тип сущности: film, person"
+// @Param id path int true "Id сущности"
+// @Param version path int true "Номер версии"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /revisions/{entity}/{id}/{version} [get]
+func (c *RevisionController) GetRevision(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetRevision")
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	version, ok := parseVersion(w, r, chi.URLParam(r, "version"), "invalid version")
+	if !ok {
+		return
+	}
+
+	rev, err := c.uc.GetRevision(chi.URLParam(r, "entity"), id, version)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Revision(rev))
+}
+
+// DiffRevisions - Сравнение версий
+// @Summary Сравнить две версии фильма или персоны
+// @Description Возвращает поля, которые отличаются в версиях from и to, со значениями в каждой из них. Статистика отзывов и связи, которые меняются не правкой записи (теги, коллекция, медиа), не сравниваются
+// @Tags revision
+// @Produce json
+// @Param entity path string true "Тип сущности: film, person"
+// @Param id path int true "Id сущности"
+// @Param from query int true "Версия, с которой сравнивать"
+// @Param to query int true "Версия, которую сравнивать"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /revisions/{entity}/{id}/diff [get]
+func (c *RevisionController) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DiffRevisions")
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	from, ok := parseVersion(w, r, r.URL.Query().Get("from"), "invalid from version")
+	if !ok {
+		return
+	}
+	to, ok := parseVersion(w, r, r.URL.Query().Get("to"), "invalid to version")
+	if !ok {
+		return
+	}
+
+	diff, err := c.uc.DiffRevisions(chi.URLParam(r, "entity"), id, from, to)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.RevisionDiff(diff))
+}
+
+// RevertRevision - Откат к версии
+// @Summary Вернуть фильм или персону к версии
+// @Description Возвращает поля и связи к выбранной версии обычным изменением: кэш и поиск обновляются, откат попадает в историю новой версией.
+// @Description Постер и аватар не откатываются. Возвращает новую версию
+// @Tags revision
+// @Produce json
+// @Param entity path string true "Тип сущности: film, person"
+// @Param id path int true "Id сущности"
+// @Param version path int true "Номер версии, к которой вернуть"
+// @Param If-Match header string true "Текущая версия сущности, например \"7\""
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 428 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /revisions/{entity}/{id}/{version}/revert [post]
+func (c *RevisionController) RevertRevision(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "RevertRevision")
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	version, ok := parseVersion(w, r, chi.URLParam(r, "version"), "invalid version")
+	if !ok {
+		return
+	}
+
+	expected, err := optimistic.Expected(r, 0)
+	if err != nil {
+		switch {
+		case errors.Is(err, optimistic.ErrVersionRequired):
+			w.WriteHeader(http.StatusPreconditionRequired)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		render.JSON(w, r, resp.Error(r, err.Error()))
+		return
+	}
+
+	newVersion, err := c.uc.RevertRevision(r.Context(), chi.URLParam(r, "entity"), id, version, expected)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.RevisionReverted(newVersion))
+}
+
+func (c *RevisionController) writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, rv.ErrUnknownEntity):
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, rv.ErrRevisionNotFound) || errors.Is(err, rv.ErrEntityNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, optimistic.ErrConflict) || errors.Is(err, rv.ErrMissingReferences):
+		w.WriteHeader(http.StatusConflict)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	default:
+		log.Error("revision request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, rv.ErrInternal.Error()))
+	}
+}
+
+func parseID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil || id == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid id"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func parseVersion(w http.ResponseWriter, r *http.Request, value string, message string) (int, bool) {
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, message))
+		return 0, false
+	}
+	return version, true
+}
@@ -0,0 +1,47 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"server/pkg/lib/revision"
+	"time"
+)
+
+// RevisionDTO - снимок фильма или персоны на версии Version. В списке версий Snapshot не заполняется
+type RevisionDTO struct {
+	ID         uint
+	EntityType string
+	EntityID   uint
+	Version    int
+	UserID     *uint
+	CreatedAt  time.Time
+	Snapshot   json.RawMessage
+}
+
+// DiffDTO - поля, которые отличаются в версиях From и To
+type DiffDTO struct {
+	From    int
+	To      int
+	Changes []revision.Change
+}
+
+type Controller interface {
+	GetRevisions(w http.ResponseWriter, r *http.Request)
+	GetRevision(w http.ResponseWriter, r *http.Request)
+	DiffRevisions(w http.ResponseWriter, r *http.Request)
+	RevertRevision(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	GetRevisions(entityType string, id uint) ([]*RevisionDTO, error)
+	GetRevision(entityType string, id uint, version int) (*RevisionDTO, error)
+	DiffRevisions(entityType string, id uint, from, to int) (*DiffDTO, error)
+	RevertRevision(ctx context.Context, entityType string, id uint, version int, expected int) (int, error)
+}
+
+type Repo interface {
+	//DB
+	GetRevisions(entityType string, id uint) ([]*RevisionDTO, error)
+	GetRevision(entityType string, id uint, version int) (*RevisionDTO, error)
+}
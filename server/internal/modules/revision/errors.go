@@ -0,0 +1,11 @@
+package revision
+
+import "errors"
+
+var (
+	ErrInternal          = errors.New("internal server error")
+	ErrUnknownEntity     = errors.New("unknown entity type, expected one of: film, person")
+	ErrRevisionNotFound  = errors.New("revision not found")
+	ErrEntityNotFound    = errors.New("entity not found")
+	ErrMissingReferences = errors.New("revision references deleted genres or persons")
+)
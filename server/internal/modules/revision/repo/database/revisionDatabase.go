@@ -0,0 +1,65 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"log/slog"
+	rv "server/internal/modules/revision"
+	"server/pkg/lib/revision"
+)
+
+type RevisionDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewRevisionDatabase(db *gorm.DB, log *slog.Logger) *RevisionDatabase {
+	return &RevisionDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetRevisions возвращает версии сущности от новых к старым, без снимков
+func (db *RevisionDatabase) GetRevisions(entityType string, id uint) ([]*rv.RevisionDTO, error) {
+	var rows []*revision.Revision
+	err := db.db.Select("revision_id", "entity_type", "entity_id", "version", "user_id", "created_at").
+		Where("entity_type = ? AND entity_id = ?", entityType, id).
+		Order("version DESC").
+		Find(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get revisions", "error", err, "entityType", entityType, "id", id)
+		return nil, rv.ErrInternal
+	}
+
+	revisions := make([]*rv.RevisionDTO, 0, len(rows))
+	for _, row := range rows {
+		revisions = append(revisions, toDTO(row))
+	}
+	return revisions, nil
+}
+
+func (db *RevisionDatabase) GetRevision(entityType string, id uint, version int) (*rv.RevisionDTO, error) {
+	var row revision.Revision
+	err := db.db.Where("entity_type = ? AND entity_id = ? AND version = ?", entityType, id, version).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, rv.ErrRevisionNotFound
+		}
+		db.log.Error("failed to get revision", "error", err, "entityType", entityType, "id", id, "version", version)
+		return nil, rv.ErrInternal
+	}
+	return toDTO(&row), nil
+}
+
+func toDTO(row *revision.Revision) *rv.RevisionDTO {
+	return &rv.RevisionDTO{
+		ID:         row.RevisionID,
+		EntityType: row.EntityType,
+		EntityID:   row.EntityID,
+		Version:    row.Version,
+		UserID:     row.UserID,
+		CreatedAt:  row.CreatedAt,
+		Snapshot:   row.Snapshot,
+	}
+}
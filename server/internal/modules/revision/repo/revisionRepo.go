@@ -0,0 +1,26 @@
+package repo
+
+import (
+	rv "server/internal/modules/revision"
+)
+
+type RevisionDB interface {
+	GetRevisions(entityType string, id uint) ([]*rv.RevisionDTO, error)
+	GetRevision(entityType string, id uint, version int) (*rv.RevisionDTO, error)
+}
+
+type Repo struct {
+	db RevisionDB
+}
+
+func NewRevisionRepo(db RevisionDB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) GetRevisions(entityType string, id uint) ([]*rv.RevisionDTO, error) {
+	return r.db.GetRevisions(entityType, id)
+}
+
+func (r *Repo) GetRevision(entityType string, id uint, version int) (*rv.RevisionDTO, error) {
+	return r.db.GetRevision(entityType, id, version)
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	f "server/internal/modules/film"
+	per "server/internal/modules/person"
+	rv "server/internal/modules/revision"
+	"server/pkg/lib/patch"
+	"server/pkg/lib/revision"
+	"slices"
+)
+
+// FilmService - откат фильма обычным частичным изменением: кэш и поиск обновляются как при правке (модуль film)
+type FilmService interface {
+	PatchFilm(ctx context.Context, patch *f.FilmPatch) (*f.FilmDTO, error)
+}
+
+// PersonService - откат персоны обычным частичным изменением (модуль person)
+type PersonService interface {
+	PatchPerson(ctx context.Context, patch *per.PersonPatch) (*per.PersonDTO, error)
+}
+
+type RevisionUseCase struct {
+	log     *slog.Logger
+	rp      rv.Repo
+	films   FilmService
+	persons PersonService
+}
+
+func NewRevisionUseCase(log *slog.Logger, rp rv.Repo, films FilmService, persons PersonService) *RevisionUseCase {
+	return &RevisionUseCase{
+		log:     log,
+		rp:      rp,
+		films:   films,
+		persons: persons,
+	}
+}
+
+func (uc *RevisionUseCase) GetRevisions(entityType string, id uint) ([]*rv.RevisionDTO, error) {
+	if !slices.Contains(revision.Entities, entityType) {
+		return nil, rv.ErrUnknownEntity
+	}
+	return uc.rp.GetRevisions(entityType, id)
+}
+
+func (uc *RevisionUseCase) GetRevision(entityType string, id uint, version int) (*rv.RevisionDTO, error) {
+	if !slices.Contains(revision.Entities, entityType) {
+		return nil, rv.ErrUnknownEntity
+	}
+	return uc.rp.GetRevision(entityType, id, version)
+}
+
+// DiffRevisions сравнивает две версии по полям, from может быть и новее to
+func (uc *RevisionUseCase) DiffRevisions(entityType string, id uint, from, to int) (*rv.DiffDTO, error) {
+	before, err := uc.GetRevision(entityType, id, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := uc.GetRevision(entityType, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := revision.Diff(before.Snapshot, after.Snapshot, revision.Ignore[entityType]...)
+	if err != nil {
+		uc.log.Error("failed to diff revisions", "error", err, "entityType", entityType, "id", id)
+		return nil, rv.ErrInternal
+	}
+
+	return &rv.DiffDTO{From: from, To: to, Changes: changes}, nil
+}
+
+// RevertRevision возвращает поля записи и связи к версии version и возвращает новую версию. Откат - новое
+// изменение: он проверяет expected, как любая правка, и сам попадает в историю. Файлы (постер, аватар)
+// в истории не хранятся и откатом не меняются
+func (uc *RevisionUseCase) RevertRevision(ctx context.Context, entityType string, id uint, version int, expected int) (int, error) {
+	rev, err := uc.GetRevision(entityType, id, version)
+	if err != nil {
+		return 0, err
+	}
+
+	switch entityType {
+	case revision.EntityFilm:
+		var film f.FilmDTO
+		if err := json.Unmarshal(rev.Snapshot, &film); err != nil {
+			uc.log.Error("failed to decode film revision", "error", err, "filmID", id, "version", version)
+			return 0, rv.ErrInternal
+		}
+
+		reverted, err := uc.films.PatchFilm(ctx, filmPatch(id, expected, &film))
+		if err != nil {
+			switch {
+			case errors.Is(err, f.ErrFilmNotFound):
+				return 0, rv.ErrEntityNotFound
+			case errors.Is(err, f.ErrGenreNotFound) || errors.Is(err, f.ErrPersonNotFound):
+				return 0, rv.ErrMissingReferences
+			}
+			return 0, err
+		}
+		return reverted.Version, nil
+	default:
+		var person per.PersonDTO
+		if err := json.Unmarshal(rev.Snapshot, &person); err != nil {
+			uc.log.Error("failed to decode person revision", "error", err, "personId", id, "version", version)
+			return 0, rv.ErrInternal
+		}
+
+		reverted, err := uc.persons.PatchPerson(ctx, &per.PersonPatch{
+			PersonId:   id,
+			Version:    expected,
+			Name:       patch.Of(person.Name),
+			Department: patch.Of(person.Department),
+			WikiUrl:    patch.Of(person.WikiUrl),
+		})
+		if err != nil {
+			if errors.Is(err, per.ErrPersonNotFound) {
+				return 0, rv.ErrEntityNotFound
+			}
+			return 0, err
+		}
+		return reverted.Version, nil
+	}
+}
+
+// filmPatch заменяет все поля и связи фильма значениями из снимка
+func filmPatch(id uint, expected int, film *f.FilmDTO) *f.FilmPatch {
+	return &f.FilmPatch{
+		ID:      id,
+		Version: expected,
+
+		ContentType: patch.Of(film.ContentType),
+		Title:       patch.Of(film.Title),
+		Synopsis:    patch.Of(film.Synopsis),
+		ReleaseDate: patch.Of(film.ReleaseDate),
+		Runtime:     patch.Of(film.Runtime),
+
+		OriginalTitle: patch.Of(film.OriginalTitle),
+		AltTitles:     patch.Of(film.AltTitles),
+		Tagline:       patch.Of(film.Tagline),
+		Countries:     patch.Of(film.Countries),
+		Languages:     patch.Of(film.Languages),
+		AgeRating:     patch.Of(film.AgeRating),
+		Budget:        patch.Of(film.Budget),
+		BoxOffice:     patch.Of(film.BoxOffice),
+		Currency:      patch.Of(film.Currency),
+		IMDbID:        patch.Of(film.ExternalIDs.IMDb),
+		KinopoiskID:   patch.Of(film.ExternalIDs.Kinopoisk),
+		TMDBID:        patch.Of(film.ExternalIDs.TMDB),
+
+		GenreIDs: patch.Of(film.GenreIDs),
+		Credits:  patch.Of(film.Credits),
+	}
+}
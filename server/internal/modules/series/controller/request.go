@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"github.com/go-playground/validator/v10"
+	"regexp"
+)
+
+type SeasonRequest struct {
+	SeasonNumber int    `json:"season_number" validate:"min=0"` // 0 - спецвыпуски
+	Title        string `json:"title" validate:"omitempty,max=500"`
+	Synopsis     string `json:"synopsis" validate:"omitempty"`
+	AirDate      string `json:"air_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type EpisodeRequest struct {
+	EpisodeNumber int    `json:"episode_number" validate:"required,min=1"`
+	Title         string `json:"title" validate:"omitempty,max=500"`
+	Synopsis      string `json:"synopsis" validate:"omitempty"`
+	AirDate       string `json:"air_date" validate:"omitempty,datetime=2006-01-02"`
+	Runtime       string `json:"runtime" validate:"omitempty,runtime_format"`
+}
+
+func validateRuntimeFormat(fl validator.FieldLevel) bool {
+	matched, _ := regexp.MatchString(`^\d+h\s*\d*m$|^\d+h$|^\d+m$`, fl.Field().String())
+
+	return matched
+}
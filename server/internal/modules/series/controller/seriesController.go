@@ -0,0 +1,500 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	sr "server/internal/modules/series"
+	resp "server/pkg/lib/response"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Controller struct {
+	log      *slog.Logger
+	uc       sr.UseCase
+	validate *validator.Validate
+}
+
+func NewSeriesController(log *slog.Logger, uc sr.UseCase) *Controller {
+	validate := validator.New()
+	if err := validate.RegisterValidation("runtime_format", validateRuntimeFormat); err != nil {
+		log.Error("failed to register runtime_format validator", "error", err)
+	}
+
+	return &Controller{
+		log:      log,
+		uc:       uc,
+		validate: validate,
+	}
+}
+
+// GetSeasons - Получение сезонов сериала
+// @Summary Получить сезоны сериала
+// @Description Возвращает сезоны сериала с количеством серий и статистикой по отзывам на сезон и его серии
+// @Tags series
+// @Produce json
+// @Param id path string true "FilmId сериала"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons [get]
+func (c *Controller) GetSeasons(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetSeasons")
+
+	filmID, ok := parseFilmID(w, r)
+	if !ok {
+		return
+	}
+
+	seasons, err := c.uc.GetSeasons(filmID)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Seasons(seasons))
+}
+
+// GetSeason - Получение сезона сериала
+// @Summary Получить сезон сериала
+// @Description Возвращает сезон сериала вместе с сериями
+// @Tags series
+// @Produce json
+// @Param id path string true "FilmId сериала"
+// @Param season path int true "Номер сезона"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons/{season} [get]
+func (c *Controller) GetSeason(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetSeason")
+
+	filmID, ok := parseFilmID(w, r)
+	if !ok {
+		return
+	}
+	seasonNumber, ok := parseNumber(w, r, "season", 0)
+	if !ok {
+		return
+	}
+
+	season, err := c.uc.GetSeason(filmID, seasonNumber)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Seasons(season))
+}
+
+// CreateSeason - Добавление сезона
+// @Summary Добавить сезон сериала
+// @Description Добавляет сезон к сериалу или мини-сериалу
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "FilmId сериала"
+// @Param json body SeasonRequest true "Данные сезона"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons [post]
+func (c *Controller) CreateSeason(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "CreateSeason")
+
+	filmID, ok := parseFilmID(w, r)
+	if !ok {
+		return
+	}
+
+	var req SeasonRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	season := toSeasonDTO(&req)
+	season.FilmID = filmID
+
+	if err := c.uc.CreateSeason(season); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.OK())
+}
+
+// UpdateSeason - Обновление сезона
+// @Summary Обновить сезон сериала
+// @Description Обновляет данные сезона, в том числе его номер
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "FilmId сериала"
+// @Param season path int true "Номер сезона"
+// @Param json body SeasonRequest true "Данные сезона"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons/{season} [put]
+func (c *Controller) UpdateSeason(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "UpdateSeason")
+
+	filmID, ok := parseFilmID(w, r)
+	if !ok {
+		return
+	}
+	seasonNumber, ok := parseNumber(w, r, "season", 0)
+	if !ok {
+		return
+	}
+
+	var req SeasonRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	if err := c.uc.UpdateSeason(filmID, seasonNumber, toSeasonDTO(&req)); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// DeleteSeason - Удаление сезона
+// @Summary Удалить сезон сериала
+// @Description Удаляет сезон вместе с сериями и отзывами на них
+// @Tags series
+// @Param id path string true "FilmId сериала"
+// @Param season path int true "Номер сезона"
+// @Success 204 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons/{season} [delete]
+func (c *Controller) DeleteSeason(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DeleteSeason")
+
+	filmID, ok := parseFilmID(w, r)
+	if !ok {
+		return
+	}
+	seasonNumber, ok := parseNumber(w, r, "season", 0)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteSeason(filmID, seasonNumber); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	render.JSON(w, r, resp.OK())
+}
+
+// GetEpisodes - Получение серий сезона
+// @Summary Получить серии сезона
+// @Description Возвращает серии сезона по порядку со статистикой по отзывам
+// @Tags series
+// @Produce json
+// @Param id path string true "FilmId сериала"
+// @Param season path int true "Номер сезона"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons/{season}/episodes [get]
+func (c *Controller) GetEpisodes(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetEpisodes")
+
+	filmID, ok := parseFilmID(w, r)
+	if !ok {
+		return
+	}
+	seasonNumber, ok := parseNumber(w, r, "season", 0)
+	if !ok {
+		return
+	}
+
+	episodes, err := c.uc.GetEpisodes(filmID, seasonNumber)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Episodes(episodes))
+}
+
+// GetEpisode - Получение серии
+// @Summary Получить серию
+// @Description Возвращает серию сезона по номеру
+// @Tags series
+// @Produce json
+// @Param id path string true "FilmId сериала"
+// @Param season path int true "Номер сезона"
+// @Param episode path int true "Номер серии"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons/{season}/episodes/{episode} [get]
+func (c *Controller) GetEpisode(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetEpisode")
+
+	filmID, ok := parseFilmID(w, r)
+	if !ok {
+		return
+	}
+	seasonNumber, ok := parseNumber(w, r, "season", 0)
+	if !ok {
+		return
+	}
+	episodeNumber, ok := parseNumber(w, r, "episode", 1)
+	if !ok {
+		return
+	}
+
+	episode, err := c.uc.GetEpisode(filmID, seasonNumber, episodeNumber)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Episodes(episode))
+}
+
+// CreateEpisode - Добавление серии
+// @Summary Добавить серию
+// @Description Добавляет серию в сезон сериала
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "FilmId сериала"
+// @Param season path int true "Номер сезона"
+// @Param json body EpisodeRequest true "Данные серии"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons/{season}/episodes [post]
+func (c *Controller) CreateEpisode(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "CreateEpisode")
+
+	filmID, ok := parseFilmID(w, r)
+	if !ok {
+		return
+	}
+	seasonNumber, ok := parseNumber(w, r, "season", 0)
+	if !ok {
+		return
+	}
+
+	var req EpisodeRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	episode := toEpisodeDTO(&req)
+	episode.FilmID = filmID
+	episode.SeasonNumber = seasonNumber
+
+	if err := c.uc.CreateEpisode(episode); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.OK())
+}
+
+// UpdateEpisode - Обновление серии
+// @Summary Обновить серию
+// @Description Обновляет данные серии, в том числе ее номер
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "FilmId сериала"
+// @Param season path int true "Номер сезона"
+// @Param episode path int true "Номер серии"
+// @Param json body EpisodeRequest true "Данные серии"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons/{season}/episodes/{episode} [put]
+func (c *Controller) UpdateEpisode(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "UpdateEpisode")
+
+	filmID, ok := parseFilmID(w, r)
+	if !ok {
+		return
+	}
+	seasonNumber, ok := parseNumber(w, r, "season", 0)
+	if !ok {
+		return
+	}
+	episodeNumber, ok := parseNumber(w, r, "episode", 1)
+	if !ok {
+		return
+	}
+
+	var req EpisodeRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	if err := c.uc.UpdateEpisode(filmID, seasonNumber, episodeNumber, toEpisodeDTO(&req)); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// DeleteEpisode - Удаление серии
+// @Summary Удалить серию
+// @Description Удаляет серию вместе с отзывами на нее
+// @Tags series
+// @Param id path string true "FilmId сериала"
+// @Param season path int true "Номер сезона"
+// @Param episode path int true "Номер серии"
+// @Success 204 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/seasons/{season}/episodes/{episode} [delete]
+func (c *Controller) DeleteEpisode(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DeleteEpisode")
+
+	filmID, ok := parseFilmID(w, r)
+	if !ok {
+		return
+	}
+	seasonNumber, ok := parseNumber(w, r, "season", 0)
+	if !ok {
+		return
+	}
+	episodeNumber, ok := parseNumber(w, r, "episode", 1)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteEpisode(filmID, seasonNumber, episodeNumber); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	render.JSON(w, r, resp.OK())
+}
+
+// decode читает и валидирует тело запроса, при ошибке сам отвечает клиенту
+func (c *Controller) decode(w http.ResponseWriter, r *http.Request, log *slog.Logger, req interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		log.Error("failed to decode request body", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
+		return false
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return false
+	}
+
+	return true
+}
+
+func (c *Controller) writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, sr.ErrFilmNotFound) || errors.Is(err, sr.ErrSeasonNotFound) || errors.Is(err, sr.ErrEpisodeNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, sr.ErrNotSeries):
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, sr.ErrSeasonExists) || errors.Is(err, sr.ErrEpisodeExists):
+		w.WriteHeader(http.StatusConflict)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	default:
+		log.Error("series request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, sr.ErrInternal.Error()))
+	}
+}
+
+func parseFilmID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid id"))
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func parseNumber(w http.ResponseWriter, r *http.Request, param string, minNumber int) (int, bool) {
+	number, err := strconv.Atoi(chi.URLParam(r, param))
+	if err != nil || number < minNumber {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid "+param+" number"))
+		return 0, false
+	}
+
+	return number, true
+}
+
+func toSeasonDTO(req *SeasonRequest) *sr.SeasonDTO {
+	return &sr.SeasonDTO{
+		SeasonNumber: req.SeasonNumber,
+		Title:        strings.TrimSpace(req.Title),
+		Synopsis:     strings.TrimSpace(req.Synopsis),
+		AirDate:      parseDate(req.AirDate),
+	}
+}
+
+func toEpisodeDTO(req *EpisodeRequest) *sr.EpisodeDTO {
+	return &sr.EpisodeDTO{
+		EpisodeNumber: req.EpisodeNumber,
+		Title:         strings.TrimSpace(req.Title),
+		Synopsis:      strings.TrimSpace(req.Synopsis),
+		AirDate:       parseDate(req.AirDate),
+		Runtime:       req.Runtime,
+	}
+}
+
+// parseDate разбирает уже провалидированную дату, пустая строка - дата выхода неизвестна
+func parseDate(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	date, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
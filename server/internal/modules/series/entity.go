@@ -0,0 +1,76 @@
+package series
+
+import (
+	"net/http"
+	"time"
+)
+
+type SeasonDTO struct {
+	SeasonID      uint          `json:"season_id"`
+	FilmID        uint          `json:"film_id"`
+	SeasonNumber  int           `json:"season_number"`
+	Title         string        `json:"title"`
+	Synopsis      string        `json:"synopsis"`
+	AirDate       *time.Time    `json:"air_date"`
+	EpisodesCount int           `json:"episodes_count"`
+	AvgRating     float64       `json:"avg_rating"`    // по отзывам на сезон и его серии
+	TotalReviews  uint          `json:"total_reviews"` // по отзывам на сезон и его серии
+	Episodes      []*EpisodeDTO `json:"episodes"`
+}
+
+type EpisodeDTO struct {
+	EpisodeID     uint       `json:"episode_id"`
+	SeasonID      uint       `json:"season_id"`
+	FilmID        uint       `json:"film_id"`
+	SeasonNumber  int        `json:"season_number"`
+	EpisodeNumber int        `json:"episode_number"`
+	Title         string     `json:"title"`
+	Synopsis      string     `json:"synopsis"`
+	AirDate       *time.Time `json:"air_date"`
+	Runtime       string     `json:"runtime"`
+	AvgRating     float64    `json:"avg_rating"`
+	TotalReviews  uint       `json:"total_reviews"`
+}
+
+type Controller interface {
+	GetSeasons(w http.ResponseWriter, r *http.Request)
+	GetSeason(w http.ResponseWriter, r *http.Request)
+	CreateSeason(w http.ResponseWriter, r *http.Request)
+	UpdateSeason(w http.ResponseWriter, r *http.Request)
+	DeleteSeason(w http.ResponseWriter, r *http.Request)
+	GetEpisodes(w http.ResponseWriter, r *http.Request)
+	GetEpisode(w http.ResponseWriter, r *http.Request)
+	CreateEpisode(w http.ResponseWriter, r *http.Request)
+	UpdateEpisode(w http.ResponseWriter, r *http.Request)
+	DeleteEpisode(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	GetSeasons(filmID uint) ([]*SeasonDTO, error)
+	GetSeason(filmID uint, seasonNumber int) (*SeasonDTO, error)
+	CreateSeason(season *SeasonDTO) error
+	UpdateSeason(filmID uint, seasonNumber int, season *SeasonDTO) error
+	DeleteSeason(filmID uint, seasonNumber int) error
+	GetEpisodes(filmID uint, seasonNumber int) ([]*EpisodeDTO, error)
+	GetEpisode(filmID uint, seasonNumber int, episodeNumber int) (*EpisodeDTO, error)
+	CreateEpisode(episode *EpisodeDTO) error
+	UpdateEpisode(filmID uint, seasonNumber int, episodeNumber int, episode *EpisodeDTO) error
+	DeleteEpisode(filmID uint, seasonNumber int, episodeNumber int) error
+}
+
+type Repo interface {
+	//DB
+	GetSeasons(filmID uint) ([]*SeasonDTO, error)
+	GetEpisodes(filmID uint) ([]*EpisodeDTO, error)
+	CreateSeason(season *SeasonDTO) error
+	UpdateSeason(filmID uint, seasonNumber int, season *SeasonDTO) error
+	DeleteSeason(filmID uint, seasonNumber int) error
+	CreateEpisode(episode *EpisodeDTO) error
+	UpdateEpisode(filmID uint, seasonNumber int, episodeNumber int, episode *EpisodeDTO) error
+	DeleteEpisode(filmID uint, seasonNumber int, episodeNumber int) error
+
+	//Cache
+	GetSeasonsFromCache(key string) ([]*SeasonDTO, error)
+	SetSeasonsToCache(key string, seasons []*SeasonDTO, ttl time.Duration) error
+	DeleteSeasonsFromCache(key string) error
+}
@@ -0,0 +1,14 @@
+package series
+
+import "errors"
+
+var (
+	ErrInternal        = errors.New("internal server error")
+	ErrMissCache       = errors.New("miss cache error")
+	ErrFilmNotFound    = errors.New("film not found")
+	ErrNotSeries       = errors.New("film is not a series")
+	ErrSeasonNotFound  = errors.New("season not found")
+	ErrSeasonExists    = errors.New("season already exists")
+	ErrEpisodeNotFound = errors.New("episode not found")
+	ErrEpisodeExists   = errors.New("episode already exists")
+)
@@ -0,0 +1,105 @@
+package series
+
+import (
+	f "server/internal/modules/film"
+	"time"
+)
+
+type Season struct {
+	SeasonID     uint       `gorm:"primaryKey;column:season_id;autoIncrement"`
+	FilmID       uint       `gorm:"column:film_id;not null"`
+	SeasonNumber int        `gorm:"column:season_number;not null"`
+	Title        string     `gorm:"column:title;type:text;not null;default:''"`
+	Synopsis     string     `gorm:"column:synopsis;type:text;not null;default:''"`
+	AirDate      *time.Time `gorm:"column:air_date;type:date"`
+	CreatedAt    time.Time  `gorm:"column:create_at"`
+}
+
+func (Season) TableName() string {
+	return "seasons"
+}
+
+type Episode struct {
+	EpisodeID     uint       `gorm:"primaryKey;column:episode_id;autoIncrement"`
+	SeasonID      uint       `gorm:"column:season_id;not null"`
+	EpisodeNumber int        `gorm:"column:episode_number;not null"`
+	Title         string     `gorm:"column:title;type:text;not null;default:''"`
+	Synopsis      string     `gorm:"column:synopsis;type:text;not null;default:''"`
+	AirDate       *time.Time `gorm:"column:air_date;type:date"`
+	Runtime       int        `gorm:"column:runtime;not null;default:0"`
+	CreatedAt     time.Time  `gorm:"column:create_at"`
+}
+
+func (Episode) TableName() string {
+	return "episodes"
+}
+
+// SeasonRow - сезон со статистикой по отзывам, результат GetSeasons
+type SeasonRow struct {
+	Season        `gorm:"embedded"`
+	EpisodesCount int     `gorm:"column:episodes_count"`
+	AvgRating     float64 `gorm:"column:avg_rating"`
+	TotalReviews  uint    `gorm:"column:total_reviews"`
+}
+
+// EpisodeRow - серия с номером сезона и статистикой по отзывам, результат GetEpisodes
+type EpisodeRow struct {
+	Episode      `gorm:"embedded"`
+	FilmID       uint    `gorm:"column:film_id"`
+	SeasonNumber int     `gorm:"column:season_number"`
+	AvgRating    float64 `gorm:"column:avg_rating"`
+	TotalReviews uint    `gorm:"column:total_reviews"`
+}
+
+func (s *SeasonRow) ToDTO() *SeasonDTO {
+	return &SeasonDTO{
+		SeasonID:      s.SeasonID,
+		FilmID:        s.FilmID,
+		SeasonNumber:  s.SeasonNumber,
+		Title:         s.Title,
+		Synopsis:      s.Synopsis,
+		AirDate:       s.AirDate,
+		EpisodesCount: s.EpisodesCount,
+		AvgRating:     s.AvgRating,
+		TotalReviews:  s.TotalReviews,
+	}
+}
+
+func (e *EpisodeRow) ToDTO() *EpisodeDTO {
+	return &EpisodeDTO{
+		EpisodeID:     e.EpisodeID,
+		SeasonID:      e.SeasonID,
+		FilmID:        e.FilmID,
+		SeasonNumber:  e.SeasonNumber,
+		EpisodeNumber: e.EpisodeNumber,
+		Title:         e.Title,
+		Synopsis:      e.Synopsis,
+		AirDate:       e.AirDate,
+		Runtime:       f.MinutesToDurationString(e.Runtime),
+		AvgRating:     e.AvgRating,
+		TotalReviews:  e.TotalReviews,
+	}
+}
+
+func (s *SeasonDTO) ToModel() *Season {
+	return &Season{
+		SeasonID:     s.SeasonID,
+		FilmID:       s.FilmID,
+		SeasonNumber: s.SeasonNumber,
+		Title:        s.Title,
+		Synopsis:     s.Synopsis,
+		AirDate:      s.AirDate,
+	}
+}
+
+func (e *EpisodeDTO) ToModel() *Episode {
+	return &Episode{
+		EpisodeID:     e.EpisodeID,
+		SeasonID:      e.SeasonID,
+		EpisodeNumber: e.EpisodeNumber,
+		Title:         e.Title,
+		Synopsis:      e.Synopsis,
+		AirDate:       e.AirDate,
+		Runtime:       f.DurationStringToMinutes(e.Runtime),
+	}
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"errors"
+	"server/internal/init/cache"
+	sr "server/internal/modules/series"
+	"time"
+)
+
+type SeriesCache struct {
+	ch      *cache.Cache
+	seasons *cache.Typed[[]*sr.SeasonDTO]
+}
+
+func NewSeriesCache(ch *cache.Cache) *SeriesCache {
+	return &SeriesCache{
+		ch:      ch,
+		seasons: cache.NewTyped[[]*sr.SeasonDTO](ch),
+	}
+}
+
+func (c *SeriesCache) SetSeasonsToCache(key string, seasons []*sr.SeasonDTO, ttl time.Duration) error {
+	return c.seasons.Set(key, seasons, ttl)
+}
+
+func (c *SeriesCache) GetSeasonsFromCache(key string) ([]*sr.SeasonDTO, error) {
+	result, err := c.seasons.Get(key)
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, sr.ErrMissCache
+	}
+	return result, err
+}
+
+func (c *SeriesCache) DeleteSeasonsFromCache(key string) error {
+	return c.seasons.Delete(key)
+}
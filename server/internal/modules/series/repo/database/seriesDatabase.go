@@ -0,0 +1,210 @@
+package database
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"log/slog"
+	sr "server/internal/modules/series"
+)
+
+type SeriesDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewSeriesDatabase(db *gorm.DB, log *slog.Logger) *SeriesDatabase {
+	return &SeriesDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetSeasons возвращает сезоны сериала по порядку. Статистика сезона считается
+// по отзывам на сам сезон и на его серии (у отзыва на серию заполнен season_id).
+func (db *SeriesDatabase) GetSeasons(filmID uint) ([]*sr.SeasonDTO, error) {
+	var rows []*sr.SeasonRow
+
+	err := db.db.Raw(`
+		SELECT s.*,
+		       (SELECT COUNT(*) FROM episodes e WHERE e.season_id = s.season_id) AS episodes_count,
+		       COALESCE(ROUND(AVG(r.rating)), 0) AS avg_rating,
+		       COUNT(r.review_id) AS total_reviews
+		FROM seasons s
+		LEFT JOIN reviews r ON r.season_id = s.season_id
+		WHERE s.film_id = ?
+		GROUP BY s.season_id
+		ORDER BY s.season_number`, filmID).Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get seasons", "error", err, "filmID", filmID)
+		return nil, sr.ErrInternal
+	}
+
+	seasons := make([]*sr.SeasonDTO, 0, len(rows))
+	for _, row := range rows {
+		seasons = append(seasons, row.ToDTO())
+	}
+
+	return seasons, nil
+}
+
+// GetEpisodes возвращает все серии сериала, упорядоченные по сезонам и номерам
+func (db *SeriesDatabase) GetEpisodes(filmID uint) ([]*sr.EpisodeDTO, error) {
+	var rows []*sr.EpisodeRow
+
+	err := db.db.Raw(`
+		SELECT e.*, s.film_id, s.season_number,
+		       COALESCE(ROUND(AVG(r.rating)), 0) AS avg_rating,
+		       COUNT(r.review_id) AS total_reviews
+		FROM episodes e
+		JOIN seasons s ON s.season_id = e.season_id
+		LEFT JOIN reviews r ON r.episode_id = e.episode_id
+		WHERE s.film_id = ?
+		GROUP BY e.episode_id, s.film_id, s.season_number
+		ORDER BY s.season_number, e.episode_number`, filmID).Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get episodes", "error", err, "filmID", filmID)
+		return nil, sr.ErrInternal
+	}
+
+	episodes := make([]*sr.EpisodeDTO, 0, len(rows))
+	for _, row := range rows {
+		episodes = append(episodes, row.ToDTO())
+	}
+
+	return episodes, nil
+}
+
+func (db *SeriesDatabase) CreateSeason(season *sr.SeasonDTO) error {
+	seasonModel := season.ToModel()
+	if err := db.db.Create(seasonModel).Error; err != nil {
+		return db.mapError(err, sr.ErrSeasonExists, sr.ErrFilmNotFound, "failed to create season")
+	}
+	season.SeasonID = seasonModel.SeasonID
+
+	return nil
+}
+
+func (db *SeriesDatabase) UpdateSeason(filmID uint, seasonNumber int, season *sr.SeasonDTO) error {
+	result := db.db.Model(&sr.Season{}).
+		Where("film_id = ? AND season_number = ?", filmID, seasonNumber).
+		Updates(map[string]interface{}{
+			"season_number": season.SeasonNumber,
+			"title":         season.Title,
+			"synopsis":      season.Synopsis,
+			"air_date":      season.AirDate,
+		})
+	if result.Error != nil {
+		return db.mapError(result.Error, sr.ErrSeasonExists, sr.ErrFilmNotFound, "failed to update season")
+	}
+	if result.RowsAffected == 0 {
+		return sr.ErrSeasonNotFound
+	}
+
+	return nil
+}
+
+func (db *SeriesDatabase) DeleteSeason(filmID uint, seasonNumber int) error {
+	result := db.db.Where("film_id = ? AND season_number = ?", filmID, seasonNumber).Delete(&sr.Season{})
+	if result.Error != nil {
+		db.log.Error("failed to delete season", "error", result.Error, "filmID", filmID)
+		return sr.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return sr.ErrSeasonNotFound
+	}
+
+	return nil
+}
+
+func (db *SeriesDatabase) CreateEpisode(episode *sr.EpisodeDTO) error {
+	seasonID, err := db.getSeasonID(episode.FilmID, episode.SeasonNumber)
+	if err != nil {
+		return err
+	}
+
+	episodeModel := episode.ToModel()
+	episodeModel.SeasonID = seasonID
+	if err := db.db.Create(episodeModel).Error; err != nil {
+		return db.mapError(err, sr.ErrEpisodeExists, sr.ErrSeasonNotFound, "failed to create episode")
+	}
+	episode.EpisodeID = episodeModel.EpisodeID
+	episode.SeasonID = seasonID
+
+	return nil
+}
+
+func (db *SeriesDatabase) UpdateEpisode(filmID uint, seasonNumber int, episodeNumber int, episode *sr.EpisodeDTO) error {
+	seasonID, err := db.getSeasonID(filmID, seasonNumber)
+	if err != nil {
+		return err
+	}
+
+	episodeModel := episode.ToModel()
+	result := db.db.Model(&sr.Episode{}).
+		Where("season_id = ? AND episode_number = ?", seasonID, episodeNumber).
+		Updates(map[string]interface{}{
+			"episode_number": episodeModel.EpisodeNumber,
+			"title":          episodeModel.Title,
+			"synopsis":       episodeModel.Synopsis,
+			"air_date":       episodeModel.AirDate,
+			"runtime":        episodeModel.Runtime,
+		})
+	if result.Error != nil {
+		return db.mapError(result.Error, sr.ErrEpisodeExists, sr.ErrSeasonNotFound, "failed to update episode")
+	}
+	if result.RowsAffected == 0 {
+		return sr.ErrEpisodeNotFound
+	}
+
+	return nil
+}
+
+func (db *SeriesDatabase) DeleteEpisode(filmID uint, seasonNumber int, episodeNumber int) error {
+	seasonID, err := db.getSeasonID(filmID, seasonNumber)
+	if err != nil {
+		return err
+	}
+
+	result := db.db.Where("season_id = ? AND episode_number = ?", seasonID, episodeNumber).Delete(&sr.Episode{})
+	if result.Error != nil {
+		db.log.Error("failed to delete episode", "error", result.Error, "seasonID", seasonID)
+		return sr.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return sr.ErrEpisodeNotFound
+	}
+
+	return nil
+}
+
+func (db *SeriesDatabase) getSeasonID(filmID uint, seasonNumber int) (uint, error) {
+	var season sr.Season
+	err := db.db.Select("season_id").
+		Where("film_id = ? AND season_number = ?", filmID, seasonNumber).
+		First(&season).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, sr.ErrSeasonNotFound
+		}
+		db.log.Error("failed to get season", "error", err, "filmID", filmID)
+		return 0, sr.ErrInternal
+	}
+
+	return season.SeasonID, nil
+}
+
+// mapError переводит нарушения уникальности и внешнего ключа в ошибки модуля
+func (db *SeriesDatabase) mapError(err error, errExists error, errParentNotFound error, msg string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return errExists
+		case "23503":
+			return errParentNotFound
+		}
+	}
+	db.log.Error(msg, "error", err)
+	return sr.ErrInternal
+}
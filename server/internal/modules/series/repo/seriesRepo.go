@@ -0,0 +1,79 @@
+package repo
+
+import (
+	sr "server/internal/modules/series"
+	"time"
+)
+
+type SeriesDB interface {
+	GetSeasons(filmID uint) ([]*sr.SeasonDTO, error)
+	GetEpisodes(filmID uint) ([]*sr.EpisodeDTO, error)
+	CreateSeason(season *sr.SeasonDTO) error
+	UpdateSeason(filmID uint, seasonNumber int, season *sr.SeasonDTO) error
+	DeleteSeason(filmID uint, seasonNumber int) error
+	CreateEpisode(episode *sr.EpisodeDTO) error
+	UpdateEpisode(filmID uint, seasonNumber int, episodeNumber int, episode *sr.EpisodeDTO) error
+	DeleteEpisode(filmID uint, seasonNumber int, episodeNumber int) error
+}
+
+type SeriesCache interface {
+	GetSeasonsFromCache(key string) ([]*sr.SeasonDTO, error)
+	SetSeasonsToCache(key string, seasons []*sr.SeasonDTO, ttl time.Duration) error
+	DeleteSeasonsFromCache(key string) error
+}
+
+type Repo struct {
+	db SeriesDB
+	ch SeriesCache
+}
+
+func NewSeriesRepo(db SeriesDB, ch SeriesCache) *Repo {
+	return &Repo{
+		db: db,
+		ch: ch,
+	}
+}
+
+func (r *Repo) GetSeasons(filmID uint) ([]*sr.SeasonDTO, error) {
+	return r.db.GetSeasons(filmID)
+}
+
+func (r *Repo) GetEpisodes(filmID uint) ([]*sr.EpisodeDTO, error) {
+	return r.db.GetEpisodes(filmID)
+}
+
+func (r *Repo) CreateSeason(season *sr.SeasonDTO) error {
+	return r.db.CreateSeason(season)
+}
+
+func (r *Repo) UpdateSeason(filmID uint, seasonNumber int, season *sr.SeasonDTO) error {
+	return r.db.UpdateSeason(filmID, seasonNumber, season)
+}
+
+func (r *Repo) DeleteSeason(filmID uint, seasonNumber int) error {
+	return r.db.DeleteSeason(filmID, seasonNumber)
+}
+
+func (r *Repo) CreateEpisode(episode *sr.EpisodeDTO) error {
+	return r.db.CreateEpisode(episode)
+}
+
+func (r *Repo) UpdateEpisode(filmID uint, seasonNumber int, episodeNumber int, episode *sr.EpisodeDTO) error {
+	return r.db.UpdateEpisode(filmID, seasonNumber, episodeNumber, episode)
+}
+
+func (r *Repo) DeleteEpisode(filmID uint, seasonNumber int, episodeNumber int) error {
+	return r.db.DeleteEpisode(filmID, seasonNumber, episodeNumber)
+}
+
+func (r *Repo) GetSeasonsFromCache(key string) ([]*sr.SeasonDTO, error) {
+	return r.ch.GetSeasonsFromCache(key)
+}
+
+func (r *Repo) SetSeasonsToCache(key string, seasons []*sr.SeasonDTO, ttl time.Duration) error {
+	return r.ch.SetSeasonsToCache(key, seasons, ttl)
+}
+
+func (r *Repo) DeleteSeasonsFromCache(key string) error {
+	return r.ch.DeleteSeasonsFromCache(key)
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	f "server/internal/modules/film"
+	sr "server/internal/modules/series"
+	"time"
+)
+
+// FilmService - то, что нужно модулю сериалов от фильмов
+type FilmService interface {
+	GetFilmByID(id uint) (*f.FilmDTO, error)
+	ReindexFilm(id uint) error
+}
+
+type SeriesUseCase struct {
+	log   *slog.Logger
+	rp    sr.Repo
+	films FilmService
+}
+
+func NewSeriesUseCase(log *slog.Logger, rp sr.Repo, films FilmService) *SeriesUseCase {
+	return &SeriesUseCase{
+		log:   log,
+		rp:    rp,
+		films: films,
+	}
+}
+
+// GetSeasons возвращает сезоны сериала вместе с сериями
+func (uc *SeriesUseCase) GetSeasons(filmID uint) ([]*sr.SeasonDTO, error) {
+	cacheKey := seasonsCacheKey(filmID)
+	if seasons, err := uc.rp.GetSeasonsFromCache(cacheKey); err == nil {
+		return seasons, nil
+	}
+
+	if err := uc.ensureSeries(filmID); err != nil {
+		return nil, err
+	}
+
+	seasons, err := uc.rp.GetSeasons(filmID)
+	if err != nil {
+		return nil, err
+	}
+
+	episodes, err := uc.rp.GetEpisodes(filmID)
+	if err != nil {
+		return nil, err
+	}
+
+	bySeason := make(map[uint]*sr.SeasonDTO, len(seasons))
+	for _, season := range seasons {
+		season.Episodes = make([]*sr.EpisodeDTO, 0, season.EpisodesCount)
+		bySeason[season.SeasonID] = season
+	}
+	for _, episode := range episodes {
+		if season, ok := bySeason[episode.SeasonID]; ok {
+			season.Episodes = append(season.Episodes, episode)
+		}
+	}
+
+	if err := uc.rp.SetSeasonsToCache(cacheKey, seasons, time.Minute*10); err != nil {
+		uc.log.Error("failed to cache seasons", "error", err)
+	}
+
+	return seasons, nil
+}
+
+func (uc *SeriesUseCase) GetSeason(filmID uint, seasonNumber int) (*sr.SeasonDTO, error) {
+	seasons, err := uc.GetSeasons(filmID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, season := range seasons {
+		if season.SeasonNumber == seasonNumber {
+			return season, nil
+		}
+	}
+
+	return nil, sr.ErrSeasonNotFound
+}
+
+func (uc *SeriesUseCase) CreateSeason(season *sr.SeasonDTO) error {
+	if err := uc.ensureSeries(season.FilmID); err != nil {
+		return err
+	}
+
+	if err := uc.rp.CreateSeason(season); err != nil {
+		return err
+	}
+
+	uc.invalidate(season.FilmID, false)
+	return nil
+}
+
+func (uc *SeriesUseCase) UpdateSeason(filmID uint, seasonNumber int, season *sr.SeasonDTO) error {
+	if err := uc.rp.UpdateSeason(filmID, seasonNumber, season); err != nil {
+		return err
+	}
+
+	// Номер сезона влияет на порядок серий в индексе
+	uc.invalidate(filmID, season.SeasonNumber != seasonNumber)
+	return nil
+}
+
+func (uc *SeriesUseCase) DeleteSeason(filmID uint, seasonNumber int) error {
+	if err := uc.rp.DeleteSeason(filmID, seasonNumber); err != nil {
+		return err
+	}
+
+	uc.invalidate(filmID, true)
+	return nil
+}
+
+func (uc *SeriesUseCase) GetEpisodes(filmID uint, seasonNumber int) ([]*sr.EpisodeDTO, error) {
+	season, err := uc.GetSeason(filmID, seasonNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return season.Episodes, nil
+}
+
+func (uc *SeriesUseCase) GetEpisode(filmID uint, seasonNumber int, episodeNumber int) (*sr.EpisodeDTO, error) {
+	episodes, err := uc.GetEpisodes(filmID, seasonNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, episode := range episodes {
+		if episode.EpisodeNumber == episodeNumber {
+			return episode, nil
+		}
+	}
+
+	return nil, sr.ErrEpisodeNotFound
+}
+
+func (uc *SeriesUseCase) CreateEpisode(episode *sr.EpisodeDTO) error {
+	if err := uc.rp.CreateEpisode(episode); err != nil {
+		return err
+	}
+
+	uc.invalidate(episode.FilmID, true)
+	return nil
+}
+
+func (uc *SeriesUseCase) UpdateEpisode(filmID uint, seasonNumber int, episodeNumber int, episode *sr.EpisodeDTO) error {
+	if err := uc.rp.UpdateEpisode(filmID, seasonNumber, episodeNumber, episode); err != nil {
+		return err
+	}
+
+	uc.invalidate(filmID, true)
+	return nil
+}
+
+func (uc *SeriesUseCase) DeleteEpisode(filmID uint, seasonNumber int, episodeNumber int) error {
+	if err := uc.rp.DeleteEpisode(filmID, seasonNumber, episodeNumber); err != nil {
+		return err
+	}
+
+	uc.invalidate(filmID, true)
+	return nil
+}
+
+// ensureSeries проверяет, что фильм существует и это сериал или мини-сериал
+func (uc *SeriesUseCase) ensureSeries(filmID uint) error {
+	film, err := uc.films.GetFilmByID(filmID)
+	if err != nil {
+		if errors.Is(err, f.ErrFilmNotFound) {
+			return sr.ErrFilmNotFound
+		}
+		return err
+	}
+
+	if film.ContentType == f.ContentTypeMovie {
+		return sr.ErrNotSeries
+	}
+
+	return nil
+}
+
+// invalidate сбрасывает кэш сезонов и при изменении серий переиндексирует сериал,
+// чтобы он находился по названиям серий
+func (uc *SeriesUseCase) invalidate(filmID uint, reindex bool) {
+	if err := uc.rp.DeleteSeasonsFromCache(seasonsCacheKey(filmID)); err != nil {
+		uc.log.Error("failed to delete seasons from cache", "error", err)
+	}
+
+	if reindex {
+		if err := uc.films.ReindexFilm(filmID); err != nil {
+			uc.log.Error("failed to reindex series in Elasticsearch", "error", err, "filmID", filmID)
+		}
+	}
+}
+
+func seasonsCacheKey(filmID uint) string {
+	return fmt.Sprintf("film:%d:seasons", filmID)
+}
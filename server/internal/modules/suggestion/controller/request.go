@@ -0,0 +1,26 @@
+package controller
+
+import "encoding/json"
+
+// SubmitRequest - предложение правки: patch - тело JSON Merge Patch с теми же полями, что у PATCH /films/{id}
+// или PATCH /persons/{id}, comment - пояснение для редактора, например источник
+type SubmitRequest struct {
+	Patch   json.RawMessage `json:"patch" validate:"required"`
+	Comment string          `json:"comment" validate:"omitempty,max=2000"`
+}
+
+// ApproveRequest - необязательный комментарий редактора к принятой правке. Version - версия сущности,
+// с которой редактор сверял правку, если нет заголовка If-Match
+type ApproveRequest struct {
+	Comment string `json:"comment" validate:"omitempty,max=2000"`
+	Version int    `json:"version" validate:"omitempty,min=1"`
+}
+
+// RejectRequest - причина отказа, автор получит ее в письме
+type RejectRequest struct {
+	Comment string `json:"comment" validate:"required,max=2000"`
+}
+
+type CommentRequest struct {
+	Text string `json:"text" validate:"required,max=2000"`
+}
@@ -0,0 +1,480 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"log/slog"
+	"net/http"
+	sg "server/internal/modules/suggestion"
+	"server/pkg/lib/optimistic"
+	"server/pkg/lib/patch"
+	resp "server/pkg/lib/response"
+	"strconv"
+)
+
+type SuggestionController struct {
+	log      *slog.Logger
+	uc       sg.UseCase
+	validate *validator.Validate
+}
+
+func NewSuggestionController(log *slog.Logger, uc sg.UseCase) *SuggestionController {
+	return &SuggestionController{
+		log:      log,
+		uc:       uc,
+		validate: validator.New(),
+	}
+}
+
+// SubmitSuggestion - Предложение правки
+// @Summary Предложить правку фильма или персоны
+// @Description Сохраняет правку в очередь редакторов. patch - тело JSON Merge Patch с теми же полями и проверками, что у PATCH /films/{id} и PATCH /persons/{id}.
+// @Description Правка, которая ничего не меняет, не принимается. В ответе отличия от текущих данных
+// @Tags suggestion
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param entity path string true "Тип сущности: film, person"
+// @Param id path int true "Id сущности"
+// @Param json body SubmitRequest true "Правка и пояснение"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /suggestions/{entity}/{id} [post]
+func (c *SuggestionController) SubmitSuggestion(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "SubmitSuggestion")
+
+	userID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req SubmitRequest
+	if !c.decode(w, r, log, &req, false) {
+		return
+	}
+
+	suggestion, err := c.uc.Submit(r.Context(), userID, chi.URLParam(r, "entity"), id, req.Patch, req.Comment)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.Suggestion(suggestion))
+}
+
+// GetMySuggestions - Мои предложения
+// @Summary Получить свои предложения правок
+// @Description Возвращает предложения текущего пользователя, сначала новые
+// @Tags suggestion
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Статус: pending, approved, rejected"
+// @Param page query int false "Номер страницы"
+// @Param page_size query int false "Размер страницы (1-100, по умолчанию 50)"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /suggestions/my [get]
+func (c *SuggestionController) GetMySuggestions(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetMySuggestions")
+
+	userID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+	filter, ok := parseFilter(w, r)
+	if !ok {
+		return
+	}
+	filter.UserID = &userID
+
+	suggestions, err := c.uc.GetSuggestions(filter)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Suggestions(suggestions))
+}
+
+// GetMySuggestion - Мое предложение
+// @Summary Получить свое предложение правки
+// @Description Возвращает предложение текущего пользователя с отличиями от текущих данных и обсуждением
+// @Tags suggestion
+// @Produce json
+// @Security ApiKeyAuth
+// @Param suggestionId path int true "Id предложения"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /suggestions/my/{suggestionId} [get]
+func (c *SuggestionController) GetMySuggestion(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetMySuggestion")
+
+	userID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, "suggestionId")
+	if !ok {
+		return
+	}
+
+	suggestion, err := c.uc.GetSuggestion(id, &userID)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Suggestion(suggestion))
+}
+
+// CommentMySuggestion - Комментарий автора
+// @Summary Ответить в обсуждении своего предложения
+// @Tags suggestion
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param suggestionId path int true "Id предложения"
+// @Param json body CommentRequest true "Комментарий"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /suggestions/my/{suggestionId}/comments [post]
+func (c *SuggestionController) CommentMySuggestion(w http.ResponseWriter, r *http.Request) {
+	c.comment(w, r, c.log.With("method", "CommentMySuggestion"), false)
+}
+
+// GetQueue - Очередь предложений
+// @Summary Получить очередь предложений правок
+// @Description Возвращает предложения с отличиями от текущих данных. По умолчанию - ожидающие решения, сначала старые.
+// @Description Если сущность удалена или правка устарела, отличия пустые. Только для администраторов
+// @Tags suggestion
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Статус: pending (по умолчанию), approved, rejected"
+// @Param entity_type query string false "Тип сущности: film, person"
+// @Param page query int false "Номер страницы"
+// @Param page_size query int false "Размер страницы (1-100, по умолчанию 50)"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/suggestions [get]
+func (c *SuggestionController) GetQueue(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetQueue")
+
+	filter, ok := parseFilter(w, r)
+	if !ok {
+		return
+	}
+	if filter.Status == "" {
+		filter.Status = sg.StatusPending
+	}
+	filter.EntityType = r.URL.Query().Get("entity_type")
+
+	suggestions, err := c.uc.GetSuggestions(filter)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Suggestions(suggestions))
+}
+
+// GetSuggestion - Предложение
+// @Summary Получить предложение правки
+// @Description Возвращает предложение с отличиями от текущих данных и обсуждением. Только для администраторов
+// @Tags suggestion
+// @Produce json
+// @Security ApiKeyAuth
+// @Param suggestionId path int true "Id предложения"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/suggestions/{suggestionId} [get]
+func (c *SuggestionController) GetSuggestion(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetSuggestion")
+
+	id, ok := parseID(w, r, "suggestionId")
+	if !ok {
+		return
+	}
+
+	suggestion, err := c.uc.GetSuggestion(id, nil)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Suggestion(suggestion))
+}
+
+// ApproveSuggestion - Принять предложение
+// @Summary Принять предложение правки
+// @Description Применяет правку к текущим данным обычным частичным изменением: кэш, поиск, журнал и история версий обновляются как при правке редактором.
+// @Description Версия сущности, с которой редактор сверял правку (current_version), передается в If-Match или поле version:
+// @Description если сущность с тех пор изменилась, правка не применяется и возвращается 409.
+// @Description Если применить правку не удалось, предложение остается в очереди. Автор получает письмо. Только для администраторов
+// @Tags suggestion
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param suggestionId path int true "Id предложения"
+// @Param If-Match header string false "Версия сущности, с которой сверялась правка, например \"3\". Без заголовка берется поле version"
+// @Param json body ApproveRequest false "Комментарий для автора"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 428 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/suggestions/{suggestionId}/approve [post]
+func (c *SuggestionController) ApproveSuggestion(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "ApproveSuggestion")
+
+	reviewerID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, "suggestionId")
+	if !ok {
+		return
+	}
+
+	var req ApproveRequest
+	if !c.decode(w, r, log, &req, true) {
+		return
+	}
+
+	version, err := optimistic.Expected(r, req.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, optimistic.ErrVersionRequired):
+			w.WriteHeader(http.StatusPreconditionRequired)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		render.JSON(w, r, resp.Error(r, err.Error()))
+		return
+	}
+
+	suggestion, err := c.uc.Approve(r.Context(), id, reviewerID, version, req.Comment)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Suggestion(suggestion))
+}
+
+// RejectSuggestion - Отклонить предложение
+// @Summary Отклонить предложение правки
+// @Description Автор получает письмо с причиной отказа. Только для администраторов
+// @Tags suggestion
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param suggestionId path int true "Id предложения"
+// @Param json body RejectRequest true "Причина отказа"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/suggestions/{suggestionId}/reject [post]
+func (c *SuggestionController) RejectSuggestion(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "RejectSuggestion")
+
+	reviewerID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, "suggestionId")
+	if !ok {
+		return
+	}
+
+	var req RejectRequest
+	if !c.decode(w, r, log, &req, false) {
+		return
+	}
+
+	suggestion, err := c.uc.Reject(id, reviewerID, req.Comment)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Suggestion(suggestion))
+}
+
+// CommentSuggestion - Комментарий редактора
+// @Summary Прокомментировать предложение правки
+// @Description Добавляет комментарий в обсуждение, автор получает письмо. Только для администраторов
+// @Tags suggestion
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param suggestionId path int true "Id предложения"
+// @Param json body CommentRequest true "Комментарий"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/suggestions/{suggestionId}/comments [post]
+func (c *SuggestionController) CommentSuggestion(w http.ResponseWriter, r *http.Request) {
+	c.comment(w, r, c.log.With("method", "CommentSuggestion"), true)
+}
+
+func (c *SuggestionController) comment(w http.ResponseWriter, r *http.Request, log *slog.Logger, editor bool) {
+	userID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, "suggestionId")
+	if !ok {
+		return
+	}
+
+	var req CommentRequest
+	if !c.decode(w, r, log, &req, false) {
+		return
+	}
+
+	comment, err := c.uc.AddComment(id, userID, req.Text, editor)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.SuggestionComment(comment))
+}
+
+// decode читает и валидирует тело запроса, при ошибке сам отвечает клиенту. optional - пустое тело допустимо
+func (c *SuggestionController) decode(w http.ResponseWriter, r *http.Request, log *slog.Logger, req interface{}, optional bool) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil && !(optional && errors.Is(err, io.EOF)) {
+		log.Error("failed to decode request body", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
+		return false
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return false
+	}
+
+	return true
+}
+
+func (c *SuggestionController) writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	var validationErrs validator.ValidationErrors
+	var clearErr *patch.ClearError
+
+	switch {
+	case errors.As(err, &validationErrs) || errors.As(err, &clearErr):
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+	case errors.Is(err, sg.ErrUnknownEntity) || errors.Is(err, sg.ErrUnknownStatus) ||
+		errors.Is(err, sg.ErrNoChanges) || errors.Is(err, patch.ErrInvalidPatch):
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, sg.ErrSuggestionNotFound) || errors.Is(err, sg.ErrEntityNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, sg.ErrNotPending) || errors.Is(err, sg.ErrNotApplicable) ||
+		errors.Is(err, sg.ErrMissingReferences) || errors.Is(err, optimistic.ErrConflict):
+		w.WriteHeader(http.StatusConflict)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	default:
+		log.Error("suggestion request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, sg.ErrInternal.Error()))
+	}
+}
+
+func userIDFromContext(w http.ResponseWriter, r *http.Request, log *slog.Logger) (uint, bool) {
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		log.Error("can't get userId from context")
+		w.WriteHeader(http.StatusUnauthorized)
+		render.JSON(w, r, resp.Error(r, "unauthorized"))
+		return 0, false
+	}
+
+	return userID, true
+}
+
+func parseID(w http.ResponseWriter, r *http.Request, param string) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, param), 10, 32)
+	if err != nil || id == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid id"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseFilter читает статус и страницу из запроса, тип сущности - только в очереди редакторов
+func parseFilter(w http.ResponseWriter, r *http.Request) (*sg.SuggestionFilter, bool) {
+	query := r.URL.Query()
+	filter := &sg.SuggestionFilter{Status: query.Get("status")}
+
+	if page := query.Get("page"); page != "" {
+		pageNum, err := strconv.Atoi(page)
+		if err != nil || pageNum < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid page format, expected positive integer"))
+			return nil, false
+		}
+		filter.Page = pageNum
+	}
+
+	if pageSize := query.Get("page_size"); pageSize != "" {
+		size, err := strconv.Atoi(pageSize)
+		if err != nil || size < 1 || size > 100 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid page_size format, expected positive integer between 1 and 100"))
+			return nil, false
+		}
+		filter.PageSize = size
+	}
+
+	return filter, true
+}
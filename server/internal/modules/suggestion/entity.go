@@ -0,0 +1,95 @@
+package suggestion
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"server/pkg/lib/revision"
+	"time"
+)
+
+const (
+	StatusPending  = "pending" // ждет решения редактора
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Entities - сущности, к которым можно предложить правку
+var Entities = []string{revision.EntityFilm, revision.EntityPerson}
+
+// Statuses - статусы предложения для фильтра очереди
+var Statuses = []string{StatusPending, StatusApproved, StatusRejected}
+
+// SuggestionDTO - предложение правки. Patch - тело JSON Merge Patch по правилам PATCH сущности,
+// BaseVersion - версия, которую видел автор. EntityName, CurrentVersion, Changes (отличия от текущих
+// данных) и Conflict (сущность изменилась после отправки) заполняются при чтении, Comments - только
+// при запросе одного предложения
+type SuggestionDTO struct {
+	ID             uint
+	EntityType     string
+	EntityID       uint
+	EntityName     string
+	UserID         uint
+	Patch          json.RawMessage
+	BaseVersion    int
+	CurrentVersion int
+	Conflict       bool
+	Status         string
+	ReviewerID     *uint
+	ReviewedAt     *time.Time
+	AppliedVersion *int
+	CreatedAt      time.Time
+	Changes        []revision.Change
+	Comments       []*CommentDTO
+}
+
+// CommentDTO - комментарий автора или редактора к предложению
+type CommentDTO struct {
+	ID           uint
+	SuggestionID uint
+	UserID       *uint
+	Text         string
+	CreatedAt    time.Time
+}
+
+// SuggestionFilter - выборка предложений, пустые Status и EntityType и nil UserID выборку не ограничивают
+type SuggestionFilter struct {
+	Status     string
+	EntityType string
+	UserID     *uint
+	Page       int
+	PageSize   int
+}
+
+type Controller interface {
+	SubmitSuggestion(w http.ResponseWriter, r *http.Request)
+	GetMySuggestions(w http.ResponseWriter, r *http.Request)
+	GetMySuggestion(w http.ResponseWriter, r *http.Request)
+	CommentMySuggestion(w http.ResponseWriter, r *http.Request)
+
+	GetQueue(w http.ResponseWriter, r *http.Request)
+	GetSuggestion(w http.ResponseWriter, r *http.Request)
+	ApproveSuggestion(w http.ResponseWriter, r *http.Request)
+	RejectSuggestion(w http.ResponseWriter, r *http.Request)
+	CommentSuggestion(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	Submit(ctx context.Context, userID uint, entityType string, id uint, body json.RawMessage, comment string) (*SuggestionDTO, error)
+	GetSuggestions(filter *SuggestionFilter) ([]*SuggestionDTO, error)
+	GetSuggestion(id uint, userID *uint) (*SuggestionDTO, error)
+	Approve(ctx context.Context, id uint, reviewerID uint, expected int, comment string) (*SuggestionDTO, error)
+	Reject(id uint, reviewerID uint, comment string) (*SuggestionDTO, error)
+	AddComment(id uint, userID uint, text string, editor bool) (*CommentDTO, error)
+}
+
+type Repo interface {
+	//DB
+	CreateSuggestion(suggestion *SuggestionDTO) error
+	GetSuggestions(filter *SuggestionFilter) ([]*SuggestionDTO, error)
+	GetSuggestion(id uint) (*SuggestionDTO, error)
+	SetStatus(id uint, from string, to string, reviewerID *uint) error
+	SetAppliedVersion(id uint, version int) error
+	GetComments(id uint) ([]*CommentDTO, error)
+	AddComment(comment *CommentDTO) error
+}
@@ -0,0 +1,15 @@
+package suggestion
+
+import "errors"
+
+var (
+	ErrInternal           = errors.New("internal server error")
+	ErrUnknownEntity      = errors.New("unknown entity type, expected one of: film, person")
+	ErrUnknownStatus      = errors.New("unknown status, expected one of: pending, approved, rejected")
+	ErrSuggestionNotFound = errors.New("suggestion not found")
+	ErrEntityNotFound     = errors.New("entity not found")
+	ErrNoChanges          = errors.New("suggestion does not change anything")
+	ErrNotPending         = errors.New("suggestion has already been reviewed")
+	ErrNotApplicable      = errors.New("suggestion can no longer be applied to current data")
+	ErrMissingReferences  = errors.New("suggestion references deleted genres or persons")
+)
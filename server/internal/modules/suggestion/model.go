@@ -0,0 +1,62 @@
+package suggestion
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Suggestion struct {
+	SuggestionID   uint            `gorm:"primaryKey;column:suggestion_id;autoIncrement"`
+	EntityType     string          `gorm:"column:entity_type;type:varchar(32);not null"`
+	EntityID       uint            `gorm:"column:entity_id;not null"`
+	UserID         uint            `gorm:"column:user_id;not null"`
+	Patch          json.RawMessage `gorm:"column:patch;type:jsonb;not null"`
+	BaseVersion    int             `gorm:"column:base_version;not null"`
+	Status         string          `gorm:"column:status;type:varchar(16);not null;default:'pending'"`
+	ReviewerID     *uint           `gorm:"column:reviewer_id"`
+	ReviewedAt     *time.Time      `gorm:"column:reviewed_at"`
+	AppliedVersion *int            `gorm:"column:applied_version"`
+	CreatedAt      time.Time       `gorm:"column:created_at"`
+}
+
+func (Suggestion) TableName() string {
+	return "edit_suggestions"
+}
+
+func (s *Suggestion) ToDTO() *SuggestionDTO {
+	return &SuggestionDTO{
+		ID:             s.SuggestionID,
+		EntityType:     s.EntityType,
+		EntityID:       s.EntityID,
+		UserID:         s.UserID,
+		Patch:          s.Patch,
+		BaseVersion:    s.BaseVersion,
+		Status:         s.Status,
+		ReviewerID:     s.ReviewerID,
+		ReviewedAt:     s.ReviewedAt,
+		AppliedVersion: s.AppliedVersion,
+		CreatedAt:      s.CreatedAt,
+	}
+}
+
+type Comment struct {
+	CommentID    uint      `gorm:"primaryKey;column:comment_id;autoIncrement"`
+	SuggestionID uint      `gorm:"column:suggestion_id;not null"`
+	UserID       *uint     `gorm:"column:user_id"`
+	Text         string    `gorm:"column:text;type:text;not null"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
+
+func (Comment) TableName() string {
+	return "edit_suggestion_comments"
+}
+
+func (c *Comment) ToDTO() *CommentDTO {
+	return &CommentDTO{
+		ID:           c.CommentID,
+		SuggestionID: c.SuggestionID,
+		UserID:       c.UserID,
+		Text:         c.Text,
+		CreatedAt:    c.CreatedAt,
+	}
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"log/slog"
+	sg "server/internal/modules/suggestion"
+	"time"
+)
+
+type SuggestionDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewSuggestionDatabase(db *gorm.DB, log *slog.Logger) *SuggestionDatabase {
+	return &SuggestionDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+func (db *SuggestionDatabase) CreateSuggestion(suggestion *sg.SuggestionDTO) error {
+	row := &sg.Suggestion{
+		EntityType:  suggestion.EntityType,
+		EntityID:    suggestion.EntityID,
+		UserID:      suggestion.UserID,
+		Patch:       suggestion.Patch,
+		BaseVersion: suggestion.BaseVersion,
+		Status:      sg.StatusPending,
+		CreatedAt:   time.Now(),
+	}
+	if err := db.db.Create(row).Error; err != nil {
+		db.log.Error("failed to create suggestion", "error", err, "entityType", suggestion.EntityType, "id", suggestion.EntityID)
+		return sg.ErrInternal
+	}
+
+	suggestion.ID = row.SuggestionID
+	suggestion.Status = row.Status
+	suggestion.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// GetSuggestions возвращает предложения: ожидающие решения - сначала старые, как в очереди, остальные - сначала новые
+func (db *SuggestionDatabase) GetSuggestions(filter *sg.SuggestionFilter) ([]*sg.SuggestionDTO, error) {
+	query := db.db.Model(&sg.Suggestion{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Status == sg.StatusPending {
+		query = query.Order("created_at, suggestion_id")
+	} else {
+		query = query.Order("created_at DESC, suggestion_id DESC")
+	}
+
+	var rows []*sg.Suggestion
+	err := query.Limit(filter.PageSize).Offset((filter.Page - 1) * filter.PageSize).Find(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get suggestions", "error", err)
+		return nil, sg.ErrInternal
+	}
+
+	suggestions := make([]*sg.SuggestionDTO, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, row.ToDTO())
+	}
+	return suggestions, nil
+}
+
+func (db *SuggestionDatabase) GetSuggestion(id uint) (*sg.SuggestionDTO, error) {
+	var row sg.Suggestion
+	if err := db.db.First(&row, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, sg.ErrSuggestionNotFound
+		}
+		db.log.Error("failed to get suggestion", "error", err, "suggestionId", id)
+		return nil, sg.ErrInternal
+	}
+	return row.ToDTO(), nil
+}
+
+// SetStatus меняет статус, только если предложение еще в статусе from: два редактора не примут его дважды.
+// Возврат в pending сбрасывает редактора и время решения
+func (db *SuggestionDatabase) SetStatus(id uint, from string, to string, reviewerID *uint) error {
+	columns := map[string]interface{}{
+		"status":      to,
+		"reviewer_id": reviewerID,
+		"reviewed_at": nil,
+	}
+	if to != sg.StatusPending {
+		columns["reviewed_at"] = time.Now()
+	}
+
+	result := db.db.Model(&sg.Suggestion{}).
+		Where("suggestion_id = ? AND status = ?", id, from).
+		Updates(columns)
+	if result.Error != nil {
+		db.log.Error("failed to set suggestion status", "error", result.Error, "suggestionId", id, "status", to)
+		return sg.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return sg.ErrNotPending
+	}
+	return nil
+}
+
+func (db *SuggestionDatabase) SetAppliedVersion(id uint, version int) error {
+	err := db.db.Model(&sg.Suggestion{}).Where("suggestion_id = ?", id).Update("applied_version", version).Error
+	if err != nil {
+		db.log.Error("failed to set applied version", "error", err, "suggestionId", id)
+		return sg.ErrInternal
+	}
+	return nil
+}
+
+// GetComments возвращает обсуждение предложения по порядку
+func (db *SuggestionDatabase) GetComments(id uint) ([]*sg.CommentDTO, error) {
+	var rows []*sg.Comment
+	err := db.db.Where("suggestion_id = ?", id).Order("created_at, comment_id").Find(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get suggestion comments", "error", err, "suggestionId", id)
+		return nil, sg.ErrInternal
+	}
+
+	comments := make([]*sg.CommentDTO, 0, len(rows))
+	for _, row := range rows {
+		comments = append(comments, row.ToDTO())
+	}
+	return comments, nil
+}
+
+func (db *SuggestionDatabase) AddComment(comment *sg.CommentDTO) error {
+	row := &sg.Comment{
+		SuggestionID: comment.SuggestionID,
+		UserID:       comment.UserID,
+		Text:         comment.Text,
+		CreatedAt:    time.Now(),
+	}
+	if err := db.db.Create(row).Error; err != nil {
+		db.log.Error("failed to add suggestion comment", "error", err, "suggestionId", comment.SuggestionID)
+		return sg.ErrInternal
+	}
+
+	comment.ID = row.CommentID
+	comment.CreatedAt = row.CreatedAt
+	return nil
+}
@@ -0,0 +1,51 @@
+package repo
+
+import (
+	sg "server/internal/modules/suggestion"
+)
+
+type SuggestionDB interface {
+	CreateSuggestion(suggestion *sg.SuggestionDTO) error
+	GetSuggestions(filter *sg.SuggestionFilter) ([]*sg.SuggestionDTO, error)
+	GetSuggestion(id uint) (*sg.SuggestionDTO, error)
+	SetStatus(id uint, from string, to string, reviewerID *uint) error
+	SetAppliedVersion(id uint, version int) error
+	GetComments(id uint) ([]*sg.CommentDTO, error)
+	AddComment(comment *sg.CommentDTO) error
+}
+
+type Repo struct {
+	db SuggestionDB
+}
+
+func NewSuggestionRepo(db SuggestionDB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) CreateSuggestion(suggestion *sg.SuggestionDTO) error {
+	return r.db.CreateSuggestion(suggestion)
+}
+
+func (r *Repo) GetSuggestions(filter *sg.SuggestionFilter) ([]*sg.SuggestionDTO, error) {
+	return r.db.GetSuggestions(filter)
+}
+
+func (r *Repo) GetSuggestion(id uint) (*sg.SuggestionDTO, error) {
+	return r.db.GetSuggestion(id)
+}
+
+func (r *Repo) SetStatus(id uint, from string, to string, reviewerID *uint) error {
+	return r.db.SetStatus(id, from, to, reviewerID)
+}
+
+func (r *Repo) SetAppliedVersion(id uint, version int) error {
+	return r.db.SetAppliedVersion(id, version)
+}
+
+func (r *Repo) GetComments(id uint) ([]*sg.CommentDTO, error) {
+	return r.db.GetComments(id)
+}
+
+func (r *Repo) AddComment(comment *sg.CommentDTO) error {
+	return r.db.AddComment(comment)
+}
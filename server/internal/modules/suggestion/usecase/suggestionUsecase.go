@@ -0,0 +1,430 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	f "server/internal/modules/film"
+	per "server/internal/modules/person"
+	sg "server/internal/modules/suggestion"
+	"server/internal/modules/user/profile"
+	"server/pkg/lib/revision"
+	"slices"
+	"strings"
+)
+
+const (
+	defaultPageSize = 50
+	// notifyCommented - письмо о комментарии редактора, а не о решении
+	notifyCommented = "commented"
+)
+
+// FilmService - текущие данные фильма и применение принятой правки обычным частичным изменением (модуль film)
+type FilmService interface {
+	GetFilmByID(id uint) (*f.FilmDTO, error)
+	PatchFilm(ctx context.Context, patch *f.FilmPatch) (*f.FilmDTO, error)
+}
+
+// PersonService - то же для персон (модуль person)
+type PersonService interface {
+	GetPerson(personId uint) (*per.PersonDTO, error)
+	PatchPerson(ctx context.Context, patch *per.PersonPatch) (*per.PersonDTO, error)
+}
+
+// ProfileService - почта автора предложения для уведомлений (модуль profile)
+type ProfileService interface {
+	GetUser(userId uint) (*profile.UserProfile, error)
+}
+
+type EmailSenderService interface {
+	SendSuggestionReviewed(email string, subject string, status string, comment string) error
+}
+
+// FilmPatchParser и PersonPatchParser разбирают тело правки по правилам PATCH сущности: те же поля,
+// проверки и нормализация (контроллеры film и person)
+type FilmPatchParser func(id uint, body []byte) (*f.FilmPatch, error)
+
+type PersonPatchParser func(personId uint, body []byte) (*per.PersonPatch, error)
+
+type SuggestionUseCase struct {
+	log         *slog.Logger
+	rp          sg.Repo
+	films       FilmService
+	persons     PersonService
+	profiles    ProfileService
+	ess         EmailSenderService
+	parseFilm   FilmPatchParser
+	parsePerson PersonPatchParser
+}
+
+func NewSuggestionUseCase(log *slog.Logger, rp sg.Repo, films FilmService, persons PersonService, profiles ProfileService,
+	ess EmailSenderService, parseFilm FilmPatchParser, parsePerson PersonPatchParser) *SuggestionUseCase {
+	return &SuggestionUseCase{
+		log:         log,
+		rp:          rp,
+		films:       films,
+		persons:     persons,
+		profiles:    profiles,
+		ess:         ess,
+		parseFilm:   parseFilm,
+		parsePerson: parsePerson,
+	}
+}
+
+// Submit сохраняет предложение правки. Тело проверяется как PATCH сущности сразу, чтобы в очередь
+// не попадали правки, которые нельзя применить, и правки, которые ничего не меняют
+func (uc *SuggestionUseCase) Submit(ctx context.Context, userID uint, entityType string, id uint, body json.RawMessage, comment string) (*sg.SuggestionDTO, error) {
+	if !slices.Contains(sg.Entities, entityType) {
+		return nil, sg.ErrUnknownEntity
+	}
+
+	suggestion := &sg.SuggestionDTO{
+		EntityType: entityType,
+		EntityID:   id,
+		UserID:     userID,
+		Patch:      body,
+	}
+	if err := uc.preview(suggestion); err != nil {
+		return nil, err
+	}
+	if len(suggestion.Changes) == 0 {
+		return nil, sg.ErrNoChanges
+	}
+
+	suggestion.BaseVersion = suggestion.CurrentVersion
+	if err := uc.rp.CreateSuggestion(suggestion); err != nil {
+		return nil, err
+	}
+
+	if comment = strings.TrimSpace(comment); comment != "" {
+		c := &sg.CommentDTO{SuggestionID: suggestion.ID, UserID: &userID, Text: comment}
+		if err := uc.rp.AddComment(c); err != nil {
+			return nil, err
+		}
+		suggestion.Comments = []*sg.CommentDTO{c}
+	}
+
+	return suggestion, nil
+}
+
+// GetSuggestions возвращает предложения вместе с отличиями от текущих данных
+func (uc *SuggestionUseCase) GetSuggestions(filter *sg.SuggestionFilter) ([]*sg.SuggestionDTO, error) {
+	if filter.EntityType != "" && !slices.Contains(sg.Entities, filter.EntityType) {
+		return nil, sg.ErrUnknownEntity
+	}
+	if filter.Status != "" && !slices.Contains(sg.Statuses, filter.Status) {
+		return nil, sg.ErrUnknownStatus
+	}
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize == 0 {
+		filter.PageSize = defaultPageSize
+	}
+
+	suggestions, err := uc.rp.GetSuggestions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, suggestion := range suggestions {
+		uc.previewQuietly(suggestion)
+	}
+	return suggestions, nil
+}
+
+// GetSuggestion возвращает предложение с отличиями и обсуждением. С userID - только предложение этого пользователя
+func (uc *SuggestionUseCase) GetSuggestion(id uint, userID *uint) (*sg.SuggestionDTO, error) {
+	suggestion, err := uc.rp.GetSuggestion(id)
+	if err != nil {
+		return nil, err
+	}
+	if userID != nil && suggestion.UserID != *userID {
+		return nil, sg.ErrSuggestionNotFound
+	}
+
+	uc.previewQuietly(suggestion)
+
+	suggestion.Comments, err = uc.rp.GetComments(id)
+	if err != nil {
+		return nil, err
+	}
+	return suggestion, nil
+}
+
+// Approve применяет правку обычным частичным изменением: проверки, кэш, поиск, журнал и история версий
+// работают как при правке редактором. expected - версия сущности, с которой редактор сверял правку:
+// если сущность с тех пор изменилась, возвращается optimistic.ErrConflict. Если правку применить
+// не удалось, предложение возвращается в очередь
+func (uc *SuggestionUseCase) Approve(ctx context.Context, id uint, reviewerID uint, expected int, comment string) (*sg.SuggestionDTO, error) {
+	suggestion, err := uc.rp.GetSuggestion(id)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.Status != sg.StatusPending {
+		return nil, sg.ErrNotPending
+	}
+
+	if err := uc.rp.SetStatus(id, sg.StatusPending, sg.StatusApproved, &reviewerID); err != nil {
+		return nil, err
+	}
+
+	version, err := uc.apply(ctx, suggestion, expected)
+	if err != nil {
+		if reopenErr := uc.rp.SetStatus(id, sg.StatusApproved, sg.StatusPending, nil); reopenErr != nil {
+			uc.log.Error("failed to return suggestion to queue", "error", reopenErr, "suggestionId", id)
+		}
+		return nil, err
+	}
+
+	if err := uc.rp.SetAppliedVersion(id, version); err != nil {
+		return nil, err
+	}
+
+	return uc.finishReview(id, reviewerID, sg.StatusApproved, comment)
+}
+
+// Reject отклоняет предложение, comment - причина для автора
+func (uc *SuggestionUseCase) Reject(id uint, reviewerID uint, comment string) (*sg.SuggestionDTO, error) {
+	suggestion, err := uc.rp.GetSuggestion(id)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.Status != sg.StatusPending {
+		return nil, sg.ErrNotPending
+	}
+
+	if err := uc.rp.SetStatus(id, sg.StatusPending, sg.StatusRejected, &reviewerID); err != nil {
+		return nil, err
+	}
+
+	return uc.finishReview(id, reviewerID, sg.StatusRejected, comment)
+}
+
+// finishReview сохраняет комментарий редактора, уведомляет автора и возвращает предложение после решения
+func (uc *SuggestionUseCase) finishReview(id uint, reviewerID uint, status string, comment string) (*sg.SuggestionDTO, error) {
+	comment = strings.TrimSpace(comment)
+	if comment != "" {
+		if err := uc.rp.AddComment(&sg.CommentDTO{SuggestionID: id, UserID: &reviewerID, Text: comment}); err != nil {
+			return nil, err
+		}
+	}
+
+	suggestion, err := uc.GetSuggestion(id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.notify(suggestion.UserID, suggestion.EntityName, status, comment)
+
+	return suggestion, nil
+}
+
+// AddComment добавляет комментарий к обсуждению. Автор комментирует только свое предложение,
+// о комментарии редактора автору приходит письмо
+func (uc *SuggestionUseCase) AddComment(id uint, userID uint, text string, editor bool) (*sg.CommentDTO, error) {
+	suggestion, err := uc.rp.GetSuggestion(id)
+	if err != nil {
+		return nil, err
+	}
+	if !editor && suggestion.UserID != userID {
+		return nil, sg.ErrSuggestionNotFound
+	}
+
+	comment := &sg.CommentDTO{SuggestionID: id, UserID: &userID, Text: strings.TrimSpace(text)}
+	if err := uc.rp.AddComment(comment); err != nil {
+		return nil, err
+	}
+
+	if editor && suggestion.UserID != userID {
+		uc.previewQuietly(suggestion)
+		uc.notify(suggestion.UserID, suggestion.EntityName, notifyCommented, comment.Text)
+	}
+
+	return comment, nil
+}
+
+// notify отправляет автору письмо в фоне, ошибки только пишутся в лог
+func (uc *SuggestionUseCase) notify(userID uint, name string, status string, comment string) {
+	user, err := uc.profiles.GetUser(userID)
+	if err != nil {
+		uc.log.Error("failed to get suggestion author", "error", err, "userId", userID)
+		return
+	}
+	if user.Email == nil || *user.Email == "" {
+		return
+	}
+
+	email := *user.Email
+	go func() {
+		if err := uc.ess.SendSuggestionReviewed(email, name, status, comment); err != nil {
+			uc.log.Error("send suggestion notification failed", slog.String("email", email), slog.String("error", err.Error()))
+		}
+	}()
+}
+
+// apply применяет правку, если версия сущности все еще expected, и возвращает новую версию
+func (uc *SuggestionUseCase) apply(ctx context.Context, suggestion *sg.SuggestionDTO, expected int) (int, error) {
+	switch suggestion.EntityType {
+	case revision.EntityFilm:
+		filmPatch, err := uc.parseFilm(suggestion.EntityID, suggestion.Patch)
+		if err != nil {
+			uc.log.Error("failed to parse stored film suggestion", "error", err, "suggestionId", suggestion.ID)
+			return 0, sg.ErrNotApplicable
+		}
+		filmPatch.Version = expected
+
+		film, err := uc.films.PatchFilm(ctx, filmPatch)
+		if err != nil {
+			return 0, filmError(err)
+		}
+		return film.Version, nil
+	default:
+		personPatch, err := uc.parsePerson(suggestion.EntityID, suggestion.Patch)
+		if err != nil {
+			uc.log.Error("failed to parse stored person suggestion", "error", err, "suggestionId", suggestion.ID)
+			return 0, sg.ErrNotApplicable
+		}
+		personPatch.Version = expected
+
+		person, err := uc.persons.PatchPerson(ctx, personPatch)
+		if err != nil {
+			return 0, personError(err)
+		}
+		return person.Version, nil
+	}
+}
+
+func filmError(err error) error {
+	switch {
+	case errors.Is(err, f.ErrFilmNotFound):
+		return sg.ErrEntityNotFound
+	case errors.Is(err, f.ErrGenreNotFound) || errors.Is(err, f.ErrPersonNotFound):
+		return sg.ErrMissingReferences
+	}
+	return err
+}
+
+func personError(err error) error {
+	if errors.Is(err, per.ErrPersonNotFound) {
+		return sg.ErrEntityNotFound
+	}
+	return err
+}
+
+// previewQuietly заполняет отличия для просмотра. Сущность могла быть удалена в корзину, а правка - устареть,
+// тогда предложение показывается без отличий
+func (uc *SuggestionUseCase) previewQuietly(suggestion *sg.SuggestionDTO) {
+	if err := uc.preview(suggestion); err != nil && !errors.Is(err, sg.ErrEntityNotFound) {
+		uc.log.Warn("failed to preview suggestion", "error", err, "suggestionId", suggestion.ID)
+	}
+}
+
+// preview применяет правку к копии текущих данных и заполняет название сущности, ее текущую версию
+// и отличия. Ошибка разбора тела возвращается как есть, чтобы контроллер показал ошибки полей
+func (uc *SuggestionUseCase) preview(suggestion *sg.SuggestionDTO) error {
+	var current, proposed interface{}
+
+	switch suggestion.EntityType {
+	case revision.EntityFilm:
+		filmPatch, err := uc.parseFilm(suggestion.EntityID, suggestion.Patch)
+		if err != nil {
+			return err
+		}
+		film, err := uc.films.GetFilmByID(suggestion.EntityID)
+		if err != nil {
+			return filmError(err)
+		}
+		suggestion.EntityName, suggestion.CurrentVersion = film.Title, film.Version
+		current, proposed = film, applyFilmPatch(*film, filmPatch)
+	default:
+		personPatch, err := uc.parsePerson(suggestion.EntityID, suggestion.Patch)
+		if err != nil {
+			return err
+		}
+		person, err := uc.persons.GetPerson(suggestion.EntityID)
+		if err != nil {
+			return personError(err)
+		}
+		suggestion.EntityName, suggestion.CurrentVersion = person.Name, person.Version
+		current, proposed = person, applyPersonPatch(*person, personPatch)
+	}
+
+	// автор ожидающей правки видел более старую версию: ее нужно сверить с изменениями, сделанными после нее
+	suggestion.Conflict = suggestion.Status == sg.StatusPending && suggestion.BaseVersion < suggestion.CurrentVersion
+
+	before, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	after, err := json.Marshal(proposed)
+	if err != nil {
+		return err
+	}
+
+	suggestion.Changes, err = revision.Diff(before, after, revision.Ignore[suggestion.EntityType]...)
+	return err
+}
+
+// applyFilmPatch повторяет на копии фильма то, что PATCH делает в базе. Срезы копии не разделяются
+// с исходным фильмом из кэша
+func applyFilmPatch(film f.FilmDTO, p *f.FilmPatch) *f.FilmDTO {
+	p.ContentType.Apply(&film.ContentType)
+	p.Title.Apply(&film.Title)
+	p.Synopsis.Apply(&film.Synopsis)
+	p.ReleaseDate.Apply(&film.ReleaseDate)
+	if p.Runtime.Set {
+		// null - 0 минут, значение приводится к формату FilmDTO.Runtime
+		film.Runtime = f.MinutesToDurationString(f.DurationStringToMinutes(p.Runtime.Value))
+	}
+
+	p.OriginalTitle.Apply(&film.OriginalTitle)
+	p.AltTitles.Apply(&film.AltTitles)
+	p.Tagline.Apply(&film.Tagline)
+	p.Countries.Apply(&film.Countries)
+	p.Languages.Apply(&film.Languages)
+	p.AgeRating.Apply(&film.AgeRating)
+	p.Budget.Apply(&film.Budget)
+	p.BoxOffice.Apply(&film.BoxOffice)
+	p.Currency.Apply(&film.Currency)
+	p.IMDbID.Apply(&film.ExternalIDs.IMDb)
+	p.KinopoiskID.Apply(&film.ExternalIDs.Kinopoisk)
+	p.TMDBID.Apply(&film.ExternalIDs.TMDB)
+
+	genreIDs := slices.Clone(film.GenreIDs)
+	p.GenreIDs.Apply(&genreIDs)
+	for _, id := range p.AddGenreIDs {
+		if !slices.Contains(genreIDs, id) {
+			genreIDs = append(genreIDs, id)
+		}
+	}
+	genreIDs = slices.DeleteFunc(genreIDs, func(id uint) bool {
+		return slices.Contains(p.RemoveGenreIDs, id)
+	})
+	film.GenreIDs = genreIDs
+
+	credits := slices.Clone(film.Credits)
+	p.Credits.Apply(&credits)
+	for _, credit := range p.AddCredits {
+		credits = slices.DeleteFunc(credits, func(c f.CreditDTO) bool {
+			return c.PersonID == credit.PersonID && c.Role == credit.Role
+		})
+		credits = append(credits, credit)
+	}
+	credits = slices.DeleteFunc(credits, func(c f.CreditDTO) bool {
+		return slices.ContainsFunc(p.RemoveCredits, func(removed f.CreditDTO) bool {
+			return removed.PersonID == c.PersonID && (removed.Role == "" || removed.Role == c.Role)
+		})
+	})
+	film.Credits = credits
+
+	return &film
+}
+
+func applyPersonPatch(person per.PersonDTO, p *per.PersonPatch) *per.PersonDTO {
+	p.Name.Apply(&person.Name)
+	p.Department.Apply(&person.Department)
+	p.WikiUrl.Apply(&person.WikiUrl)
+	return &person
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"github.com/go-playground/validator/v10"
+	"regexp"
+)
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Slug string `json:"slug" validate:"omitempty,max=100,slug"`
+}
+
+type SuggestTagRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// VoteRequest - голос за то, подходит ли тег фильму: 1 - подходит, -1 - не подходит
+type VoteRequest struct {
+	Vote int `json:"vote" validate:"required,oneof=-1 1"`
+}
+
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func validateSlug(fl validator.FieldLevel) bool {
+	return slugRegexp.MatchString(fl.Field().String())
+}
@@ -0,0 +1,472 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	tg "server/internal/modules/tag"
+	resp "server/pkg/lib/response"
+	"strconv"
+)
+
+const (
+	defaultSuggestions = 50
+	maxSuggestions     = 200
+)
+
+type Controller struct {
+	log      *slog.Logger
+	uc       tg.UseCase
+	validate *validator.Validate
+}
+
+func NewTagController(log *slog.Logger, uc tg.UseCase) *Controller {
+	validate := validator.New()
+	if err := validate.RegisterValidation("slug", validateSlug); err != nil {
+		log.Error("failed to register slug validator", "error", err)
+	}
+
+	return &Controller{
+		log:      log,
+		uc:       uc,
+		validate: validate,
+	}
+}
+
+// GetTags - Получение списка тегов
+// @Summary Получить список тегов
+// @Description Возвращает одобренные теги с количеством фильмов, можно искать по подстроке в названии
+// @Tags tag
+// @Produce json
+// @Param query query string false "Поиск по названию"
+// @Success 200 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags [get]
+func (c *Controller) GetTags(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetTags")
+
+	tags, err := c.uc.GetTags(r.URL.Query().Get("query"))
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Tags(tags))
+}
+
+// CreateTag - Создание тега
+// @Summary Создать тег
+// @Description Создает одобренный тег. Если slug не указан, он строится из названия
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param json body TagRequest true "Данные тега"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags [post]
+func (c *Controller) CreateTag(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "CreateTag")
+
+	var req TagRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	tag := &tg.TagDTO{Name: req.Name, Slug: req.Slug}
+	if err := c.uc.CreateTag(tag); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.Tags(tag))
+}
+
+// UpdateTag - Обновление тега
+// @Summary Обновить тег
+// @Description Переименовывает тег, фильмы с тегом переиндексируются
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param id path string true "Id тега"
+// @Param json body TagRequest true "Данные тега"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags/{id} [put]
+func (c *Controller) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "UpdateTag")
+
+	id, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req TagRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	tag := &tg.TagDTO{ID: id, Name: req.Name, Slug: req.Slug}
+	if err := c.uc.UpdateTag(tag); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// DeleteTag - Удаление тега
+// @Summary Удалить тег
+// @Description Удаляет тег у всех фильмов вместе с голосами
+// @Tags tag
+// @Produce json
+// @Param id path string true "Id тега"
+// @Success 204 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags/{id} [delete]
+func (c *Controller) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DeleteTag")
+
+	id, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteTag(id); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	render.JSON(w, r, resp.OK())
+}
+
+// GetSuggestions - Очередь модерации тегов
+// @Summary Получить предложенные теги
+// @Description Возвращает теги, предложенные пользователями для фильмов и ожидающие одобрения, самые старые первыми
+// @Tags tag
+// @Produce json
+// @Param limit query int false "Количество предложений (1-200, по умолчанию 50)"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags/suggestions [get]
+func (c *Controller) GetSuggestions(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetSuggestions")
+
+	limit := defaultSuggestions
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxSuggestions {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid limit format, expected positive integer between 1 and 200"))
+			return
+		}
+	}
+
+	suggestions, err := c.uc.GetSuggestions(limit)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.TagSuggestions(suggestions))
+}
+
+// GetFilmTagCloud - Облако тегов фильма
+// @Summary Получить облако тегов фильма
+// @Description Возвращает одобренные теги фильма с голосами пользователей и весом от 1 до 5, самые релевантные первыми
+// @Tags tag
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/tags [get]
+func (c *Controller) GetFilmTagCloud(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetFilmTagCloud")
+
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	tags, err := c.uc.GetFilmTagCloud(filmID)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.FilmTagCloud(tags))
+}
+
+// SuggestTag - Предложение тега
+// @Summary Предложить тег для фильма
+// @Description Предлагает фильму существующий или новый тег. Тег появится у фильма после одобрения администратором,
+// @Description предложение сразу засчитывается как голос "за"
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param json body SuggestTagRequest true "Название тега"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/tags [post]
+// @Security ApiKeyAuth
+func (c *Controller) SuggestTag(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "SuggestTag")
+
+	userID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req SuggestTagRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	tag, err := c.uc.SuggestTag(filmID, userID, req.Name)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.Tags(tag))
+}
+
+// ApproveFilmTag - Одобрение тега фильма
+// @Summary Добавить или одобрить тег фильма
+// @Description Одобряет предложенный пользователем тег или сразу добавляет тег фильму
+// @Tags tag
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param tag_id path string true "Id тега"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/tags/{tag_id} [put]
+func (c *Controller) ApproveFilmTag(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "ApproveFilmTag")
+
+	filmID, tagID, ok := parseFilmTag(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.ApproveFilmTag(filmID, tagID); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// DeleteFilmTag - Удаление тега фильма
+// @Summary Убрать тег фильма или отклонить предложение
+// @Description Убирает тег у фильма. Новый тег, который так и не был одобрен, удаляется вместе с предложением
+// @Tags tag
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param tag_id path string true "Id тега"
+// @Success 204 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/tags/{tag_id} [delete]
+func (c *Controller) DeleteFilmTag(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DeleteFilmTag")
+
+	filmID, tagID, ok := parseFilmTag(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteFilmTag(filmID, tagID); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	render.JSON(w, r, resp.OK())
+}
+
+// VoteFilmTag - Голос за тег фильма
+// @Summary Проголосовать за тег фильма
+// @Description Голос за то, насколько тег подходит фильму: 1 - подходит, -1 - не подходит. Повторный голос заменяет предыдущий
+// @Tags tag
+// @Accept json
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param tag_id path string true "Id тега"
+// @Param json body VoteRequest true "Голос"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/tags/{tag_id}/vote [put]
+// @Security ApiKeyAuth
+func (c *Controller) VoteFilmTag(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "VoteFilmTag")
+
+	userID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+
+	filmID, tagID, ok := parseFilmTag(w, r)
+	if !ok {
+		return
+	}
+
+	var req VoteRequest
+	if !c.decode(w, r, log, &req) {
+		return
+	}
+
+	if err := c.uc.VoteFilmTag(filmID, tagID, userID, req.Vote); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// DeleteFilmTagVote - Отмена голоса за тег фильма
+// @Summary Отменить голос за тег фильма
+// @Tags tag
+// @Produce json
+// @Param id path string true "FilmId фильма"
+// @Param tag_id path string true "Id тега"
+// @Success 204 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /films/{id}/tags/{tag_id}/vote [delete]
+// @Security ApiKeyAuth
+func (c *Controller) DeleteFilmTagVote(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DeleteFilmTagVote")
+
+	userID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+
+	filmID, tagID, ok := parseFilmTag(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteFilmTagVote(filmID, tagID, userID); err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	render.JSON(w, r, resp.OK())
+}
+
+// decode читает и валидирует тело запроса, при ошибке сам отвечает клиенту
+func (c *Controller) decode(w http.ResponseWriter, r *http.Request, log *slog.Logger, req interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		log.Error("failed to decode request body", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
+		return false
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return false
+	}
+
+	return true
+}
+
+func (c *Controller) writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, tg.ErrFilmNotFound) || errors.Is(err, tg.ErrTagNotFound) ||
+		errors.Is(err, tg.ErrFilmTagNotFound) || errors.Is(err, tg.ErrVoteNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, tg.ErrInvalidTagName):
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, tg.ErrTagExists) || errors.Is(err, tg.ErrFilmTagExists):
+		w.WriteHeader(http.StatusConflict)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	default:
+		log.Error("tag request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, tg.ErrInternal.Error()))
+	}
+}
+
+func userIDFromContext(w http.ResponseWriter, r *http.Request, log *slog.Logger) (uint, bool) {
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		log.Error("can't get userId from context")
+		w.WriteHeader(http.StatusUnauthorized)
+		render.JSON(w, r, resp.Error(r, "unauthorized"))
+		return 0, false
+	}
+
+	return userID, true
+}
+
+func parseID(w http.ResponseWriter, r *http.Request, param string) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, param), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid "+param))
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func parseFilmTag(w http.ResponseWriter, r *http.Request) (uint, uint, bool) {
+	filmID, ok := parseID(w, r, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	tagID, ok := parseID(w, r, "tag_id")
+	if !ok {
+		return 0, 0, false
+	}
+
+	return filmID, tagID, true
+}
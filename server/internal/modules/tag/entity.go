@@ -0,0 +1,95 @@
+package tag
+
+import (
+	"net/http"
+	"time"
+)
+
+// Статусы тегов и тегов фильмов
+const (
+	StatusApproved = "approved"
+	StatusPending  = "pending" // предложен пользователем, ждет модерации
+)
+
+type TagDTO struct {
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	Status     string    `json:"status"`
+	CreatedBy  *uint     `json:"created_by"` // nil - создан администратором или автор удален
+	FilmsCount int       `json:"films_count"`
+	CreateAt   time.Time `json:"create_at"`
+}
+
+// FilmTagDTO - тег в облаке тегов фильма. Score - сумма голосов, Weight - размер в облаке от 1 до 5
+type FilmTagDTO struct {
+	TagID     uint   `json:"tag_id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Score     int    `json:"score"`
+	Upvotes   int    `json:"upvotes"`
+	Downvotes int    `json:"downvotes"`
+	Weight    int    `json:"weight"`
+}
+
+// SuggestionDTO - тег, предложенный пользователем для фильма и ожидающий модерации
+type SuggestionDTO struct {
+	FilmID      uint      `json:"film_id"`
+	FilmTitle   string    `json:"film_title"`
+	TagID       uint      `json:"tag_id"`
+	TagName     string    `json:"tag_name"`
+	TagSlug     string    `json:"tag_slug"`
+	TagStatus   string    `json:"tag_status"` // pending - новый тег, появится в каталоге после одобрения
+	SuggestedBy *uint     `json:"suggested_by"`
+	Score       int       `json:"score"`
+	CreateAt    time.Time `json:"create_at"`
+}
+
+type Controller interface {
+	GetTags(w http.ResponseWriter, r *http.Request)
+	CreateTag(w http.ResponseWriter, r *http.Request)
+	UpdateTag(w http.ResponseWriter, r *http.Request)
+	DeleteTag(w http.ResponseWriter, r *http.Request)
+	GetSuggestions(w http.ResponseWriter, r *http.Request)
+	GetFilmTagCloud(w http.ResponseWriter, r *http.Request)
+	SuggestTag(w http.ResponseWriter, r *http.Request)
+	ApproveFilmTag(w http.ResponseWriter, r *http.Request)
+	DeleteFilmTag(w http.ResponseWriter, r *http.Request)
+	VoteFilmTag(w http.ResponseWriter, r *http.Request)
+	DeleteFilmTagVote(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	GetTags(query string) ([]*TagDTO, error)
+	CreateTag(tag *TagDTO) error
+	UpdateTag(tag *TagDTO) error
+	DeleteTag(id uint) error
+	GetSuggestions(limit int) ([]*SuggestionDTO, error)
+	GetFilmTagCloud(filmID uint) ([]*FilmTagDTO, error)
+	SuggestTag(filmID uint, userID uint, name string) (*TagDTO, error)
+	ApproveFilmTag(filmID uint, tagID uint) error
+	DeleteFilmTag(filmID uint, tagID uint) error
+	VoteFilmTag(filmID uint, tagID uint, userID uint, vote int) error
+	DeleteFilmTagVote(filmID uint, tagID uint, userID uint) error
+}
+
+type Repo interface {
+	//DB
+	GetTags(query string) ([]*TagDTO, error)
+	CreateTag(tag *TagDTO) error
+	UpdateTag(tag *TagDTO) error
+	DeleteTag(id uint) error
+	GetTagFilmIDs(tagID uint) ([]uint, error)
+	GetSuggestions(limit int) ([]*SuggestionDTO, error)
+	GetFilmTags(filmID uint) ([]*FilmTagDTO, error)
+	SuggestTag(filmID uint, userID uint, tag *TagDTO) error
+	ApproveFilmTag(filmID uint, tagID uint) error
+	DeleteFilmTag(filmID uint, tagID uint) error
+	SetVote(filmID uint, tagID uint, userID uint, vote int) error
+	DeleteVote(filmID uint, tagID uint, userID uint) error
+
+	//Cache
+	GetFilmTagsFromCache(key string) ([]*FilmTagDTO, error)
+	SetFilmTagsToCache(key string, tags []*FilmTagDTO, ttl time.Duration) error
+	DeleteFilmTagsFromCache(key string) error
+}
@@ -0,0 +1,15 @@
+package tag
+
+import "errors"
+
+var (
+	ErrInternal        = errors.New("internal server error")
+	ErrMissCache       = errors.New("miss cache error")
+	ErrFilmNotFound    = errors.New("film not found")
+	ErrTagNotFound     = errors.New("tag not found")
+	ErrTagExists       = errors.New("tag already exists")
+	ErrInvalidTagName  = errors.New("tag name must contain letters or digits")
+	ErrFilmTagNotFound = errors.New("film has no such tag")
+	ErrFilmTagExists   = errors.New("tag already added to film")
+	ErrVoteNotFound    = errors.New("vote not found")
+)
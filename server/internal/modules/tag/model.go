@@ -0,0 +1,109 @@
+package tag
+
+import "time"
+
+type Tag struct {
+	TagID     uint      `gorm:"primaryKey;column:tag_id;autoIncrement"`
+	Name      string    `gorm:"column:name;type:varchar(100);unique;not null"`
+	Slug      string    `gorm:"column:slug;type:varchar(100);unique;not null"`
+	Status    string    `gorm:"column:status;type:varchar(16);not null;default:'approved'"`
+	CreatedBy *uint     `gorm:"column:created_by"`
+	CreatedAt time.Time `gorm:"column:create_at"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+type FilmTag struct {
+	FilmID      uint      `gorm:"primaryKey;column:film_id"`
+	TagID       uint      `gorm:"primaryKey;column:tag_id"`
+	Status      string    `gorm:"column:status;type:varchar(16);not null;default:'approved'"`
+	SuggestedBy *uint     `gorm:"column:suggested_by"`
+	CreatedAt   time.Time `gorm:"column:create_at"`
+}
+
+func (FilmTag) TableName() string {
+	return "film_tags"
+}
+
+type FilmTagVote struct {
+	FilmID uint `gorm:"primaryKey;column:film_id"`
+	TagID  uint `gorm:"primaryKey;column:tag_id"`
+	UserID uint `gorm:"primaryKey;column:user_id"`
+	Vote   int  `gorm:"column:vote;not null"`
+}
+
+func (FilmTagVote) TableName() string {
+	return "film_tag_votes"
+}
+
+// TagRow - тег с количеством фильмов, результат GetTags
+type TagRow struct {
+	Tag        `gorm:"embedded"`
+	FilmsCount int `gorm:"column:films_count"`
+}
+
+// FilmTagRow - тег фильма с голосами, результат GetFilmTags
+type FilmTagRow struct {
+	TagID     uint   `gorm:"column:tag_id"`
+	Name      string `gorm:"column:name"`
+	Slug      string `gorm:"column:slug"`
+	Upvotes   int    `gorm:"column:upvotes"`
+	Downvotes int    `gorm:"column:downvotes"`
+}
+
+// SuggestionRow - результат GetSuggestions
+type SuggestionRow struct {
+	FilmID      uint      `gorm:"column:film_id"`
+	FilmTitle   string    `gorm:"column:film_title"`
+	TagID       uint      `gorm:"column:tag_id"`
+	TagName     string    `gorm:"column:tag_name"`
+	TagSlug     string    `gorm:"column:tag_slug"`
+	TagStatus   string    `gorm:"column:tag_status"`
+	SuggestedBy *uint     `gorm:"column:suggested_by"`
+	Score       int       `gorm:"column:score"`
+	CreatedAt   time.Time `gorm:"column:create_at"`
+}
+
+func (t *Tag) ToDTO() *TagDTO {
+	return &TagDTO{
+		ID:        t.TagID,
+		Name:      t.Name,
+		Slug:      t.Slug,
+		Status:    t.Status,
+		CreatedBy: t.CreatedBy,
+		CreateAt:  t.CreatedAt,
+	}
+}
+
+func (r *TagRow) ToDTO() *TagDTO {
+	dto := r.Tag.ToDTO()
+	dto.FilmsCount = r.FilmsCount
+	return dto
+}
+
+func (r *FilmTagRow) ToDTO() *FilmTagDTO {
+	return &FilmTagDTO{
+		TagID:     r.TagID,
+		Name:      r.Name,
+		Slug:      r.Slug,
+		Score:     r.Upvotes - r.Downvotes,
+		Upvotes:   r.Upvotes,
+		Downvotes: r.Downvotes,
+	}
+}
+
+func (r *SuggestionRow) ToDTO() *SuggestionDTO {
+	return &SuggestionDTO{
+		FilmID:      r.FilmID,
+		FilmTitle:   r.FilmTitle,
+		TagID:       r.TagID,
+		TagName:     r.TagName,
+		TagSlug:     r.TagSlug,
+		TagStatus:   r.TagStatus,
+		SuggestedBy: r.SuggestedBy,
+		Score:       r.Score,
+		CreateAt:    r.CreatedAt,
+	}
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"errors"
+	"server/internal/init/cache"
+	tg "server/internal/modules/tag"
+	"time"
+)
+
+type TagCache struct {
+	ch   *cache.Cache
+	tags *cache.Typed[[]*tg.FilmTagDTO]
+}
+
+func NewTagCache(ch *cache.Cache) *TagCache {
+	return &TagCache{
+		ch:   ch,
+		tags: cache.NewTyped[[]*tg.FilmTagDTO](ch),
+	}
+}
+
+func (c *TagCache) SetFilmTagsToCache(key string, tags []*tg.FilmTagDTO, ttl time.Duration) error {
+	return c.tags.Set(key, tags, ttl)
+}
+
+func (c *TagCache) GetFilmTagsFromCache(key string) ([]*tg.FilmTagDTO, error) {
+	result, err := c.tags.Get(key)
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, tg.ErrMissCache
+	}
+	return result, err
+}
+
+func (c *TagCache) DeleteFilmTagsFromCache(key string) error {
+	return c.tags.Delete(key)
+}
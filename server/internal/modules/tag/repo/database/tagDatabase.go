@@ -0,0 +1,309 @@
+package database
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	tg "server/internal/modules/tag"
+)
+
+type TagDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewTagDatabase(db *gorm.DB, log *slog.Logger) *TagDatabase {
+	return &TagDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetTags возвращает одобренные теги с количеством фильмов, query - поиск по подстроке в названии
+func (db *TagDatabase) GetTags(query string) ([]*tg.TagDTO, error) {
+	var rows []*tg.TagRow
+
+	q := db.db.Table("tags t").
+		Select("t.*, COUNT(ft.film_id) AS films_count").
+		Joins("LEFT JOIN film_tags ft ON ft.tag_id = t.tag_id AND ft.status = ?", tg.StatusApproved).
+		Where("t.status = ?", tg.StatusApproved).
+		Group("t.tag_id").
+		Order("t.name")
+	if query != "" {
+		q = q.Where("t.name ILIKE ?", "%"+query+"%")
+	}
+
+	if err := q.Scan(&rows).Error; err != nil {
+		db.log.Error("failed to get tags", "error", err)
+		return nil, tg.ErrInternal
+	}
+
+	tags := make([]*tg.TagDTO, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, row.ToDTO())
+	}
+
+	return tags, nil
+}
+
+func (db *TagDatabase) CreateTag(tag *tg.TagDTO) error {
+	tagModel := &tg.Tag{
+		Name:   tag.Name,
+		Slug:   tag.Slug,
+		Status: tg.StatusApproved,
+	}
+	if err := db.db.Create(tagModel).Error; err != nil {
+		return db.mapError(err, "failed to create tag")
+	}
+	*tag = *tagModel.ToDTO()
+
+	return nil
+}
+
+func (db *TagDatabase) UpdateTag(tag *tg.TagDTO) error {
+	result := db.db.Model(&tg.Tag{}).
+		Where("tag_id = ?", tag.ID).
+		Updates(map[string]interface{}{
+			"name": tag.Name,
+			"slug": tag.Slug,
+		})
+	if result.Error != nil {
+		return db.mapError(result.Error, "failed to update tag")
+	}
+	if result.RowsAffected == 0 {
+		return tg.ErrTagNotFound
+	}
+
+	return nil
+}
+
+func (db *TagDatabase) DeleteTag(id uint) error {
+	result := db.db.Delete(&tg.Tag{}, id)
+	if result.Error != nil {
+		db.log.Error("failed to delete tag", "error", result.Error, "id", id)
+		return tg.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return tg.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// GetTagFilmIDs возвращает фильмы, которым тег одобрен
+func (db *TagDatabase) GetTagFilmIDs(tagID uint) ([]uint, error) {
+	var filmIDs []uint
+	err := db.db.Model(&tg.FilmTag{}).
+		Where("tag_id = ? AND status = ?", tagID, tg.StatusApproved).
+		Pluck("film_id", &filmIDs).Error
+	if err != nil {
+		db.log.Error("failed to get tag films", "error", err, "tagID", tagID)
+		return nil, tg.ErrInternal
+	}
+
+	return filmIDs, nil
+}
+
+// GetSuggestions возвращает очередь модерации: предложенные теги фильмов, самые старые первыми
+func (db *TagDatabase) GetSuggestions(limit int) ([]*tg.SuggestionDTO, error) {
+	var rows []*tg.SuggestionRow
+
+	err := db.db.Raw(`
+		SELECT ft.film_id, f.title AS film_title, t.tag_id, t.name AS tag_name, t.slug AS tag_slug,
+		       t.status AS tag_status, ft.suggested_by, ft.create_at,
+		       COALESCE(SUM(v.vote), 0) AS score
+		FROM film_tags ft
+		JOIN films f ON f.film_id = ft.film_id AND f.deleted_at IS NULL
+		JOIN tags t ON t.tag_id = ft.tag_id
+		LEFT JOIN film_tag_votes v ON v.film_id = ft.film_id AND v.tag_id = ft.tag_id
+		WHERE ft.status = ?
+		GROUP BY ft.film_id, ft.tag_id, f.title, t.tag_id
+		ORDER BY ft.create_at, ft.film_id
+		LIMIT ?`, tg.StatusPending, limit).Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get tag suggestions", "error", err)
+		return nil, tg.ErrInternal
+	}
+
+	suggestions := make([]*tg.SuggestionDTO, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, row.ToDTO())
+	}
+
+	return suggestions, nil
+}
+
+// GetFilmTags возвращает одобренные теги фильма с голосами, самые релевантные первыми
+func (db *TagDatabase) GetFilmTags(filmID uint) ([]*tg.FilmTagDTO, error) {
+	var rows []*tg.FilmTagRow
+
+	err := db.db.Raw(`
+		SELECT t.tag_id, t.name, t.slug,
+		       COUNT(*) FILTER (WHERE v.vote = 1) AS upvotes,
+		       COUNT(*) FILTER (WHERE v.vote = -1) AS downvotes
+		FROM film_tags ft
+		JOIN tags t ON t.tag_id = ft.tag_id
+		LEFT JOIN film_tag_votes v ON v.film_id = ft.film_id AND v.tag_id = ft.tag_id
+		WHERE ft.film_id = ? AND ft.status = ?
+		GROUP BY t.tag_id
+		ORDER BY COALESCE(SUM(v.vote), 0) DESC, t.name`, filmID, tg.StatusApproved).Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to get film tags", "error", err, "filmID", filmID)
+		return nil, tg.ErrInternal
+	}
+
+	tags := make([]*tg.FilmTagDTO, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, row.ToDTO())
+	}
+
+	return tags, nil
+}
+
+// SuggestTag предлагает тег фильму от имени пользователя. Тег ищется по slug, если такого нет -
+// создается новый тег на модерации. Предложение сразу считается голосом автора "за"
+func (db *TagDatabase) SuggestTag(filmID uint, userID uint, tag *tg.TagDTO) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		var tagModel tg.Tag
+		err := tx.Where("slug = ?", tag.Slug).First(&tagModel).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			tagModel = tg.Tag{
+				Name:      tag.Name,
+				Slug:      tag.Slug,
+				Status:    tg.StatusPending,
+				CreatedBy: &userID,
+			}
+			if err := tx.Create(&tagModel).Error; err != nil {
+				return db.mapError(err, "failed to create suggested tag")
+			}
+		case err != nil:
+			db.log.Error("failed to get tag by slug", "error", err, "slug", tag.Slug)
+			return tg.ErrInternal
+		}
+
+		filmTag := &tg.FilmTag{
+			FilmID:      filmID,
+			TagID:       tagModel.TagID,
+			Status:      tg.StatusPending,
+			SuggestedBy: &userID,
+		}
+		if err := tx.Create(filmTag).Error; err != nil {
+			return db.mapError(err, "failed to suggest tag")
+		}
+
+		vote := &tg.FilmTagVote{FilmID: filmID, TagID: tagModel.TagID, UserID: userID, Vote: 1}
+		if err := tx.Create(vote).Error; err != nil {
+			return db.mapError(err, "failed to vote for suggested tag")
+		}
+
+		*tag = *tagModel.ToDTO()
+		return nil
+	})
+}
+
+// ApproveFilmTag одобряет предложенный тег фильма или сразу добавляет тег фильму.
+// Новый тег, предложенный пользователем, при этом тоже становится одобренным
+func (db *TagDatabase) ApproveFilmTag(filmID uint, tagID uint) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		filmTag := &tg.FilmTag{FilmID: filmID, TagID: tagID, Status: tg.StatusApproved}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "film_id"}, {Name: "tag_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status"}),
+		}).Create(filmTag).Error
+		if err != nil {
+			return db.mapError(err, "failed to approve film tag")
+		}
+
+		err = tx.Model(&tg.Tag{}).
+			Where("tag_id = ?", tagID).
+			Update("status", tg.StatusApproved).Error
+		if err != nil {
+			db.log.Error("failed to approve tag", "error", err, "tagID", tagID)
+			return tg.ErrInternal
+		}
+
+		return nil
+	})
+}
+
+// DeleteFilmTag убирает тег у фильма или отклоняет предложение. Новый тег, который так и
+// не был одобрен и больше никому не предложен, удаляется вместе с ним
+func (db *TagDatabase) DeleteFilmTag(filmID uint, tagID uint) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("film_id = ? AND tag_id = ?", filmID, tagID).Delete(&tg.FilmTag{})
+		if result.Error != nil {
+			db.log.Error("failed to delete film tag", "error", result.Error, "filmID", filmID, "tagID", tagID)
+			return tg.ErrInternal
+		}
+		if result.RowsAffected == 0 {
+			return tg.ErrFilmTagNotFound
+		}
+
+		err := tx.Exec(`
+			DELETE FROM tags
+			WHERE tag_id = ? AND status = ?
+			  AND NOT EXISTS (SELECT 1 FROM film_tags ft WHERE ft.tag_id = tags.tag_id)`,
+			tagID, tg.StatusPending).Error
+		if err != nil {
+			db.log.Error("failed to delete pending tag", "error", err, "tagID", tagID)
+			return tg.ErrInternal
+		}
+
+		return nil
+	})
+}
+
+// SetVote сохраняет голос пользователя за тег фильма, повторный голос заменяет предыдущий
+func (db *TagDatabase) SetVote(filmID uint, tagID uint, userID uint, vote int) error {
+	voteModel := &tg.FilmTagVote{FilmID: filmID, TagID: tagID, UserID: userID, Vote: vote}
+	err := db.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "film_id"}, {Name: "tag_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"vote"}),
+	}).Create(voteModel).Error
+	if err != nil {
+		return db.mapError(err, "failed to vote for film tag")
+	}
+
+	return nil
+}
+
+func (db *TagDatabase) DeleteVote(filmID uint, tagID uint, userID uint) error {
+	result := db.db.Where("film_id = ? AND tag_id = ? AND user_id = ?", filmID, tagID, userID).Delete(&tg.FilmTagVote{})
+	if result.Error != nil {
+		db.log.Error("failed to delete film tag vote", "error", result.Error, "filmID", filmID, "tagID", tagID)
+		return tg.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return tg.ErrVoteNotFound
+	}
+
+	return nil
+}
+
+// mapError переводит нарушения уникальности и внешних ключей в ошибки модуля
+func (db *TagDatabase) mapError(err error, msg string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			if pgErr.ConstraintName == "film_tags_pkey" {
+				return tg.ErrFilmTagExists
+			}
+			return tg.ErrTagExists
+		case "23503":
+			switch pgErr.ConstraintName {
+			case "fk_film":
+				return tg.ErrFilmNotFound
+			case "fk_tag":
+				return tg.ErrTagNotFound
+			case "fk_film_tag":
+				return tg.ErrFilmTagNotFound
+			}
+		}
+	}
+	db.log.Error(msg, "error", err)
+	return tg.ErrInternal
+}
@@ -0,0 +1,99 @@
+package repo
+
+import (
+	tg "server/internal/modules/tag"
+	"time"
+)
+
+type TagDB interface {
+	GetTags(query string) ([]*tg.TagDTO, error)
+	CreateTag(tag *tg.TagDTO) error
+	UpdateTag(tag *tg.TagDTO) error
+	DeleteTag(id uint) error
+	GetTagFilmIDs(tagID uint) ([]uint, error)
+	GetSuggestions(limit int) ([]*tg.SuggestionDTO, error)
+	GetFilmTags(filmID uint) ([]*tg.FilmTagDTO, error)
+	SuggestTag(filmID uint, userID uint, tag *tg.TagDTO) error
+	ApproveFilmTag(filmID uint, tagID uint) error
+	DeleteFilmTag(filmID uint, tagID uint) error
+	SetVote(filmID uint, tagID uint, userID uint, vote int) error
+	DeleteVote(filmID uint, tagID uint, userID uint) error
+}
+
+type TagCache interface {
+	GetFilmTagsFromCache(key string) ([]*tg.FilmTagDTO, error)
+	SetFilmTagsToCache(key string, tags []*tg.FilmTagDTO, ttl time.Duration) error
+	DeleteFilmTagsFromCache(key string) error
+}
+
+type Repo struct {
+	db TagDB
+	ch TagCache
+}
+
+func NewTagRepo(db TagDB, ch TagCache) *Repo {
+	return &Repo{
+		db: db,
+		ch: ch,
+	}
+}
+
+func (r *Repo) GetTags(query string) ([]*tg.TagDTO, error) {
+	return r.db.GetTags(query)
+}
+
+func (r *Repo) CreateTag(tag *tg.TagDTO) error {
+	return r.db.CreateTag(tag)
+}
+
+func (r *Repo) UpdateTag(tag *tg.TagDTO) error {
+	return r.db.UpdateTag(tag)
+}
+
+func (r *Repo) DeleteTag(id uint) error {
+	return r.db.DeleteTag(id)
+}
+
+func (r *Repo) GetTagFilmIDs(tagID uint) ([]uint, error) {
+	return r.db.GetTagFilmIDs(tagID)
+}
+
+func (r *Repo) GetSuggestions(limit int) ([]*tg.SuggestionDTO, error) {
+	return r.db.GetSuggestions(limit)
+}
+
+func (r *Repo) GetFilmTags(filmID uint) ([]*tg.FilmTagDTO, error) {
+	return r.db.GetFilmTags(filmID)
+}
+
+func (r *Repo) SuggestTag(filmID uint, userID uint, tag *tg.TagDTO) error {
+	return r.db.SuggestTag(filmID, userID, tag)
+}
+
+func (r *Repo) ApproveFilmTag(filmID uint, tagID uint) error {
+	return r.db.ApproveFilmTag(filmID, tagID)
+}
+
+func (r *Repo) DeleteFilmTag(filmID uint, tagID uint) error {
+	return r.db.DeleteFilmTag(filmID, tagID)
+}
+
+func (r *Repo) SetVote(filmID uint, tagID uint, userID uint, vote int) error {
+	return r.db.SetVote(filmID, tagID, userID, vote)
+}
+
+func (r *Repo) DeleteVote(filmID uint, tagID uint, userID uint) error {
+	return r.db.DeleteVote(filmID, tagID, userID)
+}
+
+func (r *Repo) GetFilmTagsFromCache(key string) ([]*tg.FilmTagDTO, error) {
+	return r.ch.GetFilmTagsFromCache(key)
+}
+
+func (r *Repo) SetFilmTagsToCache(key string, tags []*tg.FilmTagDTO, ttl time.Duration) error {
+	return r.ch.SetFilmTagsToCache(key, tags, ttl)
+}
+
+func (r *Repo) DeleteFilmTagsFromCache(key string) error {
+	return r.ch.DeleteFilmTagsFromCache(key)
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	f "server/internal/modules/film"
+	tg "server/internal/modules/tag"
+	"server/pkg/lib/slug"
+	"strings"
+	"time"
+)
+
+// FilmService - то, что нужно модулю тегов от фильмов: теги входят в FilmDTO и индекс поиска
+type FilmService interface {
+	GetFilmByID(id uint) (*f.FilmDTO, error)
+	ReindexFilm(id uint) error
+	InvalidateFilmCache(id uint)
+}
+
+type TagUseCase struct {
+	log   *slog.Logger
+	rp    tg.Repo
+	films FilmService
+}
+
+func NewTagUseCase(log *slog.Logger, rp tg.Repo, films FilmService) *TagUseCase {
+	return &TagUseCase{
+		log:   log,
+		rp:    rp,
+		films: films,
+	}
+}
+
+func (uc *TagUseCase) GetTags(query string) ([]*tg.TagDTO, error) {
+	return uc.rp.GetTags(strings.TrimSpace(query))
+}
+
+func (uc *TagUseCase) CreateTag(tag *tg.TagDTO) error {
+	if err := normalizeTag(tag); err != nil {
+		return err
+	}
+
+	return uc.rp.CreateTag(tag)
+}
+
+func (uc *TagUseCase) UpdateTag(tag *tg.TagDTO) error {
+	if err := normalizeTag(tag); err != nil {
+		return err
+	}
+
+	if err := uc.rp.UpdateTag(tag); err != nil {
+		return err
+	}
+
+	uc.refreshTagFilms(tag.ID)
+	return nil
+}
+
+func (uc *TagUseCase) DeleteTag(id uint) error {
+	// фильмы нужно узнать до удаления, связи удалятся каскадно
+	filmIDs, err := uc.rp.GetTagFilmIDs(id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.rp.DeleteTag(id); err != nil {
+		return err
+	}
+
+	for _, filmID := range filmIDs {
+		uc.invalidate(filmID, true)
+	}
+	return nil
+}
+
+func (uc *TagUseCase) GetSuggestions(limit int) ([]*tg.SuggestionDTO, error) {
+	return uc.rp.GetSuggestions(limit)
+}
+
+// GetFilmTagCloud возвращает облако тегов фильма: одобренные теги с голосами и весом для отображения
+func (uc *TagUseCase) GetFilmTagCloud(filmID uint) ([]*tg.FilmTagDTO, error) {
+	cacheKey := filmTagsCacheKey(filmID)
+	if tags, err := uc.rp.GetFilmTagsFromCache(cacheKey); err == nil {
+		return tags, nil
+	}
+
+	if err := uc.ensureFilm(filmID); err != nil {
+		return nil, err
+	}
+
+	tags, err := uc.rp.GetFilmTags(filmID)
+	if err != nil {
+		return nil, err
+	}
+	setWeights(tags)
+
+	if err := uc.rp.SetFilmTagsToCache(cacheKey, tags, time.Minute*10); err != nil {
+		uc.log.Error("failed to cache film tags", "error", err)
+	}
+
+	return tags, nil
+}
+
+// SuggestTag предлагает тег фильму. Существующий тег находится по slug названия,
+// иначе создается новый тег. Фильму тег добавится после одобрения администратором
+func (uc *TagUseCase) SuggestTag(filmID uint, userID uint, name string) (*tg.TagDTO, error) {
+	tag := &tg.TagDTO{Name: name}
+	if err := normalizeTag(tag); err != nil {
+		return nil, err
+	}
+
+	if err := uc.rp.SuggestTag(filmID, userID, tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (uc *TagUseCase) ApproveFilmTag(filmID uint, tagID uint) error {
+	if err := uc.rp.ApproveFilmTag(filmID, tagID); err != nil {
+		return err
+	}
+
+	uc.invalidate(filmID, true)
+	return nil
+}
+
+func (uc *TagUseCase) DeleteFilmTag(filmID uint, tagID uint) error {
+	if err := uc.rp.DeleteFilmTag(filmID, tagID); err != nil {
+		return err
+	}
+
+	uc.invalidate(filmID, true)
+	return nil
+}
+
+func (uc *TagUseCase) VoteFilmTag(filmID uint, tagID uint, userID uint, vote int) error {
+	if err := uc.rp.SetVote(filmID, tagID, userID, vote); err != nil {
+		return err
+	}
+
+	// голоса меняют только порядок тегов, индекс поиска от него не зависит
+	uc.invalidate(filmID, false)
+	return nil
+}
+
+func (uc *TagUseCase) DeleteFilmTagVote(filmID uint, tagID uint, userID uint) error {
+	if err := uc.rp.DeleteVote(filmID, tagID, userID); err != nil {
+		return err
+	}
+
+	uc.invalidate(filmID, false)
+	return nil
+}
+
+func (uc *TagUseCase) ensureFilm(filmID uint) error {
+	if _, err := uc.films.GetFilmByID(filmID); err != nil {
+		if errors.Is(err, f.ErrFilmNotFound) {
+			return tg.ErrFilmNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// refreshTagFilms сбрасывает кэш и переиндексирует фильмы с тегом после его переименования
+func (uc *TagUseCase) refreshTagFilms(tagID uint) {
+	filmIDs, err := uc.rp.GetTagFilmIDs(tagID)
+	if err != nil {
+		uc.log.Error("failed to get tag films", "error", err, "tagID", tagID)
+		return
+	}
+
+	for _, filmID := range filmIDs {
+		uc.invalidate(filmID, true)
+	}
+}
+
+// invalidate сбрасывает облако тегов и кэш фильма, при изменении набора тегов переиндексирует фильм.
+// Кэш фильма сбрасывается первым, иначе переиндексация возьмет из него старые теги
+func (uc *TagUseCase) invalidate(filmID uint, reindex bool) {
+	if err := uc.rp.DeleteFilmTagsFromCache(filmTagsCacheKey(filmID)); err != nil {
+		uc.log.Error("failed to delete film tags from cache", "error", err)
+	}
+	uc.films.InvalidateFilmCache(filmID)
+
+	if reindex {
+		if err := uc.films.ReindexFilm(filmID); err != nil {
+			uc.log.Error("failed to reindex film tags in Elasticsearch", "error", err, "filmID", filmID)
+		}
+	}
+}
+
+// normalizeTag схлопывает пробелы в названии и строит slug, если он не задан
+func normalizeTag(tag *tg.TagDTO) error {
+	tag.Name = strings.Join(strings.Fields(tag.Name), " ")
+	if tag.Slug == "" {
+		tag.Slug = slug.Slugify(tag.Name)
+	}
+	if tag.Slug == "" {
+		return tg.ErrInvalidTagName
+	}
+
+	return nil
+}
+
+// setWeights раскладывает теги по размеру в облаке от 1 до 5 линейно между наименьшим и наибольшим рейтингом
+func setWeights(tags []*tg.FilmTagDTO) {
+	if len(tags) == 0 {
+		return
+	}
+
+	// теги отсортированы по рейтингу по убыванию
+	maxScore, minScore := tags[0].Score, tags[len(tags)-1].Score
+	for _, tag := range tags {
+		if maxScore == minScore {
+			tag.Weight = 3
+			continue
+		}
+		tag.Weight = 1 + int(math.Round(4*float64(tag.Score-minScore)/float64(maxScore-minScore)))
+	}
+}
+
+func filmTagsCacheKey(filmID uint) string {
+	return fmt.Sprintf("film:%d:tags", filmID)
+}
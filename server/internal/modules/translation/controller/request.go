@@ -0,0 +1,5 @@
+package controller
+
+type TranslationRequest struct {
+	Fields map[string]string `json:"fields" validate:"required,min=1,dive,keys,required,endkeys,required,max=10000"`
+}
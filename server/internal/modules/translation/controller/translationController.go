@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	tr "server/internal/modules/translation"
+	resp "server/pkg/lib/response"
+	"strconv"
+)
+
+type TranslationController struct {
+	log      *slog.Logger
+	uc       tr.UseCase
+	validate *validator.Validate
+}
+
+func NewTranslationController(log *slog.Logger, uc tr.UseCase) *TranslationController {
+	return &TranslationController{
+		log:      log,
+		uc:       uc,
+		validate: validator.New(),
+	}
+}
+
+// GetTranslations - Переводы сущности
+// @Summary Получить переводы сущности
+// @Description Возвращает переводы полей фильма, жанра или персоны на все языки
+// @Tags translation
+// @Produce json
+// @Param entity path string true "Тип сущности: film, genre, person"
+// @Param id path int true "FilmId сущности"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /translations/{entity}/{id} [get]
+func (c *TranslationController) GetTranslations(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetTranslations")
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	translations, err := c.uc.GetTranslations(chi.URLParam(r, "entity"), id)
+	if err != nil {
+		writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Translations(translations))
+}
+
+// SetTranslation - Сохранение перевода
+// @Summary Сохранить перевод сущности
+// @Description Заменяет переводы полей сущности на указанный язык. Переводимые поля: film - title, tagline, synopsis; genre - name, description; person - name
+// @Tags translation
+// @Accept json
+// @Produce json
+// @Param entity path string true "Тип сущности: film, genre, person"
+// @Param id path int true "FilmId сущности"
+// @Param locale path string true "Язык перевода: ru, en"
+// @Param json body TranslationRequest true "Переводы полей"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /translations/{entity}/{id}/{locale} [put]
+func (c *TranslationController) SetTranslation(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "SetTranslation")
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req TranslationRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Error("failed to decode request body", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
+		return
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+
+	translation := &tr.TranslationDTO{
+		Locale: chi.URLParam(r, "locale"),
+		Fields: req.Fields,
+	}
+
+	if err := c.uc.SetTranslation(chi.URLParam(r, "entity"), id, translation); err != nil {
+		writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
+
+// DeleteTranslation - Удаление перевода
+// @Summary Удалить перевод сущности
+// @Description Удаляет все переводы полей сущности на указанный язык
+// @Tags translation
+// @Param entity path string true "Тип сущности: film, genre, person"
+// @Param id path int true "FilmId сущности"
+// @Param locale path string true "Язык перевода: ru, en"
+// @Success 204 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /translations/{entity}/{id}/{locale} [delete]
+func (c *TranslationController) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "DeleteTranslation")
+
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.uc.DeleteTranslation(chi.URLParam(r, "entity"), id, chi.URLParam(r, "locale")); err != nil {
+		writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil || id == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid id"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, tr.ErrEntityNotFound) || errors.Is(err, tr.ErrTranslationNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, tr.ErrUnknownEntity) || errors.Is(err, tr.ErrUnsupportedLocale) || errors.Is(err, tr.ErrUnknownField):
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	default:
+		log.Error("translation request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, tr.ErrInternal.Error()))
+	}
+}
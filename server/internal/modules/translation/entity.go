@@ -0,0 +1,56 @@
+package translation
+
+import (
+	"net/http"
+)
+
+// Типы сущностей, поля которых переводятся
+const (
+	EntityFilm   = "film"
+	EntityGenre  = "genre"
+	EntityPerson = "person"
+)
+
+// Fields - переводимые поля каждой сущности
+var Fields = map[string][]string{
+	EntityFilm:   {"title", "tagline", "synopsis"},
+	EntityGenre:  {"name", "description"},
+	EntityPerson: {"name"},
+}
+
+// IsTranslatable проверяет, что поле field сущности entityType переводится
+func IsTranslatable(entityType, field string) bool {
+	for _, f := range Fields[entityType] {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// TranslationDTO - переводы полей сущности на один язык
+type TranslationDTO struct {
+	Locale string            `json:"locale"`
+	Fields map[string]string `json:"fields"`
+}
+
+type Controller interface {
+	GetTranslations(w http.ResponseWriter, r *http.Request)
+	SetTranslation(w http.ResponseWriter, r *http.Request)
+	DeleteTranslation(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	GetTranslations(entityType string, entityID uint) ([]*TranslationDTO, error)
+	SetTranslation(entityType string, entityID uint, translation *TranslationDTO) error
+	DeleteTranslation(entityType string, entityID uint, locale string) error
+}
+
+type Repo interface {
+	//DB
+	EntityExists(entityType string, entityID uint) (bool, error)
+	GetTranslations(entityType string, entityID uint) ([]*TranslationDTO, error)
+	ResolveTranslations(entityType string, entityIDs []uint, locales []string) (map[uint]map[string]string, error)
+	SetTranslation(entityType string, entityID uint, translation *TranslationDTO) error
+	DeleteTranslation(entityType string, entityID uint, locale string) error
+}
@@ -0,0 +1,12 @@
+package translation
+
+import "errors"
+
+var (
+	ErrInternal            = errors.New("internal server error")
+	ErrUnknownEntity       = errors.New("unknown entity type, expected one of: film, genre, person")
+	ErrEntityNotFound      = errors.New("entity not found")
+	ErrUnsupportedLocale   = errors.New("unsupported locale")
+	ErrUnknownField        = errors.New("field is not translatable")
+	ErrTranslationNotFound = errors.New("translation not found")
+)
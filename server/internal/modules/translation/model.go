@@ -0,0 +1,13 @@
+package translation
+
+type Translation struct {
+	EntityType string `gorm:"primaryKey;column:entity_type"`
+	EntityID   uint   `gorm:"primaryKey;column:entity_id"`
+	Locale     string `gorm:"primaryKey;column:locale"`
+	Field      string `gorm:"primaryKey;column:field"`
+	Value      string `gorm:"column:value"`
+}
+
+func (Translation) TableName() string {
+	return "translations"
+}
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"log/slog"
+	tr "server/internal/modules/translation"
+	"sort"
+)
+
+// entityTables - таблица и колонка id каждой переводимой сущности
+var entityTables = map[string][2]string{
+	tr.EntityFilm:   {"films", "film_id"},
+	tr.EntityGenre:  {"genres", "genre_id"},
+	tr.EntityPerson: {"persons", "person_id"},
+}
+
+type TranslationDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewTranslationDatabase(db *gorm.DB, log *slog.Logger) *TranslationDatabase {
+	return &TranslationDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+func (db *TranslationDatabase) EntityExists(entityType string, entityID uint) (bool, error) {
+	table, ok := entityTables[entityType]
+	if !ok {
+		return false, tr.ErrUnknownEntity
+	}
+
+	var exists bool
+	if err := db.db.Raw("SELECT EXISTS (SELECT 1 FROM "+table[0]+" WHERE "+table[1]+" = ?)", entityID).
+		Scan(&exists).Error; err != nil {
+		db.log.Error("failed to check entity", "error", err, "entity_type", entityType, "entity_id", entityID)
+		return false, tr.ErrInternal
+	}
+
+	return exists, nil
+}
+
+func (db *TranslationDatabase) GetTranslations(entityType string, entityID uint) ([]*tr.TranslationDTO, error) {
+	var rows []tr.Translation
+	if err := db.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("locale, field").Find(&rows).Error; err != nil {
+		db.log.Error("failed to get translations", "error", err, "entity_type", entityType, "entity_id", entityID)
+		return nil, tr.ErrInternal
+	}
+
+	byLocale := make(map[string]*tr.TranslationDTO)
+	for _, row := range rows {
+		t, ok := byLocale[row.Locale]
+		if !ok {
+			t = &tr.TranslationDTO{Locale: row.Locale, Fields: make(map[string]string)}
+			byLocale[row.Locale] = t
+		}
+		t.Fields[row.Field] = row.Value
+	}
+
+	translations := make([]*tr.TranslationDTO, 0, len(byLocale))
+	for _, t := range byLocale {
+		translations = append(translations, t)
+	}
+	sort.Slice(translations, func(i, j int) bool { return translations[i].Locale < translations[j].Locale })
+
+	return translations, nil
+}
+
+// ResolveTranslations возвращает для каждой сущности перевод каждого поля на первый из locales, для которого он есть.
+// Поля без перевода в результат не попадают, вызывающий оставляет основное значение
+func (db *TranslationDatabase) ResolveTranslations(entityType string, entityIDs []uint, locales []string) (map[uint]map[string]string, error) {
+	result := make(map[uint]map[string]string)
+	if len(entityIDs) == 0 || len(locales) == 0 {
+		return result, nil
+	}
+
+	var rows []tr.Translation
+	err := db.db.Raw(`
+		SELECT DISTINCT ON (entity_id, field) entity_id, field, value
+		FROM translations
+		WHERE entity_type = ? AND entity_id IN ? AND locale IN ?
+		ORDER BY entity_id, field, array_position(ARRAY[?]::text[], locale::text)`,
+		entityType, entityIDs, locales, locales).Scan(&rows).Error
+	if err != nil {
+		db.log.Error("failed to resolve translations", "error", err, "entity_type", entityType)
+		return nil, tr.ErrInternal
+	}
+
+	for _, row := range rows {
+		if result[row.EntityID] == nil {
+			result[row.EntityID] = make(map[string]string)
+		}
+		result[row.EntityID][row.Field] = row.Value
+	}
+
+	return result, nil
+}
+
+// SetTranslation заменяет все переводы сущности на язык translation.Locale
+func (db *TranslationDatabase) SetTranslation(entityType string, entityID uint, translation *tr.TranslationDTO) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("entity_type = ? AND entity_id = ? AND locale = ?", entityType, entityID, translation.Locale).
+			Delete(&tr.Translation{}).Error; err != nil {
+			db.log.Error("failed to delete translations", "error", err, "entity_type", entityType, "entity_id", entityID)
+			return tr.ErrInternal
+		}
+
+		rows := make([]tr.Translation, 0, len(translation.Fields))
+		for field, value := range translation.Fields {
+			rows = append(rows, tr.Translation{
+				EntityType: entityType,
+				EntityID:   entityID,
+				Locale:     translation.Locale,
+				Field:      field,
+				Value:      value,
+			})
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		if err := tx.Create(&rows).Error; err != nil {
+			db.log.Error("failed to create translations", "error", err, "entity_type", entityType, "entity_id", entityID)
+			return tr.ErrInternal
+		}
+
+		return nil
+	})
+}
+
+func (db *TranslationDatabase) DeleteTranslation(entityType string, entityID uint, locale string) error {
+	result := db.db.Where("entity_type = ? AND entity_id = ? AND locale = ?", entityType, entityID, locale).
+		Delete(&tr.Translation{})
+	if result.Error != nil {
+		db.log.Error("failed to delete translation", "error", result.Error, "entity_type", entityType, "entity_id", entityID)
+		return tr.ErrInternal
+	}
+	if result.RowsAffected == 0 {
+		return tr.ErrTranslationNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package repo
+
+import (
+	tr "server/internal/modules/translation"
+)
+
+type TranslationDB interface {
+	EntityExists(entityType string, entityID uint) (bool, error)
+	GetTranslations(entityType string, entityID uint) ([]*tr.TranslationDTO, error)
+	ResolveTranslations(entityType string, entityIDs []uint, locales []string) (map[uint]map[string]string, error)
+	SetTranslation(entityType string, entityID uint, translation *tr.TranslationDTO) error
+	DeleteTranslation(entityType string, entityID uint, locale string) error
+}
+
+type Repo struct {
+	db TranslationDB
+}
+
+func NewTranslationRepo(db TranslationDB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) EntityExists(entityType string, entityID uint) (bool, error) {
+	return r.db.EntityExists(entityType, entityID)
+}
+
+func (r *Repo) GetTranslations(entityType string, entityID uint) ([]*tr.TranslationDTO, error) {
+	return r.db.GetTranslations(entityType, entityID)
+}
+
+func (r *Repo) ResolveTranslations(entityType string, entityIDs []uint, locales []string) (map[uint]map[string]string, error) {
+	return r.db.ResolveTranslations(entityType, entityIDs, locales)
+}
+
+func (r *Repo) SetTranslation(entityType string, entityID uint, translation *tr.TranslationDTO) error {
+	return r.db.SetTranslation(entityType, entityID, translation)
+}
+
+func (r *Repo) DeleteTranslation(entityType string, entityID uint, locale string) error {
+	return r.db.DeleteTranslation(entityType, entityID, locale)
+}
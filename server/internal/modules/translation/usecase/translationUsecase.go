@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"log/slog"
+	tr "server/internal/modules/translation"
+	"server/pkg/middleware/locale"
+)
+
+// FilmService - переиндексация фильма: переводы названия и описания попадают в поисковый индекс
+type FilmService interface {
+	ReindexFilm(id uint) error
+}
+
+type TranslationUseCase struct {
+	log   *slog.Logger
+	rp    tr.Repo
+	films FilmService
+}
+
+func NewTranslationUseCase(log *slog.Logger, rp tr.Repo, films FilmService) *TranslationUseCase {
+	return &TranslationUseCase{
+		log:   log,
+		rp:    rp,
+		films: films,
+	}
+}
+
+func (uc *TranslationUseCase) GetTranslations(entityType string, entityID uint) ([]*tr.TranslationDTO, error) {
+	if err := uc.ensureEntity(entityType, entityID); err != nil {
+		return nil, err
+	}
+
+	return uc.rp.GetTranslations(entityType, entityID)
+}
+
+func (uc *TranslationUseCase) SetTranslation(entityType string, entityID uint, translation *tr.TranslationDTO) error {
+	lang, ok := locale.Match(translation.Locale)
+	if !ok {
+		return tr.ErrUnsupportedLocale
+	}
+	translation.Locale = lang
+
+	if err := uc.ensureEntity(entityType, entityID); err != nil {
+		return err
+	}
+
+	for field := range translation.Fields {
+		if !tr.IsTranslatable(entityType, field) {
+			return tr.ErrUnknownField
+		}
+	}
+
+	if err := uc.rp.SetTranslation(entityType, entityID, translation); err != nil {
+		return err
+	}
+
+	uc.reindex(entityType, entityID)
+	return nil
+}
+
+func (uc *TranslationUseCase) DeleteTranslation(entityType string, entityID uint, lang string) error {
+	if _, ok := tr.Fields[entityType]; !ok {
+		return tr.ErrUnknownEntity
+	}
+
+	lang, ok := locale.Match(lang)
+	if !ok {
+		return tr.ErrUnsupportedLocale
+	}
+
+	if err := uc.rp.DeleteTranslation(entityType, entityID, lang); err != nil {
+		return err
+	}
+
+	uc.reindex(entityType, entityID)
+	return nil
+}
+
+func (uc *TranslationUseCase) ensureEntity(entityType string, entityID uint) error {
+	if _, ok := tr.Fields[entityType]; !ok {
+		return tr.ErrUnknownEntity
+	}
+
+	exists, err := uc.rp.EntityExists(entityType, entityID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return tr.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+// reindex обновляет поисковый индекс: переводы жанров и персон в индексе фильмов не участвуют
+func (uc *TranslationUseCase) reindex(entityType string, entityID uint) {
+	if entityType != tr.EntityFilm {
+		return
+	}
+	if err := uc.films.ReindexFilm(entityID); err != nil {
+		uc.log.Error("failed to reindex film after translation change", "error", err, "film_id", entityID)
+	}
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	tr "server/internal/modules/trash"
+	resp "server/pkg/lib/response"
+	"strconv"
+)
+
+type TrashController struct {
+	log *slog.Logger
+	uc  tr.UseCase
+}
+
+func NewTrashController(log *slog.Logger, uc tr.UseCase) *TrashController {
+	return &TrashController{
+		log: log,
+		uc:  uc,
+	}
+}
+
+// GetTrash - Содержимое корзины
+// @Summary Получить содержимое корзины
+// @Description Возвращает удаленные фильмы, персоны, жанры и пользователей, сначала удаленные последними. purge_at - когда сущность будет удалена окончательно вместе с файлами. Только для администраторов
+// @Tags trash
+// @Produce json
+// @Security ApiKeyAuth
+// @Param entity_type query string false "Тип сущности: film, person, genre, user"
+// @Param page query int false "Номер страницы"
+// @Param page_size query int false "Размер страницы (1-100, по умолчанию 50)"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/trash [get]
+func (c *TrashController) GetTrash(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "GetTrash")
+
+	query := r.URL.Query()
+	filter := &tr.ItemFilter{EntityType: query.Get("entity_type")}
+
+	if page := query.Get("page"); page != "" {
+		pageNum, err := strconv.Atoi(page)
+		if err != nil || pageNum < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid page format, expected positive integer"))
+			return
+		}
+		filter.Page = pageNum
+	}
+
+	if pageSize := query.Get("page_size"); pageSize != "" {
+		size, err := strconv.Atoi(pageSize)
+		if err != nil || size < 1 || size > 100 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, "invalid page_size format, expected positive integer between 1 and 100"))
+			return
+		}
+		filter.PageSize = size
+	}
+
+	items, err := c.uc.GetTrash(filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, tr.ErrUnknownEntity):
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		default:
+			log.Error("failed to get trash", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, tr.ErrInternal.Error()))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.Trash(items))
+}
+
+// Restore - Возврат из корзины
+// @Summary Вернуть сущность из корзины
+// @Description Возвращает удаленный фильм, персону, жанр или пользователя вместе со связями и отзывами. Только для администраторов
+// @Tags trash
+// @Produce json
+// @Security ApiKeyAuth
+// @Param entity path string true "Тип сущности: film, person, genre, user"
+// @Param id path int true "Id сущности"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response "Email, логин или название уже заняты другой сущностью"
+// @Failure 500 {object} response.Response
+// @Router /admin/trash/{entity}/{id}/restore [post]
+func (c *TrashController) Restore(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "Restore")
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil || id == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "invalid id"))
+		return
+	}
+
+	if err := c.uc.Restore(r.Context(), chi.URLParam(r, "entity"), uint(id)); err != nil {
+		switch {
+		case errors.Is(err, tr.ErrUnknownEntity):
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		case errors.Is(err, tr.ErrNotInTrash):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		case errors.Is(err, tr.ErrRestoreTaken):
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		default:
+			log.Error("failed to restore from trash", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, tr.ErrInternal.Error()))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.OK())
+}
@@ -0,0 +1,47 @@
+package trash
+
+import (
+	"context"
+	"net/http"
+	"server/pkg/lib/audit"
+	"time"
+)
+
+// Entities - типы сущностей, которые удаляются в корзину
+var Entities = []string{audit.Film.Type, audit.Person.Type, audit.Genre.Type, audit.User.Type}
+
+// ItemDTO - сущность в корзине. Name - название фильма, имя персоны, название жанра или логин пользователя,
+// PurgeAt - когда сущность будет удалена окончательно
+type ItemDTO struct {
+	EntityType string
+	EntityID   uint
+	Name       string
+	DeletedAt  time.Time
+	PurgeAt    time.Time
+}
+
+// ItemFilter - поиск по корзине, пустой EntityType выборку не ограничивает
+type ItemFilter struct {
+	EntityType string
+	Page       int
+	PageSize   int
+}
+
+type Controller interface {
+	GetTrash(w http.ResponseWriter, r *http.Request)
+	Restore(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	GetTrash(filter *ItemFilter) ([]*ItemDTO, error)
+	Restore(ctx context.Context, entityType string, id uint) error
+	PurgeExpired()
+}
+
+type Repo interface {
+	//DB
+	GetTrash(filter *ItemFilter) ([]*ItemDTO, error)
+	GetExpired(deletedBefore time.Time, limit int) ([]*ItemDTO, error)
+	Restore(ctx context.Context, entityType string, id uint) error
+	Purge(ctx context.Context, item *ItemDTO) error
+}
@@ -0,0 +1,10 @@
+package trash
+
+import "errors"
+
+var (
+	ErrInternal      = errors.New("internal server error")
+	ErrUnknownEntity = errors.New("unknown entity type, expected one of: film, person, genre, user")
+	ErrNotInTrash    = errors.New("entity not found in trash")
+	ErrRestoreTaken  = errors.New("unique fields of the entity are taken by another one, change them before restoring")
+)
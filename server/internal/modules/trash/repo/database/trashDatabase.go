@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"log/slog"
+	tr "server/internal/modules/trash"
+	"server/pkg/lib/audit"
+	"server/pkg/lib/revision"
+	"strings"
+	"time"
+)
+
+// table - таблица с мягким удалением и колонка, по которой сущность узнается в корзине
+type table struct {
+	entity     audit.Entity
+	nameColumn string
+}
+
+var tables = []table{
+	{entity: audit.Film, nameColumn: "title"},
+	{entity: audit.Person, nameColumn: "name"},
+	{entity: audit.Genre, nameColumn: "name"},
+	{entity: audit.User, nameColumn: "login"},
+}
+
+func findTable(entityType string) (table, bool) {
+	for _, t := range tables {
+		if t.entity.Type == entityType {
+			return t, true
+		}
+	}
+	return table{}, false
+}
+
+// trashed - строки корзины одной таблицы или всех, если entityType пустой
+func trashed(entityType string) string {
+	selects := make([]string, 0, len(tables))
+	for _, t := range tables {
+		if entityType != "" && t.entity.Type != entityType {
+			continue
+		}
+		selects = append(selects, fmt.Sprintf(
+			"SELECT '%s' AS entity_type, %s AS entity_id, %s AS name, deleted_at FROM %s WHERE deleted_at IS NOT NULL",
+			t.entity.Type, t.entity.IDColumn, t.nameColumn, t.entity.Table))
+	}
+	return strings.Join(selects, " UNION ALL ")
+}
+
+type TrashDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewTrashDatabase(db *gorm.DB, log *slog.Logger) *TrashDatabase {
+	return &TrashDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetTrash возвращает содержимое корзины, сначала удаленное последним
+func (db *TrashDatabase) GetTrash(filter *tr.ItemFilter) ([]*tr.ItemDTO, error) {
+	var items []*tr.ItemDTO
+	err := db.db.Raw(`SELECT * FROM (`+trashed(filter.EntityType)+`) trash
+		ORDER BY deleted_at DESC, entity_type, entity_id
+		LIMIT ? OFFSET ?`, filter.PageSize, (filter.Page-1)*filter.PageSize).Scan(&items).Error
+	if err != nil {
+		db.log.Error("failed to get trash", "error", err)
+		return nil, tr.ErrInternal
+	}
+	return items, nil
+}
+
+// GetExpired возвращает сущности, удаленные раньше deletedBefore, начиная с самых старых
+func (db *TrashDatabase) GetExpired(deletedBefore time.Time, limit int) ([]*tr.ItemDTO, error) {
+	var items []*tr.ItemDTO
+	err := db.db.Raw(`SELECT * FROM (`+trashed("")+`) trash
+		WHERE deleted_at < ?
+		ORDER BY deleted_at
+		LIMIT ?`, deletedBefore, limit).Scan(&items).Error
+	if err != nil {
+		db.log.Error("failed to get expired trash", "error", err)
+		return nil, tr.ErrInternal
+	}
+	return items, nil
+}
+
+// Restore возвращает сущность из корзины. Связи и отзывы при удалении не трогались, поэтому возвращаются вместе с ней
+func (db *TrashDatabase) Restore(ctx context.Context, entityType string, id uint) error {
+	t, ok := findTable(entityType)
+	if !ok {
+		return tr.ErrUnknownEntity
+	}
+
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := t.entity.SnapshotDeleted(tx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return tr.ErrNotInTrash
+		}
+
+		// уникальность проверяется только среди неудаленных строк, пока сущность была в корзине,
+		// ее email, логин или название могли занять
+		if err := tx.Table(t.entity.Table).Where(t.entity.IDColumn+" = ?", id).Update("deleted_at", nil).Error; err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return tr.ErrRestoreTaken
+			}
+			return err
+		}
+
+		return t.entity.Record(ctx, tx, audit.ActionRestore, id, before)
+	})
+	if err != nil {
+		if errors.Is(err, tr.ErrNotInTrash) || errors.Is(err, tr.ErrRestoreTaken) {
+			return err
+		}
+		db.log.Error("failed to restore from trash", "error", err, "entityType", entityType, "id", id)
+		return tr.ErrInternal
+	}
+	return nil
+}
+
+// Purge удаляет сущность из корзины окончательно вместе с историей версий, связи и отзывы удаляются внешними ключами.
+// Сущность, которую успели вернуть из корзины, не удаляется
+func (db *TrashDatabase) Purge(ctx context.Context, item *tr.ItemDTO) error {
+	t, ok := findTable(item.EntityType)
+	if !ok {
+		return tr.ErrUnknownEntity
+	}
+
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := t.entity.SnapshotDeleted(tx, item.EntityID)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return tr.ErrNotInTrash
+		}
+
+		err = tx.Exec("DELETE FROM "+t.entity.Table+" WHERE "+t.entity.IDColumn+" = ? AND deleted_at IS NOT NULL", item.EntityID).Error
+		if err != nil {
+			return err
+		}
+		// у истории версий нет внешнего ключа на сущность
+		err = tx.Where("entity_type = ? AND entity_id = ?", item.EntityType, item.EntityID).Delete(&revision.Revision{}).Error
+		if err != nil {
+			return err
+		}
+		// и у предложений правок тоже
+		err = tx.Exec("DELETE FROM edit_suggestions WHERE entity_type = ? AND entity_id = ?", item.EntityType, item.EntityID).Error
+		if err != nil {
+			return err
+		}
+
+		return t.entity.Record(ctx, tx, audit.ActionPurge, item.EntityID, before)
+	})
+	if err != nil {
+		if errors.Is(err, tr.ErrNotInTrash) {
+			return err
+		}
+		db.log.Error("failed to purge from trash", "error", err, "entityType", item.EntityType, "id", item.EntityID)
+		return tr.ErrInternal
+	}
+	return nil
+}
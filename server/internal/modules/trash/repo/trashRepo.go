@@ -0,0 +1,38 @@
+package repo
+
+import (
+	"context"
+	tr "server/internal/modules/trash"
+	"time"
+)
+
+type TrashDB interface {
+	GetTrash(filter *tr.ItemFilter) ([]*tr.ItemDTO, error)
+	GetExpired(deletedBefore time.Time, limit int) ([]*tr.ItemDTO, error)
+	Restore(ctx context.Context, entityType string, id uint) error
+	Purge(ctx context.Context, item *tr.ItemDTO) error
+}
+
+type Repo struct {
+	db TrashDB
+}
+
+func NewTrashRepo(db TrashDB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) GetTrash(filter *tr.ItemFilter) ([]*tr.ItemDTO, error) {
+	return r.db.GetTrash(filter)
+}
+
+func (r *Repo) GetExpired(deletedBefore time.Time, limit int) ([]*tr.ItemDTO, error) {
+	return r.db.GetExpired(deletedBefore, limit)
+}
+
+func (r *Repo) Restore(ctx context.Context, entityType string, id uint) error {
+	return r.db.Restore(ctx, entityType, id)
+}
+
+func (r *Repo) Purge(ctx context.Context, item *tr.ItemDTO) error {
+	return r.db.Purge(ctx, item)
+}
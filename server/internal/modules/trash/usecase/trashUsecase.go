@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"server/config"
+	tr "server/internal/modules/trash"
+	"server/pkg/lib/audit"
+	"slices"
+	"time"
+)
+
+const (
+	defaultPageSize = 50
+	// purgeBatch - сколько сущностей удаляется окончательно за один проход очистки
+	purgeBatch = 100
+)
+
+// FilmService - поиск, кэш и файлы фильмов (модуль film)
+type FilmService interface {
+	ReindexFilm(id uint) error
+	InvalidateFilmCache(id uint)
+	CleanupFilm(id uint)
+}
+
+// PersonService - кэш и аватары персон (модуль person)
+type PersonService interface {
+	InvalidatePersonCache(personId uint)
+	CleanupPerson(personId uint, name string)
+}
+
+// GenreService - кэш и обложки жанров (модуль genre)
+type GenreService interface {
+	InvalidateGenreCache(genreID uint)
+	CleanupGenre(genreID uint)
+}
+
+// ProfileService - аватары пользователей (модуль user/profile)
+type ProfileService interface {
+	CleanupUser(userId uint, login string)
+}
+
+type TrashUseCase struct {
+	log      *slog.Logger
+	rp       tr.Repo
+	films    FilmService
+	persons  PersonService
+	genres   GenreService
+	profiles ProfileService
+	cfg      config.TrashConfig
+}
+
+func NewTrashUseCase(log *slog.Logger, rp tr.Repo, films FilmService, persons PersonService, genres GenreService, profiles ProfileService, cfg config.TrashConfig) *TrashUseCase {
+	return &TrashUseCase{
+		log:      log,
+		rp:       rp,
+		films:    films,
+		persons:  persons,
+		genres:   genres,
+		profiles: profiles,
+		cfg:      cfg,
+	}
+}
+
+func (uc *TrashUseCase) GetTrash(filter *tr.ItemFilter) ([]*tr.ItemDTO, error) {
+	if filter.EntityType != "" && !slices.Contains(tr.Entities, filter.EntityType) {
+		return nil, tr.ErrUnknownEntity
+	}
+
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize == 0 {
+		filter.PageSize = defaultPageSize
+	}
+
+	items, err := uc.rp.GetTrash(filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item.PurgeAt = item.DeletedAt.Add(uc.cfg.Retention)
+	}
+	return items, nil
+}
+
+// Restore возвращает сущность из корзины, фильм снова попадает в поиск
+func (uc *TrashUseCase) Restore(ctx context.Context, entityType string, id uint) error {
+	if !slices.Contains(tr.Entities, entityType) {
+		return tr.ErrUnknownEntity
+	}
+
+	if err := uc.rp.Restore(ctx, entityType, id); err != nil {
+		return err
+	}
+
+	switch entityType {
+	case audit.Film.Type:
+		uc.films.InvalidateFilmCache(id)
+		if err := uc.films.ReindexFilm(id); err != nil {
+			uc.log.Error("failed to index restored film in Elasticsearch", "error", err, "filmID", id)
+		}
+	case audit.Person.Type:
+		uc.persons.InvalidatePersonCache(id)
+	case audit.Genre.Type:
+		uc.genres.InvalidateGenreCache(id)
+	}
+
+	return nil
+}
+
+// PurgeExpired окончательно удаляет сущности, пролежавшие в корзине дольше срока хранения, вместе с их файлами
+// и документами поиска. Запускается по расписанию, удаления пишутся в журнал без автора
+func (uc *TrashUseCase) PurgeExpired() {
+	threshold := time.Now().Add(-uc.cfg.Retention)
+
+	var purged int
+	for {
+		items, err := uc.rp.GetExpired(threshold, purgeBatch)
+		if err != nil {
+			uc.log.Error("failed to get expired trash", "error", err)
+			return
+		}
+
+		batchPurged := 0
+		for _, item := range items {
+			if err := uc.rp.Purge(context.Background(), item); err != nil {
+				if !errors.Is(err, tr.ErrNotInTrash) {
+					uc.log.Error("failed to purge from trash", "error", err, "entityType", item.EntityType, "id", item.EntityID)
+				}
+				continue
+			}
+			uc.cleanup(item)
+			batchPurged++
+		}
+		purged += batchPurged
+
+		// неудаляемые строки остаются в выборке, поэтому следующий проход без продвижения не нужен
+		if len(items) < purgeBatch || batchPurged == 0 {
+			break
+		}
+	}
+
+	uc.log.Info("purged expired trash", slog.Int("count", purged))
+}
+
+// cleanup удаляет то, что осталось от сущности вне БД
+func (uc *TrashUseCase) cleanup(item *tr.ItemDTO) {
+	switch item.EntityType {
+	case audit.Film.Type:
+		uc.films.CleanupFilm(item.EntityID)
+	case audit.Person.Type:
+		uc.persons.CleanupPerson(item.EntityID, item.Name)
+	case audit.Genre.Type:
+		uc.genres.CleanupGenre(item.EntityID)
+	case audit.User.Type:
+		uc.profiles.CleanupUser(item.EntityID, item.Name)
+	}
+}
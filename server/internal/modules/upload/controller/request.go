@@ -0,0 +1,10 @@
+package controller
+
+// CreateUploadRequest - начало загрузки. TargetID - FilmId для постера или PersonId для аватара персоны,
+// для аватара пользователя не нужен. Size - точный размер файла в байтах, он входит в подпись ссылки
+type CreateUploadRequest struct {
+	Purpose     string `json:"purpose" validate:"required,oneof=film_poster person_avatar user_avatar"`
+	TargetID    uint   `json:"target_id" validate:"required_unless=Purpose user_avatar"`
+	ContentType string `json:"content_type" validate:"required,oneof=image/jpeg image/png image/webp image/gif"`
+	Size        int64  `json:"size" validate:"required,gt=0"`
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	up "server/internal/modules/upload"
+	avatarManager "server/pkg/lib/avatarMenager"
+	resp "server/pkg/lib/response"
+)
+
+type Controller struct {
+	log      *slog.Logger
+	uc       up.UseCase
+	validate *validator.Validate
+}
+
+func NewUploadController(log *slog.Logger, uc up.UseCase) *Controller {
+	return &Controller{
+		log:      log,
+		uc:       uc,
+		validate: validator.New(),
+	}
+}
+
+// CreateUpload - Начало загрузки изображения в S3
+// @Summary Получить ссылку для загрузки изображения
+// @Description Возвращает подписанную ссылку, по которой клиент загружает файл напрямую в S3 методом PUT
+// @Description с указанными заголовками. Ссылка действует 15 минут, после загрузки нужно вызвать finalize.
+// @Description Постер фильма - до 10 МБ, аватары - до 5 МБ
+// @Tags upload
+// @Accept json
+// @Produce json
+// @Param json body CreateUploadRequest true "Назначение, тип и размер файла"
+// @Success 201 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /uploads [post]
+// @Security ApiKeyAuth
+func (c *Controller) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "CreateUpload")
+
+	userID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+
+	var req CreateUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error("failed to decode request body", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
+		return
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+
+	upload := &up.UploadDTO{
+		Purpose:     req.Purpose,
+		TargetID:    req.TargetID,
+		UserID:      userID,
+		ContentType: req.ContentType,
+		Size:        req.Size,
+	}
+	presigned, err := c.uc.CreateUpload(upload)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	render.JSON(w, r, resp.PresignedUpload(presigned))
+}
+
+// FinalizeUpload - Завершение загрузки изображения
+// @Summary Завершить загрузку изображения
+// @Description Забирает загруженный файл из S3, проверяет формат и разрешение, строит все размеры
+// @Description и ставит изображение на место: постер фильма, аватар персоны или пользователя
+// @Tags upload
+// @Produce json
+// @Param id path string true "Id загрузки"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 410 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /uploads/{id}/finalize [post]
+// @Security ApiKeyAuth
+func (c *Controller) FinalizeUpload(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("method", "FinalizeUpload")
+
+	userID, ok := userIDFromContext(w, r, log)
+	if !ok {
+		return
+	}
+
+	result, err := c.uc.FinalizeUpload(r.Context(), chi.URLParam(r, "id"), userID)
+	if err != nil {
+		c.writeError(w, r, log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.FinalizedUpload(result))
+}
+
+func (c *Controller) writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, up.ErrUploadNotFound) || errors.Is(err, up.ErrTargetNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, up.ErrUploadExpired):
+		w.WriteHeader(http.StatusGone)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, up.ErrFileNotUploaded):
+		w.WriteHeader(http.StatusConflict)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, up.ErrFileTooLarge):
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	case errors.Is(err, up.ErrInvalidPurpose) ||
+		errors.Is(err, avatarManager.ErrInvalidTypePoster) || errors.Is(err, avatarManager.ErrInvalidResolutionPoster) ||
+		errors.Is(err, avatarManager.ErrInvalidTypeAvatar) || errors.Is(err, avatarManager.ErrInvalidResolutionAvatar):
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, err.Error()))
+	default:
+		log.Error("upload request failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, up.ErrInternal.Error()))
+	}
+}
+
+func userIDFromContext(w http.ResponseWriter, r *http.Request, log *slog.Logger) (uint, bool) {
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		log.Error("can't get userId from context")
+		w.WriteHeader(http.StatusUnauthorized)
+		render.JSON(w, r, resp.Error(r, "unauthorized"))
+		return 0, false
+	}
+
+	return userID, true
+}
@@ -0,0 +1,109 @@
+package upload
+
+import (
+	"context"
+	"net/http"
+	avatarManager "server/pkg/lib/avatarMenager"
+	"time"
+)
+
+// Назначения загрузок: куда попадет изображение после finalize
+const (
+	PurposeFilmPoster   = "film_poster"   // постер фильма, TargetID - FilmId
+	PurposePersonAvatar = "person_avatar" // аватар персоны, TargetID - PersonId
+	PurposeUserAvatar   = "user_avatar"   // аватар пользователя, TargetID - сам пользователь
+)
+
+// StagingPrefix - папка временных файлов загрузок в каждом бакете назначения, ключ файла - StagingPrefix + id загрузки
+const StagingPrefix = "staging/"
+
+// Purpose - бакет, профиль обработки и наибольший размер файла для назначения загрузки
+type Purpose struct {
+	Bucket  string
+	Profile avatarManager.Profile
+	MaxSize int64
+}
+
+var purposes = map[string]Purpose{
+	PurposeFilmPoster:   {Bucket: "filmposter", Profile: avatarManager.PosterProfile, MaxSize: 10 << 20},
+	PurposePersonAvatar: {Bucket: "actoravatar", Profile: avatarManager.AvatarProfile, MaxSize: 5 << 20},
+	PurposeUserAvatar:   {Bucket: "useravatar", Profile: avatarManager.AvatarProfile, MaxSize: 5 << 20},
+}
+
+// PurposeConfig возвращает настройки назначения, false - неизвестное назначение
+func PurposeConfig(purpose string) (Purpose, bool) {
+	p, ok := purposes[purpose]
+	return p, ok
+}
+
+// Buckets возвращает бакеты всех назначений, в них лежат временные файлы загрузок
+func Buckets() []string {
+	seen := make(map[string]bool, len(purposes))
+	buckets := make([]string, 0, len(purposes))
+	for _, p := range purposes {
+		if !seen[p.Bucket] {
+			seen[p.Bucket] = true
+			buckets = append(buckets, p.Bucket)
+		}
+	}
+	return buckets
+}
+
+// UploadDTO - начатая загрузка. Файл лежит в Bucket по ключу StagingKey до finalize или до очистки cron
+type UploadDTO struct {
+	ID          string    `json:"id"`
+	Purpose     string    `json:"purpose"`
+	TargetID    uint      `json:"target_id"`
+	UserID      uint      `json:"user_id"`
+	Bucket      string    `json:"bucket"`
+	StagingKey  string    `json:"staging_key"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreateAt    time.Time `json:"create_at"`
+}
+
+// PresignedUploadDTO - подписанный запрос для загрузки файла в S3. Клиент отправляет файл методом Method
+// на URL с заголовками Headers до UploadExpiresAt
+type PresignedUploadDTO struct {
+	Upload          *UploadDTO
+	URL             string
+	Method          string
+	Headers         map[string]string
+	UploadExpiresAt time.Time
+}
+
+// FinalizedUploadDTO - результат finalize: адрес папки с размерами изображения, заглушка и цвет
+type FinalizedUploadDTO struct {
+	ID       string
+	Purpose  string
+	TargetID uint
+	URL      string
+	Blurhash string
+	Color    string
+}
+
+type Controller interface {
+	CreateUpload(w http.ResponseWriter, r *http.Request)
+	FinalizeUpload(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	CreateUpload(upload *UploadDTO) (*PresignedUploadDTO, error)
+	FinalizeUpload(ctx context.Context, id string, userID uint) (*FinalizedUploadDTO, error)
+	CleanStaging()
+}
+
+type Repo interface {
+	//DB
+	CreateUpload(upload *UploadDTO) error
+	GetUpload(id string) (*UploadDTO, error)
+	DeleteUpload(id string) error
+	DeleteExpiredUploads(before time.Time) ([]*UploadDTO, error)
+
+	//S3
+	PresignUpload(upload *UploadDTO, ttl time.Duration) (*PresignedUploadDTO, error)
+	GetStagingFile(upload *UploadDTO, maxSize int64) ([]byte, error)
+	DeleteStagingFile(upload *UploadDTO) error
+	DeleteStaleStagingFiles(bucket string, before time.Time) (int, error)
+}
@@ -0,0 +1,14 @@
+package upload
+
+import "errors"
+
+var (
+	ErrInternal        = errors.New("internal server error")
+	ErrUploadNotFound  = errors.New("upload not found")
+	ErrUploadExpired   = errors.New("upload expired, request a new upload url")
+	ErrInvalidPurpose  = errors.New("invalid upload purpose, supported purposes are film_poster, person_avatar, user_avatar")
+	ErrFileTooLarge    = errors.New("file too large, maximum size is 10 MB for posters and 5 MB for avatars")
+	ErrFileNotUploaded = errors.New("file is not uploaded yet, upload the file before finalizing")
+	ErrTargetNotFound  = errors.New("upload target not found")
+	ErrUploadFailed    = errors.New("failed to save uploaded image")
+)
@@ -0,0 +1,49 @@
+package upload
+
+import "time"
+
+type Upload struct {
+	UploadID    string    `gorm:"primaryKey;column:upload_id;type:uuid"`
+	Purpose     string    `gorm:"column:purpose;type:varchar(32);not null"`
+	TargetID    uint      `gorm:"column:target_id;not null"`
+	UserID      uint      `gorm:"column:user_id;not null"`
+	Bucket      string    `gorm:"column:bucket;type:varchar(64);not null"`
+	StagingKey  string    `gorm:"column:staging_key;type:text;not null"`
+	ContentType string    `gorm:"column:content_type;type:varchar(32);not null"`
+	Size        int64     `gorm:"column:size;not null"`
+	ExpiresAt   time.Time `gorm:"column:expires_at;not null"`
+	CreatedAt   time.Time `gorm:"column:create_at"`
+}
+
+func (Upload) TableName() string {
+	return "uploads"
+}
+
+func (u *Upload) ToDTO() *UploadDTO {
+	return &UploadDTO{
+		ID:          u.UploadID,
+		Purpose:     u.Purpose,
+		TargetID:    u.TargetID,
+		UserID:      u.UserID,
+		Bucket:      u.Bucket,
+		StagingKey:  u.StagingKey,
+		ContentType: u.ContentType,
+		Size:        u.Size,
+		ExpiresAt:   u.ExpiresAt,
+		CreateAt:    u.CreatedAt,
+	}
+}
+
+func FromDTO(dto *UploadDTO) *Upload {
+	return &Upload{
+		UploadID:    dto.ID,
+		Purpose:     dto.Purpose,
+		TargetID:    dto.TargetID,
+		UserID:      dto.UserID,
+		Bucket:      dto.Bucket,
+		StagingKey:  dto.StagingKey,
+		ContentType: dto.ContentType,
+		Size:        dto.Size,
+		ExpiresAt:   dto.ExpiresAt,
+	}
+}
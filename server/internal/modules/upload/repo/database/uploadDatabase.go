@@ -0,0 +1,74 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	up "server/internal/modules/upload"
+	"time"
+)
+
+type UploadDatabase struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewUploadDatabase(db *gorm.DB, log *slog.Logger) *UploadDatabase {
+	return &UploadDatabase{
+		db:  db,
+		log: log,
+	}
+}
+
+func (db *UploadDatabase) CreateUpload(upload *up.UploadDTO) error {
+	uploadModel := up.FromDTO(upload)
+	if err := db.db.Create(uploadModel).Error; err != nil {
+		db.log.Error("failed to create upload", "error", err)
+		return up.ErrInternal
+	}
+	*upload = *uploadModel.ToDTO()
+
+	return nil
+}
+
+func (db *UploadDatabase) GetUpload(id string) (*up.UploadDTO, error) {
+	var uploadModel up.Upload
+	err := db.db.Where("upload_id = ?", id).First(&uploadModel).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, up.ErrUploadNotFound
+	} else if err != nil {
+		db.log.Error("failed to get upload", "error", err, "uploadID", id)
+		return nil, up.ErrInternal
+	}
+
+	return uploadModel.ToDTO(), nil
+}
+
+func (db *UploadDatabase) DeleteUpload(id string) error {
+	if err := db.db.Where("upload_id = ?", id).Delete(&up.Upload{}).Error; err != nil {
+		db.log.Error("failed to delete upload", "error", err, "uploadID", id)
+		return up.ErrInternal
+	}
+
+	return nil
+}
+
+// DeleteExpiredUploads удаляет загрузки, которые истекли раньше before, и возвращает их для удаления файлов
+func (db *UploadDatabase) DeleteExpiredUploads(before time.Time) ([]*up.UploadDTO, error) {
+	var models []up.Upload
+	err := db.db.Clauses(clause.Returning{}).
+		Where("expires_at < ?", before).
+		Delete(&models).Error
+	if err != nil {
+		db.log.Error("failed to delete expired uploads", "error", err)
+		return nil, up.ErrInternal
+	}
+
+	uploads := make([]*up.UploadDTO, 0, len(models))
+	for i := range models {
+		uploads = append(uploads, models[i].ToDTO())
+	}
+
+	return uploads, nil
+}
@@ -0,0 +1,55 @@
+package s3
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/init/blob"
+	up "server/internal/modules/upload"
+	"time"
+)
+
+type UploadS3 struct {
+	log   *slog.Logger
+	store blob.Store
+}
+
+func NewUploadS3(log *slog.Logger, store blob.Store) *UploadS3 {
+	return &UploadS3{log: log, store: store}
+}
+
+func (s *UploadS3) PresignUpload(upload *up.UploadDTO, ttl time.Duration) (*up.PresignedUploadDTO, error) {
+	req, err := s.store.PresignPut(upload.Bucket, upload.StagingKey, upload.ContentType, upload.Size, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &up.PresignedUploadDTO{
+		Upload:  upload,
+		URL:     req.URL,
+		Method:  req.Method,
+		Headers: req.Headers,
+	}, nil
+}
+
+func (s *UploadS3) GetStagingFile(upload *up.UploadDTO, maxSize int64) ([]byte, error) {
+	data, err := s.store.Get(upload.Bucket, upload.StagingKey, maxSize)
+	switch {
+	case errors.Is(err, blob.ErrNotFound):
+		return nil, up.ErrFileNotUploaded
+	case errors.Is(err, blob.ErrTooLarge):
+		return nil, up.ErrFileTooLarge
+	case err != nil:
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (s *UploadS3) DeleteStagingFile(upload *up.UploadDTO) error {
+	return s.store.Delete(upload.Bucket, upload.StagingKey)
+}
+
+// DeleteStaleStagingFiles удаляет временные файлы бакета, загруженные раньше before
+func (s *UploadS3) DeleteStaleStagingFiles(bucket string, before time.Time) (int, error) {
+	return s.store.DeleteOlderThan(bucket, up.StagingPrefix, before)
+}
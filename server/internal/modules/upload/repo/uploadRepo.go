@@ -0,0 +1,64 @@
+package repo
+
+import (
+	up "server/internal/modules/upload"
+	"time"
+)
+
+type UploadDB interface {
+	CreateUpload(upload *up.UploadDTO) error
+	GetUpload(id string) (*up.UploadDTO, error)
+	DeleteUpload(id string) error
+	DeleteExpiredUploads(before time.Time) ([]*up.UploadDTO, error)
+}
+
+type UploadS3 interface {
+	PresignUpload(upload *up.UploadDTO, ttl time.Duration) (*up.PresignedUploadDTO, error)
+	GetStagingFile(upload *up.UploadDTO, maxSize int64) ([]byte, error)
+	DeleteStagingFile(upload *up.UploadDTO) error
+	DeleteStaleStagingFiles(bucket string, before time.Time) (int, error)
+}
+
+type Repo struct {
+	db UploadDB
+	s3 UploadS3
+}
+
+func NewUploadRepo(db UploadDB, s3 UploadS3) *Repo {
+	return &Repo{
+		db: db,
+		s3: s3,
+	}
+}
+
+func (r *Repo) CreateUpload(upload *up.UploadDTO) error {
+	return r.db.CreateUpload(upload)
+}
+
+func (r *Repo) GetUpload(id string) (*up.UploadDTO, error) {
+	return r.db.GetUpload(id)
+}
+
+func (r *Repo) DeleteUpload(id string) error {
+	return r.db.DeleteUpload(id)
+}
+
+func (r *Repo) DeleteExpiredUploads(before time.Time) ([]*up.UploadDTO, error) {
+	return r.db.DeleteExpiredUploads(before)
+}
+
+func (r *Repo) PresignUpload(upload *up.UploadDTO, ttl time.Duration) (*up.PresignedUploadDTO, error) {
+	return r.s3.PresignUpload(upload, ttl)
+}
+
+func (r *Repo) GetStagingFile(upload *up.UploadDTO, maxSize int64) ([]byte, error) {
+	return r.s3.GetStagingFile(upload, maxSize)
+}
+
+func (r *Repo) DeleteStagingFile(upload *up.UploadDTO) error {
+	return r.s3.DeleteStagingFile(upload)
+}
+
+func (r *Repo) DeleteStaleStagingFiles(bucket string, before time.Time) (int, error) {
+	return r.s3.DeleteStaleStagingFiles(bucket, before)
+}
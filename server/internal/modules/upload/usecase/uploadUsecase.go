@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	f "server/internal/modules/film"
+	per "server/internal/modules/person"
+	up "server/internal/modules/upload"
+	u "server/internal/modules/user"
+	"server/internal/modules/user/profile"
+	avatarManager "server/pkg/lib/avatarMenager"
+	"time"
+)
+
+const (
+	// presignTTL - сколько действует подписанная ссылка на загрузку файла
+	presignTTL = 15 * time.Minute
+	// uploadTTL - сколько после начала загрузки можно вызвать finalize, потом временный файл удаляет cron
+	uploadTTL = time.Hour
+)
+
+// FilmService - постер фильма после finalize (модуль film)
+type FilmService interface {
+	GetFilmByID(id uint) (*f.FilmDTO, error)
+	SetPosterImage(ctx context.Context, id uint, img *avatarManager.Image) (*f.FilmDTO, error)
+}
+
+// PersonService - аватар персоны после finalize (модуль person)
+type PersonService interface {
+	GetPerson(personId uint) (*per.PersonDTO, error)
+	SetAvatarImage(ctx context.Context, personId uint, img *avatarManager.Image) (*per.PersonDTO, error)
+}
+
+// ProfileService - аватар пользователя после finalize (модуль user/profile)
+type ProfileService interface {
+	SetAvatarImage(ctx context.Context, userId uint, img *avatarManager.Image) (*profile.UserProfile, error)
+}
+
+type UploadUseCase struct {
+	log      *slog.Logger
+	rp       up.Repo
+	films    FilmService
+	persons  PersonService
+	profiles ProfileService
+}
+
+func NewUploadUseCase(log *slog.Logger, rp up.Repo, films FilmService, persons PersonService, profiles ProfileService) *UploadUseCase {
+	return &UploadUseCase{
+		log:      log,
+		rp:       rp,
+		films:    films,
+		persons:  persons,
+		profiles: profiles,
+	}
+}
+
+// CreateUpload начинает загрузку: проверяет назначение и размер, сохраняет загрузку и подписывает
+// ссылку на временный ключ в бакете назначения. Аватар пользователя всегда загружается себе
+func (uc *UploadUseCase) CreateUpload(upload *up.UploadDTO) (*up.PresignedUploadDTO, error) {
+	purpose, ok := up.PurposeConfig(upload.Purpose)
+	if !ok {
+		return nil, up.ErrInvalidPurpose
+	}
+	if upload.Size > purpose.MaxSize {
+		return nil, up.ErrFileTooLarge
+	}
+
+	if upload.Purpose == up.PurposeUserAvatar {
+		upload.TargetID = upload.UserID
+	}
+	if err := uc.ensureTarget(upload); err != nil {
+		return nil, err
+	}
+
+	upload.ID = uuid.NewString()
+	upload.Bucket = purpose.Bucket
+	upload.StagingKey = up.StagingPrefix + upload.ID
+	upload.ExpiresAt = time.Now().Add(uploadTTL)
+	if err := uc.rp.CreateUpload(upload); err != nil {
+		return nil, err
+	}
+
+	presigned, err := uc.rp.PresignUpload(upload, presignTTL)
+	if err != nil {
+		uc.log.Error("failed to presign upload", "error", err, "uploadID", upload.ID)
+		return nil, up.ErrInternal
+	}
+	presigned.UploadExpiresAt = time.Now().Add(presignTTL)
+
+	return presigned, nil
+}
+
+// FinalizeUpload забирает загруженный файл из S3, проверяет его и строит размеры по профилю назначения,
+// сохраняет изображение на место (постер фильма, аватар) и удаляет временный файл и загрузку
+func (uc *UploadUseCase) FinalizeUpload(ctx context.Context, id string, userID uint) (*up.FinalizedUploadDTO, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, up.ErrUploadNotFound
+	}
+
+	upload, err := uc.rp.GetUpload(id)
+	if err != nil {
+		return nil, err
+	}
+	// чужая загрузка не отличается от несуществующей
+	if upload.UserID != userID {
+		return nil, up.ErrUploadNotFound
+	}
+	if time.Now().After(upload.ExpiresAt) {
+		return nil, up.ErrUploadExpired
+	}
+
+	purpose, ok := up.PurposeConfig(upload.Purpose)
+	if !ok {
+		return nil, up.ErrInvalidPurpose
+	}
+
+	data, err := uc.rp.GetStagingFile(upload, purpose.MaxSize)
+	if err != nil {
+		if errors.Is(err, up.ErrFileNotUploaded) || errors.Is(err, up.ErrFileTooLarge) {
+			return nil, err
+		}
+		uc.log.Error("failed to get staging file", "error", err, "uploadID", id)
+		return nil, up.ErrInternal
+	}
+
+	img, err := avatarManager.ProcessBytes(data, purpose.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := uc.saveImage(ctx, upload, img)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.rp.DeleteStagingFile(upload); err != nil {
+		uc.log.Error("failed to delete staging file", "error", err, "uploadID", id)
+	}
+	if err := uc.rp.DeleteUpload(id); err != nil {
+		uc.log.Error("failed to delete finalized upload", "error", err, "uploadID", id)
+	}
+
+	return result, nil
+}
+
+// CleanStaging удаляет истекшие загрузки с их временными файлами и временные файлы без загрузок,
+// например оставшиеся после сбоя между удалением загрузки и файла. Запускается по cron
+func (uc *UploadUseCase) CleanStaging() {
+	now := time.Now()
+
+	expired, err := uc.rp.DeleteExpiredUploads(now)
+	if err != nil {
+		uc.log.Error("failed to delete expired uploads", "error", err)
+	}
+	for _, upload := range expired {
+		if err := uc.rp.DeleteStagingFile(upload); err != nil {
+			uc.log.Error("failed to delete staging file", "error", err, "uploadID", upload.ID)
+		}
+	}
+
+	stale := 0
+	for _, bucket := range up.Buckets() {
+		deleted, err := uc.rp.DeleteStaleStagingFiles(bucket, now.Add(-uploadTTL))
+		if err != nil {
+			uc.log.Error("failed to delete stale staging files", "error", err, "bucket", bucket)
+		}
+		stale += deleted
+	}
+
+	uc.log.Info("cleaned staging uploads", slog.Int("expired", len(expired)), slog.Int("stale_files", stale))
+}
+
+// ensureTarget проверяет, что фильм или персона, для которых начата загрузка, существуют
+func (uc *UploadUseCase) ensureTarget(upload *up.UploadDTO) error {
+	var err error
+	switch upload.Purpose {
+	case up.PurposeFilmPoster:
+		_, err = uc.films.GetFilmByID(upload.TargetID)
+	case up.PurposePersonAvatar:
+		_, err = uc.persons.GetPerson(upload.TargetID)
+	}
+
+	return mapTargetError(err)
+}
+
+func (uc *UploadUseCase) saveImage(ctx context.Context, upload *up.UploadDTO, img *avatarManager.Image) (*up.FinalizedUploadDTO, error) {
+	result := &up.FinalizedUploadDTO{
+		ID:       upload.ID,
+		Purpose:  upload.Purpose,
+		TargetID: upload.TargetID,
+		Blurhash: img.Blurhash,
+		Color:    img.DominantColor,
+	}
+
+	switch upload.Purpose {
+	case up.PurposeFilmPoster:
+		film, err := uc.films.SetPosterImage(ctx, upload.TargetID, img)
+		if err != nil {
+			return nil, uc.saveError(err, upload)
+		}
+		result.URL = film.PosterURL
+	case up.PurposePersonAvatar:
+		person, err := uc.persons.SetAvatarImage(ctx, upload.TargetID, img)
+		if err != nil {
+			return nil, uc.saveError(err, upload)
+		}
+		result.URL = *person.AvatarUrl
+	case up.PurposeUserAvatar:
+		user, err := uc.profiles.SetAvatarImage(ctx, upload.TargetID, img)
+		if err != nil {
+			return nil, uc.saveError(err, upload)
+		}
+		result.URL = *user.AvatarUrl
+	default:
+		return nil, up.ErrInvalidPurpose
+	}
+
+	return result, nil
+}
+
+func (uc *UploadUseCase) saveError(err error, upload *up.UploadDTO) error {
+	if err = mapTargetError(err); errors.Is(err, up.ErrTargetNotFound) {
+		return err
+	}
+	uc.log.Error("failed to save uploaded image", "error", err, "uploadID", upload.ID, "purpose", upload.Purpose)
+	return up.ErrUploadFailed
+}
+
+func mapTargetError(err error) error {
+	if errors.Is(err, f.ErrFilmNotFound) || errors.Is(err, per.ErrPersonNotFound) || errors.Is(err, u.ErrUserNotFound) {
+		return up.ErrTargetNotFound
+	}
+	return err
+}
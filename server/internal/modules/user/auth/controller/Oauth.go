@@ -32,11 +32,11 @@ func (c *AuthController) Oauth(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, u.ErrUnsupportedProvider):
 			log.Info("unsupported provider", slog.String("provider", provider))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("unsupported provider"))
+			render.JSON(w, r, resp.Error(r, "unsupported provider"))
 		default:
 			log.Error("internal", slog.String("error", err.Error()))
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("internal server error"))
+			render.JSON(w, r, resp.Error(r, "internal server error"))
 		}
 		return
 	}
@@ -65,17 +65,17 @@ func (c *AuthController) OauthCallback(w http.ResponseWriter, r *http.Request) {
 	state := r.URL.Query().Get("state")
 	code := r.URL.Query().Get("code")
 
-	ExistedUser, AccessToken, RefreshToken, err := c.uc.Callback(provider, state, code)
+	ExistedUser, AccessToken, RefreshToken, err := c.uc.Callback(r.Context(), provider, state, code)
 	if err != nil {
 		switch {
 		case errors.Is(err, u.ErrUnsupportedProvider):
 			log.Info("unsupported provider", slog.String("provider", provider))
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("unsupported provider"))
+			render.JSON(w, r, resp.Error(r, "unsupported provider"))
 		default:
 			log.Error("internal", slog.String("error", err.Error()))
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("internal server error"))
+			render.JSON(w, r, resp.Error(r, "internal server error"))
 		}
 		return
 	}
@@ -27,20 +27,20 @@ func (c *AuthController) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, u.ErrNoRefreshToken):
 			w.WriteHeader(http.StatusUnauthorized)
-			render.JSON(w, r, resp.Error(err.Error()))
+			render.JSON(w, r, resp.Error(r, err.Error()))
 		case errors.Is(err, u.ErrInvalidToken):
 			w.WriteHeader(http.StatusUnauthorized)
-			render.JSON(w, r, resp.Error(err.Error()))
+			render.JSON(w, r, resp.Error(r, err.Error()))
 		case errors.Is(err, u.ErrExpiredToken):
 			w.WriteHeader(http.StatusUnauthorized)
-			render.JSON(w, r, resp.Error(err.Error()))
+			render.JSON(w, r, resp.Error(r, err.Error()))
 		case errors.Is(err, u.ErrUserNotFound):
 			w.WriteHeader(http.StatusUnauthorized)
-			render.JSON(w, r, resp.Error(err.Error()))
+			render.JSON(w, r, resp.Error(r, err.Error()))
 		default:
 			log.Error(err.Error())
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(u.ErrInternal.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrInternal.Error()))
 		}
 		return
 	}
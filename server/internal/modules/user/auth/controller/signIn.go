@@ -29,16 +29,16 @@ func (c *AuthController) SignIn(w http.ResponseWriter, r *http.Request) {
 	var req UserSignInRequest
 
 	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		log.Error("failed to decode request body", err)
+		log.Error("failed to decode request body", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("failed to decode request"))
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
 		return
 	}
 
 	if err := c.validate.Struct(req); err != nil {
-		log.Error("failed to validate request", err)
+		log.Error("failed to validate request", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+		render.JSON(w, r, resp.ValidationError(r, err))
 		return
 	}
 
@@ -47,14 +47,14 @@ func (c *AuthController) SignIn(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, u.ErrUserNotFound):
 			w.WriteHeader(http.StatusUnauthorized)
-			render.JSON(w, r, resp.Error("failed email or login or password"))
+			render.JSON(w, r, resp.Error(r, "failed email or login or password"))
 		case errors.Is(err, u.ErrEmailNotConfirmed):
 			w.WriteHeader(http.StatusForbidden)
-			render.JSON(w, r, resp.Error("email not confirmed"))
+			render.JSON(w, r, resp.Error(r, "email not confirmed"))
 		default:
-			log.Error("failed to sign in", err)
+			log.Error("failed to sign in", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("internal server error"))
+			render.JSON(w, r, resp.Error(r, "internal server error"))
 		}
 		return
 	}
@@ -27,33 +27,33 @@ func (c *AuthController) SignUp(w http.ResponseWriter, r *http.Request) {
 	var req UserSignUpRequest
 
 	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		log.Error("failed to decode request body", err)
+		log.Error("failed to decode request body", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("failed to decode request"))
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
 		return
 	}
 
 	if err := c.validate.Struct(req); err != nil {
-		log.Error("failed to validate request", err)
+		log.Error("failed to validate request", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+		render.JSON(w, r, resp.ValidationError(r, err))
 		return
 	}
 
-	if err := c.uc.SignUp(req.Email, req.Login, req.Password); err != nil {
+	if err := c.uc.SignUp(r.Context(), req.Email, req.Login, req.Password); err != nil {
 		switch {
 		case errors.Is(err, u.ErrEmailExists):
 			log.Info("email already exists", slog.String("email", req.Email))
 			w.WriteHeader(http.StatusConflict)
-			render.JSON(w, r, resp.Error(err.Error()))
+			render.JSON(w, r, resp.Error(r, err.Error()))
 		case errors.Is(err, u.ErrLoginExists):
 			log.Info("login already exists", slog.String("login", req.Login))
 			w.WriteHeader(http.StatusConflict)
-			render.JSON(w, r, resp.Error("login already exists"))
+			render.JSON(w, r, resp.Error(r, "login already exists"))
 		default:
-			log.Error("failed to sign up user", err)
+			log.Error("failed to sign up user", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("internal server error"))
+			render.JSON(w, r, resp.Error(r, "internal server error"))
 		}
 		return
 	}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 )
 
@@ -24,15 +25,15 @@ type Controller interface {
 }
 
 type UseCase interface {
-	SignUp(email string, login string, password string) error
+	SignUp(ctx context.Context, email string, login string, password string) error
 	SignIn(email string, login string, password string) (string, string, error)
 	GetAuthURL(provider string) (string, error)
-	Callback(provider, state, code string) (bool, string, string, error)
+	Callback(ctx context.Context, provider, state, code string) (bool, string, string, error)
 	RefreshToken(r *http.Request) (string, error)
 }
 
 type Repo interface {
-	CreateUser(user *UserAuth) (uint, error)
+	CreateUser(ctx context.Context, user *UserAuth) (uint, error)
 	GetUserByEmail(email string) (*UserAuth, error)
 	GetUserByLogin(login string) (*UserAuth, error)
 	GetUserById(id uint) (*UserAuth, error)
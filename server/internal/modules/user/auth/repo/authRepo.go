@@ -1,6 +1,9 @@
 package repo
 
-import "server/internal/modules/user/auth"
+import (
+	"context"
+	"server/internal/modules/user/auth"
+)
 
 type AuthCache interface {
 	SaveStateCode(state string) error
@@ -8,7 +11,7 @@ type AuthCache interface {
 }
 
 type AuthDb interface {
-	CreateUser(user *auth.UserAuth) (uint, error)
+	CreateUser(ctx context.Context, user *auth.UserAuth) (uint, error)
 	GetUserByEmail(email string) (*auth.UserAuth, error)
 	GetUserByLogin(login string) (*auth.UserAuth, error)
 	GetUserById(id uint) (*auth.UserAuth, error)
@@ -26,8 +29,8 @@ func NewRepo(db AuthDb, ch AuthCache) *Repo {
 	}
 }
 
-func (r *Repo) CreateUser(user *auth.UserAuth) (uint, error) {
-	return r.db.CreateUser(user)
+func (r *Repo) CreateUser(ctx context.Context, user *auth.UserAuth) (uint, error) {
+	return r.db.CreateUser(ctx, user)
 }
 
 func (r *Repo) GetUserByEmail(email string) (*auth.UserAuth, error) {
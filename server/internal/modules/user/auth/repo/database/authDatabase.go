@@ -1,11 +1,13 @@
 package database
 
 import (
+	"context"
 	"errors"
 	"gorm.io/gorm"
 	"log/slog"
 	"server/internal/modules/user"
 	"server/internal/modules/user/auth"
+	"server/pkg/lib/audit"
 	"strings"
 )
 
@@ -22,10 +24,16 @@ func NewAuthDatabase(db *gorm.DB, log *slog.Logger) *AuthDatabase {
 	}
 }
 
-func (db *AuthDatabase) CreateUser(User *auth.UserAuth) (uint, error) {
+func (db *AuthDatabase) CreateUser(ctx context.Context, User *auth.UserAuth) (uint, error) {
 	userModel := user.FromAuthUser(User)
 
-	if err := db.db.Create(userModel).Error; err != nil {
+	err := db.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(userModel).Error; err != nil {
+			return err
+		}
+		return audit.User.Record(ctx, tx, audit.ActionCreate, userModel.UserId, nil)
+	})
+	if err != nil {
 		db.log.Error(err.Error())
 		if strings.Contains(err.Error(), "login") {
 			return 0, user.ErrLoginExists
@@ -30,7 +30,7 @@ func NewAuthUseCase(log *slog.Logger, rp auth.Repo) *AuthUseCase {
 	}
 }
 
-func (uc *AuthUseCase) SignUp(email string, login string, password string) error {
+func (uc *AuthUseCase) SignUp(ctx context.Context, email string, login string, password string) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return u.ErrInternal
@@ -43,7 +43,7 @@ func (uc *AuthUseCase) SignUp(email string, login string, password string) error
 		HashedPassword: &hashPassword,
 	}
 
-	_, err = uc.rp.CreateUser(user)
+	_, err = uc.rp.CreateUser(ctx, user)
 	return err
 }
 
@@ -158,7 +158,7 @@ func (uc *AuthUseCase) GetAuthURL(provider string) (string, error) {
 	return config.AuthCodeURL(state, oauth2.AccessTypeOnline), nil
 }
 
-func (uc *AuthUseCase) Callback(provider, state, code string) (bool, string, string, error) {
+func (uc *AuthUseCase) Callback(ctx context.Context, provider, state, code string) (bool, string, string, error) {
 	config, ok := oauthConfigs[provider]
 	if !ok {
 		return false, "", "", u.ErrUnsupportedProvider
@@ -169,12 +169,12 @@ func (uc *AuthUseCase) Callback(provider, state, code string) (bool, string, str
 		return false, "", "", err
 	}
 
-	token, err := config.Exchange(context.Background(), code)
+	token, err := config.Exchange(ctx, code)
 	if err != nil {
 		return false, "", "", err
 	}
 
-	client := config.Client(context.Background(), token)
+	client := config.Client(ctx, token)
 	user, err := fetchUserInfo(client, provider)
 	if err != nil {
 		return false, "", "", err
@@ -182,11 +182,11 @@ func (uc *AuthUseCase) Callback(provider, state, code string) (bool, string, str
 
 	existingUser, err := uc.rp.GetUserByEmail(user.Email)
 	if errors.Is(err, u.ErrUserNotFound) {
-		userId, err := uc.rp.CreateUser(user)
+		userId, err := uc.rp.CreateUser(ctx, user)
 		if err != nil {
 			if errors.Is(err, u.ErrLoginExists) {
 				user.Login = ""
-				userId, err = uc.rp.CreateUser(user)
+				userId, err = uc.rp.CreateUser(ctx, user)
 				if err != nil {
 					return false, "", "", err
 				}
@@ -43,16 +43,16 @@ func (c *EmailController) SendConfirmedEmailCode(w http.ResponseWriter, r *http.
 	var req SendConfirmedEmailCodeRequest
 
 	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		log.Error("failed to decode request body", err)
+		log.Error("failed to decode request body", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("failed to decode request"))
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
 		return
 	}
 
 	if err := c.validate.Struct(req); err != nil {
-		log.Info("failed to validate request data", err)
+		log.Info("failed to validate request data", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+		render.JSON(w, r, resp.ValidationError(r, err))
 		return
 	}
 
@@ -61,13 +61,13 @@ func (c *EmailController) SendConfirmedEmailCode(w http.ResponseWriter, r *http.
 		switch {
 		case errors.Is(err, u.ErrEmailAlreadyConfirmed):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(u.ErrEmailAlreadyConfirmed.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrEmailAlreadyConfirmed.Error()))
 		case errors.Is(err, u.ErrUserNotFound):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(u.ErrUserNotFound.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrUserNotFound.Error()))
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("internal server error"))
+			render.JSON(w, r, resp.Error(r, "internal server error"))
 		}
 
 		return
@@ -95,15 +95,15 @@ func (c *EmailController) EmailConfirmed(w http.ResponseWriter, r *http.Request)
 	var req EmailConfirmedRequest
 
 	if err := render.DecodeJSON(r.Body, &req); err != nil {
-		log.Error("failed to decode request body", err)
+		log.Error("failed to decode request body", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("failed to decode request"))
+		render.JSON(w, r, resp.Error(r, "failed to decode request"))
 		return
 	}
 
 	if err := c.validate.Struct(req); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+		render.JSON(w, r, resp.ValidationError(r, err))
 		return
 	}
 
@@ -111,17 +111,17 @@ func (c *EmailController) EmailConfirmed(w http.ResponseWriter, r *http.Request)
 		switch {
 		case errors.Is(err, u.ErrUserNotFound):
 			w.WriteHeader(http.StatusNotFound)
-			render.JSON(w, r, resp.Error(u.ErrUserNotFound.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrUserNotFound.Error()))
 		case errors.Is(err, u.ErrEmailAlreadyConfirmed):
 			w.WriteHeader(http.StatusConflict)
-			render.JSON(w, r, resp.Error(u.ErrEmailAlreadyConfirmed.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrEmailAlreadyConfirmed.Error()))
 		case errors.Is(err, u.ErrInvalidConfirmCode):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(u.ErrEmailNotConfirmed.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrEmailNotConfirmed.Error()))
 		default:
-			log.Error("failed to confirm email", err)
+			log.Error("failed to confirm email", "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("internal server error"))
+			render.JSON(w, r, resp.Error(r, "internal server error"))
 		}
 		return
 	}
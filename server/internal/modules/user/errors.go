@@ -18,7 +18,7 @@ var (
 	ErrInvalidConfirmCode      = errors.New("invalid confirm code")
 	ErrInvalidSizeAvatar       = errors.New("file size exceeds 1 MB limit")
 	ErrInvalidTypeAvatar       = errors.New("invalid type avatar, supported avatar formats are jpg, jpeg, png, webp, or no animated gif")
-	ErrInvalidResolutionAvatar = errors.New("invalid resolution avatar, supported avatar resolution 1x1")
+	ErrInvalidResolutionAvatar = errors.New("invalid resolution avatar, minimal avatar resolution 128x128")
 	ErrInvalidAvatarFile       = errors.New("invalid avatar file")
 	ErrUserAuthWithOauth2      = errors.New("pls auth with oauth2")
 )
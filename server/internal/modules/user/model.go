@@ -1,20 +1,24 @@
 package user
 
 import (
+	"gorm.io/gorm"
 	"server/internal/modules/user/auth"
 	"server/internal/modules/user/profile"
 	"time"
 )
 
 type User struct {
-	UserId         uint      `gorm:"primaryKey;column:user_id"`
-	HashedPassword *string   `gorm:"size:255;column:hashed_password"`
-	IsAdmin        bool      `gorm:"default:false;column:is_admin"`
-	Login          string    `gorm:"unique;size:100;not null;column:login"`
-	Email          string    `gorm:"unique;size:100;not null;column:email"`
-	VerifiedEmail  bool      `gorm:"default:false;column:verified_email"`
-	AvatarURL      string    `gorm:"default:'https://useravatar.storage-173.s3hoster.by/default/';column:avatar_url"`
-	CreatedAt      time.Time `gorm:"column:create_at"`
+	UserId         uint           `gorm:"primaryKey;column:user_id"`
+	HashedPassword *string        `gorm:"size:255;column:hashed_password"`
+	IsAdmin        bool           `gorm:"default:false;column:is_admin"`
+	Login          string         `gorm:"unique;size:100;not null;column:login"`
+	Email          string         `gorm:"unique;size:100;not null;column:email"`
+	VerifiedEmail  bool           `gorm:"default:false;column:verified_email"`
+	AvatarURL      string         `gorm:"default:'https://useravatar.storage-173.s3hoster.by/default/';column:avatar_url"`
+	AvatarBlurhash string         `gorm:"default:'';not null;column:avatar_blurhash"`
+	AvatarColor    string         `gorm:"default:'';not null;column:avatar_color"`
+	CreatedAt      time.Time      `gorm:"column:create_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"column:deleted_at"`
 }
 
 func ToAuthUser(user *User) *auth.UserAuth {
@@ -41,17 +45,25 @@ func FromAuthUser(user *auth.UserAuth) *User {
 
 func ToProfileUser(user *User) *profile.UserProfile {
 	return &profile.UserProfile{
-		UserId:    user.UserId,
-		Email:     &user.Email,
-		Login:     &user.Login,
-		AvatarUrl: &user.AvatarURL,
+		UserId:         user.UserId,
+		Email:          &user.Email,
+		Login:          &user.Login,
+		AvatarUrl:      &user.AvatarURL,
+		AvatarBlurhash: user.AvatarBlurhash,
+		AvatarColor:    user.AvatarColor,
 	}
 }
 
 func FromProfileUser(user *profile.UserProfile) *User {
-	return &User{
-		UserId:    user.UserId,
-		Login:     *user.Login,
-		AvatarURL: *user.AvatarUrl,
+	userModel := &User{
+		UserId:         user.UserId,
+		Login:          *user.Login,
+		AvatarBlurhash: user.AvatarBlurhash,
+		AvatarColor:    user.AvatarColor,
 	}
+	if user.AvatarUrl != nil {
+		userModel.AvatarURL = *user.AvatarUrl
+	}
+
+	return userModel
 }
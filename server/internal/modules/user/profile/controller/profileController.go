@@ -6,9 +6,11 @@ import (
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	u "server/internal/modules/user"
 	"server/internal/modules/user/profile"
+	"server/pkg/lib/patch"
 	resp "server/pkg/lib/response"
 	"time"
 )
@@ -21,6 +23,7 @@ type ProfileController struct {
 
 func NewProfileController(log *slog.Logger, uc profile.UseCase) *ProfileController {
 	validate := validator.New()
+	patch.RegisterValidation(validate)
 	return &ProfileController{
 		log:      log,
 		uc:       uc,
@@ -32,6 +35,7 @@ func NewProfileController(log *slog.Logger, uc profile.UseCase) *ProfileControll
 // @Summary      Update user profile
 // @Description  Updates the user profile information, including login and avatar.
 // @Description  The request accepts a JSON part with the login data, an optional avatar file, and a query parameter `reset_avatar`.
+// @Description  Larger avatars can be uploaded directly to S3 via /uploads (purpose user_avatar).
 // @Tags         profile
 // @Accept       multipart/form-data
 // @Produce      json
@@ -53,7 +57,7 @@ func (c *ProfileController) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		log.Error("can't get userId from context")
 		w.WriteHeader(http.StatusUnauthorized)
-		render.JSON(w, r, resp.Error("unauthorized"))
+		render.JSON(w, r, resp.Error(r, "unauthorized"))
 		return
 	}
 
@@ -61,12 +65,12 @@ func (c *ProfileController) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		if err.Error() == "http: request body too large" {
 			log.Error("request body exceeds maximum allowed size")
 			w.WriteHeader(http.StatusRequestEntityTooLarge)
-			render.JSON(w, r, resp.Error(u.ErrInvalidSizeAvatar.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrInvalidSizeAvatar.Error()))
 			return
 		}
-		log.Error("failed to parse multipart form", err)
+		log.Error("failed to parse multipart form", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid multipart form"))
+		render.JSON(w, r, resp.Error(r, "invalid multipart form"))
 		return
 	}
 
@@ -74,16 +78,16 @@ func (c *ProfileController) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	jsonData := r.FormValue("json")
 	if err := json.Unmarshal([]byte(jsonData), &req); err != nil {
-		log.Error("failed to decode JSON part", err)
+		log.Error("failed to decode JSON part", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error("invalid JSON part"))
+		render.JSON(w, r, resp.Error(r, "invalid JSON part"))
 		return
 	}
 
 	if err := c.validate.Struct(req); err != nil {
-		log.Info("failed to validate request data", err)
+		log.Info("failed to validate request data", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.ValidationError(err))
+		render.JSON(w, r, resp.ValidationError(r, err))
 		return
 	}
 
@@ -98,35 +102,35 @@ func (c *ProfileController) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	if errors.Is(err, http.ErrMissingFile) {
 		file = nil
 	} else if err != nil {
-		log.Error("failed to retrieve avatar file", err)
+		log.Error("failed to retrieve avatar file", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
-		render.JSON(w, r, resp.Error(u.ErrInvalidAvatarFile.Error()))
+		render.JSON(w, r, resp.Error(r, u.ErrInvalidAvatarFile.Error()))
 		return
 	} else {
 		defer func() {
 			if file != nil {
 				if err := file.Close(); err != nil {
-					log.Error("failed to close avatar file", err)
+					log.Error("failed to close avatar file", "error", err)
 				}
 			}
 		}()
 	}
 
-	if err := c.uc.UpdateUser(user, &file); err != nil {
+	if err := c.uc.UpdateUser(r.Context(), user, &file); err != nil {
 		log.Error(err.Error())
 		switch {
 		case errors.Is(err, u.ErrUserNotFound):
 			w.WriteHeader(http.StatusNotFound)
-			render.JSON(w, r, resp.Error(err.Error()))
+			render.JSON(w, r, resp.Error(r, err.Error()))
 		case errors.Is(err, u.ErrUserAuthWithOauth2):
 			w.WriteHeader(http.StatusUnauthorized)
-			render.JSON(w, r, resp.Error(u.ErrUserAuthWithOauth2.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrUserAuthWithOauth2.Error()))
 		case errors.Is(err, u.ErrInvalidResolutionAvatar) || errors.Is(err, u.ErrInvalidTypeAvatar):
 			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
+			render.JSON(w, r, resp.Error(r, err.Error()))
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(u.ErrInternal.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrInternal.Error()))
 		}
 		return
 	}
@@ -135,6 +139,95 @@ func (c *ProfileController) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, resp.OK())
 }
 
+// PatchUser
+// @Summary      Partially update user profile
+// @Description  Changes only the fields present in the body (JSON Merge Patch, RFC 7396).
+// @Description  "avatar": null resets the avatar to default, new avatars are uploaded via /uploads (purpose user_avatar).
+// @Tags         profile
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        json body PatchUserRequest true "Changed fields"
+// @Success      200  {object} response.Response  "Updated profile"
+// @Failure      400  {object} response.Response  "Invalid request data"
+// @Failure      401  {object} response.Response  "Unauthorized"
+// @Failure      404  {object} response.Response  "User not found"
+// @Failure      415  {object} response.Response  "Unsupported content type"
+// @Failure      500  {object} response.Response  "Internal server error"
+// @Router       /profile [patch]
+// @Security     ApiKeyAuth
+func (c *ProfileController) PatchUser(w http.ResponseWriter, r *http.Request) {
+	log := c.log.With("op", "PatchUserHandler")
+
+	userId, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		log.Error("can't get userId from context")
+		w.WriteHeader(http.StatusUnauthorized)
+		render.JSON(w, r, resp.Error(r, "unauthorized"))
+		return
+	}
+
+	var req PatchUserRequest
+	if err := patch.Decode(r, &req); err != nil {
+		switch {
+		case errors.Is(err, patch.ErrUnsupportedContentType):
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		render.JSON(w, r, resp.Error(r, err.Error()))
+		return
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+	if err := patch.Required(map[string]patch.Clearable{"login": req.Login}); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(r, err))
+		return
+	}
+	if req.Avatar.Present() {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(r, "avatar can only be reset with null, upload a new one via /uploads"))
+		return
+	}
+
+	user := &profile.UserProfile{
+		UserId:      userId,
+		ResetAvatar: req.Avatar.IsNull(),
+	}
+	if req.Login.Present() {
+		user.Login = &req.Login.Value
+	}
+
+	var noAvatar multipart.File
+	if err := c.uc.UpdateUser(r.Context(), user, &noAvatar); err != nil {
+		switch {
+		case errors.Is(err, u.ErrUserNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(r, err.Error()))
+		default:
+			log.Error("failed to patch user", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(r, u.ErrInternal.Error()))
+		}
+		return
+	}
+
+	updated, err := c.uc.GetUser(userId)
+	if err != nil {
+		log.Error("failed to get user after patch", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(r, u.ErrInternal.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, resp.UserProfile(updated))
+}
+
 // GetUser
 // @Summary      Get user profile
 // @Description  Retrieves the user profile information.
@@ -153,7 +246,7 @@ func (c *ProfileController) GetUser(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		log.Error("can't get userId from context")
 		w.WriteHeader(http.StatusUnauthorized)
-		render.JSON(w, r, resp.Error("unauthorized"))
+		render.JSON(w, r, resp.Error(r, "unauthorized"))
 		return
 	}
 
@@ -163,10 +256,10 @@ func (c *ProfileController) GetUser(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, u.ErrUserNotFound):
 			w.WriteHeader(http.StatusNotFound)
-			render.JSON(w, r, resp.Error(u.ErrUserNotFound.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrUserNotFound.Error()))
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(u.ErrInternal.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrInternal.Error()))
 		}
 		return
 	}
@@ -192,11 +285,11 @@ func (c *ProfileController) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		log.Error("can't get userId from context")
 		w.WriteHeader(http.StatusUnauthorized)
-		render.JSON(w, r, resp.Error("unauthorized"))
+		render.JSON(w, r, resp.Error(r, "unauthorized"))
 		return
 	}
 
-	if err := c.uc.DeleteUser(userId); err != nil {
+	if err := c.uc.DeleteUser(r.Context(), userId); err != nil {
 		log.Error(err.Error())
 		switch {
 		case errors.Is(err, u.ErrInternal):
@@ -204,7 +297,7 @@ func (c *ProfileController) DeleteUser(w http.ResponseWriter, r *http.Request) {
 			render.JSON(w, r, u.ErrInternal.Error())
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(u.ErrInternal.Error()))
+			render.JSON(w, r, resp.Error(r, u.ErrInternal.Error()))
 		}
 		return
 	}
@@ -1,5 +1,17 @@
 package controller
 
+import (
+	"encoding/json"
+	"server/pkg/lib/patch"
+)
+
 type UpdateUserRequest struct {
 	Login *string `json:"login,omitempty" validate:"omitempty,min=3,max=50"`
 }
+
+// PatchUserRequest - тело PATCH /profile (RFC 7396): поля, которых нет в теле, не меняются.
+// avatar принимает только null - сброс аватара на стандартный
+type PatchUserRequest struct {
+	Login  patch.Field[string]          `json:"login" validate:"omitempty,min=3,max=50"`
+	Avatar patch.Field[json.RawMessage] `json:"avatar"`
+}
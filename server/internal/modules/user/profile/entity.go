@@ -1,34 +1,41 @@
 package profile
 
 import (
+	"context"
 	"mime/multipart"
 	"net/http"
 )
 
 type UserProfile struct {
 	UserId      uint
-	AvatarUrl   *string
+	AvatarUrl   *string // Папка с размерами аватара (avatarManager.AvatarProfile)
 	Login       *string
 	Email       *string
 	ResetAvatar bool
+
+	AvatarBlurhash string
+	AvatarColor    string
 }
 
 type Controller interface {
 	UpdateUser(w http.ResponseWriter, r *http.Request)
+	PatchUser(w http.ResponseWriter, r *http.Request)
 	GetUser(w http.ResponseWriter, r *http.Request)
 	DeleteUser(w http.ResponseWriter, r *http.Request)
 }
 
 type UseCase interface {
-	UpdateUser(profile *UserProfile, avatar *multipart.File) error
+	UpdateUser(ctx context.Context, profile *UserProfile, avatar *multipart.File) error
 	GetUser(userId uint) (*UserProfile, error)
-	DeleteUser(userId uint) error
+	DeleteUser(ctx context.Context, userId uint) error
+	CleanupUser(userId uint, login string)
 }
 
 type Repo interface {
 	GetUserById(userId uint) (*UserProfile, error)
-	UpdateUser(user *UserProfile) error
-	UploadAvatar(avatarSmall []byte, avatarLarge []byte, login *string, userId uint) (*string, error)
-	DeleteUser(userId uint) error
+	UpdateUser(ctx context.Context, user *UserProfile) error
+	UploadAvatar(variants map[string][]byte, login *string, userId uint) (*string, error)
+	DeleteUser(ctx context.Context, userId uint) error
 	DeleteAvatar(login *string, userId uint) error
+	DefaultAvatarURL() string
 }
@@ -1,11 +1,13 @@
 package database
 
 import (
+	"context"
 	"errors"
 	"gorm.io/gorm"
 	"log/slog"
 	u "server/internal/modules/user"
 	"server/internal/modules/user/profile"
+	"server/pkg/lib/audit"
 )
 
 type ProfileFDatabase struct {
@@ -33,40 +35,60 @@ func (db *ProfileFDatabase) GetUserById(userId uint) (*profile.UserProfile, erro
 	return u.ToProfileUser(&user), nil
 }
 
-func (db *ProfileFDatabase) UpdateUser(user *profile.UserProfile) error {
-	var NowUser u.User
-
-	if err := db.db.First(&NowUser, user.UserId).Error; err != nil {
-		return err
-	}
+func (db *ProfileFDatabase) UpdateUser(ctx context.Context, user *profile.UserProfile) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.User.Snapshot(tx, user.UserId)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return u.ErrUserNotFound
+		}
 
-	updatedUser := u.FromProfileUser(user)
+		var NowUser u.User
+		if err := tx.First(&NowUser, user.UserId).Error; err != nil {
+			return err
+		}
 
-	updatedUser.Email = NowUser.Email
+		updatedUser := u.FromProfileUser(user)
+
+		// профиль меняет только логин и аватар, остальные поля берутся из текущей записи
+		updatedUser.Email = NowUser.Email
+		updatedUser.HashedPassword = NowUser.HashedPassword
+		updatedUser.IsAdmin = NowUser.IsAdmin
+		updatedUser.VerifiedEmail = NowUser.VerifiedEmail
+		updatedUser.CreatedAt = NowUser.CreatedAt
+		if user.AvatarUrl == nil {
+			updatedUser.AvatarURL = NowUser.AvatarURL
+			updatedUser.AvatarBlurhash = NowUser.AvatarBlurhash
+			updatedUser.AvatarColor = NowUser.AvatarColor
+		}
 
-	if err := db.db.Save(updatedUser).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return u.ErrUserNotFound
+		if err := tx.Save(updatedUser).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return u.ErrUserNotFound
+			}
+			return err
 		}
-		return err
-	}
 
-	return nil
+		return audit.User.Record(ctx, tx, audit.ActionUpdate, user.UserId, before)
+	})
 }
 
-func (db *ProfileFDatabase) DeleteUser(userId uint) error {
-	var user u.User
-
-	if err := db.db.First(&user, userId).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+func (db *ProfileFDatabase) DeleteUser(ctx context.Context, userId uint) error {
+	return db.db.Transaction(func(tx *gorm.DB) error {
+		before, err := audit.User.Snapshot(tx, userId)
+		if err != nil {
+			return err
+		}
+		if before == nil {
 			return u.ErrUserNotFound
 		}
-		return err
-	}
 
-	if err := db.db.Delete(&user).Error; err != nil {
-		return err
-	}
+		if err := tx.Delete(&u.User{}, userId).Error; err != nil {
+			return err
+		}
 
-	return nil
+		return audit.User.Record(ctx, tx, audit.ActionDelete, userId, before)
+	})
 }
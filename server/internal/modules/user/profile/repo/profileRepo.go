@@ -1,16 +1,20 @@
 package repo
 
-import "server/internal/modules/user/profile"
+import (
+	"context"
+	"server/internal/modules/user/profile"
+)
 
 type ProfileDb interface {
 	GetUserById(userId uint) (*profile.UserProfile, error)
-	UpdateUser(user *profile.UserProfile) error
-	DeleteUser(userId uint) error
+	UpdateUser(ctx context.Context, user *profile.UserProfile) error
+	DeleteUser(ctx context.Context, userId uint) error
 }
 
 type ProfileS3 interface {
-	UploadAvatar(avatarSmall []byte, avatarLarge []byte, login string, userId uint) (*string, error)
+	UploadAvatar(variants map[string][]byte, login string, userId uint) (*string, error)
 	DeleteAvatar(login string, userId uint) error
+	DefaultAvatarURL() string
 }
 
 type Repo struct {
@@ -29,18 +33,22 @@ func (r *Repo) GetUserById(userId uint) (*profile.UserProfile, error) {
 	return r.db.GetUserById(userId)
 }
 
-func (r *Repo) UpdateUser(user *profile.UserProfile) error {
-	return r.db.UpdateUser(user)
+func (r *Repo) UpdateUser(ctx context.Context, user *profile.UserProfile) error {
+	return r.db.UpdateUser(ctx, user)
 }
 
-func (r *Repo) DeleteUser(userId uint) error {
-	return r.db.DeleteUser(userId)
+func (r *Repo) DeleteUser(ctx context.Context, userId uint) error {
+	return r.db.DeleteUser(ctx, userId)
 }
 
-func (r *Repo) UploadAvatar(avatarSmall []byte, avatarLarge []byte, login *string, userId uint) (*string, error) {
-	return r.s3.UploadAvatar(avatarSmall, avatarLarge, *login, userId)
+func (r *Repo) UploadAvatar(variants map[string][]byte, login *string, userId uint) (*string, error) {
+	return r.s3.UploadAvatar(variants, *login, userId)
 }
 
 func (r *Repo) DeleteAvatar(login *string, userId uint) error {
 	return r.s3.DeleteAvatar(*login, userId)
 }
+
+func (r *Repo) DefaultAvatarURL() string {
+	return r.s3.DefaultAvatarURL()
+}
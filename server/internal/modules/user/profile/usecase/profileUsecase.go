@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"mime/multipart"
@@ -21,7 +22,7 @@ func NewProfileUseCase(log *slog.Logger, rp profile.Repo) *ProfileUseCase {
 	}
 }
 
-func (uc *ProfileUseCase) UpdateUser(user *profile.UserProfile, avatar *multipart.File) error {
+func (uc *ProfileUseCase) UpdateUser(ctx context.Context, user *profile.UserProfile, avatar *multipart.File) error {
 	log := uc.log.With("op", "UpdateUser")
 
 	findUser, err := uc.rp.GetUserById(user.UserId)
@@ -36,17 +37,14 @@ func (uc *ProfileUseCase) UpdateUser(user *profile.UserProfile, avatar *multipar
 		user.Login = findUser.Login
 	}
 
+	// прежний аватар удалит PurgeRetiredMedia, когда триггер media_objects выведет его при изменении
 	if user.ResetAvatar {
-		defaultAvatar := "https://useravatar.storage-173.s3hoster.by/default"
-		if err := uc.rp.DeleteAvatar(findUser.Login, user.UserId); err != nil {
-			log.Error("failed to delete avatar", err)
-			return err
-		}
+		defaultAvatar := uc.rp.DefaultAvatarURL()
 		user.AvatarUrl = &defaultAvatar
-	} else if avatar != nil {
-		smallAvatar, largeAvatar, err := avatarManager.ParsingAvatarImage(avatar)
+	} else if *avatar != nil {
+		img, err := avatarManager.Process(avatar, avatarManager.AvatarProfile)
 		if err != nil {
-			log.Error("failed to parse avatar image", err)
+			log.Error("failed to parse avatar image", "error", err)
 			switch {
 			case errors.Is(err, avatarManager.ErrInvalidTypeAvatar):
 				return u.ErrInvalidTypeAvatar
@@ -57,28 +55,65 @@ func (uc *ProfileUseCase) UpdateUser(user *profile.UserProfile, avatar *multipar
 			}
 		}
 
-		avatarUrl, err := uc.rp.UploadAvatar(smallAvatar, largeAvatar, user.Login, user.UserId)
+		avatarUrl, err := uc.rp.UploadAvatar(img.Variants, user.Login, user.UserId)
 		if err != nil {
-			log.Error("failed to upload avatar", err)
+			log.Error("failed to upload avatar", "error", err)
 			return err
 		}
 
 		user.AvatarUrl = avatarUrl
+		user.AvatarBlurhash = img.Blurhash
+		user.AvatarColor = img.DominantColor
 	}
 
-	if err := uc.rp.UpdateUser(user); err != nil {
-		log.Error("failed to update user", err)
+	if err := uc.rp.UpdateUser(ctx, user); err != nil {
+		log.Error("failed to update user", "error", err)
 		return u.ErrInternal
 	}
 
 	return nil
 }
 
+// SetAvatarImage заменяет аватар пользователя уже обработанным изображением, например загруженным напрямую в S3
+func (uc *ProfileUseCase) SetAvatarImage(ctx context.Context, userId uint, img *avatarManager.Image) (*profile.UserProfile, error) {
+	user, err := uc.rp.GetUserById(userId)
+	if err != nil {
+		if errors.Is(err, u.ErrUserNotFound) {
+			return nil, err
+		}
+		return nil, u.ErrInternal
+	}
+
+	avatarUrl, err := uc.rp.UploadAvatar(img.Variants, user.Login, userId)
+	if err != nil {
+		uc.log.Error("failed to upload avatar", "error", err)
+		return nil, err
+	}
+
+	user.AvatarUrl = avatarUrl
+	user.AvatarBlurhash = img.Blurhash
+	user.AvatarColor = img.DominantColor
+	if err := uc.rp.UpdateUser(ctx, user); err != nil {
+		uc.log.Error("failed to update user", "error", err)
+		return nil, u.ErrInternal
+	}
+
+	return user, nil
+}
+
 func (uc *ProfileUseCase) GetUser(userId uint) (*profile.UserProfile, error) {
 	user, err := uc.rp.GetUserById(userId)
 	return user, err
 }
 
-func (uc *ProfileUseCase) DeleteUser(userId uint) error {
-	return uc.rp.DeleteUser(userId)
+// DeleteUser переносит пользователя в корзину, аватар удаляется только при очистке корзины
+func (uc *ProfileUseCase) DeleteUser(ctx context.Context, userId uint) error {
+	return uc.rp.DeleteUser(ctx, userId)
+}
+
+// CleanupUser удаляет аватар окончательно удаленного пользователя
+func (uc *ProfileUseCase) CleanupUser(userId uint, login string) {
+	if err := uc.rp.DeleteAvatar(&login, userId); err != nil {
+		uc.log.Error("failed to delete user avatar", "error", err, "userId", userId)
+	}
 }
@@ -0,0 +1,219 @@
+package TaskService
+
+import (
+	"fmt"
+	"log/slog"
+	"server/internal/init/blob"
+	"strings"
+	"time"
+)
+
+// mediaSources - бакеты, запросы, возвращающие адреса их файлов, на которые ссылается БД, и типы сущностей
+// в media_objects. Адрес с "/" на конце - папка со всеми размерами изображения
+var mediaSources = []struct {
+	bucket      string
+	query       string
+	entityTypes []string
+}{
+	{
+		bucket:      "filmposter",
+		query:       "SELECT poster_url FROM films WHERE poster_url <> '' UNION ALL SELECT url FROM film_images WHERE url <> ''",
+		entityTypes: []string{"film_poster", "film_image"},
+	},
+	{bucket: "actoravatar", query: "SELECT avatar_url FROM persons WHERE avatar_url <> ''", entityTypes: []string{"person_avatar"}},
+	{bucket: "useravatar", query: "SELECT avatar_url FROM users WHERE avatar_url <> ''", entityTypes: []string{"user_avatar"}},
+	{bucket: "genrecover", query: "SELECT cover_url FROM genres WHERE cover_url <> ''", entityTypes: []string{"genre_cover"}},
+}
+
+// purgeBatch - сколько выведенных папок удаляется за один запуск
+const purgeBatch = 1000
+
+// protectedPrefixes не сверяются: файлы по умолчанию нужны всегда, временные файлы загрузок чистит модуль upload
+var protectedPrefixes = []string{"default/", "staging/"}
+
+// orphanReport - итог сверки одного бакета
+type orphanReport struct {
+	Bucket  string
+	Objects int
+	Orphans []blob.Object
+	Bytes   int64
+	Deleted int
+}
+
+// ReconcileMedia сверяет файлы бакетов с адресами в БД и удаляет файлы, на которые ничего не ссылается
+// и которые загружены раньше gracePeriod. С dryRun файлы только выводятся в лог.
+// Бакет, для которого не удалось прочитать БД или список файлов, пропускается целиком
+func (t *TaskService) ReconcileMedia(gracePeriod time.Duration, dryRun bool) {
+	log := t.log.With("op", "ReconcileMedia", "dryRun", dryRun)
+	before := time.Now().Add(-gracePeriod)
+
+	for _, source := range mediaSources {
+		report, err := t.reconcileBucket(source.bucket, source.query, source.entityTypes, before, dryRun)
+		if err != nil {
+			log.Error("failed to reconcile bucket", "bucket", source.bucket, "error", err)
+			continue
+		}
+
+		for _, orphan := range report.Orphans {
+			log.Info("orphaned media", "bucket", report.Bucket, "key", orphan.Key, "size", orphan.Size, "lastModified", orphan.LastModified)
+		}
+		log.Info("reconciled bucket",
+			slog.String("bucket", report.Bucket),
+			slog.Int("objects", report.Objects),
+			slog.Int("orphans", len(report.Orphans)),
+			slog.Int64("bytes", report.Bytes),
+			slog.Int("deleted", report.Deleted),
+		)
+	}
+}
+
+// reconcileBucket - выведенные, но еще не удаленные папки из media_objects тоже считаются используемыми,
+// их удаляет PurgeRetiredMedia
+func (t *TaskService) reconcileBucket(bucket, query string, entityTypes []string, before time.Time, dryRun bool) (*orphanReport, error) {
+	var urls []string
+	err := t.db.Raw(query+" UNION ALL SELECT url FROM media_objects WHERE entity_type IN ?", entityTypes).Scan(&urls).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// ключи и папки, на которые ссылается БД
+	refs := make(map[string]struct{}, len(urls))
+	var folders []string
+	for _, rawURL := range urls {
+		key, ok := blob.KeyFromURL(rawURL, bucket)
+		key = strings.TrimLeft(key, "/")
+		if !ok || key == "" {
+			continue
+		}
+		refs[key] = struct{}{}
+		if strings.HasSuffix(key, "/") {
+			folders = append(folders, key)
+		}
+	}
+	// адреса есть, но ни один не распознан - формат адресов изменился, удалять по такой сверке нельзя
+	if len(urls) > 0 && len(refs) == 0 {
+		return nil, fmt.Errorf("none of %d referenced urls belong to bucket", len(urls))
+	}
+
+	objects, err := t.store.List(bucket, "")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &orphanReport{Bucket: bucket, Objects: len(objects)}
+	for _, object := range objects {
+		if !object.LastModified.Before(before) || isProtected(object.Key) || isReferenced(object.Key, refs, folders) {
+			continue
+		}
+
+		report.Orphans = append(report.Orphans, object)
+		report.Bytes += object.Size
+		if dryRun {
+			continue
+		}
+
+		if err := t.store.Delete(bucket, object.Key); err != nil {
+			t.log.Error("failed to delete orphaned media", "bucket", bucket, "key", object.Key, "error", err)
+			continue
+		}
+		report.Deleted++
+	}
+
+	return report, nil
+}
+
+type retiredMedia struct {
+	EntityType string
+	URL        string
+}
+
+// PurgeRetiredMedia удаляет папки изображений, замененных раньше gracePeriod: к этому времени их уже
+// не отдают кэши CDN и браузеров. Папка, которая снова стала активной (то же изображение загрузили
+// повторно), не удаляется. С dryRun папки только выводятся в лог
+func (t *TaskService) PurgeRetiredMedia(gracePeriod time.Duration, dryRun bool) {
+	log := t.log.With("op", "PurgeRetiredMedia", "dryRun", dryRun)
+
+	var rows []retiredMedia
+	err := t.db.Raw(`
+		SELECT DISTINCT ON (m.url) m.entity_type, m.url
+		FROM media_objects m
+		WHERE m.retired_at < ?
+		  AND NOT EXISTS (SELECT 1 FROM media_objects a WHERE a.url = m.url AND a.retired_at IS NULL)
+		ORDER BY m.url
+		LIMIT ?`, time.Now().Add(-gracePeriod), purgeBatch).Scan(&rows).Error
+	if err != nil {
+		log.Error("failed to get retired media", "error", err)
+		return
+	}
+
+	purged := 0
+	for _, row := range rows {
+		bucket := mediaBucket(row.EntityType)
+		key, ok := blob.KeyFromURL(row.URL, bucket)
+		key = strings.TrimLeft(key, "/")
+
+		var err error
+		switch {
+		case dryRun:
+			log.Info("retired media", "bucket", bucket, "key", key)
+			continue
+		case !ok || key == "" || isProtected(key):
+			// файлов для удаления нет, удаляется только запись
+		case strings.HasSuffix(key, "/"):
+			err = t.store.DeletePrefix(bucket, key)
+		default:
+			// ключи старого формата сохранялись с "/" в начале
+			if err = t.store.Delete(bucket, key); err == nil {
+				err = t.store.Delete(bucket, "/"+key)
+			}
+		}
+		if err != nil {
+			log.Error("failed to delete retired media", "bucket", bucket, "key", key, "error", err)
+			continue
+		}
+
+		if err := t.db.Exec("DELETE FROM media_objects WHERE url = ? AND retired_at IS NOT NULL", row.URL).Error; err != nil {
+			log.Error("failed to delete retired media record", "url", row.URL, "error", err)
+			continue
+		}
+		purged++
+	}
+
+	log.Info("purged retired media", slog.Int("retired", len(rows)), slog.Int("purged", purged))
+}
+
+func mediaBucket(entityType string) string {
+	for _, source := range mediaSources {
+		for _, t := range source.entityTypes {
+			if t == entityType {
+				return source.bucket
+			}
+		}
+	}
+	return ""
+}
+
+func isProtected(key string) bool {
+	for _, prefix := range protectedPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isReferenced - на файл ссылаются напрямую (старый формат одним файлом) или он лежит в папке изображения
+func isReferenced(key string, refs map[string]struct{}, folders []string) bool {
+	// ключи старого формата сохранялись с "/" в начале
+	key = strings.TrimLeft(key, "/")
+	if _, ok := refs[key]; ok {
+		return true
+	}
+
+	for _, folder := range folders {
+		if strings.HasPrefix(key, folder) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,27 +1,60 @@
 package TaskService
 
 import (
+	"context"
 	"gorm.io/gorm"
 	"log/slog"
+	"server/internal/init/blob"
 	u "server/internal/modules/user"
+	"server/pkg/lib/audit"
 	"time"
 )
 
 type TaskService struct {
-	db  *gorm.DB
-	log *slog.Logger
+	db    *gorm.DB
+	log   *slog.Logger
+	store blob.Store
 }
 
-func NewTaskService(db *gorm.DB, log *slog.Logger) *TaskService {
-	return &TaskService{db: db, log: log}
+func NewTaskService(db *gorm.DB, log *slog.Logger, store blob.Store) *TaskService {
+	return &TaskService{db: db, log: log, store: store}
 }
 
+// CleanUnverifiedUsers удаляет пользователей, не подтвердивших почту за сутки, сразу, минуя корзину.
+// Удаления пишутся в журнал без автора
 func (t *TaskService) CleanUnverifiedUsers() {
 	threshold := time.Now().Add(-24 * time.Hour)
-	result := t.db.Where("verified_email = ? AND create_at <= ?", false, threshold).Delete(&u.User{})
-	if result.Error != nil {
-		t.log.Error("error deleting unverified users", slog.String("error", result.Error.Error()))
+
+	var deleted int64
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Model(&u.User{}).Where("verified_email = ? AND create_at <= ?", false, threshold).
+			Pluck("user_id", &ids).Error; err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			before, err := audit.User.Snapshot(tx, id)
+			if err != nil {
+				return err
+			}
+			if before == nil {
+				continue
+			}
+
+			if err := tx.Unscoped().Delete(&u.User{}, id).Error; err != nil {
+				return err
+			}
+			if err := audit.User.Record(context.Background(), tx, audit.ActionDelete, id, before); err != nil {
+				return err
+			}
+			deleted++
+		}
+		return nil
+	})
+	if err != nil {
+		t.log.Error("error deleting unverified users", slog.String("error", err.Error()))
 	} else {
-		t.log.Info("deleted unverified users", slog.Int64("count", result.RowsAffected))
+		t.log.Info("deleted unverified users", slog.Int64("count", deleted))
 	}
 }
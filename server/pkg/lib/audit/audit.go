@@ -0,0 +1,210 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5/middleware"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// Журнал изменений: кто, когда и откуда создал, изменил или удалил сущность и какие колонки поменялись.
+// Запись делается в транзакции изменения, поэтому откаченное изменение в журнал не попадает
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	// ActionRestore - возврат сущности из корзины, ActionPurge - окончательное удаление из корзины
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+)
+
+// Entity - таблица сущности в журнале. SoftDelete - удаленные строки остаются в таблице с deleted_at
+type Entity struct {
+	Type       string
+	Table      string
+	IDColumn   string
+	SoftDelete bool
+}
+
+var (
+	Film   = Entity{Type: "film", Table: "films", IDColumn: "film_id", SoftDelete: true}
+	Person = Entity{Type: "person", Table: "persons", IDColumn: "person_id", SoftDelete: true}
+	Genre  = Entity{Type: "genre", Table: "genres", IDColumn: "genre_id", SoftDelete: true}
+	Review = Entity{Type: "review", Table: "reviews", IDColumn: "review_id"}
+	User   = Entity{Type: "user", Table: "users", IDColumn: "user_id", SoftDelete: true}
+)
+
+// Entities - типы сущностей, по которым можно искать в журнале
+var Entities = []string{Film.Type, Person.Type, Genre.Type, Review.Type, User.Type}
+
+// redacted - колонки, значения которых не пишутся в журнал, остается только факт изменения
+var redacted = map[string]bool{
+	"hashed_password": true,
+}
+
+const redactedValue = "[redacted]"
+
+type ctxKey struct{}
+
+// Middleware сохраняет IP клиента для журнала. Пользователь и id запроса берутся из контекста при записи:
+// их кладут middleware авторизации и middleware.RequestID
+func Middleware() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ip := r.RemoteAddr
+			if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+				ip = host
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, ip)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Actor - автор изменения. UserID nil - изменение без авторизации или из фоновой задачи
+type Actor struct {
+	UserID    *uint
+	RequestID string
+	IP        string
+}
+
+func ActorFromContext(ctx context.Context) Actor {
+	var actor Actor
+	if ctx == nil {
+		return actor
+	}
+
+	if userId, ok := ctx.Value("userId").(uint); ok {
+		actor.UserID = &userId
+	}
+	actor.RequestID = middleware.GetReqID(ctx)
+	actor.IP, _ = ctx.Value(ctxKey{}).(string)
+	return actor
+}
+
+// Snapshot читает строку сущности до изменения и блокирует ее до конца транзакции, чтобы параллельное
+// изменение не попало между снимком и записью. nil, если строки нет или она в корзине
+func (e Entity) Snapshot(tx *gorm.DB, id uint) (map[string]interface{}, error) {
+	if e.SoftDelete {
+		tx = tx.Where("deleted_at IS NULL")
+	}
+	return e.row(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+// SnapshotDeleted - то же, что Snapshot, но для строки в корзине. nil, если строки нет или она не удалена
+func (e Entity) SnapshotDeleted(tx *gorm.DB, id uint) (map[string]interface{}, error) {
+	return e.row(tx.Where("deleted_at IS NOT NULL").Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func (e Entity) row(tx *gorm.DB, id uint) (map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	if err := tx.Table(e.Table).Where(e.IDColumn+" = ?", id).Limit(1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return rows[0], nil
+}
+
+// Record пишет изменение в журнал в транзакции tx. before - строка до изменения из Snapshot, для создания nil.
+// Строка после изменения читается здесь же, кроме удаления. Мягкое удаление пишется как изменение deleted_at
+func (e Entity) Record(ctx context.Context, tx *gorm.DB, action string, id uint, before map[string]interface{}) error {
+	// строку после изменения уже заблокировало само изменение
+	var after map[string]interface{}
+	if action != ActionPurge && (action != ActionDelete || e.SoftDelete) {
+		var err error
+		if after, err = e.row(tx, id); err != nil {
+			return err
+		}
+	}
+
+	diff, err := json.Marshal(Diff(before, after))
+	if err != nil {
+		return err
+	}
+
+	actor := ActorFromContext(ctx)
+	return tx.Create(&Log{
+		UserID:     actor.UserID,
+		Action:     action,
+		EntityType: e.Type,
+		EntityID:   id,
+		Diff:       diff,
+		RequestID:  actor.RequestID,
+		IP:         actor.IP,
+		CreatedAt:  time.Now(),
+	}).Error
+}
+
+// Changes - изменившиеся колонки до и после изменения
+type Changes struct {
+	Before map[string]interface{} `json:"before,omitempty"`
+	After  map[string]interface{} `json:"after,omitempty"`
+}
+
+// Diff оставляет только колонки, значения которых отличаются. У создания все колонки попадают в After,
+// у удаления - в Before
+func Diff(before, after map[string]interface{}) Changes {
+	changes := Changes{}
+	for column, value := range before {
+		if other, ok := after[column]; after == nil || !ok || !equal(value, other) {
+			if changes.Before == nil {
+				changes.Before = make(map[string]interface{})
+			}
+			changes.Before[column] = redact(column, value)
+		}
+	}
+	for column, value := range after {
+		if other, ok := before[column]; before == nil || !ok || !equal(value, other) {
+			if changes.After == nil {
+				changes.After = make(map[string]interface{})
+			}
+			changes.After[column] = redact(column, value)
+		}
+	}
+	return changes
+}
+
+// equal сравнивает значения в том виде, в котором они попадут в журнал: время и числа из драйвера
+// приходят разными типами
+func equal(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(ja) == string(jb)
+}
+
+func redact(column string, value interface{}) interface{} {
+	if redacted[column] && value != nil {
+		return redactedValue
+	}
+	return value
+}
+
+// Log - запись журнала
+type Log struct {
+	AuditID    uint            `gorm:"column:audit_id;primaryKey"`
+	UserID     *uint           `gorm:"column:user_id"`
+	Action     string          `gorm:"column:action"`
+	EntityType string          `gorm:"column:entity_type"`
+	EntityID   uint            `gorm:"column:entity_id"`
+	Diff       json.RawMessage `gorm:"column:diff;type:jsonb"`
+	RequestID  string          `gorm:"column:request_id"`
+	IP         string          `gorm:"column:ip"`
+	CreatedAt  time.Time       `gorm:"column:created_at"`
+}
+
+func (Log) TableName() string {
+	return "audit_log"
+}
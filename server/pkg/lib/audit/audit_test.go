@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	created := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		before map[string]interface{}
+		after  map[string]interface{}
+		want   Changes
+	}{
+		{
+			name:  "create",
+			after: map[string]interface{}{"title": "Alien", "runtime": 117},
+			want:  Changes{After: map[string]interface{}{"title": "Alien", "runtime": 117}},
+		},
+		{
+			name:   "delete",
+			before: map[string]interface{}{"title": "Alien"},
+			want:   Changes{Before: map[string]interface{}{"title": "Alien"}},
+		},
+		{
+			name:   "only changed columns",
+			before: map[string]interface{}{"title": "Alien", "runtime": 117, "version": 1},
+			after:  map[string]interface{}{"title": "Aliens", "runtime": 117, "version": 2},
+			want: Changes{
+				Before: map[string]interface{}{"title": "Alien", "version": 1},
+				After:  map[string]interface{}{"title": "Aliens", "version": 2},
+			},
+		},
+		{
+			name:   "nothing changed",
+			before: map[string]interface{}{"title": "Alien"},
+			after:  map[string]interface{}{"title": "Alien"},
+			want:   Changes{},
+		},
+		{
+			name:   "driver types compare by value",
+			before: map[string]interface{}{"runtime": int64(117), "create_at": created},
+			after:  map[string]interface{}{"runtime": int32(117), "create_at": created.In(time.UTC)},
+			want:   Changes{},
+		},
+		{
+			name:   "cleared value",
+			before: map[string]interface{}{"tagline": "In space no one can hear you scream"},
+			after:  map[string]interface{}{"tagline": nil},
+			want: Changes{
+				Before: map[string]interface{}{"tagline": "In space no one can hear you scream"},
+				After:  map[string]interface{}{"tagline": nil},
+			},
+		},
+		{
+			name:   "column only on one side",
+			before: map[string]interface{}{"title": "Alien"},
+			after:  map[string]interface{}{"title": "Alien", "slug": "alien"},
+			want:   Changes{After: map[string]interface{}{"slug": "alien"}},
+		},
+		{
+			name:   "password is redacted",
+			before: map[string]interface{}{"hashed_password": "old-hash", "login": "ripley"},
+			after:  map[string]interface{}{"hashed_password": "new-hash", "login": "ripley"},
+			want: Changes{
+				Before: map[string]interface{}{"hashed_password": redactedValue},
+				After:  map[string]interface{}{"hashed_password": redactedValue},
+			},
+		},
+		{
+			name:   "empty password is not redacted",
+			before: map[string]interface{}{"hashed_password": nil},
+			after:  map[string]interface{}{"hashed_password": "hash"},
+			want: Changes{
+				Before: map[string]interface{}{"hashed_password": nil},
+				After:  map[string]interface{}{"hashed_password": redactedValue},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package avatarManager
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/chai2010/webp"
+	"github.com/nfnt/resize"
+	"image"
+	"io"
+	"mime/multipart"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Variant - один размер изображения. Width x Height - рамка: изображение вписывается в нее
+// с сохранением пропорций и не увеличивается, если оно меньше рамки
+type Variant struct {
+	Name   string
+	Width  uint
+	Height uint
+}
+
+// Profile - настройки обработки изображений одного типа сущности
+type Profile struct {
+	MinWidth  int
+	MinHeight int
+	// Crop - обрезать изображение по центру до пропорций самого большого варианта (аватары, обложки).
+	// Без него пропорции оригинала сохраняются
+	Crop     bool
+	Quality  float32
+	Variants []Variant
+
+	ErrType       error
+	ErrResolution error
+}
+
+var (
+	PosterProfile = Profile{
+		MinWidth:  400,
+		MinHeight: 600,
+		Quality:   85,
+		Variants: []Variant{
+			{Name: "200x300", Width: 200, Height: 300},
+			{Name: "400x600", Width: 400, Height: 600},
+			{Name: "800x1200", Width: 800, Height: 1200},
+		},
+		ErrType:       ErrInvalidTypePoster,
+		ErrResolution: ErrInvalidResolutionPoster,
+	}
+
+	AvatarProfile = Profile{
+		MinWidth:  128,
+		MinHeight: 128,
+		Crop:      true,
+		Quality:   80,
+		Variants: []Variant{
+			{Name: "64x64", Width: 64, Height: 64},
+			{Name: "256x256", Width: 256, Height: 256},
+			{Name: "512x512", Width: 512, Height: 512},
+		},
+		ErrType:       ErrInvalidTypeAvatar,
+		ErrResolution: ErrInvalidResolutionAvatar,
+	}
+
+	CoverProfile = Profile{
+		MinWidth:  640,
+		MinHeight: 360,
+		Crop:      true,
+		Quality:   85,
+		Variants: []Variant{
+			{Name: "320x180", Width: 320, Height: 180},
+			{Name: "640x360", Width: 640, Height: 360},
+			{Name: "1280x720", Width: 1280, Height: 720},
+		},
+		ErrType:       ErrInvalidTypeCover,
+		ErrResolution: ErrInvalidResolutionCover,
+	}
+
+	// BackdropProfile - фон страницы фильма, пропорции кадра сохраняются
+	BackdropProfile = Profile{
+		MinWidth:  1280,
+		MinHeight: 720,
+		Quality:   85,
+		Variants: []Variant{
+			{Name: "640x360", Width: 640, Height: 360},
+			{Name: "1280x720", Width: 1280, Height: 720},
+			{Name: "1920x1080", Width: 1920, Height: 1080},
+		},
+		ErrType:       ErrInvalidTypeImage,
+		ErrResolution: ErrInvalidResolutionBackdrop,
+	}
+
+	StillProfile = Profile{
+		MinWidth:  640,
+		MinHeight: 360,
+		Quality:   85,
+		Variants: []Variant{
+			{Name: "320x180", Width: 320, Height: 180},
+			{Name: "640x360", Width: 640, Height: 360},
+			{Name: "1280x720", Width: 1280, Height: 720},
+		},
+		ErrType:       ErrInvalidTypeImage,
+		ErrResolution: ErrInvalidResolutionStill,
+	}
+
+	// LogoProfile - логотип названия, обычно png с прозрачностью, поэтому качество выше
+	LogoProfile = Profile{
+		MinWidth:  200,
+		MinHeight: 50,
+		Quality:   90,
+		Variants: []Variant{
+			{Name: "300x150", Width: 300, Height: 150},
+			{Name: "600x300", Width: 600, Height: 300},
+		},
+		ErrType:       ErrInvalidTypeImage,
+		ErrResolution: ErrInvalidResolutionLogo,
+	}
+)
+
+// Image - результат обработки: WebP каждого варианта по имени, размеры исходного изображения
+// (после обрезки), blurhash для заглушки и средний цвет в формате #rrggbb
+type Image struct {
+	Variants      map[string][]byte
+	Width         int
+	Height        int
+	Blurhash      string
+	DominantColor string
+}
+
+// Process читает загруженный файл и обрабатывает его по профилю
+func Process(file *multipart.File, profile Profile) (*Image, error) {
+	buffer := new(bytes.Buffer)
+	if _, err := io.Copy(buffer, *file); err != nil {
+		return nil, ErrInternal
+	}
+
+	return ProcessBytes(buffer.Bytes(), profile)
+}
+
+// ProcessBytes проверяет формат и разрешение изображения, строит все варианты профиля,
+// blurhash и средний цвет
+func ProcessBytes(data []byte, profile Profile) (*Image, error) {
+	img, err := decodeImage(data, profile.ErrType)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() < profile.MinWidth || bounds.Dy() < profile.MinHeight {
+		return nil, profile.ErrResolution
+	}
+
+	if profile.Crop && len(profile.Variants) > 0 {
+		largest := profile.Variants[len(profile.Variants)-1]
+		img = cropToRatio(img, int(largest.Width), int(largest.Height))
+		bounds = img.Bounds()
+	}
+
+	result := &Image{
+		Variants: make(map[string][]byte, len(profile.Variants)),
+		Width:    bounds.Dx(),
+		Height:   bounds.Dy(),
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var encodeErr error
+
+	for _, variant := range profile.Variants {
+		wg.Add(1)
+		go func(variant Variant) {
+			defer wg.Done()
+			resized := resize.Thumbnail(variant.Width, variant.Height, img, resize.Lanczos3)
+			buffer := new(bytes.Buffer)
+			err := webp.Encode(buffer, resized, &webp.Options{Quality: profile.Quality})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				encodeErr = ErrInternal
+				return
+			}
+			result.Variants[variant.Name] = buffer.Bytes()
+		}(variant)
+	}
+
+	// Заглушка считается по маленькой копии, по полному изображению это слишком долго
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		small := resize.Thumbnail(blurhashSampleSize, blurhashSampleSize, img, resize.Bilinear)
+		hash, color := blurhash(small, blurhashComponentsX, blurhashComponentsY)
+
+		mu.Lock()
+		defer mu.Unlock()
+		result.Blurhash = hash
+		result.DominantColor = color
+	}()
+
+	wg.Wait()
+
+	if encodeErr != nil {
+		return nil, encodeErr
+	}
+
+	return result, nil
+}
+
+// cropToRatio обрезает изображение по центру до пропорций width:height
+func cropToRatio(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	cropW, cropH := w, w*height/width
+	if cropH > h {
+		cropW, cropH = h*width/height, h
+	}
+	if cropW == w && cropH == h {
+		return img
+	}
+
+	sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return img
+	}
+
+	x0 := bounds.Min.X + (w-cropW)/2
+	y0 := bounds.Min.Y + (h-cropH)/2
+	return sub.SubImage(image.Rect(x0, y0, x0+cropW, y0+cropH))
+}
+
+// VariantKey - имя объекта варианта внутри папки изображения
+func VariantKey(name string) string {
+	return name + ".webp"
+}
+
+// ContentHash - хэш всех вариантов изображения для ключа его папки. Другое изображение - другая папка,
+// поэтому объекты не перезаписываются и кэши CDN и браузеров не отдают старую версию
+func ContentHash(variants map[string][]byte) string {
+	names := make([]string, 0, len(variants))
+	for name := range variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write(variants[name])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Srcset возвращает адреса вариантов по адресу папки изображения (заканчивается на "/").
+// Для изображений, загруженных до появления вариантов (один файл), все варианты указывают на него.
+// Для заглушки по умолчанию возвращается nil: клиент сам выбирает ее под тему оформления
+func Srcset(baseURL string, profile Profile) map[string]string {
+	if baseURL == "" || strings.HasSuffix(strings.TrimSuffix(baseURL, "/"), "/default") {
+		return nil
+	}
+
+	srcset := make(map[string]string, len(profile.Variants))
+	for _, variant := range profile.Variants {
+		if strings.HasSuffix(baseURL, "/") {
+			srcset[variant.Name] = baseURL + VariantKey(variant.Name)
+		} else {
+			srcset[variant.Name] = baseURL
+		}
+	}
+
+	return srcset
+}
@@ -3,6 +3,7 @@ package emailsender
 import (
 	"fmt"
 	"gopkg.in/gomail.v2"
+	"html"
 	"os"
 	"server/config"
 )
@@ -110,3 +111,98 @@ func (e *EmailSender) SendConfirmEmail(code string, email string) error {
 	}
 	return nil
 }
+
+// SendSuggestionReviewed сообщает автору предложения правки о решении редактора или его комментарии.
+// status - approved, rejected или commented, subject - название фильма или имя персоны
+func (e *EmailSender) SendSuggestionReviewed(email string, subject string, status string, comment string) error {
+	var title, text string
+	switch status {
+	case "approved":
+		title = "Ваша правка принята"
+		text = "Редактор принял ваше предложение правки для «" + html.EscapeString(subject) + "». Изменения уже на сайте. Спасибо за помощь!"
+	case "rejected":
+		title = "Ваша правка отклонена"
+		text = "Редактор отклонил ваше предложение правки для «" + html.EscapeString(subject) + "»."
+	default:
+		title = "Новый комментарий к вашей правке"
+		text = "Редактор оставил комментарий к вашему предложению правки для «" + html.EscapeString(subject) + "»."
+	}
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", "OfflinerMen@yandex.by")
+	m.SetHeader("To", email)
+	m.SetHeader("Subject", title)
+	body := `<!DOCTYPE html>
+    <html lang="ru">
+    <head>
+        <meta charset="UTF-8">
+        <meta name="viewport" content="width=device-width, initial-scale=1.0">
+        <link rel="preconnect" href="https://fonts.googleapis.com">
+        <link rel="preconnect" href="https://fonts.gstatic.com" crossorigin>
+        <link href="https://fonts.googleapis.com/css2?family=Montserrat:ital,wght@0,100..900;1,100..900&display=swap" rel="stylesheet">
+        <title>` + title + `</title>
+        <style>
+            body {
+                font-family: "Montserrat", sans-serif;
+                background-color: #f4f4f4;
+                margin: 0;
+                padding: 20px;
+            }
+            .container {
+                max-width: 600px;
+                margin: auto;
+                background: white;
+                padding: 20px;
+                border-radius: 5px;
+                box-shadow: 0 0 10px rgba(0,0,0,0.1);
+            }
+            h1 {
+                color: #000000;
+                font-weight: 900;
+                font-size: 32px;
+            }
+            p {
+                font-size: 16px;
+                font-weight: 300;
+                line-height: 1.5;
+                color: #000000;
+            }
+            .comment {
+                background: #eee;
+                padding: 10px;
+                border-radius: 5px;
+                margin: 20px 0;
+                white-space: pre-wrap;
+            }
+            .footer {
+                font-size: 12px;
+                color: #888;
+                text-align: center;
+                margin-top: 20px;
+            }
+        </style>
+    </head>
+    <body>
+        <div class="container">
+            <h1>` + title + `</h1>
+            <p>Здравствуйте!</p>
+            <p>` + text + `</p>`
+	if comment != "" {
+		body += `
+            <p>Комментарий редактора:</p>
+            <div class="comment">` + html.EscapeString(comment) + `</div>`
+	}
+	body += `
+            <p>Если у вас возникли вопросы, не стесняйтесь обращаться в службу <a href="http://localhost:8080/auth/yandex">поддержки</a>.</p>
+        </div>
+        <div class="footer">
+            <p>&copy; 2024 Offliner. Все права защищены.</p>
+        </div>
+    </body>
+    </html>`
+	m.SetBody("text/html", body)
+	if err := e.SmtpServer.DialAndSend(m); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package optimistic
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Оптимистическая блокировка: у строки есть счетчик version, каждое изменение увеличивает его.
+// Клиент передает версию, которую видел, и изменение применяется, только если она не устарела
+
+var (
+	ErrConflict        = errors.New("entity was modified by another request, reload it and retry")
+	ErrVersionRequired = errors.New("version is required, pass it in If-Match header or version field")
+	ErrInvalidIfMatch  = errors.New("invalid If-Match header, expected entity version in quotes")
+)
+
+// Expected возвращает версию, на которой клиент основывал изменения: из If-Match ("3", W/"3" или ETag
+// ответа GET "3-<хэш>") или, если заголовка нет, из поля version тела запроса
+func Expected(r *http.Request, bodyVersion int) (int, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		if bodyVersion <= 0 {
+			return 0, ErrVersionRequired
+		}
+		return bodyVersion, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	// хэш тела, который добавил httpcache, для изменения не важен
+	if i := strings.IndexByte(tag, '-'); i > 0 {
+		tag = tag[:i]
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, ErrInvalidIfMatch
+	}
+	return version, nil
+}
+
+// ETag - тег с версией сущности. httpcache добавляет к нему хэш тела, полученный тег клиент передает
+// в If-None-Match для кэша и в If-Match для изменения
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// SetETag выставляет ETag по версии до записи ответа, httpcache дополняет его хэшем тела
+func SetETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", ETag(version))
+}
+
+// Check сравнивает версию клиента с текущей до изменений, которые нельзя откатить (загрузка
+// и удаление файлов). expected 0 - внутреннее изменение без проверки
+func Check(current, expected int) error {
+	if expected != 0 && current != expected {
+		return ErrConflict
+	}
+	return nil
+}
+
+// Bump увеличивает версию строки и возвращает новую. Вызывается в транзакции первым: строка
+// блокируется до ее конца, и параллельное изменение дождется коммита и получит ErrConflict.
+// expected 0 - внутреннее изменение без проверки. Если строки нет, возвращает gorm.ErrRecordNotFound
+func Bump(tx *gorm.DB, table, idColumn string, id uint, expected int) (int, error) {
+	var versions []int
+	err := tx.Raw(
+		"UPDATE "+table+" SET version = version + 1 WHERE "+idColumn+" = ? AND (? = 0 OR version = ?) RETURNING version",
+		id, expected, expected,
+	).Scan(&versions).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(versions) == 1 {
+		return versions[0], nil
+	}
+
+	var exists bool
+	if err := tx.Raw("SELECT EXISTS (SELECT 1 FROM "+table+" WHERE "+idColumn+" = ?)", id).Scan(&exists).Error; err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return 0, ErrConflict
+}
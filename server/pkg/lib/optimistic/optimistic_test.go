@@ -0,0 +1,199 @@
+package optimistic
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExpected(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifMatch     string
+		bodyVersion int
+		want        int
+		wantErr     error
+	}{
+		{name: "strong tag", ifMatch: `"3"`, want: 3},
+		{name: "weak tag", ifMatch: `W/"3"`, want: 3},
+		{name: "spaces around tag", ifMatch: ` "12" `, want: 12},
+		{name: "tag of GET response", ifMatch: `"3-9f86d081884c7d65"`, want: 3},
+		{name: "weak tag of GET response", ifMatch: `W/"3-9f86d081884c7d65"`, want: 3},
+		{name: "header wins over body", ifMatch: `"3"`, bodyVersion: 7, want: 3},
+		{name: "body without header", bodyVersion: 7, want: 7},
+		{name: "no version", wantErr: ErrVersionRequired},
+		{name: "not a number", ifMatch: `"abc"`, wantErr: ErrInvalidIfMatch},
+		{name: "any", ifMatch: `*`, wantErr: ErrInvalidIfMatch},
+		{name: "zero", ifMatch: `"0"`, wantErr: ErrInvalidIfMatch},
+		{name: "negative", ifMatch: `"-1"`, wantErr: ErrInvalidIfMatch},
+		{name: "body hash only", ifMatch: `"9f86d081884c7d65"`, wantErr: ErrInvalidIfMatch},
+		{name: "list of tags", ifMatch: `"3", "4"`, wantErr: ErrInvalidIfMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			got, err := Expected(r, tt.bodyVersion)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expected() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestETagRoundTrip(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPut, "/", nil)
+	r.Header.Set("If-Match", ETag(42))
+
+	got, err := Expected(r, 0)
+	if err != nil || got != 42 {
+		t.Errorf("Expected(ETag(42)) = %d, %v, want 42, nil", got, err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  int
+		expected int
+		wantErr  error
+	}{
+		{name: "same version", current: 3, expected: 3},
+		{name: "internal change", current: 3, expected: 0},
+		{name: "stale version", current: 4, expected: 3, wantErr: ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Check(tt.current, tt.expected); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		name     string
+		returned []int64 // версии, которые вернул UPDATE ... RETURNING
+		exists   bool
+		want     int
+		wantErr  error
+	}{
+		{name: "bumped", returned: []int64{4}, want: 4},
+		{name: "stale version", exists: true, wantErr: ErrConflict},
+		{name: "no row", exists: false, wantErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeConn{returned: tt.returned, exists: tt.exists}
+			db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(fakeConnector{conn})}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("gorm.Open() error = %v", err)
+			}
+
+			got, err := Bump(db, "films", "film_id", 5, 3)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Bump() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Bump() = %d, want %d", got, tt.want)
+			}
+			if len(conn.queries) == 0 || !strings.HasPrefix(conn.queries[0], "UPDATE films SET version = version + 1") {
+				t.Errorf("first query = %v, want version update", conn.queries)
+			}
+		})
+	}
+}
+
+// fakeConn отвечает на запросы Bump без базы: UPDATE возвращает returned, SELECT EXISTS - exists
+type fakeConn struct {
+	returned []int64
+	exists   bool
+	queries  []string
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.queries = append(c.queries, query)
+	switch {
+	case strings.HasPrefix(query, "UPDATE"):
+		values := make([][]driver.Value, 0, len(c.returned))
+		for _, version := range c.returned {
+			values = append(values, []driver.Value{version})
+		}
+		return &fakeRows{columns: []string{"version"}, values: values}, nil
+	case strings.HasPrefix(query, "SELECT EXISTS"):
+		return &fakeRows{columns: []string{"exists"}, values: [][]driver.Value{{c.exists}}}, nil
+	}
+	return nil, errors.New("unexpected query: " + query)
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeConnector struct {
+	conn *fakeConn
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{c.conn}
+}
+
+type fakeDriver struct {
+	conn *fakeConn
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return d.conn, nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
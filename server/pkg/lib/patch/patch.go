@@ -0,0 +1,163 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+// Частичные изменения по RFC 7396 (JSON Merge Patch): поле, которого нет в теле, не меняется,
+// null очищает поле, значение заменяет его. Массивы заменяются целиком
+
+const ContentType = "application/merge-patch+json"
+
+var (
+	ErrUnsupportedContentType = errors.New("unsupported content type, expected application/merge-patch+json")
+	ErrInvalidPatch           = errors.New("invalid patch, expected JSON object with known fields")
+)
+
+// Field - поле тела PATCH. Set - поле есть в теле, Null - передан null, тогда Value - нулевое значение
+type Field[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// Of - поле со значением, например для изменений, собранных в коде
+func Of[T any](value T) Field[T] {
+	return Field[T]{Set: true, Value: value}
+}
+
+// UnmarshalJSON вызывается только для полей, которые есть в теле, поэтому отсутствующее поле остается с Set = false
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		var zero T
+		f.Null, f.Value = true, zero
+		return nil
+	}
+	f.Null = false
+	return json.Unmarshal(data, &f.Value)
+}
+
+// Present - передано значение, а не null
+func (f Field[T]) Present() bool {
+	return f.Set && !f.Null
+}
+
+// IsNull - поле передано как null
+func (f Field[T]) IsNull() bool {
+	return f.Set && f.Null
+}
+
+// Cleared - поле передано как null или пустое значение
+func (f Field[T]) Cleared() bool {
+	return f.Set && (f.Null || reflect.ValueOf(&f.Value).Elem().IsZero())
+}
+
+// Apply записывает поле в dst, если оно передано: значение или нулевое значение для null
+func (f Field[T]) Apply(dst *T) {
+	if f.Set {
+		*dst = f.Value
+	}
+}
+
+// Put добавляет колонку в изменения для Updates, если поле передано. null записывается нулевым значением
+func Put[T any](columns map[string]interface{}, column string, f Field[T]) {
+	if f.Set {
+		columns[column] = f.Value
+	}
+}
+
+func (f Field[T]) validationValue() interface{} {
+	if !f.Present() {
+		return nil
+	}
+	return f.Value
+}
+
+type validationValuer interface {
+	validationValue() interface{}
+}
+
+// Decode читает тело PATCH: JSON объект с Content-Type application/merge-patch+json или application/json.
+// Неизвестные поля - ошибка, иначе опечатка в имени поля молча ничего бы не меняла
+func Decode(r *http.Request, v interface{}) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != ContentType && mediaType != "application/json") {
+		return ErrUnsupportedContentType
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		return ErrInvalidPatch
+	}
+	return Unmarshal(raw, v)
+}
+
+// Unmarshal разбирает уже прочитанное тело PATCH по тем же правилам, что и Decode,
+// например правку, которая хранится и применяется позже
+func Unmarshal(data []byte, v interface{}) error {
+	// тело - объект, а не null, массив или значение, которыми RFC 7396 заменяет сущность целиком
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return ErrInvalidPatch
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return ErrInvalidPatch
+	}
+	return nil
+}
+
+// RegisterValidation учит validator проверять Field по тегам поля: проверяется переданное значение,
+// отсутствующее поле и null пропускаются как пустые (omitempty), поэтому обязательные поля проверяет Required.
+// Field с другими типами передаются в types
+func RegisterValidation(v *validator.Validate, types ...interface{}) {
+	types = append(types, Field[string]{}, Field[int]{}, Field[int64]{}, Field[uint]{},
+		Field[[]string]{}, Field[[]uint]{})
+
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if valuer, ok := field.Interface().(validationValuer); ok {
+			return valuer.validationValue()
+		}
+		return nil
+	}, types...)
+}
+
+// ClearError - null или пустое значение передано для поля, которое нельзя очистить
+type ClearError struct {
+	Field string
+}
+
+func (e *ClearError) Error() string {
+	return fmt.Sprintf("field %s cannot be cleared", e.Field)
+}
+
+type Clearable interface {
+	Cleared() bool
+}
+
+// Required проверяет, что обязательные поля не очищаются. fields - имя поля в JSON и само поле
+func Required(fields map[string]Clearable) error {
+	names := make([]string, 0, len(fields))
+	for name, field := range fields {
+		if field.Cleared() {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	sort.Strings(names)
+	return &ClearError{Field: names[0]}
+}
@@ -0,0 +1,188 @@
+package patch
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testPatch struct {
+	Title   Field[string] `json:"title"`
+	Runtime Field[int]    `json:"runtime"`
+}
+
+func TestUnmarshalField(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		title Field[string]
+	}{
+		{name: "absent", body: `{}`, title: Field[string]{}},
+		{name: "null", body: `{"title": null}`, title: Field[string]{Set: true, Null: true}},
+		{name: "value", body: `{"title": "Alien"}`, title: Field[string]{Set: true, Value: "Alien"}},
+		{name: "empty value", body: `{"title": ""}`, title: Field[string]{Set: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p testPatch
+			if err := Unmarshal([]byte(tt.body), &p); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if p.Title != tt.title {
+				t.Errorf("Title = %+v, want %+v", p.Title, tt.title)
+			}
+			if p.Runtime.Set {
+				t.Errorf("Runtime.Set = true for absent field")
+			}
+		})
+	}
+}
+
+func TestFieldState(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   Field[string]
+		present bool
+		isNull  bool
+		cleared bool
+	}{
+		{name: "absent", field: Field[string]{}},
+		{name: "null", field: Field[string]{Set: true, Null: true}, isNull: true, cleared: true},
+		{name: "empty value", field: Field[string]{Set: true}, present: true, cleared: true},
+		{name: "value", field: Of("Alien"), present: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Present(); got != tt.present {
+				t.Errorf("Present() = %v, want %v", got, tt.present)
+			}
+			if got := tt.field.IsNull(); got != tt.isNull {
+				t.Errorf("IsNull() = %v, want %v", got, tt.isNull)
+			}
+			if got := tt.field.Cleared(); got != tt.cleared {
+				t.Errorf("Cleared() = %v, want %v", got, tt.cleared)
+			}
+		})
+	}
+}
+
+func TestApplyAndPut(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   Field[string]
+		want    string
+		written bool
+	}{
+		{name: "absent keeps value", field: Field[string]{}, want: "old"},
+		{name: "null clears value", field: Field[string]{Set: true, Null: true}, want: "", written: true},
+		{name: "value replaces value", field: Of("new"), want: "new", written: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := "old"
+			tt.field.Apply(&value)
+			if value != tt.want {
+				t.Errorf("Apply() = %q, want %q", value, tt.want)
+			}
+
+			columns := make(map[string]interface{})
+			Put(columns, "title", tt.field)
+			got, ok := columns["title"]
+			if ok != tt.written {
+				t.Fatalf("Put() wrote column = %v, want %v", ok, tt.written)
+			}
+			if ok && got != tt.want {
+				t.Errorf("Put() column = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "empty", body: ``},
+		{name: "null", body: `null`},
+		{name: "array", body: `[{"title": "Alien"}]`},
+		{name: "unknown field", body: `{"name": "Alien"}`},
+		{name: "wrong type", body: `{"runtime": "long"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p testPatch
+			if err := Unmarshal([]byte(tt.body), &p); !errors.Is(err, ErrInvalidPatch) {
+				t.Errorf("Unmarshal() error = %v, want %v", err, ErrInvalidPatch)
+			}
+		})
+	}
+}
+
+func TestDecodeContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		wantErr     error
+	}{
+		{name: "merge patch", contentType: ContentType},
+		{name: "json with charset", contentType: "application/json; charset=utf-8"},
+		{name: "form", contentType: "application/x-www-form-urlencoded", wantErr: ErrUnsupportedContentType},
+		{name: "missing", contentType: "", wantErr: ErrUnsupportedContentType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"title": "Alien"}`))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			var p testPatch
+			if err := Decode(r, &p); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequired(t *testing.T) {
+	tests := []struct {
+		name      string
+		fields    map[string]Clearable
+		wantField string
+	}{
+		{name: "absent", fields: map[string]Clearable{"title": Field[string]{}}},
+		{name: "value", fields: map[string]Clearable{"title": Of("Alien")}},
+		{name: "null", fields: map[string]Clearable{"title": Field[string]{Set: true, Null: true}}, wantField: "title"},
+		{
+			name: "first cleared field by name",
+			fields: map[string]Clearable{
+				"title":   Field[string]{Set: true},
+				"runtime": Field[int]{Set: true, Null: true},
+			},
+			wantField: "runtime",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Required(tt.fields)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Required() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var clearErr *ClearError
+			if !errors.As(err, &clearErr) || clearErr.Field != tt.wantField {
+				t.Errorf("Required() error = %v, want ClearError for %s", err, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package response
+
+import avatarManager "server/pkg/lib/avatarMenager"
+
+// ImageData - изображение во всех размерах. URL - папка изображения (или сам файл для загруженных
+// до появления размеров), Srcset - адрес каждого размера по имени (например "400x600"),
+// Blurhash и DominantColor - заглушка, которую клиент показывает до загрузки изображения
+type ImageData struct {
+	URL           string            `json:"url"`
+	Srcset        map[string]string `json:"srcset,omitempty"`
+	Blurhash      string            `json:"blurhash,omitempty"`
+	DominantColor string            `json:"dominant_color,omitempty"`
+}
+
+func toImageData(url string, blurhash string, color string, profile avatarManager.Profile) *ImageData {
+	if url == "" {
+		return nil
+	}
+
+	return &ImageData{
+		URL:           url,
+		Srcset:        avatarManager.Srcset(url, profile),
+		Blurhash:      blurhash,
+		DominantColor: color,
+	}
+}
+
+func posterData(url string, blurhash string, color string) *ImageData {
+	return toImageData(url, blurhash, color, avatarManager.PosterProfile)
+}
+
+func avatarData(url *string, blurhash string, color string) *ImageData {
+	if url == nil {
+		return nil
+	}
+	return toImageData(*url, blurhash, color, avatarManager.AvatarProfile)
+}
+
+func coverData(url string, blurhash string, color string) *ImageData {
+	return toImageData(url, blurhash, color, avatarManager.CoverProfile)
+}
+
+func backdropData(url string, blurhash string, color string) *ImageData {
+	return toImageData(url, blurhash, color, avatarManager.BackdropProfile)
+}
@@ -0,0 +1,230 @@
+package response
+
+import (
+	"net/http"
+	"server/pkg/middleware/locale"
+)
+
+// messages - каталог сообщений API. Ключ - английский текст, он же остается в коде ошибок и в логах,
+// поэтому для locale.EN перевод не нужен. Сообщение без перевода отдается как есть.
+var messages = map[string]map[string]string{
+	locale.RU: {
+		// общие
+		"internal server error":        "внутренняя ошибка сервера",
+		"invalid server error":         "внутренняя ошибка сервера",
+		"failed to decode request":     "не удалось разобрать запрос",
+		"unable to parse form":         "не удалось разобрать форму",
+		"invalid multipart form":       "некорректная multipart форма",
+		"missing JSON data":            "отсутствуют JSON данные",
+		"invalid JSON data":            "некорректные JSON данные",
+		"invalid JSON part":            "некорректная JSON часть формы",
+		"failed to get file from form": "не удалось получить файл из формы",
+		"invalid id":                   "некорректный идентификатор",
+		"invalid limit":                "некорректный limit",
+		"query parameter is required":  "параметр query обязателен",
+		"invalid state":                "некорректный state",
+		"unsupported provider":         "провайдер не поддерживается",
+
+		// пользователи и авторизация
+		"access forbidden":                    "доступ запрещен",
+		"unauthorized":                        "требуется авторизация",
+		"no access token":                     "отсутствует access токен",
+		"no refresh token":                    "отсутствует refresh токен",
+		"invalid token":                       "недействительный токен",
+		"token expired":                       "срок действия токена истек",
+		"user not found":                      "пользователь не найден",
+		"failed email or login or password":   "неверный email, логин или пароль",
+		"email already confirmed":             "email уже подтвержден",
+		"email not confirmed":                 "email не подтвержден",
+		"invalid confirm code":                "неверный код подтверждения",
+		"login already exists":                "логин уже занят",
+		"user with this email already exists": "пользователь с таким email уже существует",
+		"user with this login already exists": "пользователь с таким логином уже существует",
+		"pls auth with oauth2":                "войдите через OAuth2",
+		"invalid reviewer UserId":             "некорректный идентификатор рецензента",
+
+		// изображения
+		"invalid avatar file":                                          "некорректный файл аватара",
+		"invalid sizeAvatar error":                                     "некорректный размер аватара",
+		"file size exceeds 1 MB limit":                                 "размер файла превышает 1 МБ",
+		"invalid resolution avatar, minimal avatar resolution 128x128": "некорректное разрешение аватара, минимальное разрешение 128x128",
+		"invalid type avatar, supported avatar formats are jpg, jpeg, png, webp, or no animated gif": "некорректный формат аватара, поддерживаются jpg, jpeg, png, webp и неанимированный gif",
+		"invalid resolution poster, minimal poster resolution 400x600":                               "некорректное разрешение постера, минимальное разрешение 400x600",
+		"invalid type poster, supported avatar formats are jpg, jpeg, png, webp, or no animated gif": "некорректный формат постера, поддерживаются jpg, jpeg, png, webp и неанимированный gif",
+		"invalid resolution cover, minimal cover resolution 640x360":                                 "некорректное разрешение обложки, минимальное разрешение 640x360",
+		"invalid type cover, supported cover formats are jpg, jpeg, png, webp, or no animated gif":   "некорректный формат обложки, поддерживаются jpg, jpeg, png, webp и неанимированный gif",
+		"invalid type image, supported image formats are jpg, jpeg, png, webp, or no animated gif":   "некорректный формат изображения, поддерживаются jpg, jpeg, png, webp и неанимированный gif",
+		"invalid resolution backdrop, minimal backdrop resolution 1280x720":                          "некорректное разрешение фона, минимальное разрешение 1280x720",
+		"invalid resolution still, minimal still resolution 640x360":                                 "некорректное разрешение кадра, минимальное разрешение 640x360",
+		"invalid resolution logo, minimal logo resolution 200x50":                                    "некорректное разрешение логотипа, минимальное разрешение 200x50",
+
+		// фильмы
+		"film not found":                                                        "фильм не найден",
+		"film already exists":                                                   "фильм уже существует",
+		"invalid film data":                                                     "некорректные данные фильма",
+		"invalid film FilmId":                                                   "некорректный идентификатор фильма",
+		"film poster not found":                                                 "постер фильма не найден",
+		"film poster upload failed":                                             "не удалось загрузить постер фильма",
+		"film search failed":                                                    "не удалось выполнить поиск фильмов",
+		"film is not a series":                                                  "фильм не является сериалом",
+		"invalid release_date":                                                  "некорректная дата выхода",
+		"invalid create_at format":                                              "некорректный формат create_at",
+		"invalid genre_ids format":                                              "некорректный формат genre_ids",
+		"invalid actor_ids format":                                              "некорректный формат actor_ids",
+		"invalid director_ids format":                                           "некорректный формат director_ids",
+		"invalid min_rating format":                                             "некорректный формат min_rating",
+		"invalid max_rating format":                                             "некорректный формат max_rating",
+		"invalid min_year format":                                               "некорректный формат min_year",
+		"invalid max_year format":                                               "некорректный формат max_year",
+		"invalid min_date format, expected RFC3339":                             "некорректный формат min_date, ожидается RFC3339",
+		"invalid max_date format, expected RFC3339":                             "некорректный формат max_date, ожидается RFC3339",
+		"invalid min_duration format, examples: 2h30m, 90m":                     "некорректный формат min_duration, например: 2h30m, 90m",
+		"invalid max_duration format, examples: 2h30m, 90m":                     "некорректный формат max_duration, например: 2h30m, 90m",
+		"invalid min_budget format, expected non-negative integer":              "некорректный формат min_budget, ожидается неотрицательное целое число",
+		"invalid max_budget format, expected non-negative integer":              "некорректный формат max_budget, ожидается неотрицательное целое число",
+		"invalid min_box_office format, expected non-negative integer":          "некорректный формат min_box_office, ожидается неотрицательное целое число",
+		"invalid max_box_office format, expected non-negative integer":          "некорректный формат max_box_office, ожидается неотрицательное целое число",
+		"invalid page format, expected positive integer":                        "некорректный формат page, ожидается положительное целое число",
+		"invalid page_size format, expected positive integer between 1 and 100": "некорректный формат page_size, ожидается целое число от 1 до 100",
+		"invalid limit format, expected positive integer between 1 and 100":     "некорректный формат limit, ожидается целое число от 1 до 100",
+		"invalid limit format, expected positive integer between 1 and 50":      "некорректный формат limit, ожидается целое число от 1 до 50",
+
+		// сериалы
+		"season not found":                              "сезон не найден",
+		"season already exists":                         "сезон уже существует",
+		"episode not found":                             "серия не найдена",
+		"episode already exists":                        "серия уже существует",
+		"invalid season number":                         "некорректный номер сезона",
+		"invalid episode number":                        "некорректный номер серии",
+		"season or episode does not belong to the film": "сезон или серия не относятся к фильму",
+
+		// коллекции и связи
+		"collection not found":                       "коллекция не найдена",
+		"collection already exists":                  "коллекция уже существует",
+		"film already belongs to another collection": "фильм уже входит в другую коллекцию",
+		"relation already exists":                    "связь уже существует",
+		"relation not found":                         "связь не найдена",
+		"film can't be related to itself":            "фильм не может быть связан сам с собой",
+		"invalid relation, expected one of: sequel, prequel, remake, spin_off": "некорректный тип связи, ожидается одно из: sequel, prequel, remake, spin_off",
+
+		// жанры
+		"no such genre":                                      "жанр не найден",
+		"genre not found":                                    "жанр не найден",
+		"genre already exists":                               "жанр уже существует",
+		"invalid genre id":                                   "некорректный идентификатор жанра",
+		"no such parent genre":                               "родительский жанр не найден",
+		"genre with this slug already exists":                "жанр с таким slug уже существует",
+		"genre cannot be nested into itself or its subgenre": "жанр нельзя вложить в самого себя или в свой поджанр",
+		"failed to upload genre cover":                       "не удалось загрузить обложку жанра",
+
+		// персоны
+		"person not found":  "персона не найдена",
+		"invalid person id": "некорректный идентификатор персоны",
+		"invalid actor id":  "некорректный идентификатор актера",
+
+		// рецензии
+		"no such review":        "рецензия не найдена",
+		"review exists":         "рецензия уже существует",
+		"invalid review FilmId": "некорректный идентификатор фильма рецензии",
+
+		// галерея фильма
+		"film image not found":     "изображение фильма не найдено",
+		"film image upload failed": "не удалось загрузить изображение фильма",
+		"invalid image_id":         "некорректный идентификатор изображения",
+		"invalid image type, supported types are backdrop, still, logo, poster": "некорректный тип изображения, поддерживаются backdrop, still, logo, poster",
+		"image order must list every image of the type exactly once":            "в новом порядке должно быть каждое изображение типа ровно один раз",
+		"invalid primary format, expected true or false":                        "некорректный формат primary, ожидается true или false",
+
+		// видео фильма
+		"film video not found":        "видео фильма не найдено",
+		"video already added to film": "видео уже добавлено фильму",
+		"invalid video_id":            "некорректный идентификатор видео",
+		"invalid video type, supported types are trailer, teaser, clip":  "некорректный тип видео, поддерживаются trailer, teaser, clip",
+		"invalid video url, supported providers are youtube, vk, rutube": "некорректная ссылка на видео, поддерживаются youtube, vk, rutube",
+
+		// загрузки в S3
+		"upload not found":                         "загрузка не найдена",
+		"upload expired, request a new upload url": "срок загрузки истек, запросите новую ссылку",
+		"upload target not found":                  "фильм, персона или пользователь для загрузки не найдены",
+		"failed to save uploaded image":            "не удалось сохранить загруженное изображение",
+		"invalid upload purpose, supported purposes are film_poster, person_avatar, user_avatar": "некорректное назначение загрузки, поддерживаются film_poster, person_avatar, user_avatar",
+		"file too large, maximum size is 10 MB for posters and 5 MB for avatars":                 "файл слишком большой, максимум 10 МБ для постеров и 5 МБ для аватаров",
+		"file is not uploaded yet, upload the file before finalizing":                            "файл еще не загружен, загрузите файл перед завершением",
+
+		// версии
+		"entity was modified by another request, reload it and retry":      "запись изменена другим запросом, загрузите ее заново и повторите",
+		"version is required, pass it in If-Match header or version field": "нужна версия записи, передайте ее в заголовке If-Match или в поле version",
+		"invalid If-Match header, expected entity version in quotes":       "некорректный заголовок If-Match, ожидается версия записи в кавычках",
+
+		// частичные изменения
+		"unsupported content type, expected application/merge-patch+json":   "неподдерживаемый тип содержимого, ожидается application/merge-patch+json",
+		"invalid patch, expected JSON object with known fields":             "некорректное изменение, ожидается JSON объект с известными полями",
+		"avatar can only be reset with null, upload a new one via /uploads": "аватар можно только сбросить значением null, новый загружается через /uploads",
+
+		// журнал изменений
+		"unknown entity type, expected one of: film, person, genre, review, user": "неизвестный тип сущности, ожидается одно из: film, person, genre, review, user",
+		"entity_id filter requires entity_type":                                   "фильтр entity_id требует entity_type",
+		"invalid time range, from must be before to":                              "некорректный период, from должен быть раньше to",
+		"invalid entity_id format":                                                "некорректный формат entity_id",
+		"invalid user_id format":                                                  "некорректный формат user_id",
+		"invalid from format, expected RFC3339":                                   "некорректный формат from, ожидается RFC3339",
+		"invalid to format, expected RFC3339":                                     "некорректный формат to, ожидается RFC3339",
+
+		// корзина
+		"unknown entity type, expected one of: film, person, genre, user": "неизвестный тип сущности, ожидается одно из: film, person, genre, user",
+		"entity not found in trash":                                       "сущность не найдена в корзине",
+
+		// история версий
+		"unknown entity type, expected one of: film, person": "неизвестный тип сущности, ожидается одно из: film, person",
+		"revision not found":                            "версия не найдена",
+		"revision references deleted genres or persons": "версия ссылается на удаленные жанры или персоны",
+		"invalid version":                               "некорректный номер версии",
+		"invalid from version":                          "некорректная версия from",
+		"invalid to version":                            "некорректная версия to",
+
+		// предложения правок
+		"unknown status, expected one of: pending, approved, rejected": "неизвестный статус, ожидается одно из: pending, approved, rejected",
+		"suggestion not found":                                "предложение правки не найдено",
+		"suggestion does not change anything":                 "предложение ничего не меняет",
+		"suggestion has already been reviewed":                "предложение уже рассмотрено",
+		"suggestion can no longer be applied to current data": "предложение больше нельзя применить к текущим данным",
+		"suggestion references deleted genres or persons":     "предложение ссылается на удаленные жанры или персоны",
+
+		// теги
+		"tag not found":                           "тег не найден",
+		"tag already exists":                      "тег уже существует",
+		"tag name must contain letters or digits": "название тега должно содержать буквы или цифры",
+		"film has no such tag":                    "у фильма нет такого тега",
+		"tag already added to film":               "тег уже добавлен фильму",
+		"vote not found":                          "голос не найден",
+		"invalid tag_id":                          "некорректный идентификатор тега",
+		"invalid limit format, expected positive integer between 1 and 200": "некорректный формат limit, ожидается целое число от 1 до 200",
+
+		// переводы
+		"unknown entity type, expected one of: film, genre, person": "неизвестный тип сущности, ожидается одно из: film, genre, person",
+		"entity not found":          "сущность не найдена",
+		"unsupported locale":        "язык не поддерживается",
+		"field is not translatable": "поле не переводится",
+		"translation not found":     "перевод не найден",
+
+		// валидация, %s - имя поля
+		"field %s is a required field":  "поле %s обязательно",
+		"field %s is not a valid Email": "поле %s не является корректным email",
+		"field %s is not a valid Login": "поле %s не является корректным логином",
+		"field %s has an invalid value": "поле %s имеет некорректное значение",
+		"field %s cannot be cleared":    "поле %s нельзя очистить",
+	},
+}
+
+// Translate возвращает сообщение на языке lang, если перевода нет - исходное сообщение
+func Translate(lang, msg string) string {
+	if translated, ok := messages[lang][msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// translate - Translate для языка запроса
+func translate(r *http.Request, msg string) string {
+	return Translate(locale.FromRequest(r), msg)
+}
@@ -1,14 +1,29 @@
 package response
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
-	act "server/internal/modules/actor"
+	"net/http"
+	au "server/internal/modules/audit"
+	col "server/internal/modules/collection"
 	f "server/internal/modules/film"
 	g "server/internal/modules/genre"
+	md "server/internal/modules/media"
+	per "server/internal/modules/person"
+	rec "server/internal/modules/recommendation"
 	r "server/internal/modules/review"
+	rv "server/internal/modules/revision"
+	sr "server/internal/modules/series"
+	sg "server/internal/modules/suggestion"
+	tg "server/internal/modules/tag"
+	tr "server/internal/modules/translation"
+	trs "server/internal/modules/trash"
+	up "server/internal/modules/upload"
 	u "server/internal/modules/user/profile"
+	"server/pkg/lib/patch"
+	"server/pkg/lib/videolink"
 	"strings"
 	"time"
 )
@@ -31,57 +46,63 @@ type AccessTokenData struct {
 }
 
 type UserProfileData struct {
-	Email     *string `json:"email,omitempty"`
-	Login     *string `json:"login,omitempty"`
-	AvatarUrl *string `json:"avatar_url"`
+	Email  *string    `json:"email,omitempty"`
+	Login  *string    `json:"login,omitempty"`
+	Avatar *ImageData `json:"avatar"`
 }
 
 func UserProfile(user *u.UserProfile) Response {
 	return Response{
 		Status: StatusOK,
 		Data: UserProfileData{
-			Email:     user.Email,
-			Login:     user.Login,
-			AvatarUrl: user.AvatarUrl,
+			Email:  user.Email,
+			Login:  user.Login,
+			Avatar: avatarData(user.AvatarUrl, user.AvatarBlurhash, user.AvatarColor),
 		},
 	}
 }
 
-type ActorData struct {
-	Id        uint       `json:"actor_id"`
-	Name      *string    `json:"name,omitempty"`
-	WikiUrl   *string    `json:"wiki_url,omitempty"`
-	AvatarUrl *string    `json:"avatar_url"`
-	CreatedAt *time.Time `json:"created_at"`
+type PersonData struct {
+	Id         uint       `json:"person_id"`
+	Name       *string    `json:"name,omitempty"`
+	Department *string    `json:"department,omitempty"`
+	WikiUrl    *string    `json:"wiki_url,omitempty"`
+	Avatar     *ImageData `json:"avatar"`
+	CreatedAt  *time.Time `json:"created_at"`
+	Version    int        `json:"version"`
 }
 
-func Actors(actors interface{}) Response {
-	switch v := actors.(type) {
-	case *act.ActorDTO:
+func Persons(persons interface{}) Response {
+	switch v := persons.(type) {
+	case *per.PersonDTO:
 		return Response{
 			Status: StatusOK,
-			Data: ActorData{
-				Id:        v.ActorId,
-				Name:      &v.Name,
-				WikiUrl:   &v.WikiUrl,
-				AvatarUrl: v.AvatarUrl,
-				CreatedAt: &v.CreatedAt,
+			Data: PersonData{
+				Id:         v.PersonId,
+				Name:       &v.Name,
+				Department: &v.Department,
+				WikiUrl:    &v.WikiUrl,
+				Avatar:     avatarData(v.AvatarUrl, v.AvatarBlurhash, v.AvatarColor),
+				CreatedAt:  &v.CreatedAt,
+				Version:    v.Version,
 			},
 		}
-	case []*act.ActorDTO:
-		var actors []ActorData
-		for _, actor := range v {
-			actors = append(actors, ActorData{
-				Id:        actor.ActorId,
-				Name:      &actor.Name,
-				WikiUrl:   &actor.WikiUrl,
-				AvatarUrl: actor.AvatarUrl,
-				CreatedAt: &actor.CreatedAt,
+	case []*per.PersonDTO:
+		var persons []PersonData
+		for _, person := range v {
+			persons = append(persons, PersonData{
+				Id:         person.PersonId,
+				Name:       &person.Name,
+				Department: &person.Department,
+				WikiUrl:    &person.WikiUrl,
+				Avatar:     avatarData(person.AvatarUrl, person.AvatarBlurhash, person.AvatarColor),
+				CreatedAt:  &person.CreatedAt,
+				Version:    person.Version,
 			})
 		}
 		return Response{
 			Status: StatusOK,
-			Data:   actors,
+			Data:   persons,
 		}
 	default:
 		return Response{
@@ -91,10 +112,70 @@ func Actors(actors interface{}) Response {
 	}
 }
 
+type FilmographyEntryData struct {
+	FilmID       uint       `json:"film_id"`
+	Title        string     `json:"title"`
+	Poster       *ImageData `json:"poster"`
+	ReleaseDate  time.Time  `json:"release_date"`
+	Character    string     `json:"character,omitempty"`
+	BillingOrder int        `json:"billing_order"`
+}
+
+type FilmographyGroupData struct {
+	Role  string                 `json:"role"`
+	Films []FilmographyEntryData `json:"films"`
+}
+
+func Filmography(filmography []*per.FilmographyGroupDTO) Response {
+	groups := make([]FilmographyGroupData, 0, len(filmography))
+	for _, group := range filmography {
+		films := make([]FilmographyEntryData, 0, len(group.Films))
+		for _, entry := range group.Films {
+			films = append(films, FilmographyEntryData{
+				FilmID:       entry.FilmID,
+				Title:        entry.Title,
+				Poster:       posterData(entry.PosterURL, entry.PosterBlurhash, entry.PosterColor),
+				ReleaseDate:  entry.ReleaseDate,
+				Character:    entry.Character,
+				BillingOrder: entry.BillingOrder,
+			})
+		}
+		groups = append(groups, FilmographyGroupData{
+			Role:  group.Role,
+			Films: films,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   groups,
+	}
+}
+
 type GenreData struct {
-	Id        uint       `json:"genre_id"`
-	Name      *string    `json:"name,omitempty"`
-	CreatedAt *time.Time `json:"created_at"`
+	Id          uint       `json:"genre_id"`
+	Name        *string    `json:"name,omitempty"`
+	Slug        string     `json:"slug,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Cover       *ImageData `json:"cover,omitempty"`
+	ParentID    *uint      `json:"parent_id,omitempty"`
+	CreatedAt   *time.Time `json:"created_at"`
+	Version     int        `json:"version,omitempty"`
+}
+
+type GenrePageData struct {
+	Genre     GenreData     `json:"genre"`
+	Ancestors []GenreData   `json:"ancestors"`
+	Children  []GenreData   `json:"children"`
+	TopFilms  []TopFilmData `json:"top_films"`
+}
+
+type TopFilmData struct {
+	ID           uint       `json:"id"`
+	Title        string     `json:"title"`
+	Poster       *ImageData `json:"poster"`
+	ReleaseDate  time.Time  `json:"release_date"`
+	AvgRating    float64    `json:"avg_rating"`
+	TotalReviews int        `json:"total_reviews"`
 }
 
 func Genres(genres interface{}) Response {
@@ -102,20 +183,12 @@ func Genres(genres interface{}) Response {
 	case *g.GenreDTO:
 		return Response{
 			Status: StatusOK,
-			Data: GenreData{
-				Id:        v.GenreId,
-				Name:      &v.Name,
-				CreatedAt: &v.CreateAt,
-			},
+			Data:   toGenreData(v),
 		}
 	case []*g.GenreDTO:
 		var genres []GenreData
 		for _, genre := range v {
-			genres = append(genres, GenreData{
-				Id:        genre.GenreId,
-				Name:      &genre.Name,
-				CreatedAt: &genre.CreateAt,
-			})
+			genres = append(genres, toGenreData(genre))
 		}
 		return Response{
 			Status: StatusOK,
@@ -129,15 +202,69 @@ func Genres(genres interface{}) Response {
 	}
 }
 
+func GenrePage(page *g.GenrePageDTO) Response {
+	data := GenrePageData{
+		Genre:     toGenreData(page.Genre),
+		Ancestors: make([]GenreData, 0, len(page.Ancestors)),
+		Children:  make([]GenreData, 0, len(page.Children)),
+		TopFilms:  make([]TopFilmData, 0, len(page.TopFilms)),
+	}
+	for _, genre := range page.Ancestors {
+		data.Ancestors = append(data.Ancestors, toGenreData(genre))
+	}
+	for _, genre := range page.Children {
+		data.Children = append(data.Children, toGenreData(genre))
+	}
+	for _, film := range page.TopFilms {
+		data.TopFilms = append(data.TopFilms, TopFilmData{
+			ID:           film.FilmID,
+			Title:        film.Title,
+			Poster:       posterData(film.PosterURL, film.PosterBlurhash, film.PosterColor),
+			ReleaseDate:  film.ReleaseDate,
+			AvgRating:    film.AvgRating,
+			TotalReviews: film.TotalReviews,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   data,
+	}
+}
+
+func toGenreData(genre *g.GenreDTO) GenreData {
+	return GenreData{
+		Id:          genre.GenreId,
+		Name:        &genre.Name,
+		Slug:        genre.Slug,
+		Description: genre.Description,
+		Cover:       coverData(genre.CoverURL, genre.CoverBlurhash, genre.CoverColor),
+		ParentID:    genre.ParentID,
+		CreatedAt:   &genre.CreateAt,
+		Version:     genre.Version,
+	}
+}
+
 type FilmData struct {
-	ID          uint      `json:"id"`
-	Title       string    `json:"title"`
-	PosterURL   string    `json:"poster_url"`
-	Synopsis    string    `json:"synopsis"`
-	ReleaseDate time.Time `json:"release_date"`
-	Runtime     string    `json:"runtime"`
-	Producer    string    `json:"producer"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          uint       `json:"id"`
+	ContentType string     `json:"content_type"`
+	Title       string     `json:"title"`
+	Poster      *ImageData `json:"poster"`
+	Synopsis    string     `json:"synopsis"`
+	ReleaseDate time.Time  `json:"release_date"`
+	Runtime     string     `json:"runtime"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Version     int        `json:"version"`
+
+	OriginalTitle string          `json:"original_title,omitempty"`
+	AltTitles     []string        `json:"alt_titles,omitempty"`
+	Tagline       string          `json:"tagline,omitempty"`
+	Countries     []string        `json:"countries,omitempty"`
+	Languages     []string        `json:"languages,omitempty"`
+	AgeRating     string          `json:"age_rating,omitempty"`
+	Budget        int64           `json:"budget,omitempty"`
+	BoxOffice     int64           `json:"box_office,omitempty"`
+	Currency      string          `json:"currency,omitempty"`
+	ExternalIDs   ExternalIDsData `json:"external_ids"`
 
 	AvgRating          float64 `json:"avg_rating"`
 	TotalReviews       uint    `json:"total_reviews"`
@@ -147,143 +274,419 @@ type FilmData struct {
 	CountRatings61_80  uint    `json:"count_ratings_61_80"`
 	CountRatings81_100 uint    `json:"count_ratings_81_100"`
 
-	GenreIDs []uint      `json:"genre_ids,omitempty"` // Только ID жанров
-	ActorIDs []uint      `json:"actor_ids,omitempty"` // Только ID актеров
-	Genres   []GenreData `json:"genres,omitempty"`    // Полные данные жанров
-	Actors   []ActorData `json:"actors,omitempty"`    // Полные данные актеров
+	GenreIDs []uint       `json:"genre_ids,omitempty"` // Только ID жанров
+	Genres   []GenreData  `json:"genres,omitempty"`    // Полные данные жанров
+	Cast     []CreditData `json:"cast"`                // Актерский состав в порядке титров
+	Crew     []CreditData `json:"crew"`                // Режиссеры, сценаристы, продюсеры, композиторы
+
+	Collection *FilmCollectionData `json:"collection,omitempty"`
+	Tags       []FilmTagData       `json:"tags,omitempty"` // Одобренные теги, самые релевантные первыми
+
+	Backdrop *ImageData `json:"backdrop"` // Основной фон из галереи фильма
+	Trailer  *VideoData `json:"trailer"`  // Основной трейлер фильма
+}
+
+type FilmTagData struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type FilmCollectionData struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+type CreditData struct {
+	PersonID     uint       `json:"person_id"`
+	Name         string     `json:"name"`
+	Avatar       *ImageData `json:"avatar,omitempty"`
+	Role         string     `json:"role"`
+	Character    string     `json:"character,omitempty"`
+	BillingOrder int        `json:"billing_order"`
+}
+
+type ExternalIDsData struct {
+	IMDb      string `json:"imdb,omitempty"`
+	Kinopoisk string `json:"kinopoisk,omitempty"`
+	TMDB      string `json:"tmdb,omitempty"`
 }
 
 func Films(films interface{}) Response {
 	switch v := films.(type) {
 	case *f.FilmDTO:
-		filmData := FilmData{
-			ID:          v.ID,
-			Title:       v.Title,
-			PosterURL:   v.PosterURL,
-			Synopsis:    v.Synopsis,
-			ReleaseDate: v.ReleaseDate,
-			Runtime:     v.Runtime,
-			Producer:    v.Producer,
-			CreatedAt:   v.CreateAt,
-
-			AvgRating:          v.AvgRating,
-			TotalReviews:       v.TotalReviews,
-			CountRatings0_20:   v.CountRatings0_20,
-			CountRatings21_40:  v.CountRatings21_40,
-			CountRatings41_60:  v.CountRatings41_60,
-			CountRatings61_80:  v.CountRatings61_80,
-			CountRatings81_100: v.CountRatings81_100,
-		}
-
-		// Если переданы только FilmId жанров
-		if len(v.GenreIDs) > 0 {
-			filmData.GenreIDs = v.GenreIDs
-		}
-
-		// Если переданы полные данные жанров
-		if len(v.Genres) > 0 {
-			genres := make([]GenreData, len(v.Genres))
-			for i, genre := range v.Genres {
-				genres[i] = GenreData{
-					Id:        genre.GenreId,
-					Name:      &genre.Name,
-					CreatedAt: &genre.CreateAt,
-				}
-			}
-			filmData.Genres = genres
+		return Response{
+			Status: StatusOK,
+			Data:   toFilmData(v),
 		}
 
-		// Если переданы только FilmId актеров
-		if len(v.ActorIDs) > 0 {
-			filmData.ActorIDs = v.ActorIDs
+	case []*f.FilmDTO:
+		var filmList []FilmData
+		for _, film := range v {
+			filmList = append(filmList, toFilmData(film))
+		}
+		return Response{
+			Status: StatusOK,
+			Data:   filmList,
 		}
 
-		// Если переданы полные данные актеров
-		if len(v.Actors) > 0 {
-			actors := make([]ActorData, len(v.Actors))
-			for i, actor := range v.Actors {
-				actors[i] = ActorData{
-					Id:        actor.ActorId,
-					Name:      &actor.Name,
-					WikiUrl:   &actor.WikiUrl,
-					AvatarUrl: actor.AvatarUrl,
-					CreatedAt: &actor.CreatedAt,
-				}
-			}
-			filmData.Actors = actors
+	default:
+		return Response{
+			Status: StatusError,
+			Error:  "invalid server error",
+		}
+	}
+}
+
+func toFilmData(film *f.FilmDTO) FilmData {
+	filmData := FilmData{
+		ID:                 film.ID,
+		ContentType:        film.ContentType,
+		Title:              film.Title,
+		Poster:             posterData(film.PosterURL, film.PosterBlurhash, film.PosterColor),
+		Synopsis:           film.Synopsis,
+		ReleaseDate:        film.ReleaseDate,
+		Runtime:            film.Runtime,
+		CreatedAt:          film.CreateAt,
+		Version:            film.Version,
+		OriginalTitle:      film.OriginalTitle,
+		AltTitles:          film.AltTitles,
+		Tagline:            film.Tagline,
+		Countries:          film.Countries,
+		Languages:          film.Languages,
+		AgeRating:          film.AgeRating,
+		Budget:             film.Budget,
+		BoxOffice:          film.BoxOffice,
+		Currency:           film.Currency,
+		AvgRating:          film.AvgRating,
+		TotalReviews:       film.TotalReviews,
+		CountRatings0_20:   film.CountRatings0_20,
+		CountRatings21_40:  film.CountRatings21_40,
+		CountRatings41_60:  film.CountRatings41_60,
+		CountRatings61_80:  film.CountRatings61_80,
+		CountRatings81_100: film.CountRatings81_100,
+		ExternalIDs: ExternalIDsData{
+			IMDb:      film.ExternalIDs.IMDb,
+			Kinopoisk: film.ExternalIDs.Kinopoisk,
+			TMDB:      film.ExternalIDs.TMDB,
+		},
+	}
+
+	// Если переданы только FilmId жанров
+	if len(film.GenreIDs) > 0 {
+		filmData.GenreIDs = film.GenreIDs
+	}
+
+	// Если переданы полные данные жанров
+	if len(film.Genres) > 0 {
+		genres := make([]GenreData, len(film.Genres))
+		for i := range film.Genres {
+			genres[i] = toGenreData(&film.Genres[i])
+		}
+		filmData.Genres = genres
+	}
+
+	filmData.Cast = make([]CreditData, 0)
+	filmData.Crew = make([]CreditData, 0)
+	for _, credit := range film.Credits {
+		data := CreditData{
+			PersonID:     credit.PersonID,
+			Name:         credit.Name,
+			Avatar:       avatarData(credit.AvatarUrl, credit.AvatarBlurhash, credit.AvatarColor),
+			Role:         credit.Role,
+			Character:    credit.Character,
+			BillingOrder: credit.BillingOrder,
+		}
+		if credit.Role == per.RoleCast {
+			filmData.Cast = append(filmData.Cast, data)
+		} else {
+			filmData.Crew = append(filmData.Crew, data)
 		}
+	}
+
+	if film.Collection != nil {
+		filmData.Collection = &FilmCollectionData{
+			ID:       film.Collection.ID,
+			Name:     film.Collection.Name,
+			Position: film.Collection.Position,
+		}
+	}
+
+	for _, tag := range film.Tags {
+		filmData.Tags = append(filmData.Tags, FilmTagData{
+			ID:   tag.ID,
+			Name: tag.Name,
+			Slug: tag.Slug,
+		})
+	}
+
+	if film.Backdrop != nil {
+		filmData.Backdrop = backdropData(film.Backdrop.URL, film.Backdrop.Blurhash, film.Backdrop.Color)
+	}
+
+	if film.Trailer != nil {
+		filmData.Trailer = &VideoData{
+			ID:       film.Trailer.ID,
+			Type:     md.VideoTypeTrailer,
+			Provider: film.Trailer.Provider,
+			Key:      film.Trailer.Key,
+			Name:     film.Trailer.Name,
+			Language: film.Trailer.Language,
+		}
+		filmData.Trailer.setLinks()
+	}
+
+	return filmData
+}
+
+type SimilarFilmData struct {
+	Film    FilmData `json:"film"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+func SimilarFilms(similar []*f.SimilarFilmDTO) Response {
+	similarList := make([]SimilarFilmData, 0, len(similar))
+	for _, s := range similar {
+		similarList = append(similarList, SimilarFilmData{
+			Film:    toFilmData(s.Film),
+			Score:   s.Score,
+			Reasons: s.Reasons,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   similarList,
+	}
+}
+
+type RecommendationData struct {
+	Film    FilmData `json:"film"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
 
+func Recommendations(recommendations []*rec.RecommendationDTO) Response {
+	recommendationList := make([]RecommendationData, 0, len(recommendations))
+	for _, rc := range recommendations {
+		recommendationList = append(recommendationList, RecommendationData{
+			Film:    toFilmData(rc.Film),
+			Score:   rc.Score,
+			Reasons: rc.Reasons,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   recommendationList,
+	}
+}
+
+type CollectionData struct {
+	ID          uint                 `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	CreatedAt   time.Time            `json:"created_at"`
+	Films       []CollectionFilmData `json:"films,omitempty"`
+	Stats       CollectionStatsData  `json:"stats"`
+}
+
+type CollectionFilmData struct {
+	FilmID       uint       `json:"film_id"`
+	Position     int        `json:"position"`
+	Title        string     `json:"title"`
+	Poster       *ImageData `json:"poster"`
+	ReleaseDate  time.Time  `json:"release_date"`
+	ContentType  string     `json:"content_type"`
+	AvgRating    float64    `json:"avg_rating"`
+	TotalReviews uint       `json:"total_reviews"`
+}
+
+type CollectionStatsData struct {
+	FilmsCount         int     `json:"films_count"`
+	AvgRating          float64 `json:"avg_rating"`
+	MeanFilmRating     float64 `json:"mean_film_rating"`
+	TotalReviews       uint    `json:"total_reviews"`
+	CountRatings0_20   uint    `json:"count_ratings_0_20"`
+	CountRatings21_40  uint    `json:"count_ratings_21_40"`
+	CountRatings41_60  uint    `json:"count_ratings_41_60"`
+	CountRatings61_80  uint    `json:"count_ratings_61_80"`
+	CountRatings81_100 uint    `json:"count_ratings_81_100"`
+	BestFilmID         *uint   `json:"best_film_id,omitempty"`
+}
+
+type RelatedFilmData struct {
+	FilmID      uint       `json:"film_id"`
+	Title       string     `json:"title"`
+	Poster      *ImageData `json:"poster"`
+	ReleaseDate time.Time  `json:"release_date"`
+	Relation    string     `json:"relation"`
+}
+
+type RelatedTitlesData struct {
+	Collection *CollectionData   `json:"collection"`
+	Relations  []RelatedFilmData `json:"relations"`
+}
+
+func Collections(collections interface{}) Response {
+	switch v := collections.(type) {
+	case *col.CollectionDTO:
 		return Response{
 			Status: StatusOK,
-			Data:   filmData,
+			Data:   toCollectionData(v),
 		}
+	case []*col.CollectionDTO:
+		collectionList := make([]CollectionData, 0, len(v))
+		for _, collection := range v {
+			collectionList = append(collectionList, toCollectionData(collection))
+		}
+		return Response{
+			Status: StatusOK,
+			Data:   collectionList,
+		}
+	default:
+		return Response{
+			Status: StatusError,
+			Error:  "invalid server error",
+		}
+	}
+}
 
-	case []*f.FilmDTO:
-		var filmList []FilmData
-		for _, film := range v {
-			filmData := FilmData{
-				ID:                 film.ID,
-				Title:              film.Title,
-				PosterURL:          film.PosterURL,
-				Synopsis:           film.Synopsis,
-				ReleaseDate:        film.ReleaseDate,
-				Runtime:            film.Runtime,
-				Producer:           film.Producer,
-				CreatedAt:          film.CreateAt,
-				AvgRating:          film.AvgRating,
-				TotalReviews:       film.TotalReviews,
-				CountRatings0_20:   film.CountRatings0_20,
-				CountRatings21_40:  film.CountRatings21_40,
-				CountRatings41_60:  film.CountRatings41_60,
-				CountRatings61_80:  film.CountRatings61_80,
-				CountRatings81_100: film.CountRatings81_100,
-			}
+func RelatedTitles(related *col.RelatedTitlesDTO) Response {
+	relatedData := RelatedTitlesData{
+		Relations: make([]RelatedFilmData, 0, len(related.Relations)),
+	}
+	if related.Collection != nil {
+		collectionData := toCollectionData(related.Collection)
+		relatedData.Collection = &collectionData
+	}
+	for _, film := range related.Relations {
+		relatedData.Relations = append(relatedData.Relations, RelatedFilmData{
+			FilmID:      film.FilmID,
+			Title:       film.Title,
+			Poster:      posterData(film.PosterURL, film.PosterBlurhash, film.PosterColor),
+			ReleaseDate: film.ReleaseDate,
+			Relation:    film.Relation,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   relatedData,
+	}
+}
 
-			// Если переданы только FilmId жанров
-			if len(film.GenreIDs) > 0 {
-				filmData.GenreIDs = film.GenreIDs
-			}
+func toCollectionData(collection *col.CollectionDTO) CollectionData {
+	collectionData := CollectionData{
+		ID:          collection.ID,
+		Name:        collection.Name,
+		Description: collection.Description,
+		CreatedAt:   collection.CreateAt,
+	}
 
-			// Если переданы полные данные жанров
-			if len(film.Genres) > 0 {
-				genres := make([]GenreData, len(film.Genres))
-				for i, genre := range film.Genres {
-					genres[i] = GenreData{
-						Id:        genre.GenreId,
-						Name:      &genre.Name,
-						CreatedAt: &genre.CreateAt,
-					}
-				}
-				filmData.Genres = genres
-			}
+	for _, film := range collection.Films {
+		collectionData.Films = append(collectionData.Films, CollectionFilmData{
+			FilmID:       film.FilmID,
+			Position:     film.Position,
+			Title:        film.Title,
+			Poster:       posterData(film.PosterURL, film.PosterBlurhash, film.PosterColor),
+			ReleaseDate:  film.ReleaseDate,
+			ContentType:  film.ContentType,
+			AvgRating:    film.AvgRating,
+			TotalReviews: film.TotalReviews,
+		})
+	}
 
-			// Если переданы только FilmId актеров
-			if len(film.ActorIDs) > 0 {
-				filmData.ActorIDs = film.ActorIDs
-			}
+	if stats := collection.Stats; stats != nil {
+		collectionData.Stats = CollectionStatsData{
+			FilmsCount:         stats.FilmsCount,
+			AvgRating:          stats.AvgRating,
+			MeanFilmRating:     stats.MeanFilmRating,
+			TotalReviews:       stats.TotalReviews,
+			CountRatings0_20:   stats.CountRatings0_20,
+			CountRatings21_40:  stats.CountRatings21_40,
+			CountRatings41_60:  stats.CountRatings41_60,
+			CountRatings61_80:  stats.CountRatings61_80,
+			CountRatings81_100: stats.CountRatings81_100,
+			BestFilmID:         stats.BestFilmID,
+		}
+	}
 
-			// Если переданы полные данные актеров
-			if len(film.Actors) > 0 {
-				actors := make([]ActorData, len(film.Actors))
-				for i, actor := range film.Actors {
-					actors[i] = ActorData{
-						Id:        actor.ActorId,
-						Name:      &actor.Name,
-						WikiUrl:   &actor.WikiUrl,
-						AvatarUrl: actor.AvatarUrl,
-						CreatedAt: &actor.CreatedAt,
-					}
-				}
-				filmData.Actors = actors
-			}
+	return collectionData
+}
+
+type SeasonData struct {
+	SeasonID      uint          `json:"season_id"`
+	FilmID        uint          `json:"film_id"`
+	SeasonNumber  int           `json:"season_number"`
+	Title         string        `json:"title"`
+	Synopsis      string        `json:"synopsis"`
+	AirDate       *time.Time    `json:"air_date"`
+	EpisodesCount int           `json:"episodes_count"`
+	AvgRating     float64       `json:"avg_rating"`
+	TotalReviews  uint          `json:"total_reviews"`
+	Episodes      []EpisodeData `json:"episodes,omitempty"`
+}
+
+type EpisodeData struct {
+	EpisodeID     uint       `json:"episode_id"`
+	SeasonID      uint       `json:"season_id"`
+	FilmID        uint       `json:"film_id"`
+	SeasonNumber  int        `json:"season_number"`
+	EpisodeNumber int        `json:"episode_number"`
+	Title         string     `json:"title"`
+	Synopsis      string     `json:"synopsis"`
+	AirDate       *time.Time `json:"air_date"`
+	Runtime       string     `json:"runtime"`
+	AvgRating     float64    `json:"avg_rating"`
+	TotalReviews  uint       `json:"total_reviews"`
+}
 
-			filmList = append(filmList, filmData)
+// Seasons отдает один сезон вместе с сериями, а список сезонов - без серий
+func Seasons(seasons interface{}) Response {
+	switch v := seasons.(type) {
+	case *sr.SeasonDTO:
+		seasonData := toSeasonData(v)
+		seasonData.Episodes = make([]EpisodeData, 0, len(v.Episodes))
+		for _, episode := range v.Episodes {
+			seasonData.Episodes = append(seasonData.Episodes, toEpisodeData(episode))
 		}
 		return Response{
 			Status: StatusOK,
-			Data:   filmList,
+			Data:   seasonData,
+		}
+	case []*sr.SeasonDTO:
+		seasonList := make([]SeasonData, 0, len(v))
+		for _, season := range v {
+			seasonList = append(seasonList, toSeasonData(season))
+		}
+		return Response{
+			Status: StatusOK,
+			Data:   seasonList,
 		}
+	default:
+		return Response{
+			Status: StatusError,
+			Error:  "invalid server error",
+		}
+	}
+}
 
+func Episodes(episodes interface{}) Response {
+	switch v := episodes.(type) {
+	case *sr.EpisodeDTO:
+		return Response{
+			Status: StatusOK,
+			Data:   toEpisodeData(v),
+		}
+	case []*sr.EpisodeDTO:
+		episodeList := make([]EpisodeData, 0, len(v))
+		for _, episode := range v {
+			episodeList = append(episodeList, toEpisodeData(episode))
+		}
+		return Response{
+			Status: StatusOK,
+			Data:   episodeList,
+		}
 	default:
 		return Response{
 			Status: StatusError,
@@ -292,13 +695,46 @@ func Films(films interface{}) Response {
 	}
 }
 
+func toSeasonData(season *sr.SeasonDTO) SeasonData {
+	return SeasonData{
+		SeasonID:      season.SeasonID,
+		FilmID:        season.FilmID,
+		SeasonNumber:  season.SeasonNumber,
+		Title:         season.Title,
+		Synopsis:      season.Synopsis,
+		AirDate:       season.AirDate,
+		EpisodesCount: season.EpisodesCount,
+		AvgRating:     season.AvgRating,
+		TotalReviews:  season.TotalReviews,
+	}
+}
+
+func toEpisodeData(episode *sr.EpisodeDTO) EpisodeData {
+	return EpisodeData{
+		EpisodeID:     episode.EpisodeID,
+		SeasonID:      episode.SeasonID,
+		FilmID:        episode.FilmID,
+		SeasonNumber:  episode.SeasonNumber,
+		EpisodeNumber: episode.EpisodeNumber,
+		Title:         episode.Title,
+		Synopsis:      episode.Synopsis,
+		AirDate:       episode.AirDate,
+		Runtime:       episode.Runtime,
+		AvgRating:     episode.AvgRating,
+		TotalReviews:  episode.TotalReviews,
+	}
+}
+
 type ReviewData struct {
 	ReviewID   uint       `json:"review_id"`
 	UserID     uint       `json:"user_id"`
 	FilmID     uint       `json:"film_id"`
+	SeasonID   *uint      `json:"season_id,omitempty"`
+	EpisodeID  *uint      `json:"episode_id,omitempty"`
 	Rating     int        `json:"rating"`
 	ReviewText string     `json:"review_text"`
 	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	Version    int        `json:"version"`
 }
 
 func Reviews(reviews interface{}) Response {
@@ -310,9 +746,12 @@ func Reviews(reviews interface{}) Response {
 				ReviewID:   v.ReviewID,
 				UserID:     v.UserID,
 				FilmID:     v.FilmID,
+				SeasonID:   v.SeasonID,
+				EpisodeID:  v.EpisodeID,
 				Rating:     v.Rating,
 				ReviewText: v.ReviewText,
 				CreatedAt:  &v.CreateAt,
+				Version:    v.Version,
 			},
 		}
 	case []*r.ReviewDTO:
@@ -322,9 +761,12 @@ func Reviews(reviews interface{}) Response {
 				ReviewID:   review.ReviewID,
 				UserID:     review.UserID,
 				FilmID:     review.FilmID,
+				SeasonID:   review.SeasonID,
+				EpisodeID:  review.EpisodeID,
 				Rating:     review.Rating,
 				ReviewText: review.ReviewText,
 				CreatedAt:  &review.CreateAt,
+				Version:    review.Version,
 			})
 		}
 		return Response{
@@ -354,32 +796,57 @@ func OK() Response {
 	}
 }
 
-func Error(error string) Response {
+type VersionData struct {
+	Version int `json:"version"`
+}
+
+// Version - ответ на изменение сущности с ее новой версией для следующего If-Match
+func Version(version int) Response {
+	return Response{
+		Status: StatusOK,
+		Data:   VersionData{Version: version},
+	}
+}
+
+// Conflict - ответ 409 на изменение по устаревшей версии, data - текущее состояние сущности на сервере
+func Conflict(r *http.Request, error string, data interface{}) Response {
+	return Response{
+		Status: StatusError,
+		Error:  translate(r, error),
+		Data:   data,
+	}
+}
+
+// Error - ответ с ошибкой, сообщение переводится на язык запроса по каталогу messages
+func Error(r *http.Request, error string) Response {
 	return Response{
 		Status: StatusError,
-		Error:  error,
+		Error:  translate(r, error),
 	}
 }
 
-func ValidationError(err error) Response {
+func ValidationError(r *http.Request, err error) Response {
 	var errMsgs []string
 
 	var validationErrs validator.ValidationErrors
-	if errors.As(err, &validationErrs) {
+	var clearErr *patch.ClearError
+	if errors.As(err, &clearErr) {
+		errMsgs = append(errMsgs, fmt.Sprintf(translate(r, "field %s cannot be cleared"), clearErr.Field))
+	} else if errors.As(err, &validationErrs) {
 		for _, err := range validationErrs {
 			switch err.ActualTag() {
 			case "required":
-				errMsgs = append(errMsgs, fmt.Sprintf("field %s is a required field", err.Field()))
+				errMsgs = append(errMsgs, fmt.Sprintf(translate(r, "field %s is a required field"), err.Field()))
 			case "email":
-				errMsgs = append(errMsgs, fmt.Sprintf("field %s is not a valid Email", err.Field()))
+				errMsgs = append(errMsgs, fmt.Sprintf(translate(r, "field %s is not a valid Email"), err.Field()))
 			case "login":
-				errMsgs = append(errMsgs, fmt.Sprintf("field %s is not a valid Login", err.Field()))
+				errMsgs = append(errMsgs, fmt.Sprintf(translate(r, "field %s is not a valid Login"), err.Field()))
 			default:
-				errMsgs = append(errMsgs, fmt.Sprintf("field %s has an invalid value", err.Field()))
+				errMsgs = append(errMsgs, fmt.Sprintf(translate(r, "field %s has an invalid value"), err.Field()))
 			}
 		}
 	} else {
-		errMsgs = append(errMsgs, err.Error())
+		errMsgs = append(errMsgs, translate(r, err.Error()))
 	}
 
 	return Response{
@@ -387,3 +854,522 @@ func ValidationError(err error) Response {
 		Error:  strings.Join(errMsgs, ", "),
 	}
 }
+
+type TranslationData struct {
+	Locale string            `json:"locale"`
+	Fields map[string]string `json:"fields"`
+}
+
+func Translations(translations []*tr.TranslationDTO) Response {
+	data := make([]TranslationData, 0, len(translations))
+	for _, t := range translations {
+		data = append(data, TranslationData{
+			Locale: t.Locale,
+			Fields: t.Fields,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   data,
+	}
+}
+
+type TagData struct {
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	Status     string    `json:"status"`
+	FilmsCount int       `json:"films_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// FilmTagCloudData - тег в облаке тегов фильма, Weight - размер тега от 1 до 5
+type FilmTagCloudData struct {
+	TagID     uint   `json:"tag_id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Score     int    `json:"score"`
+	Upvotes   int    `json:"upvotes"`
+	Downvotes int    `json:"downvotes"`
+	Weight    int    `json:"weight"`
+}
+
+type TagSuggestionData struct {
+	FilmID      uint      `json:"film_id"`
+	FilmTitle   string    `json:"film_title"`
+	TagID       uint      `json:"tag_id"`
+	TagName     string    `json:"tag_name"`
+	TagSlug     string    `json:"tag_slug"`
+	TagStatus   string    `json:"tag_status"`
+	SuggestedBy *uint     `json:"suggested_by"`
+	Score       int       `json:"score"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func Tags(tags interface{}) Response {
+	switch v := tags.(type) {
+	case *tg.TagDTO:
+		return Response{
+			Status: StatusOK,
+			Data:   toTagData(v),
+		}
+	case []*tg.TagDTO:
+		tagList := make([]TagData, 0, len(v))
+		for _, tag := range v {
+			tagList = append(tagList, toTagData(tag))
+		}
+		return Response{
+			Status: StatusOK,
+			Data:   tagList,
+		}
+	default:
+		return Response{
+			Status: StatusError,
+			Error:  "invalid server error",
+		}
+	}
+}
+
+func toTagData(tag *tg.TagDTO) TagData {
+	return TagData{
+		ID:         tag.ID,
+		Name:       tag.Name,
+		Slug:       tag.Slug,
+		Status:     tag.Status,
+		FilmsCount: tag.FilmsCount,
+		CreatedAt:  tag.CreateAt,
+	}
+}
+
+func FilmTagCloud(tags []*tg.FilmTagDTO) Response {
+	data := make([]FilmTagCloudData, 0, len(tags))
+	for _, tag := range tags {
+		data = append(data, FilmTagCloudData{
+			TagID:     tag.TagID,
+			Name:      tag.Name,
+			Slug:      tag.Slug,
+			Score:     tag.Score,
+			Upvotes:   tag.Upvotes,
+			Downvotes: tag.Downvotes,
+			Weight:    tag.Weight,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   data,
+	}
+}
+
+func TagSuggestions(suggestions []*tg.SuggestionDTO) Response {
+	data := make([]TagSuggestionData, 0, len(suggestions))
+	for _, s := range suggestions {
+		data = append(data, TagSuggestionData{
+			FilmID:      s.FilmID,
+			FilmTitle:   s.FilmTitle,
+			TagID:       s.TagID,
+			TagName:     s.TagName,
+			TagSlug:     s.TagSlug,
+			TagStatus:   s.TagStatus,
+			SuggestedBy: s.SuggestedBy,
+			Score:       s.Score,
+			CreatedAt:   s.CreateAt,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   data,
+	}
+}
+
+// FilmImageData - изображение из галереи фильма, Position - порядок среди изображений того же типа
+type FilmImageData struct {
+	ID        uint       `json:"id"`
+	Type      string     `json:"type"`
+	Image     *ImageData `json:"image"`
+	Width     int        `json:"width"`
+	Height    int        `json:"height"`
+	Position  int        `json:"position"`
+	IsPrimary bool       `json:"is_primary"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func FilmImages(images interface{}) Response {
+	switch v := images.(type) {
+	case *md.FilmImageDTO:
+		return Response{
+			Status: StatusOK,
+			Data:   toFilmImageData(v),
+		}
+	case []*md.FilmImageDTO:
+		imageList := make([]FilmImageData, 0, len(v))
+		for _, image := range v {
+			imageList = append(imageList, toFilmImageData(image))
+		}
+		return Response{
+			Status: StatusOK,
+			Data:   imageList,
+		}
+	default:
+		return Response{
+			Status: StatusError,
+			Error:  "invalid server error",
+		}
+	}
+}
+
+func toFilmImageData(image *md.FilmImageDTO) FilmImageData {
+	profile, _ := md.ImageProfile(image.Type)
+	return FilmImageData{
+		ID:        image.ID,
+		Type:      image.Type,
+		Image:     toImageData(image.URL, image.Blurhash, image.Color, profile),
+		Width:     image.Width,
+		Height:    image.Height,
+		Position:  image.Position,
+		IsPrimary: image.IsPrimary,
+		CreatedAt: image.CreateAt,
+	}
+}
+
+// VideoData - видео фильма у провайдера. URL - страница видео, EmbedURL - плеер для iframe,
+// ThumbnailURL - превью, если его можно получить без запроса к провайдеру (только YouTube)
+type VideoData struct {
+	ID           uint       `json:"id"`
+	Type         string     `json:"type"`
+	Provider     string     `json:"provider"`
+	Key          string     `json:"key"`
+	Name         string     `json:"name,omitempty"`
+	Language     string     `json:"language,omitempty"`
+	Official     bool       `json:"official"`
+	PublishedAt  *time.Time `json:"published_at,omitempty"`
+	URL          string     `json:"url"`
+	EmbedURL     string     `json:"embed_url"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+}
+
+func (v *VideoData) setLinks() {
+	v.URL = videolink.WatchURL(v.Provider, v.Key)
+	v.EmbedURL = videolink.EmbedURL(v.Provider, v.Key)
+	v.ThumbnailURL = videolink.ThumbnailURL(v.Provider, v.Key)
+}
+
+func FilmVideos(videos interface{}) Response {
+	switch v := videos.(type) {
+	case *md.FilmVideoDTO:
+		return Response{
+			Status: StatusOK,
+			Data:   toVideoData(v),
+		}
+	case []*md.FilmVideoDTO:
+		videoList := make([]VideoData, 0, len(v))
+		for _, video := range v {
+			videoList = append(videoList, toVideoData(video))
+		}
+		return Response{
+			Status: StatusOK,
+			Data:   videoList,
+		}
+	default:
+		return Response{
+			Status: StatusError,
+			Error:  "invalid server error",
+		}
+	}
+}
+
+func toVideoData(video *md.FilmVideoDTO) VideoData {
+	data := VideoData{
+		ID:          video.ID,
+		Type:        video.Type,
+		Provider:    video.Provider,
+		Key:         video.Key,
+		Name:        video.Name,
+		Language:    video.Language,
+		Official:    video.Official,
+		PublishedAt: video.PublishedAt,
+		CreatedAt:   &video.CreateAt,
+	}
+	data.setLinks()
+
+	return data
+}
+
+// PresignedUploadData - подписанная ссылка на загрузку файла: клиент отправляет файл методом Method
+// на URL с заголовками Headers до ExpiresAt, затем вызывает /uploads/{id}/finalize
+type PresignedUploadData struct {
+	ID        string            `json:"id"`
+	Purpose   string            `json:"purpose"`
+	TargetID  uint              `json:"target_id"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+func PresignedUpload(presigned *up.PresignedUploadDTO) Response {
+	return Response{
+		Status: StatusOK,
+		Data: PresignedUploadData{
+			ID:        presigned.Upload.ID,
+			Purpose:   presigned.Upload.Purpose,
+			TargetID:  presigned.Upload.TargetID,
+			URL:       presigned.URL,
+			Method:    presigned.Method,
+			Headers:   presigned.Headers,
+			ExpiresAt: presigned.UploadExpiresAt,
+		},
+	}
+}
+
+type FinalizedUploadData struct {
+	ID       string     `json:"id"`
+	Purpose  string     `json:"purpose"`
+	TargetID uint       `json:"target_id"`
+	Image    *ImageData `json:"image"`
+}
+
+func FinalizedUpload(result *up.FinalizedUploadDTO) Response {
+	purpose, _ := up.PurposeConfig(result.Purpose)
+	return Response{
+		Status: StatusOK,
+		Data: FinalizedUploadData{
+			ID:       result.ID,
+			Purpose:  result.Purpose,
+			TargetID: result.TargetID,
+			Image:    toImageData(result.URL, result.Blurhash, result.Color, purpose.Profile),
+		},
+	}
+}
+
+// AuditLogData - запись журнала изменений, UserID null - изменение без авторизации или фоновой задачей
+type AuditLogData struct {
+	ID         uint            `json:"id"`
+	UserID     *uint           `json:"user_id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uint            `json:"entity_id"`
+	Diff       json.RawMessage `json:"diff"`
+	RequestID  string          `json:"request_id"`
+	IP         string          `json:"ip"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func AuditLog(entries []*au.LogDTO) Response {
+	data := make([]AuditLogData, 0, len(entries))
+	for _, e := range entries {
+		data = append(data, AuditLogData{
+			ID:         e.ID,
+			UserID:     e.UserID,
+			Action:     e.Action,
+			EntityType: e.EntityType,
+			EntityID:   e.EntityID,
+			Diff:       e.Diff,
+			RequestID:  e.RequestID,
+			IP:         e.IP,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   data,
+	}
+}
+
+// TrashItemData - сущность в корзине, purge_at - когда она будет удалена окончательно
+type TrashItemData struct {
+	EntityType string    `json:"entity_type"`
+	EntityID   uint      `json:"entity_id"`
+	Name       string    `json:"name"`
+	DeletedAt  time.Time `json:"deleted_at"`
+	PurgeAt    time.Time `json:"purge_at"`
+}
+
+func Trash(items []*trs.ItemDTO) Response {
+	data := make([]TrashItemData, 0, len(items))
+	for _, item := range items {
+		data = append(data, TrashItemData{
+			EntityType: item.EntityType,
+			EntityID:   item.EntityID,
+			Name:       item.Name,
+			DeletedAt:  item.DeletedAt,
+			PurgeAt:    item.PurgeAt,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   data,
+	}
+}
+
+// RevisionData - версия фильма или персоны, snapshot только при запросе одной версии
+type RevisionData struct {
+	Version   int             `json:"version"`
+	UserID    *uint           `json:"user_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Snapshot  json.RawMessage `json:"snapshot,omitempty"`
+}
+
+func toRevisionData(rev *rv.RevisionDTO) RevisionData {
+	return RevisionData{
+		Version:   rev.Version,
+		UserID:    rev.UserID,
+		CreatedAt: rev.CreatedAt,
+		Snapshot:  rev.Snapshot,
+	}
+}
+
+func Revisions(revisions []*rv.RevisionDTO) Response {
+	data := make([]RevisionData, 0, len(revisions))
+	for _, rev := range revisions {
+		data = append(data, toRevisionData(rev))
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   data,
+	}
+}
+
+func Revision(rev *rv.RevisionDTO) Response {
+	return Response{
+		Status: StatusOK,
+		Data:   toRevisionData(rev),
+	}
+}
+
+// RevisionChangeData - поле, которое отличается в двух версиях
+type RevisionChangeData struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+type RevisionDiffData struct {
+	From    int                  `json:"from"`
+	To      int                  `json:"to"`
+	Changes []RevisionChangeData `json:"changes"`
+}
+
+func RevisionDiff(diff *rv.DiffDTO) Response {
+	changes := make([]RevisionChangeData, 0, len(diff.Changes))
+	for _, change := range diff.Changes {
+		changes = append(changes, RevisionChangeData{
+			Field: change.Field,
+			From:  change.From,
+			To:    change.To,
+		})
+	}
+	return Response{
+		Status: StatusOK,
+		Data: RevisionDiffData{
+			From:    diff.From,
+			To:      diff.To,
+			Changes: changes,
+		},
+	}
+}
+
+// RevisionRevertedData - версия сущности после отката
+type RevisionRevertedData struct {
+	Version int `json:"version"`
+}
+
+func RevisionReverted(version int) Response {
+	return Response{
+		Status: StatusOK,
+		Data:   RevisionRevertedData{Version: version},
+	}
+}
+
+// SuggestionData - предложение правки, changes - отличия от текущих данных, current_version - текущая версия сущности.
+// comments только при запросе одного предложения
+type SuggestionData struct {
+	ID             uint                    `json:"id"`
+	EntityType     string                  `json:"entity_type"`
+	EntityID       uint                    `json:"entity_id"`
+	EntityName     string                  `json:"entity_name"`
+	UserID         uint                    `json:"user_id"`
+	Patch          json.RawMessage         `json:"patch"`
+	BaseVersion    int                     `json:"base_version"`
+	CurrentVersion int                     `json:"current_version"`
+	Conflict       bool                    `json:"conflict"`
+	Status         string                  `json:"status"`
+	ReviewerID     *uint                   `json:"reviewer_id"`
+	ReviewedAt     *time.Time              `json:"reviewed_at"`
+	AppliedVersion *int                    `json:"applied_version"`
+	CreatedAt      time.Time               `json:"created_at"`
+	Changes        []RevisionChangeData    `json:"changes"`
+	Comments       []SuggestionCommentData `json:"comments,omitempty"`
+}
+
+type SuggestionCommentData struct {
+	ID        uint      `json:"id"`
+	UserID    *uint     `json:"user_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func toSuggestionCommentData(comment *sg.CommentDTO) SuggestionCommentData {
+	return SuggestionCommentData{
+		ID:        comment.ID,
+		UserID:    comment.UserID,
+		Text:      comment.Text,
+		CreatedAt: comment.CreatedAt,
+	}
+}
+
+func toSuggestionData(suggestion *sg.SuggestionDTO) SuggestionData {
+	data := SuggestionData{
+		ID:             suggestion.ID,
+		EntityType:     suggestion.EntityType,
+		EntityID:       suggestion.EntityID,
+		EntityName:     suggestion.EntityName,
+		UserID:         suggestion.UserID,
+		Patch:          suggestion.Patch,
+		BaseVersion:    suggestion.BaseVersion,
+		CurrentVersion: suggestion.CurrentVersion,
+		Conflict:       suggestion.Conflict,
+		Status:         suggestion.Status,
+		ReviewerID:     suggestion.ReviewerID,
+		ReviewedAt:     suggestion.ReviewedAt,
+		AppliedVersion: suggestion.AppliedVersion,
+		CreatedAt:      suggestion.CreatedAt,
+		Changes:        make([]RevisionChangeData, 0, len(suggestion.Changes)),
+	}
+	for _, change := range suggestion.Changes {
+		data.Changes = append(data.Changes, RevisionChangeData{
+			Field: change.Field,
+			From:  change.From,
+			To:    change.To,
+		})
+	}
+	for _, comment := range suggestion.Comments {
+		data.Comments = append(data.Comments, toSuggestionCommentData(comment))
+	}
+	return data
+}
+
+func Suggestions(suggestions []*sg.SuggestionDTO) Response {
+	data := make([]SuggestionData, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		data = append(data, toSuggestionData(suggestion))
+	}
+	return Response{
+		Status: StatusOK,
+		Data:   data,
+	}
+}
+
+func Suggestion(suggestion *sg.SuggestionDTO) Response {
+	return Response{
+		Status: StatusOK,
+		Data:   toSuggestionData(suggestion),
+	}
+}
+
+func SuggestionComment(comment *sg.CommentDTO) Response {
+	return Response{
+		Status: StatusOK,
+		Data:   toSuggestionCommentData(comment),
+	}
+}
@@ -0,0 +1,132 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"gorm.io/gorm"
+	"reflect"
+	"server/pkg/lib/audit"
+	"sort"
+	"time"
+)
+
+// История версий: после каждого изменения фильма или персоны сохраняется полный снимок DTO вместе со связями.
+// В отличие от журнала изменений по снимку можно вернуть запись к прежней версии
+
+const (
+	EntityFilm   = "film"
+	EntityPerson = "person"
+)
+
+// Entities - типы сущностей, у которых есть история версий
+var Entities = []string{EntityFilm, EntityPerson}
+
+// Revision - снимок сущности на версии Version (см. pkg/lib/optimistic)
+type Revision struct {
+	RevisionID uint            `gorm:"column:revision_id;primaryKey"`
+	EntityType string          `gorm:"column:entity_type"`
+	EntityID   uint            `gorm:"column:entity_id"`
+	Version    int             `gorm:"column:version"`
+	Snapshot   json.RawMessage `gorm:"column:snapshot;type:jsonb"`
+	UserID     *uint           `gorm:"column:user_id"`
+	CreatedAt  time.Time       `gorm:"column:created_at"`
+}
+
+func (Revision) TableName() string {
+	return "revisions"
+}
+
+// Save сохраняет снимок сущности. Вызывается в транзакции изменения после optimistic.Bump с версией,
+// которую он вернул, поэтому у каждой версии ровно один снимок
+func Save(ctx context.Context, db *gorm.DB, entityType string, id uint, version int, snapshot interface{}) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	actor := audit.ActorFromContext(ctx)
+	return db.Create(&Revision{
+		EntityType: entityType,
+		EntityID:   id,
+		Version:    version,
+		Snapshot:   data,
+		UserID:     actor.UserID,
+		CreatedAt:  time.Now(),
+	}).Error
+}
+
+// Ignore - поля снимков, которые не сравниваются: версия, статистика, изображения и связи, которые
+// меняются не правкой записи, и поля запроса
+var Ignore = map[string][]string{
+	EntityFilm: {
+		"version", "create_at", "remove_poster", "poster_url", "poster_blurhash", "poster_color",
+		"avg_rating", "total_reviews", "count_ratings_0_20", "count_ratings_21_40", "count_ratings_41_60", "count_ratings_61_80", "count_ratings_81_100",
+		"genres", "collection", "tags", "backdrop", "trailer",
+	},
+	EntityPerson: {"person_id", "version", "create_at", "reset_avatar", "avatar_url", "avatar_blurhash", "avatar_color"},
+}
+
+// Change - поле, которое отличается в двух снимках
+type Change struct {
+	Field string
+	From  json.RawMessage
+	To    json.RawMessage
+}
+
+// Diff сравнивает два снимка по полям верхнего уровня, поля из ignore не сравниваются.
+// Поля, которого нет в одном из снимков, там null. Результат отсортирован по имени поля
+func Diff(from, to json.RawMessage, ignore ...string) ([]Change, error) {
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(from, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &after); err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool, len(ignore))
+	for _, field := range ignore {
+		skip[field] = true
+	}
+
+	fields := make(map[string]struct{}, len(before)+len(after))
+	for field := range before {
+		fields[field] = struct{}{}
+	}
+	for field := range after {
+		fields[field] = struct{}{}
+	}
+
+	changes := make([]Change, 0)
+	for field := range fields {
+		if skip[field] {
+			continue
+		}
+		a, b := orNull(before[field]), orNull(after[field])
+		if equal(a, b) {
+			continue
+		}
+		changes = append(changes, Change{Field: field, From: a, To: b})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes, nil
+}
+
+func orNull(value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
+
+// equal сравнивает значения без учета порядка ключей и пробелов
+func equal(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,84 @@
+package revision
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   string
+		to     string
+		ignore []string
+		want   [][3]string // поле, было, стало
+	}{
+		{name: "same snapshot", from: `{"title":"Alien","runtime":117}`, to: `{"title":"Alien","runtime":117}`, want: [][3]string{}},
+		{
+			name: "changed fields are sorted",
+			from: `{"title":"Alien","runtime":117,"description":"old"}`,
+			to:   `{"title":"Aliens","runtime":137,"description":"old"}`,
+			want: [][3]string{{"runtime", "117", "137"}, {"title", `"Alien"`, `"Aliens"`}},
+		},
+		{
+			name:   "ignored fields",
+			from:   `{"title":"Alien","version":1,"poster_url":"a.jpg"}`,
+			to:     `{"title":"Alien","version":2,"poster_url":"b.jpg"}`,
+			ignore: Ignore[EntityFilm],
+			want:   [][3]string{},
+		},
+		{name: "missing field is null", from: `{"title":"Alien"}`, to: `{"title":"Alien","slug":"alien"}`, want: [][3]string{{"slug", "null", `"alien"`}}},
+		{name: "removed field is null", from: `{"title":"Alien","slug":"alien"}`, to: `{"title":"Alien"}`, want: [][3]string{{"slug", `"alien"`, "null"}}},
+		{name: "null equals missing", from: `{"slug":null}`, to: `{}`, want: [][3]string{}},
+		{
+			name: "key order and spaces",
+			from: `{"country":{"code":"US","name":"USA"}}`,
+			to:   `{ "country" : { "name" : "USA", "code" : "US" } }`,
+			want: [][3]string{},
+		},
+		{
+			name: "nested change",
+			from: `{"crew":[{"person_id":1}]}`,
+			to:   `{"crew":[{"person_id":2}]}`,
+			want: [][3]string{{"crew", `[{"person_id":1}]`, `[{"person_id":2}]`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff(json.RawMessage(tt.from), json.RawMessage(tt.to), tt.ignore...)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+
+			got := make([][3]string, 0, len(changes))
+			for _, change := range changes {
+				got = append(got, [3]string{change.Field, string(change.From), string(change.To)})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "invalid from", from: `{"title":`, to: `{}`},
+		{name: "invalid to", from: `{}`, to: `not json`},
+		{name: "not an object", from: `[1,2]`, to: `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Diff(json.RawMessage(tt.from), json.RawMessage(tt.to)); err == nil {
+				t.Errorf("Diff(%s, %s) error = nil, want error", tt.from, tt.to)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package videolink
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Провайдеры видео
+const (
+	ProviderYouTube = "youtube"
+	ProviderVK      = "vk"
+	ProviderRutube  = "rutube"
+)
+
+var ErrInvalidVideoURL = errors.New("invalid video url, supported providers are youtube, vk, rutube")
+
+// Link - видео у провайдера. Key - id видео: 11 символов у YouTube, "{oid}_{id}" у VK, 32 hex у Rutube
+type Link struct {
+	Provider string
+	Key      string
+}
+
+var (
+	youtubeKey = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vkKey      = regexp.MustCompile(`^-?[0-9]+_[0-9]+$`)
+	vkPath     = regexp.MustCompile(`video(-?[0-9]+_[0-9]+)`)
+	rutubeKey  = regexp.MustCompile(`^[0-9a-f]{32}$`)
+)
+
+// Parse разбирает ссылку на видео YouTube, VK или Rutube и приводит ее к провайдеру и id видео.
+// Проверяется только вид ссылки, к провайдеру запросов не делается
+func Parse(rawURL string) (Link, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return Link{}, ErrInvalidVideoURL
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+
+	var link Link
+	switch host {
+	case "youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		link = Link{Provider: ProviderYouTube, Key: parseYouTube(u, segments)}
+	case "youtu.be":
+		link = Link{Provider: ProviderYouTube, Key: first(segments)}
+	case "vk.com", "vk.ru", "vkvideo.ru":
+		link = Link{Provider: ProviderVK, Key: parseVK(u, segments)}
+	case "rutube.ru":
+		link = Link{Provider: ProviderRutube, Key: parseRutube(segments)}
+	default:
+		return Link{}, ErrInvalidVideoURL
+	}
+
+	if !ValidKey(link.Provider, link.Key) {
+		return Link{}, ErrInvalidVideoURL
+	}
+
+	return link, nil
+}
+
+// ValidKey проверяет формат id видео для провайдера
+func ValidKey(provider string, key string) bool {
+	switch provider {
+	case ProviderYouTube:
+		return youtubeKey.MatchString(key)
+	case ProviderVK:
+		return vkKey.MatchString(key)
+	case ProviderRutube:
+		return rutubeKey.MatchString(key)
+	default:
+		return false
+	}
+}
+
+// WatchURL возвращает ссылку на страницу видео у провайдера
+func WatchURL(provider string, key string) string {
+	switch provider {
+	case ProviderYouTube:
+		return "https://www.youtube.com/watch?v=" + key
+	case ProviderVK:
+		return "https://vk.com/video" + key
+	case ProviderRutube:
+		return "https://rutube.ru/video/" + key + "/"
+	default:
+		return ""
+	}
+}
+
+// EmbedURL возвращает адрес плеера для iframe
+func EmbedURL(provider string, key string) string {
+	switch provider {
+	case ProviderYouTube:
+		return "https://www.youtube-nocookie.com/embed/" + key
+	case ProviderVK:
+		oid, id, _ := strings.Cut(key, "_")
+		return "https://vk.com/video_ext.php?oid=" + oid + "&id=" + id
+	case ProviderRutube:
+		return "https://rutube.ru/play/embed/" + key
+	default:
+		return ""
+	}
+}
+
+// ThumbnailURL возвращает превью видео, если его адрес можно построить без запроса к провайдеру
+func ThumbnailURL(provider string, key string) string {
+	if provider == ProviderYouTube {
+		return "https://i.ytimg.com/vi/" + key + "/hqdefault.jpg"
+	}
+	return ""
+}
+
+// parseYouTube: watch?v=KEY, embed/KEY, shorts/KEY, live/KEY, v/KEY
+func parseYouTube(u *url.URL, segments []string) string {
+	if len(segments) == 1 && segments[0] == "watch" {
+		return u.Query().Get("v")
+	}
+	if len(segments) == 2 {
+		switch segments[0] {
+		case "embed", "shorts", "live", "v":
+			return segments[1]
+		}
+	}
+	return ""
+}
+
+// parseVK: video-OID_ID, video_ext.php?oid=OID&id=ID, любая страница с ?z=video-OID_ID
+func parseVK(u *url.URL, segments []string) string {
+	query := u.Query()
+	if len(segments) == 1 && segments[0] == "video_ext.php" {
+		return query.Get("oid") + "_" + query.Get("id")
+	}
+	if z := query.Get("z"); z != "" {
+		if m := vkPath.FindStringSubmatch(z); m != nil {
+			return m[1]
+		}
+	}
+	if len(segments) == 1 {
+		if m := vkPath.FindStringSubmatch(segments[0]); m != nil && m[0] == segments[0] {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// parseRutube: video/KEY/, play/embed/KEY, shorts/KEY
+func parseRutube(segments []string) string {
+	switch {
+	case len(segments) == 2 && (segments[0] == "video" || segments[0] == "shorts"):
+		return strings.ToLower(segments[1])
+	case len(segments) == 3 && segments[0] == "play" && segments[1] == "embed":
+		return strings.ToLower(segments[2])
+	}
+	return ""
+}
+
+func first(segments []string) string {
+	if len(segments) == 0 {
+		return ""
+	}
+	return segments[0]
+}
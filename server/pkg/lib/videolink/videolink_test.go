@@ -0,0 +1,126 @@
+package videolink
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	ytKey     = "dQw4w9WgXcQ"
+	rutubeHex = "0123456789abcdef0123456789abcdef"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    Link
+		wantErr bool
+	}{
+		{name: "youtube watch", url: "https://www.youtube.com/watch?v=" + ytKey + "&t=42", want: Link{ProviderYouTube, ytKey}},
+		{name: "youtube mobile", url: "https://m.youtube.com/watch?v=" + ytKey, want: Link{ProviderYouTube, ytKey}},
+		{name: "youtube without scheme", url: "youtube.com/watch?v=" + ytKey, want: Link{ProviderYouTube, ytKey}},
+		{name: "youtube short link", url: "https://youtu.be/" + ytKey + "?si=abc", want: Link{ProviderYouTube, ytKey}},
+		{name: "youtube embed", url: "https://www.youtube-nocookie.com/embed/" + ytKey, want: Link{ProviderYouTube, ytKey}},
+		{name: "youtube shorts", url: "https://youtube.com/shorts/" + ytKey, want: Link{ProviderYouTube, ytKey}},
+		{name: "youtube music", url: "https://music.youtube.com/watch?v=" + ytKey, want: Link{ProviderYouTube, ytKey}},
+		{name: "youtube channel", url: "https://www.youtube.com/@channel", wantErr: true},
+		{name: "youtube short key", url: "https://youtu.be/abc", wantErr: true},
+		{name: "vk video page", url: "https://vk.com/video-12345_678", want: Link{ProviderVK, "-12345_678"}},
+		{name: "vk video of user", url: "https://vkvideo.ru/video12345_678", want: Link{ProviderVK, "12345_678"}},
+		{name: "vk player", url: "https://vk.com/video_ext.php?oid=-12345&id=678&hd=2", want: Link{ProviderVK, "-12345_678"}},
+		{name: "vk video in wall", url: "https://vk.com/club1?z=video-12345_678%2Fpl_wall", want: Link{ProviderVK, "-12345_678"}},
+		{name: "vk page without video", url: "https://vk.com/club1", wantErr: true},
+		{name: "vk video with suffix", url: "https://vk.com/video-12345_678abc", wantErr: true},
+		{name: "rutube video", url: "https://rutube.ru/video/" + rutubeHex + "/", want: Link{ProviderRutube, rutubeHex}},
+		{name: "rutube upper case", url: "https://rutube.ru/video/0123456789ABCDEF0123456789ABCDEF/", want: Link{ProviderRutube, rutubeHex}},
+		{name: "rutube player", url: "https://rutube.ru/play/embed/" + rutubeHex, want: Link{ProviderRutube, rutubeHex}},
+		{name: "rutube channel", url: "https://rutube.ru/channel/123/", wantErr: true},
+		{name: "unknown provider", url: "https://vimeo.com/123456", wantErr: true},
+		{name: "not http", url: "ftp://youtube.com/watch?v=" + ytKey, wantErr: true},
+		{name: "empty", url: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.url)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidVideoURL) {
+					t.Fatalf("Parse(%q) error = %v, want %v", tt.url, err, ErrInvalidVideoURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.url, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLs(t *testing.T) {
+	tests := []struct {
+		provider  string
+		key       string
+		watch     string
+		embed     string
+		thumbnail string
+	}{
+		{
+			provider:  ProviderYouTube,
+			key:       ytKey,
+			watch:     "https://www.youtube.com/watch?v=" + ytKey,
+			embed:     "https://www.youtube-nocookie.com/embed/" + ytKey,
+			thumbnail: "https://i.ytimg.com/vi/" + ytKey + "/hqdefault.jpg",
+		},
+		{
+			provider: ProviderVK,
+			key:      "-12345_678",
+			watch:    "https://vk.com/video-12345_678",
+			embed:    "https://vk.com/video_ext.php?oid=-12345&id=678",
+		},
+		{
+			provider: ProviderRutube,
+			key:      rutubeHex,
+			watch:    "https://rutube.ru/video/" + rutubeHex + "/",
+			embed:    "https://rutube.ru/play/embed/" + rutubeHex,
+		},
+		{provider: "vimeo", key: "123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			if got := WatchURL(tt.provider, tt.key); got != tt.watch {
+				t.Errorf("WatchURL() = %q, want %q", got, tt.watch)
+			}
+			if got := EmbedURL(tt.provider, tt.key); got != tt.embed {
+				t.Errorf("EmbedURL() = %q, want %q", got, tt.embed)
+			}
+			if got := ThumbnailURL(tt.provider, tt.key); got != tt.thumbnail {
+				t.Errorf("ThumbnailURL() = %q, want %q", got, tt.thumbnail)
+			}
+		})
+	}
+}
+
+// Ссылки, которые строит пакет, разбираются обратно в то же видео
+func TestParseRoundTrip(t *testing.T) {
+	links := []Link{
+		{ProviderYouTube, ytKey},
+		{ProviderVK, "-12345_678"},
+		{ProviderRutube, rutubeHex},
+	}
+
+	for _, link := range links {
+		t.Run(link.Provider, func(t *testing.T) {
+			for _, url := range []string{WatchURL(link.Provider, link.Key), EmbedURL(link.Provider, link.Key)} {
+				got, err := Parse(url)
+				if err != nil || got != link {
+					t.Errorf("Parse(%q) = %+v, %v, want %+v", url, got, err, link)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// New отвечает на условные GET запросы. Ответ 200 буферизуется и получает сильный ETag - хэш тела вместе
+// с Content-Language. Обработчики одной сущности выставляют тег с ее версией (optimistic.SetETag), к нему
+// добавляется хэш: тело меняется и без новой версии (оценки, теги, переводы), а тег с версией подходит
+// для If-Match при изменении. При совпадении If-None-Match отдается 304 без тела. If-Modified-Since
+// не поддерживается: время изменения строки не отражает оценки, теги и переводы в ответе, и 304 отдавался бы
+// на устаревшее тело. Успешным ответам ставится Cache-Control: public с maxAge,
+// stale-while-revalidate разрешает CDN отдавать устаревший ответ, пока он обновляется
+func New(maxAge, staleWhileRevalidate time.Duration) func(next http.Handler) http.Handler {
+	cacheControl := fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+	if staleWhileRevalidate > 0 {
+		cacheControl += fmt.Sprintf(", stale-while-revalidate=%d", int(staleWhileRevalidate.Seconds()))
+	}
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			bw := &bufferedWriter{header: w.Header(), status: http.StatusOK}
+			next.ServeHTTP(bw, r)
+
+			if bw.status != http.StatusOK {
+				w.WriteHeader(bw.status)
+				_, _ = w.Write(bw.body.Bytes())
+				return
+			}
+
+			h := w.Header()
+			h.Set("ETag", etag(h, bw.body.Bytes()))
+			h.Set("Cache-Control", cacheControl)
+
+			if notModified(r, h) {
+				// в 304 остаются только заголовки, описывающие закэшированный ответ
+				h.Del("Content-Type")
+				h.Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(bw.body.Bytes())
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// etag - хэш тела и языка ответа, перед ним версия сущности, если обработчик ее выставил: "3-<хэш>"
+func etag(h http.Header, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(h.Get("Content-Language")))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	sum := hex.EncodeToString(hash.Sum(nil)[:16])
+
+	if version := strings.Trim(h.Get("ETag"), `"`); version != "" {
+		return `"` + version + "-" + sum + `"`
+	}
+	return `"` + sum + `"`
+}
+
+// notModified - совпадение If-None-Match с ETag ответа
+func notModified(r *http.Request, h http.Header) bool {
+	inm := r.Header.Get("If-None-Match")
+	return inm != "" && etagMatch(inm, h.Get("ETag"))
+}
+
+// etagMatch - слабое сравнение: для GET допускаются и слабые теги, которые CDN делает из сильных при сжатии
+func etagMatch(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+type bufferedWriter struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
@@ -0,0 +1,180 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const body = `{"data":"film"}`
+
+// page - ответ обработчика: etag - тег, который выставил бы обработчик по версии сущности
+type page struct {
+	status int
+	etag   string
+	lang   string
+	body   string
+}
+
+func handler(p page) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p.etag != "" {
+			w.Header().Set("ETag", p.etag)
+		}
+		if p.lang != "" {
+			w.Header().Set("Content-Language", p.lang)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if p.status == 0 {
+			p.status = http.StatusOK
+		}
+		if p.body == "" {
+			p.body = body
+		}
+		w.WriteHeader(p.status)
+		_, _ = w.Write([]byte(p.body))
+	})
+}
+
+// tagOf - ETag, который middleware выставит ответу p
+func tagOf(t *testing.T, p page) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	New(time.Minute, 0)(handler(p)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec.Header().Get("ETag")
+}
+
+func TestETag(t *testing.T) {
+	hash := tagOf(t, page{})
+	v3 := tagOf(t, page{etag: `"3"`})
+
+	if !strings.HasPrefix(v3, `"3-`) || !strings.HasSuffix(v3, strings.TrimPrefix(hash, `"`)) {
+		t.Errorf("version tag = %s, want version 3 and body hash %s", v3, hash)
+	}
+
+	tests := []struct {
+		name  string
+		other page
+	}{
+		{name: "body changed without new version", other: page{etag: `"3"`, body: `{"data":"film","avg_rating":80}`}},
+		{name: "new version", other: page{etag: `"4"`}},
+		{name: "other language", other: page{etag: `"3"`, lang: "en"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagOf(t, tt.other); got == v3 {
+				t.Errorf("ETag = %s, want it to differ from %s", got, v3)
+			}
+		})
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	hash := tagOf(t, page{})
+	v3 := tagOf(t, page{etag: `"3"`})
+	v3en := tagOf(t, page{etag: `"3"`, lang: "en"})
+	v4 := tagOf(t, page{etag: `"4"`})
+
+	tests := []struct {
+		name       string
+		method     string
+		page       page
+		headers    map[string]string
+		wantStatus int
+		wantETag   string
+	}{
+		{name: "no condition", wantStatus: http.StatusOK, wantETag: hash},
+		{name: "body hash matches", headers: map[string]string{"If-None-Match": hash}, wantStatus: http.StatusNotModified, wantETag: hash},
+		{name: "body hash differs", headers: map[string]string{"If-None-Match": `"other"`}, wantStatus: http.StatusOK, wantETag: hash},
+		{name: "version tag matches", page: page{etag: `"3"`}, headers: map[string]string{"If-None-Match": v3}, wantStatus: http.StatusNotModified, wantETag: v3},
+		{name: "version tag is stale", page: page{etag: `"4"`}, headers: map[string]string{"If-None-Match": v3}, wantStatus: http.StatusOK, wantETag: v4},
+		{
+			name:       "body changed without new version",
+			page:       page{etag: `"3"`, body: `{"data":"film","avg_rating":80}`},
+			headers:    map[string]string{"If-None-Match": v3},
+			wantStatus: http.StatusOK,
+			wantETag:   tagOf(t, page{etag: `"3"`, body: `{"data":"film","avg_rating":80}`}),
+		},
+		{name: "other language", page: page{etag: `"3"`, lang: "ru"}, headers: map[string]string{"If-None-Match": v3en}, wantStatus: http.StatusOK, wantETag: tagOf(t, page{etag: `"3"`, lang: "ru"})},
+		{name: "bare version is not enough", page: page{etag: `"3"`}, headers: map[string]string{"If-None-Match": `"3"`}, wantStatus: http.StatusOK, wantETag: v3},
+		{name: "weak tag from CDN", page: page{etag: `"3"`}, headers: map[string]string{"If-None-Match": "W/" + v3}, wantStatus: http.StatusNotModified, wantETag: v3},
+		{name: "one of listed tags", page: page{etag: `"3"`}, headers: map[string]string{"If-None-Match": v4 + ", " + v3}, wantStatus: http.StatusNotModified, wantETag: v3},
+		{name: "any tag", page: page{etag: `"3"`}, headers: map[string]string{"If-None-Match": `*`}, wantStatus: http.StatusNotModified, wantETag: v3},
+		{
+			name:       "If-Modified-Since is ignored",
+			page:       page{etag: `"3"`},
+			headers:    map[string]string{"If-Modified-Since": time.Now().UTC().Format(http.TimeFormat)},
+			wantStatus: http.StatusOK,
+			wantETag:   v3,
+		},
+		{name: "error is not cached", page: page{status: http.StatusNotFound}, headers: map[string]string{"If-None-Match": `*`}, wantStatus: http.StatusNotFound},
+		{name: "write method is passed through", method: http.MethodPut, page: page{etag: `"3"`}, headers: map[string]string{"If-None-Match": v3}, wantStatus: http.StatusOK, wantETag: `"3"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			r := httptest.NewRequest(method, "/", nil)
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			New(time.Minute, time.Hour)(handler(tt.page)).ServeHTTP(rec, r)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+
+			want := tt.page.body
+			if want == "" {
+				want = body
+			}
+			switch tt.wantStatus {
+			case http.StatusNotModified:
+				if rec.Body.Len() != 0 {
+					t.Errorf("304 body = %q, want empty", rec.Body.String())
+				}
+				if rec.Header().Get("Content-Type") != "" {
+					t.Errorf("304 has Content-Type %q", rec.Header().Get("Content-Type"))
+				}
+			default:
+				if rec.Body.String() != want {
+					t.Errorf("body = %q, want %q", rec.Body.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	tests := []struct {
+		name                 string
+		maxAge               time.Duration
+		staleWhileRevalidate time.Duration
+		want                 string
+	}{
+		{name: "max age only", maxAge: time.Minute, want: "public, max-age=60"},
+		{name: "with stale while revalidate", maxAge: time.Minute, staleWhileRevalidate: time.Hour, want: "public, max-age=60, stale-while-revalidate=3600"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			New(tt.maxAge, tt.staleWhileRevalidate)(handler(page{})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if got := rec.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
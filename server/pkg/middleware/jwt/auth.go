@@ -64,7 +64,7 @@ func NewAdminAuth(log *slog.Logger) func(next http.Handler) http.Handler {
 			if !claims.IsAdmin {
 				log.Info("user is not admin")
 				w.WriteHeader(http.StatusForbidden)
-				render.JSON(w, r, resp.Error("access forbidden"))
+				render.JSON(w, r, resp.Error(r, "access forbidden"))
 				return
 			}
 
@@ -81,9 +81,9 @@ func handleAuthError(w http.ResponseWriter, r *http.Request, log *slog.Logger, e
 	log.Error("auth error", slog.String("error", err.Error()))
 	if errors.Is(err, jwt.ErrNoAccessToken) {
 		w.WriteHeader(http.StatusUnauthorized)
-		render.JSON(w, r, resp.Error(err.Error()))
+		render.JSON(w, r, resp.Error(r, err.Error()))
 	} else {
 		w.WriteHeader(http.StatusUnauthorized)
-		render.JSON(w, r, resp.Error(err.Error()))
+		render.JSON(w, r, resp.Error(r, err.Error()))
 	}
 }
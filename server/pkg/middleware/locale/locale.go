@@ -0,0 +1,111 @@
+package locale
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	RU = "ru"
+	EN = "en"
+
+	// Default - язык, на котором хранятся основные поля сущностей
+	Default = RU
+)
+
+// Supported - языки, на которые переводятся контент и сообщения API
+var Supported = []string{RU, EN}
+
+type ctxKey struct{}
+
+// New определяет язык запроса: параметр lang важнее заголовка Accept-Language,
+// неподдерживаемые значения пропускаются, в крайнем случае используется Default
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			lang := Negotiate(r.URL.Query().Get("lang"), r.Header.Get("Accept-Language"))
+
+			w.Header().Set("Content-Language", lang)
+			w.Header().Add("Vary", "Accept-Language")
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, lang)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// FromContext возвращает язык запроса, Default если middleware не подключен
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(ctxKey{}).(string); ok {
+		return lang
+	}
+	return Default
+}
+
+// FromRequest - сокращение для FromContext(r.Context())
+func FromRequest(r *http.Request) string {
+	return FromContext(r.Context())
+}
+
+// Negotiate выбирает поддерживаемый язык из параметра lang и заголовка Accept-Language
+func Negotiate(lang, acceptLanguage string) string {
+	if l, ok := Match(lang); ok {
+		return l
+	}
+
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag: fields[0], q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if l, ok := Match(c.tag); ok {
+			return l
+		}
+	}
+
+	return Default
+}
+
+// Match приводит языковой тег к поддерживаемому языку: "en-US" -> "en"
+func Match(tag string) (string, bool) {
+	tag = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "_", "-")
+	if base, _, found := strings.Cut(tag, "-"); found {
+		tag = base
+	}
+	for _, l := range Supported {
+		if l == tag {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// Fallbacks - порядок поиска перевода: запрошенный язык, затем Default
+func Fallbacks(lang string) []string {
+	if lang == Default {
+		return []string{Default}
+	}
+	return []string{lang, Default}
+}
@@ -0,0 +1,121 @@
+package locale
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		lang           string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "nothing", want: Default},
+		{name: "lang parameter", lang: "en", want: EN},
+		{name: "lang parameter wins over header", lang: "ru", acceptLanguage: "en", want: RU},
+		{name: "unsupported lang parameter falls back to header", lang: "de", acceptLanguage: "en", want: EN},
+		{name: "region subtag", acceptLanguage: "en-US", want: EN},
+		{name: "underscore and case", acceptLanguage: "EN_gb", want: EN},
+		{name: "first supported in order", acceptLanguage: "de, en, ru", want: EN},
+		{name: "highest q wins", acceptLanguage: "ru;q=0.5, en;q=0.9", want: EN},
+		{name: "no q means 1", acceptLanguage: "en;q=0.8, ru", want: RU},
+		{name: "equal q keeps header order", acceptLanguage: "en;q=0.7, ru;q=0.7", want: EN},
+		{name: "q=0 is refused", acceptLanguage: "en;q=0, de", want: Default},
+		{name: "unsupported only", acceptLanguage: "de-DE, fr;q=0.8", want: Default},
+		{name: "spaces around params", acceptLanguage: "ru ; q=0.1 , en ; q=0.2", want: EN},
+		{name: "invalid q is ignored", acceptLanguage: "ru;q=0.5, en;q=abc", want: EN},
+		{name: "any language", acceptLanguage: "*", want: Default},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.lang, tt.acceptLanguage); got != tt.want {
+				t.Errorf("Negotiate(%q, %q) = %q, want %q", tt.lang, tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   string
+		wantOk bool
+	}{
+		{tag: "ru", want: RU, wantOk: true},
+		{tag: " en-US ", want: EN, wantOk: true},
+		{tag: "en_GB", want: EN, wantOk: true},
+		{tag: "de"},
+		{tag: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, ok := Match(tt.tag)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Match(%q) = %q, %v, want %q, %v", tt.tag, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	tests := []struct {
+		lang string
+		want []string
+	}{
+		{lang: Default, want: []string{Default}},
+		{lang: EN, want: []string{EN, Default}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			if got := Fallbacks(tt.lang); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fallbacks(%q) = %v, want %v", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "default", url: "/", want: Default},
+		{name: "header", url: "/", acceptLanguage: "en-US,en;q=0.9", want: EN},
+		{name: "query parameter", url: "/?lang=en", acceptLanguage: "ru", want: EN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = FromRequest(r)
+			})
+
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			New()(next).ServeHTTP(rec, r)
+
+			if got != tt.want {
+				t.Errorf("FromRequest() = %q, want %q", got, tt.want)
+			}
+			if lang := rec.Header().Get("Content-Language"); lang != tt.want {
+				t.Errorf("Content-Language = %q, want %q", lang, tt.want)
+			}
+			if vary := rec.Header().Get("Vary"); vary != "Accept-Language" {
+				t.Errorf("Vary = %q, want Accept-Language", vary)
+			}
+		})
+	}
+}